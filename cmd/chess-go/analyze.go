@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"chess_go/internal/analysis"
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// runAnalyze implements "chess-go analyze": it searches every position of the
+// games in one or more PGN files and writes them back annotated with
+// evaluations, inaccuracies, mistakes and blunders, and the better lines,
+// with a per-side summary on stderr. It returns the exit code.
+func runAnalyze(args []string) int {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	depth := fs.Int("depth", 0, "search each position to this depth")
+	moveTime := fs.Duration("movetime", 0, "search each position for this long (default 1s)")
+	nodes := fs.Int64("nodes", 0, "search each position for this many nodes")
+	out := fs.String("out", "", "write the annotated PGN to this file (default: stdout)")
+	inaccuracy := fs.Int("inaccuracy", 50, "centipawn loss of an inaccuracy (?!)")
+	mistake := fs.Int("mistake", 100, "centipawn loss of a mistake (?)")
+	blunder := fs.Int("blunder", 300, "centipawn loss of a blunder (??)")
+	syzygy := fs.String("syzygy", "", "probe Syzygy tablebases from this directory")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go analyze [flags] games.pgn...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var cfg engine.Config
+	if *syzygy != "" {
+		tb, err := chess.OpenSyzygy(*syzygy)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "analyze:", err)
+			return 1
+		}
+		cfg.Tablebase, cfg.Syzygy50MoveRule = tb, true
+	}
+	opts := analysis.Options{
+		Search:     cfg.Search,
+		Limit:      engine.TimeControl{Depth: *depth, MoveTime: *moveTime, Nodes: *nodes},
+		Inaccuracy: *inaccuracy, Mistake: *mistake, Blunder: *blunder,
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "analyze:", err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	n := 0
+	for _, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "analyze:", err)
+			return 1
+		}
+		sc := chess.NewPGNScanner(f)
+		for sc.Scan() {
+			n++
+			rep, err := analysis.Analyze(ctx, sc.Game(), opts)
+			if err != nil {
+				_ = f.Close()
+				fmt.Fprintf(os.Stderr, "analyze: game %d: %v\n", n, err)
+				return 1
+			}
+			if n > 1 {
+				fmt.Fprintln(bw)
+			}
+			fmt.Fprint(bw, rep.Annotated().String())
+			printSummary(n, rep)
+		}
+		_ = f.Close()
+		if err := sc.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "analyze: %s: %v\n", path, err)
+			return 1
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "analyze:", err)
+		return 1
+	}
+	return 0
+}
+
+// printSummary writes each side's ACPL and error counts to stderr.
+func printSummary(n int, rep analysis.Report) {
+	fmt.Fprintf(os.Stderr, "game %d: %s - %s %s\n", n, tagOr(rep.Game, "White"), tagOr(rep.Game, "Black"), rep.Game.Result)
+	for _, side := range []struct {
+		name  string
+		color chess.Color
+	}{{"White", chess.White}, {"Black", chess.Black}} {
+		fmt.Fprintf(os.Stderr, "  %s: ACPL %.1f, %d inaccuracies, %d mistakes, %d blunders\n", side.name,
+			rep.ACPL(side.color), rep.Count(side.color, analysis.Inaccuracy),
+			rep.Count(side.color, analysis.Mistake), rep.Count(side.color, analysis.Blunder))
+	}
+}
+
+func tagOr(pg chess.PGNGame, name string) string {
+	if v := pg.Tags[name]; v != "" {
+		return v
+	}
+	return "?"
+}
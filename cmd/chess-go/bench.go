@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+
+	engine "chess_go/internal/engine"
+)
+
+// runBench implements "chess-go bench [depth]": it searches the fixed
+// engine.BenchPositions to a fixed depth, writing a line per position to
+// stderr and the total node count, the bench signature, and the speed to
+// stdout. It returns the exit code.
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: chess-go bench [depth]\n\nSearches %d fixed positions to depth (default %d).\n",
+			len(engine.BenchPositions), engine.DefaultBenchDepth)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	depth := engine.DefaultBenchDepth
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		d, err := strconv.Atoi(fs.Arg(0))
+		if err != nil || d < 1 {
+			fmt.Fprintf(os.Stderr, "bench: invalid depth %q\n", fs.Arg(0))
+			return 2
+		}
+		depth = d
+	default:
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	res, err := engine.Config{}.Bench(ctx, depth, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "bench:", err)
+		return 1
+	}
+	if res.Positions < len(engine.BenchPositions) {
+		fmt.Fprintf(os.Stderr, "bench: interrupted after %d positions\n", res.Positions)
+		return 1
+	}
+	fmt.Printf("Total time (ms) : %d\n", res.Elapsed.Milliseconds())
+	fmt.Printf("Nodes searched  : %d\n", res.Nodes)
+	fmt.Printf("Nodes/second    : %d\n", res.NPS())
+	return 0
+}
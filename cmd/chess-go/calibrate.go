@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// runCalibrate implements "chess-go calibrate": every Skill Level plays the
+// levels one and two above it in self-play, and a Bradley-Terry fit of all
+// results gives a rating table anchored at engine.MinElo. It returns the exit code.
+func runCalibrate(args []string) int {
+	fs := flag.NewFlagSet("calibrate", flag.ContinueOnError)
+	games := fs.Int("games", 20, "games per pair of levels (rounded up to even)")
+	from := fs.Int("from", 0, "lowest Skill Level")
+	to := fs.Int("to", engine.MaxSkill-1, "highest Skill Level")
+	seed := fs.Int64("seed", 1, "seed for the random opening plies")
+	plies := fs.Int("opening-plies", 4, "random plies played before each game pair")
+	minStep := fs.Int("min-step", 20, "smallest Elo step between adjacent levels")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go calibrate [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *from < 0 || *to >= engine.MaxSkill || *from >= *to {
+		fmt.Fprintf(os.Stderr, "calibrate: levels must satisfy 0 <= from < to < %d\n", engine.MaxSkill)
+		return 2
+	}
+
+	//nolint:gosec
+	rng := rand.New(rand.NewSource(*seed))
+	n := *to - *from + 1
+	var results []pairResult
+	for i := 0; i < n; i++ {
+		for gap := 1; gap <= 2 && i+gap < n; gap++ {
+			lo, hi := *from+i, *from+i+gap
+			r := pairResult{a: i, b: i + gap}
+			for g := 0; g < (*games+1)/2; g++ {
+				opening := randomOpening(rng, *plies)
+				r.score += playCalibrationGame(opening, hi, lo)
+				r.score += 1 - playCalibrationGame(opening, lo, hi)
+				r.games += 2
+			}
+			results = append(results, r)
+			fmt.Fprintf(os.Stderr, "calibrate: level %d vs %d: %.1f/%d\n", hi, lo, r.score, r.games)
+		}
+	}
+
+	ratings := fitRatings(n, results)
+	elo := make([]int, n)
+	for i := range elo {
+		elo[i] = engine.MinElo + int(math.Round(ratings[i]-ratings[0]))
+		if i > 0 {
+			elo[i] = max(elo[i], elo[i-1]+*minStep)
+		}
+	}
+	var sb strings.Builder
+	for i, e := range elo {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%d", e)
+	}
+	fmt.Println(sb.String())
+	return 0
+}
+
+// pairResult is the score of level b against level a (indices from the lowest level).
+type pairResult struct {
+	a, b  int
+	score float64
+	games int
+}
+
+// fitRatings returns Bradley-Terry ratings in Elo for n levels by the MM
+// algorithm, counting draws as half a win each. Every pair also gets one
+// virtual draw so that a clean sweep still has a finite rating.
+func fitRatings(n int, results []pairResult) []float64 {
+	wins := make([]float64, n)
+	for _, r := range results {
+		wins[r.b] += r.score + 0.5
+		wins[r.a] += float64(r.games) - r.score + 0.5
+	}
+	gamma := make([]float64, n)
+	for i := range gamma {
+		gamma[i] = 1
+	}
+	for iter := 0; iter < 1000; iter++ {
+		denom := make([]float64, n)
+		for _, r := range results {
+			g := float64(r.games+1) / (gamma[r.a] + gamma[r.b])
+			denom[r.a] += g
+			denom[r.b] += g
+		}
+		for i := range gamma {
+			gamma[i] = wins[i] / denom[i]
+		}
+	}
+	ratings := make([]float64, n)
+	for i, g := range gamma {
+		ratings[i] = 400 * math.Log10(g)
+	}
+	return ratings
+}
+
+// randomOpening plays n random legal plies from the start position.
+func randomOpening(rng *rand.Rand, n int) chess.Game {
+	g, _ := chess.NewGameFromFEN(chess.StartFEN)
+	for i := 0; i < n; i++ {
+		moves := g.LegalMoves()
+		if len(moves) == 0 || g.Result() != chess.InProgress {
+			break
+		}
+		g, _ = g.Apply(moves[rng.Intn(len(moves))])
+	}
+	return g
+}
+
+// playCalibrationGame plays g out between two skill levels and returns White's
+// score. Games still running after 300 plies are adjudicated drawn.
+func playCalibrationGame(g chess.Game, white, black int) float64 {
+	for ply := 0; ply < 300 && g.Result() == chess.InProgress; ply++ {
+		level := white
+		if g.State.ActiveColor == chess.Black {
+			level = black
+		}
+		res := engine.Search(g, engine.TimeControl{Strength: engine.SkillLevel(level)}, io.Discard)
+		next, err := g.Apply(res.BestMove)
+		if err != nil {
+			break
+		}
+		g = next
+	}
+	switch g.Result() {
+	case chess.WhiteWins:
+		return 1
+	case chess.BlackWins:
+		return 0
+	default:
+		return 0.5
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/suite"
+)
+
+// runEPD implements "chess-go epd": it searches every position of one or more
+// EPD suites, prints one line per position and a summary per suite, and can
+// write a JUnit XML report. It returns the exit code, which is 1 when a
+// position is unsolved or cannot be scored, so that CI fails on a tactical
+// regression. Positions named with -xfail are known to be unsolved and only
+// reported.
+func runEPD(args []string) int {
+	fs := flag.NewFlagSet("epd", flag.ContinueOnError)
+	depth := fs.Int("depth", 0, "search each position to this depth")
+	moveTime := fs.Duration("movetime", 0, "search each position for this long (default: the record's acd, else 1s)")
+	nodes := fs.Int64("nodes", 0, "search each position for this many nodes")
+	junit := fs.String("junit", "", "write a JUnit XML report to this file")
+	syzygy := fs.String("syzygy", "", "probe Syzygy tablebases from this directory")
+	xfail := fs.String("xfail", "", "comma-separated ids of positions expected to be unsolved")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go epd [flags] suite.epd...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var cfg engine.Config
+	if *syzygy != "" {
+		tb, err := chess.OpenSyzygy(*syzygy)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "epd:", err)
+			return 1
+		}
+		cfg.Tablebase, cfg.Syzygy50MoveRule = tb, true
+	}
+	opts := suite.Options{
+		Search:     cfg.Search,
+		Limit:      engine.TimeControl{Depth: *depth, MoveTime: *moveTime, Nodes: *nodes},
+		ExpectFail: strings.FieldsFunc(*xfail, func(r rune) bool { return r == ',' }),
+		Progress: func(r suite.Result) {
+			status := "ok"
+			switch {
+			case r.Err != nil:
+				status = "error: " + r.Err.Error()
+			case !r.Scored:
+				status = "-"
+			case !r.Solved && r.ExpectFail:
+				status = "xfail " + r.Expected()
+			case !r.Solved:
+				status = "FAIL " + r.Expected()
+			case r.ExpectFail:
+				status = "ok, expected to fail"
+			}
+			fmt.Printf("%4d %-24s %-8s depth %2d %8.3fs  %s\n",
+				r.Index, r.ID, r.SAN, r.Search.Depth, r.Search.Elapsed.Seconds(), status)
+		},
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var reports []suite.Report
+	failed := false
+	for _, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "epd:", err)
+			return 1
+		}
+		records, err := chess.ReadEPD(f)
+		_ = f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "epd: %s: %v\n", path, err)
+			return 1
+		}
+		rep := suite.Run(ctx, filepath.Base(path), records, opts)
+		reports = append(reports, rep)
+
+		solved, scored := rep.Solved()
+		fmt.Printf("%s: solved %d/%d", rep.Name, solved, scored)
+		if scored > 0 {
+			fmt.Printf(" (%.1f%%)", 100*float64(solved)/float64(scored))
+		}
+		if points, possible := rep.Points(); possible > 0 {
+			fmt.Printf(", points %d/%d", points, possible)
+		}
+		if n := len(opts.ExpectFail); n > 0 {
+			fmt.Printf(", %d expected to fail", n)
+		}
+		fmt.Printf(" in %.1fs\n", rep.Elapsed.Seconds())
+		for _, r := range rep.Results {
+			failed = failed || r.Regressed()
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if *junit != "" {
+		f, err := os.Create(*junit)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "epd:", err)
+			return 1
+		}
+		err = suite.WriteJUnit(f, reports)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "epd:", err)
+			return 1
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
@@ -1,20 +1,324 @@
 // Command chess-go is the TUI binary for the chess engine.
 // It wires the tui, engine, and chess packages and starts the game loop.
+//
+// Subcommands:
+//
+//	chess-go [option flags] [-engine CMD]   play against the engine in the terminal
+//	         [-color C] [-fen FEN | -pgn FILE] [-movetime T | -depth N] [-clock TC]
+//	         [-style S] [-coords] [-flip] [-fullscreen] [-accessible]
+//	chess-go -hotseat [-autoflip]   two players take turns at one terminal
+//	chess-go -analysis [-lines N] [-fen FEN | -pgn FILE]   analyse positions with the engine's best lines
+//	chess-go uci        speak UCI on stdin/stdout for chess GUIs; options as flags or -config
+//	chess-go xboard     speak CECP (XBoard/WinBoard protocol 2) on stdin/stdout
+//	chess-go makebook   build a Polyglot opening book from PGN games
+//	chess-go maketb     generate DTM endgame tables by retrograde analysis
+//	chess-go calibrate  measure the Elo of each Skill Level in self-play
+//	chess-go match      play two engines against each other, with Elo and SPRT reports
+//	chess-go watch      watch two engines play a series, move by move with eval and PV
+//	chess-go view       replay PGN games with comments and variations; play on from any move
+//	chess-go epd        run EPD test suites (bm/am/c0) with an optional JUnit report
+//	chess-go tune       fit the evaluation weights to game results (Texel tuning)
+//	chess-go analyze    annotate PGN games with evaluations, blunders and ACPL
+//	chess-go bench      search a fixed position set; prints the node signature and NPS
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	chess "chess_go/internal/chess"
+	"chess_go/internal/clock"
 	engine "chess_go/internal/engine"
 	"chess_go/internal/tui"
 )
 
 func main() {
-	game := tui.NewGame(os.Stdin, os.Stdout, func(g chess.Game, _ engine.TimeControl) chess.Move {
-		tc := engine.TimeControl{MoveTime: 100 * time.Millisecond}
-		return engine.Search(g, tc, os.Stderr).BestMove
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "uci":
+			os.Exit(runUCI(os.Args[2:]))
+		case "xboard":
+			os.Exit(runXBoard(os.Args[2:]))
+		case "makebook":
+			os.Exit(runMakeBook(os.Args[2:]))
+		case "maketb":
+			os.Exit(runMakeTB(os.Args[2:]))
+		case "calibrate":
+			os.Exit(runCalibrate(os.Args[2:]))
+		case "match":
+			os.Exit(runMatch(os.Args[2:]))
+		case "watch":
+			os.Exit(runWatch(os.Args[2:]))
+		case "view":
+			os.Exit(runView(os.Args[2:]))
+		case "epd":
+			os.Exit(runEPD(os.Args[2:]))
+		case "tune":
+			os.Exit(runTune(os.Args[2:]))
+		case "analyze":
+			os.Exit(runAnalyze(os.Args[2:]))
+		case "bench":
+			os.Exit(runBench(os.Args[2:]))
+		}
+	}
+	os.Exit(run(os.Args[1:]))
+}
+
+// run implements chess-go without a subcommand: a game or an analysis in the
+// terminal. Flags are checked before the engine starts, and the engine is
+// closed before run returns the exit code.
+func run(args []string) int {
+	fs := flag.NewFlagSet("chess-go", flag.ContinueOnError)
+	builtin := engine.NewBuiltin()
+	builtin.Options().RegisterFlags(fs)
+	aliases := registerAliases(fs, builtin.Options())
+	external := fs.String("engine", "", "play against the UCI engine started by this command line instead of the built-in one")
+	options := map[string]string{}
+	fs.Func("option", "`Name=Value` engine option (repeatable)", setOption(options))
+	config := fs.String("config", "", "read engine options from this file of \"Name = Value\" lines")
+	color := fs.String("color", "white", "your side: white, black or random")
+	fen := fs.String("fen", "", "start from this position")
+	pgn := fs.String("pgn", "", "continue the first game of this PGN file from its last position")
+	movetime := fs.Duration("movetime", 100*time.Millisecond, "engine thinking time per move without a clock")
+	depth := fs.Int("depth", 0, "engine search depth per move without a clock; overrides -movetime")
+	control := fs.String("clock", "", "clock time control `TC` such as 5+3 (minutes+increment), 15d5 (delay), 15b5 (Bronstein delay) or 40/90+30,30+30 (stages)")
+	style := fs.String("style", "auto", "board style: auto (by terminal, NO_COLOR and COLORTERM), ascii, unicode, 256 or truecolor")
+	coords := fs.Bool("coords", false, "show rank and file labels on all four sides of the board")
+	flip := fs.Bool("flip", false, "draw the board from the engine's side")
+	fullScreen := fs.Bool("fullscreen", false, "play full-screen: move with the arrow keys, with panels for the moves, captures, clocks and engine")
+	analysis := fs.Bool("analysis", false, "analyse positions instead of playing: the engine searches until you enter a move, undo or a FEN")
+	lines := fs.Int("lines", 3, "best lines shown with -analysis (the MultiPV option)")
+	hotSeat := fs.Bool("hotseat", false, "two players take turns at this terminal instead of playing the engine")
+	autoFlip := fs.Bool("autoflip", false, "with -hotseat, turn the board to the side to move after every move")
+	accessible := fs.Bool("accessible", false, "for screen readers: describe moves and positions in words instead of drawing the board")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	setup, err := gameSetup(*color, *fen, *pgn, *movetime, *depth, *control)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "chess-go:", err)
+		return 2
+	}
+	boardStyle, err := parseStyle(*style)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "chess-go:", err)
+		return 2
+	}
+	boardStyle.AllCoordinates, boardStyle.Flipped = *coords, *flip
+	if *accessible {
+		if err := accessibleOnly(fs); err != nil {
+			fmt.Fprintln(os.Stderr, "chess-go:", err)
+			return 2
+		}
+		boardStyle = tui.Style{Accessible: true}
+	}
+	terminal := isTerminal(os.Stdout) && !*accessible // no redrawing in place under a screen reader
+	setup.HotSeat, setup.AutoFlip = *hotSeat, *autoFlip
+
+	var eng engine.Engine = builtin
+	if f := strings.Fields(*external); len(f) > 0 {
+		e, err := engine.StartUCIEngine(f[0], f[1:]...)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "chess-go:", err)
+			return 1
+		}
+		eng = e
+	}
+	defer eng.Close()
+	if *config != "" {
+		settings, err := engine.LoadConfig(*config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "chess-go:", err)
+			return 1
+		}
+		for _, s := range settings {
+			if err := eng.SetOption(s.Name, s.Value); err != nil {
+				fmt.Fprintf(os.Stderr, "chess-go: %s: %v\n", eng.Name(), err)
+			}
+		}
+	}
+	if *analysis {
+		options["MultiPV"] = strconv.Itoa(max(*lines, 1))
+	}
+	// Option flags have set the built-in engine's options as they were
+	// parsed; they are set again to pass them to an external engine and to
+	// override -config.
+	flagged := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		flagged[f.Name] = true
+		for _, name := range aliases[f.Name] {
+			flagged[engine.FlagName(name)] = true
+		}
 	})
+	for _, opt := range builtin.Options().List() {
+		if flagged[engine.FlagName(opt.Name)] {
+			options[opt.Name] = builtin.Options().Value(opt.Name)
+		}
+	}
+	for name, value := range options {
+		if err := eng.SetOption(name, value); err != nil {
+			fmt.Fprintf(os.Stderr, "chess-go: %s: %v\n", eng.Name(), err)
+		}
+	}
+
+	search := func(g chess.Game, tc engine.TimeControl, info func(engine.SearchResult)) engine.SearchResult {
+		if err := eng.SetPosition(g, nil); err != nil {
+			fmt.Fprintln(os.Stderr, "chess-go:", err)
+			return engine.SearchResult{}
+		}
+		res, err := eng.Search(context.Background(), tc, info)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "chess-go:", err)
+		}
+		return res
+	}
+	if *analysis {
+		analyse := func(ctx context.Context, g chess.Game, info func(engine.SearchResult)) {
+			if err := eng.SetPosition(g, nil); err != nil {
+				fmt.Fprintln(os.Stderr, "chess-go:", err)
+				return
+			}
+			if _, err := eng.Search(ctx, engine.TimeControl{Infinite: true}, info); err != nil {
+				fmt.Fprintln(os.Stderr, "chess-go:", err)
+			}
+		}
+		tui.NewAnalysis(os.Stdin, os.Stdout, analyse, setup.Start).WithLines(*lines).
+			WithStyle(boardStyle).WithTerminal(terminal).Run()
+		return 0
+	}
+	game := tui.NewGameWithSetup(os.Stdin, os.Stdout, func(g chess.Game, tc engine.TimeControl) chess.Move {
+		return search(g, tc, nil).BestMove
+	}, setup).WithSearch(search).WithTerminal(terminal).WithStyle(boardStyle)
+	if menu(fs) {
+		if game, err = game.Menu(); err != nil {
+			fmt.Fprintln(os.Stderr, "chess-go: start menu:", err)
+			return 1
+		}
+	}
+	if *fullScreen {
+		term, err := tui.OpenTerminal(os.Stdin, os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "chess-go:", err)
+			return 2
+		}
+		game = game.WithFullScreen(term)
+	}
 	game.Run()
+	return 0
+}
+
+// registerAliases defines -skill and -elo, the flags chess-go had before the
+// option flags, on fs. It returns the options each of them sets.
+func registerAliases(fs *flag.FlagSet, opts *engine.Options) map[string][]string {
+	fs.Func("skill", "`N`: short for -skill-level N", func(v string) error {
+		_, err := opts.Set("Skill Level", v)
+		return err
+	})
+	fs.Func("elo", "`N`: short for -uci-limitstrength -uci-elo N", func(v string) error {
+		if _, err := opts.Set("UCI_Elo", v); err != nil {
+			return err
+		}
+		_, err := opts.Set("UCI_LimitStrength", "true")
+		return err
+	})
+	return map[string][]string{
+		"skill": {"Skill Level"},
+		"elo":   {"UCI_Elo", "UCI_LimitStrength"},
+	}
+}
+
+// accessibleOnly reports the flags set in fs that -accessible excludes: it
+// draws no board, so the board's style and full-screen play do not apply.
+func accessibleOnly(fs *flag.FlagSet) error {
+	var set []string
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "style", "coords", "flip", "fullscreen":
+			set = append(set, "-"+f.Name)
+		}
+	})
+	if len(set) > 0 {
+		return fmt.Errorf("-accessible draws no board and cannot be used with %s", strings.Join(set, ", "))
+	}
+	return nil
+}
+
+// gameSetup builds the TUI game setup from the flags.
+func gameSetup(color, fen, pgn string, movetime time.Duration, depth int, control string) (tui.Setup, error) {
+	s := tui.DefaultSetup()
+	var err error
+	if s.Human, err = tui.ParseSide(color); err != nil {
+		return s, err
+	}
+	switch {
+	case fen != "" && pgn != "":
+		return s, errors.New("-fen and -pgn are mutually exclusive")
+	case fen != "":
+		if s.Start, err = chess.NewGameFromFEN(fen); err != nil {
+			return s, err
+		}
+	case pgn != "":
+		if s.Start, err = tui.LoadPGN(pgn); err != nil {
+			return s, err
+		}
+	}
+	switch {
+	case depth < 0:
+		return s, fmt.Errorf("invalid -depth %d", depth)
+	case depth > 0:
+		s.Think = engine.TimeControl{Depth: depth}
+	case movetime <= 0:
+		return s, fmt.Errorf("invalid -movetime %v", movetime)
+	default:
+		s.Think = engine.TimeControl{MoveTime: movetime}
+	}
+	if control != "" {
+		if s.Clock, err = clock.Parse(control); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// parseStyle returns the board style named by the -style flag.
+func parseStyle(name string) (tui.Style, error) {
+	switch name {
+	case "auto":
+		return tui.DetectStyle(isTerminal(os.Stdout), os.Getenv), nil
+	case "ascii":
+		return tui.Style{}, nil
+	case "unicode":
+		return tui.Style{Unicode: true}, nil
+	case "256":
+		return tui.Style{Unicode: true, Color: tui.Color256}, nil
+	case "truecolor":
+		return tui.Style{Unicode: true, Color: tui.TrueColor}, nil
+	}
+	return tui.Style{}, fmt.Errorf("unknown -style %q, want auto, ascii, unicode, 256 or truecolor", name)
+}
+
+// menu reports whether to ask for the game setup: stdin is a terminal and no
+// setup flag was given.
+func menu(fs *flag.FlagSet) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "color", "fen", "pgn", "movetime", "depth", "clock", "hotseat":
+			set = true
+		}
+	})
+	return !set && isTerminal(os.Stdin)
+}
+
+// isTerminal reports whether f is a terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"chess_go/internal/book"
+)
+
+// runMakeBook implements "chess-go makebook": it reads one or more PGN files and
+// writes a Polyglot book. It returns the process exit code.
+func runMakeBook(args []string) int {
+	fs := flag.NewFlagSet("makebook", flag.ContinueOnError)
+	out := fs.String("out", "book.bin", "output Polyglot book file")
+	maxPly := fs.Int("depth", 16, "only record moves from the first N plies")
+	minGames := fs.Int("min-games", 3, "drop moves played in fewer than N games")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go makebook [flags] games.pgn...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	b := book.NewBuilder(book.BuildOptions{MaxPly: *maxPly, MinGames: *minGames})
+	for _, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "makebook:", err)
+			return 1
+		}
+		err = b.AddPGN(f)
+		_ = f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "makebook: %s: %v\n", path, err)
+			return 1
+		}
+	}
+
+	bk := b.Book()
+	if err := bk.WriteFile(*out); err != nil {
+		fmt.Fprintln(os.Stderr, "makebook:", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "makebook: %d games, %d entries written to %s\n", b.Games(), bk.Len(), *out)
+	return 0
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/tablebase"
+)
+
+// runMakeTB implements "chess-go maketb": it generates DTM tables for the
+// given material sets, together with the tables they depend on, and writes
+// them to a directory. It returns the process exit code.
+func runMakeTB(args []string) int {
+	fs := flag.NewFlagSet("maketb", flag.ContinueOnError)
+	out := fs.String("out", "tablebases", "output directory for .cgtb tables")
+	verify := fs.Bool("verify", false, "check every table against the forward move generator")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go maketb [flags] KQK KRK KPK KBNK...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	tb := tablebase.New()
+	for _, material := range fs.Args() {
+		start := time.Now()
+		t, err := tb.Generate(material)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "maketb:", err)
+			return 1
+		}
+		_, longest := t.Longest(chess.White)
+		fmt.Fprintf(os.Stderr, "maketb: %s generated in %v, longest %s\n",
+			t.Material(), time.Since(start).Round(time.Millisecond), longest)
+	}
+	if *verify {
+		for _, t := range tb.Tables() {
+			if err := tb.Verify(t.Material()); err != nil {
+				fmt.Fprintln(os.Stderr, "maketb:", err)
+				return 1
+			}
+		}
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, "maketb:", err)
+		return 1
+	}
+	if err := tb.WriteDir(*out); err != nil {
+		fmt.Fprintln(os.Stderr, "maketb:", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "maketb: %d tables written to %s\n", len(tb.Tables()), *out)
+	return 0
+}
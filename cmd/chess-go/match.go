@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/match"
+)
+
+// runMatch implements "chess-go match": engine A plays engine B, each either
+// the built-in search or an external UCI binary, and the result is reported
+// as an Elo difference and, with -sprt, a sequential test. It returns the exit
+// code: 0 when the match finished, 1 on errors.
+func runMatch(args []string) int {
+	fs := flag.NewFlagSet("match", flag.ContinueOnError)
+	specA := fs.String("a", "builtin", "engine A: builtin[:skill=N,elo=N,syzygy=DIR,weights=FILE,evalfile=FILE] or a UCI command line")
+	specB := fs.String("b", "builtin", "engine B, as for -a")
+	nameA := fs.String("name-a", "", "name of engine A in reports and PGN (default from -a)")
+	nameB := fs.String("name-b", "", "name of engine B (default from -b)")
+	optsA, optsB := map[string]string{}, map[string]string{}
+	fs.Func("option-a", "`Name=Value` UCI option for an external engine A (repeatable)", setOption(optsA))
+	fs.Func("option-b", "`Name=Value` UCI option for an external engine B (repeatable)", setOption(optsB))
+	games := fs.Int("games", 100, "games to play, in pairs with colours reversed")
+	concurrency := fs.Int("concurrency", 1, "games played at once; keep it at or below the number of CPUs")
+	tcFlag := fs.String("tc", "10+0.1", "clock as [moves/]seconds[+increment]; empty for none")
+	moveTime := fs.Duration("movetime", 0, "fixed time per move instead of a clock")
+	depth := fs.Int("depth", 0, "fixed depth per move instead of a clock")
+	nodes := fs.Int64("nodes", 0, "fixed nodes per move instead of a clock")
+	margin := fs.Duration("margin", 50*time.Millisecond, "clock overrun tolerated before a loss on time")
+	openings := fs.String("openings", "", "opening suite (.epd or .pgn); default the start position")
+	pgnOut := fs.String("pgnout", "", "write every game to this PGN file")
+	drawAfter := fs.Int("draw-after", 40, "earliest move number for draw adjudication")
+	drawMoves := fs.Int("draw-moves", 8, "consecutive moves within -draw-score to adjudicate a draw; 0 disables")
+	drawScore := fs.Int("draw-score", 10, "draw adjudication score bound in centipawns")
+	resignMoves := fs.Int("resign-moves", 3, "consecutive moves beyond -resign-score to adjudicate a loss; 0 disables")
+	resignScore := fs.Int("resign-score", 800, "resign adjudication score in centipawns")
+	maxPlies := fs.Int("max-plies", 400, "adjudicate games still running after N plies as drawn; 0 disables")
+	sprtFlag := fs.String("sprt", "", "run an SPRT: elo0,elo1[,alpha,beta], e.g. 0,5 (alpha = beta = 0.05)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go match [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *concurrency > runtime.NumCPU() {
+		fmt.Fprintf(os.Stderr, "match: warning: %d concurrent games on %d CPUs will distort clocked games\n",
+			*concurrency, runtime.NumCPU())
+	}
+
+	var tc match.TimeControl
+	if *tcFlag != "" && *moveTime == 0 && *depth == 0 && *nodes == 0 {
+		var err error
+		if tc, err = match.ParseTimeControl(*tcFlag); err != nil {
+			fmt.Fprintln(os.Stderr, "match:", err)
+			return 2
+		}
+	}
+	tc.MoveTime, tc.Depth, tc.Nodes, tc.Margin = *moveTime, *depth, *nodes, *margin
+
+	m := match.Match{
+		Event:       "chess-go match",
+		Games:       *games,
+		Concurrency: *concurrency,
+		TimeControl: tc,
+		Adjudication: match.Adjudication{
+			DrawMoveNumber: *drawAfter, DrawMoves: *drawMoves, DrawScore: *drawScore,
+			ResignMoves: *resignMoves, ResignScore: *resignScore, MaxPlies: *maxPlies,
+		},
+	}
+	var err error
+	if m.A, err = contender(*specA, *nameA, optsA); err == nil {
+		m.B, err = contender(*specB, *nameB, optsB)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "match:", err)
+		return 2
+	}
+	if m.A.Name == m.B.Name {
+		m.A.Name, m.B.Name = m.A.Name+" (A)", m.B.Name+" (B)"
+	}
+	if *sprtFlag != "" {
+		sprt, err := parseSPRT(*sprtFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "match:", err)
+			return 2
+		}
+		m.SPRT = &sprt
+	}
+	if *openings != "" {
+		if m.Openings, err = match.OpenOpenings(*openings); err != nil {
+			fmt.Fprintln(os.Stderr, "match:", err)
+			return 1
+		}
+	}
+	if *pgnOut != "" {
+		f, err := os.Create(*pgnOut)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "match:", err)
+			return 1
+		}
+		defer f.Close()
+		m.PGN = f
+	}
+
+	m.Progress = func(g match.Game, rep match.Report) {
+		diff, margin := rep.Score.Elo()
+		line := fmt.Sprintf("game %d: %s - %s %s {%s}  %s  elo %+.1f ± %.1f",
+			g.Round, g.White, g.Black, g.Result, g.Termination, rep.Score, diff, margin)
+		if m.SPRT != nil {
+			lower, upper := m.SPRT.Bounds()
+			line += fmt.Sprintf("  llr %.2f (%.2f, %.2f)", rep.LLR, lower, upper)
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	rep, err := m.Run(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "match:", err)
+		return 1
+	}
+	diff, errBar := rep.Score.Elo()
+	fmt.Printf("%s vs %s: %s in %d games (%.1f%%)\n", m.A.Name, m.B.Name, rep.Score,
+		rep.Score.Games(), 100*rep.Score.Points()/float64(max(rep.Score.Games(), 1)))
+	fmt.Printf("Elo difference: %+.1f ± %.1f (95%%)\n", diff, errBar)
+	if m.SPRT != nil {
+		lower, upper := m.SPRT.Bounds()
+		fmt.Printf("SPRT [%g, %g]: LLR %.2f (%.2f, %.2f): %s\n",
+			m.SPRT.Elo0, m.SPRT.Elo1, rep.LLR, lower, upper, rep.Decision)
+	}
+	return 0
+}
+
+// contender builds a match contender from a -a or -b spec: "builtin" with
+// optional comma-separated settings, or the command line of a UCI engine.
+func contender(spec, name string, options map[string]string) (match.Contender, error) {
+	kind, settings, _ := strings.Cut(spec, ":")
+	if kind != "builtin" {
+		f := strings.Fields(spec)
+		if len(f) == 0 {
+			return match.Contender{}, fmt.Errorf("empty engine spec")
+		}
+		if name == "" {
+			name = f[0]
+		}
+		return match.UCI(name, f[0], f[1:], options), nil
+	}
+
+	var cfg engine.Config
+	var strength engine.Strength
+	for _, kv := range strings.Split(settings, ",") {
+		if kv == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(kv, "=")
+		switch key {
+		case "skill", "elo":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return match.Contender{}, fmt.Errorf("%s: %q is not a number", key, value)
+			}
+			if key == "skill" {
+				strength = engine.SkillLevel(n)
+			} else {
+				strength = engine.EloStrength(n)
+			}
+		case "syzygy":
+			tb, err := chess.OpenSyzygy(value)
+			if err != nil {
+				return match.Contender{}, err
+			}
+			cfg.Tablebase, cfg.Syzygy50MoveRule = tb, true
+		case "weights":
+			w, err := engine.LoadWeights(value)
+			if err != nil {
+				return match.Contender{}, err
+			}
+			cfg.Weights = w
+		case "evalfile":
+			nn, err := engine.LoadNetwork(value)
+			if err != nil {
+				return match.Contender{}, err
+			}
+			cfg.Network = nn
+		default:
+			return match.Contender{}, fmt.Errorf("unknown builtin setting %q", key)
+		}
+	}
+	if name == "" {
+		name = spec
+	}
+	search := func(ctx context.Context, g chess.Game, tc engine.TimeControl, info io.Writer) engine.SearchResult {
+		tc.Strength = strength
+		return cfg.Search(ctx, g, tc, info)
+	}
+	return match.InProcess(name, search), nil
+}
+
+// setOption returns a flag.Func setter collecting Name=Value pairs into opts.
+func setOption(opts map[string]string) func(string) error {
+	return func(s string) error {
+		name, value, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("want Name=Value, got %q", s)
+		}
+		opts[name] = value
+		return nil
+	}
+}
+
+// parseSPRT parses "elo0,elo1[,alpha,beta]".
+func parseSPRT(s string) (match.SPRT, error) {
+	f := strings.Split(s, ",")
+	if len(f) != 2 && len(f) != 4 {
+		return match.SPRT{}, fmt.Errorf("sprt: want elo0,elo1[,alpha,beta], got %q", s)
+	}
+	v := []float64{0, 0, 0.05, 0.05}
+	for i, x := range f {
+		n, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil {
+			return match.SPRT{}, fmt.Errorf("sprt: %q is not a number", x)
+		}
+		v[i] = n
+	}
+	if v[0] >= v[1] || v[2] <= 0 || v[2] >= 1 || v[3] <= 0 || v[3] >= 1 {
+		return match.SPRT{}, fmt.Errorf("sprt: want elo0 < elo1 and 0 < alpha, beta < 1")
+	}
+	return match.SPRT{Elo0: v[0], Elo1: v[1], Alpha: v[2], Beta: v[3]}, nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tune"
+)
+
+// runTune implements "chess-go tune": it loads labelled positions from EPD
+// files and quiet positions from PGN games, fits the evaluation weights to
+// the game results and writes them as a JSON weight file and, optionally, as
+// Go source for internal/engine/weights.go. It returns the exit code.
+func runTune(args []string) int {
+	fs := flag.NewFlagSet("tune", flag.ContinueOnError)
+	out := fs.String("out", "weights.json", "write the tuned weights as JSON to this file")
+	goOut := fs.String("go", "", "also write the tuned weights as Go source to this file")
+	start := fs.String("weights", "", "start from this JSON weight file (default: the built-in weights)")
+	epochs := fs.Int("epochs", 500, "gradient descent steps")
+	rate := fs.Float64("rate", 1, "Adam step size in centipawns")
+	k := fs.Float64("k", 0, "sigmoid scale (default: fitted to the starting weights)")
+	threads := fs.Int("threads", runtime.NumCPU(), "goroutines computing the gradient")
+	skip := fs.Int("skip", 8, "opening plies skipped in PGN games")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go tune [flags] positions.epd|games.pgn...")
+		fmt.Fprintln(fs.Output(), "EPD records carry the game result in a c9 or result operation.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	weights := engine.DefaultWeights
+	if *start != "" {
+		w, err := engine.LoadWeights(*start)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tune:", err)
+			return 1
+		}
+		weights = *w
+	}
+
+	var data tune.Dataset
+	for _, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tune:", err)
+			return 1
+		}
+		var n int
+		if strings.EqualFold(filepath.Ext(path), ".epd") {
+			n, err = data.ReadEPD(f)
+		} else {
+			n, err = data.ReadPGN(f, tune.PGNOptions{SkipPlies: *skip})
+		}
+		_ = f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "tune: %s: %v\n", path, err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "tune: %s: %d positions\n", path, n)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	tuned, res, err := tune.Tune(ctx, &data, weights, tune.Options{
+		K: *k, Epochs: *epochs, Rate: *rate, Workers: *threads,
+		Progress: func(epoch int, mse float64) {
+			if epoch == 1 || epoch%50 == 0 {
+				fmt.Fprintf(os.Stderr, "tune: epoch %d: error %.6f\n", epoch, mse)
+			}
+		},
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tune:", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "tune: %d positions, K %.4f, error %.6f -> %.6f after %d epochs\n",
+		data.Len(), res.K, res.Start, res.Final, res.Epochs)
+
+	var buf bytes.Buffer
+	if err := tune.WriteJSON(&buf, tuned); err == nil {
+		err = os.WriteFile(*out, buf.Bytes(), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "tune:", err)
+		return 1
+	}
+	if *goOut != "" {
+		buf.Reset()
+		note := fmt.Sprintf("Tuned from %d positions (K %.4f, error %.6f).", data.Len(), res.K, res.Final)
+		if err := tune.WriteGo(&buf, tuned, note); err == nil {
+			err = os.WriteFile(*goOut, buf.Bytes(), 0o644)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "tune:", err)
+			return 1
+		}
+	}
+	return 0
+}
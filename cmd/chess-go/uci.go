@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	engine "chess_go/internal/engine"
+)
+
+// runUCI implements "chess-go uci": it speaks UCI on stdin and stdout. Every
+// UCI option is also a flag, and -config reads option settings from a file,
+// so that a GUI that cannot set options still gets them. It returns the exit
+// code.
+func runUCI(args []string) int {
+	h := engine.NewUCIHandler(nil)
+	fs := flag.NewFlagSet("uci", flag.ContinueOnError)
+	fs.Func("config", "read `file` of \"Name = Value\" option lines; later flags override it", func(path string) error {
+		settings, err := engine.LoadConfig(path)
+		if err != nil {
+			return err
+		}
+		return h.Options().Apply(settings)
+	})
+	h.Options().RegisterFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go uci [-config file] [option flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	h.Run(os.Stdin, os.Stdout)
+	return 0
+}
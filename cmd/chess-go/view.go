@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tui"
+)
+
+// runView implements "chess-go view": it replays the games of a PGN file move
+// by move, with their tags, comments, NAGs and variations, choosing among
+// several games from a searchable list, and lets the player play on against
+// the engine from any position. It returns the exit code.
+func runView(args []string) int {
+	fs := flag.NewFlagSet("view", flag.ContinueOnError)
+	styleName := fs.String("style", "auto", "board style: auto, ascii, unicode, 256 or truecolor")
+	flip := fs.Bool("flip", false, "draw the board from Black's side")
+	accessible := fs.Bool("accessible", false, "for screen readers: describe moves and positions in words instead of drawing the board")
+	skill := fs.Int("skill", engine.MaxSkill, "Skill Level of the engine played with the play command, 0 to 20")
+	moveTime := fs.Duration("movetime", 100*time.Millisecond, "engine thinking time per move with the play command")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go view [flags] games.pgn")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *moveTime <= 0 {
+		fmt.Fprintf(os.Stderr, "view: invalid -movetime %v\n", *moveTime)
+		return 2
+	}
+	style, err := parseStyle(*styleName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "view:", err)
+		return 2
+	}
+	style.Flipped = *flip
+	if *accessible {
+		style = tui.Style{Accessible: true}
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "view:", err)
+		return 1
+	}
+	games, err := chess.ReadPGN(f)
+	_ = f.Close()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "view:", err)
+		return 1
+	}
+
+	eng := engine.NewBuiltin()
+	defer eng.Close()
+	if err := eng.SetOption("Skill Level", strconv.Itoa(*skill)); err != nil {
+		fmt.Fprintln(os.Stderr, "view:", err)
+		return 2
+	}
+	play := func(g chess.Game, tc engine.TimeControl) chess.Move {
+		if err := eng.SetPosition(g, nil); err != nil {
+			fmt.Fprintln(os.Stderr, "view:", err)
+			return chess.Move{}
+		}
+		res, err := eng.Search(context.Background(), tc, nil)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "view:", err)
+		}
+		return res.BestMove
+	}
+	tui.NewViewer(os.Stdin, os.Stdout, games).WithStyle(style).
+		WithEngine(play, engine.TimeControl{MoveTime: *moveTime}).Run()
+	return 0
+}
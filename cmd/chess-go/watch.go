@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/match"
+	"chess_go/internal/tui"
+)
+
+// defaultWatchMoveTime is the time per move when watch is given no clock,
+// depth or node limit.
+const defaultWatchMoveTime = 500 * time.Millisecond
+
+// runWatch implements "chess-go watch": engine A plays engine B one game at a
+// time, colours alternating, with the board drawn after every move alongside
+// an eval bar and the principal variation, and the running score after every
+// game. It returns the exit code: 0 when the series finished, 1 on errors.
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	specA := fs.String("a", "builtin", "engine A: builtin[:skill=N,elo=N,syzygy=DIR,weights=FILE,evalfile=FILE] or a UCI command line")
+	specB := fs.String("b", "builtin", "engine B, as for -a")
+	nameA := fs.String("name-a", "", "name of engine A on screen and in PGN (default from -a)")
+	nameB := fs.String("name-b", "", "name of engine B (default from -b)")
+	optsA, optsB := map[string]string{}, map[string]string{}
+	fs.Func("option-a", "`Name=Value` UCI option for an external engine A (repeatable)", setOption(optsA))
+	fs.Func("option-b", "`Name=Value` UCI option for an external engine B (repeatable)", setOption(optsB))
+	games := fs.Int("games", 2, "games to play, in pairs with colours reversed")
+	tcFlag := fs.String("tc", "", "clock as [moves/]seconds[+increment]; empty for none")
+	moveTime := fs.Duration("movetime", 0, "fixed time per move (default 500ms without -tc, -depth or -nodes)")
+	depth := fs.Int("depth", 0, "fixed depth per move")
+	nodes := fs.Int64("nodes", 0, "fixed nodes per move")
+	delay := fs.Duration("delay", time.Second, "pause after each move")
+	openings := fs.String("openings", "", "opening suite (.epd or .pgn); default the start position")
+	pgnOut := fs.String("pgnout", "", "write every game to this PGN file")
+	maxPlies := fs.Int("max-plies", 400, "adjudicate games still running after N plies as drawn; 0 disables")
+	styleName := fs.String("style", "auto", "board style: auto, ascii, unicode, 256 or truecolor")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go watch [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var tc match.TimeControl
+	if *tcFlag != "" {
+		var err error
+		if tc, err = match.ParseTimeControl(*tcFlag); err != nil {
+			fmt.Fprintln(os.Stderr, "watch:", err)
+			return 2
+		}
+	} else if *moveTime == 0 && *depth == 0 && *nodes == 0 {
+		*moveTime = defaultWatchMoveTime
+	}
+	tc.MoveTime, tc.Depth, tc.Nodes, tc.Margin = *moveTime, *depth, *nodes, 50*time.Millisecond
+	style, err := parseStyle(*styleName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "watch:", err)
+		return 2
+	}
+
+	m := match.Match{
+		Event:        "chess-go watch",
+		Games:        *games,
+		TimeControl:  tc,
+		Adjudication: match.Adjudication{MaxPlies: *maxPlies},
+	}
+	if m.A, err = contender(*specA, *nameA, optsA); err == nil {
+		m.B, err = contender(*specB, *nameB, optsB)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "watch:", err)
+		return 2
+	}
+	if m.A.Name == m.B.Name {
+		m.A.Name, m.B.Name = m.A.Name+" (A)", m.B.Name+" (B)"
+	}
+	if *openings != "" {
+		if m.Openings, err = match.OpenOpenings(*openings); err != nil {
+			fmt.Fprintln(os.Stderr, "watch:", err)
+			return 1
+		}
+	}
+	if *pgnOut != "" {
+		f, err := os.Create(*pgnOut)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "watch:", err)
+			return 1
+		}
+		defer f.Close()
+		m.PGN = f
+	}
+
+	spectator := tui.NewSpectator(os.Stdout, *delay).WithStyle(style).WithTerminal(isTerminal(os.Stdout))
+	total := *games + *games%2 // as Match rounds it
+	// Moves arrive from the game's worker and results from the match, so the
+	// score between them is guarded.
+	var mu sync.Mutex
+	var score match.Score
+	standing := func() string {
+		return fmt.Sprintf("%s %g - %g %s", m.A.Name, score.Points(), float64(score.Games())-score.Points(), m.B.Name)
+	}
+	m.Move = func(round int, pos match.Position, res engine.SearchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		white, black := m.A.Name, m.B.Name
+		if round%2 == 0 {
+			white, black = black, white
+		}
+		title := fmt.Sprintf("Game %d of %d: %s (White) vs %s (Black)   %s", round, total, white, black, standing())
+		spectator.Show(title, positionBefore(pos), pos.Moves[len(pos.Moves)-1], res)
+	}
+	m.Progress = func(g match.Game, rep match.Report) {
+		mu.Lock()
+		defer mu.Unlock()
+		score = rep.Score
+		spectator.Announce(fmt.Sprintf("Game %d: %s - %s %s {%s}   %s (%s)",
+			g.Round, g.White, g.Black, g.Result, g.Termination, standing(), rep.Score))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	rep, err := m.Run(ctx)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "watch:", err)
+		return 1
+	}
+	fmt.Printf("%s vs %s: %s in %d games\n", m.A.Name, m.B.Name, rep.Score, rep.Score.Games())
+	return 0
+}
+
+// positionBefore returns the position before the last move of pos.
+func positionBefore(pos match.Position) chess.Game {
+	g := pos.Start
+	for _, mv := range pos.Moves[:len(pos.Moves)-1] {
+		g, _ = g.Apply(mv)
+	}
+	return g
+}
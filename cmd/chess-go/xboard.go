@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	engine "chess_go/internal/engine"
+)
+
+// runXBoard implements "chess-go xboard": it speaks CECP on stdin and stdout
+// until quit or the end of input. It takes no flags, since an XBoard GUI
+// sets everything over the protocol, and returns the exit code.
+func runXBoard(args []string) int {
+	fs := flag.NewFlagSet("xboard", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: chess-go xboard")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return 2
+	}
+	engine.NewXBoardHandler(nil).Run(os.Stdin, os.Stdout)
+	return 0
+}
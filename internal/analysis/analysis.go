@@ -0,0 +1,308 @@
+// Package analysis reviews played games with the engine.
+//
+// Every position of a game is searched. The drop in evaluation caused by each
+// move, from the mover's point of view, classifies it as best, good, an
+// inaccuracy, a mistake or a blunder, and the average centipawn loss (ACPL)
+// of each side summarises the game. The result can be written as a PGN game
+// annotated with [%eval] comments, move suffixes and the better lines.
+package analysis
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// Class grades a move by the evaluation it gives away.
+type Class int
+
+const (
+	Best       Class = iota // the engine's choice, or no loss at all
+	Good                    // a loss below the inaccuracy threshold
+	Inaccuracy              // "?!"
+	Mistake                 // "?"
+	Blunder                 // "??"
+)
+
+var classNames = [...]string{"best", "good", "inaccuracy", "mistake", "blunder"}
+
+func (c Class) String() string { return classNames[c] }
+
+// Suffix returns the move suffix annotation of the class, e.g. "??".
+func (c Class) Suffix() string {
+	switch c {
+	case Inaccuracy:
+		return "?!"
+	case Mistake:
+		return "?"
+	case Blunder:
+		return "??"
+	}
+	return ""
+}
+
+// evalCap bounds evaluations when losses are measured, so that a mate score
+// counts as a decisive advantage rather than an enormous number.
+const evalCap = 1000
+
+// Options configures an analysis.
+type Options struct {
+	// Search evaluates positions; nil selects engine.SearchContext.
+	Search engine.SearchFunc
+	// Limit is the search limit for each position; the zero value uses the
+	// engine's default move time.
+	Limit engine.TimeControl
+	// Inaccuracy, Mistake and Blunder are the centipawn losses from which
+	// moves are so classified; zero selects 50, 100 and 300.
+	Inaccuracy, Mistake, Blunder int
+	// VariationPlies bounds the length of better lines; 0 selects 8.
+	VariationPlies int
+	// Progress, when set, is called after each position is searched.
+	Progress func(done, total int)
+}
+
+// Move is the verdict on one move of the game.
+type Move struct {
+	Ply    int // 0 for the first move
+	Move   chess.Move
+	SAN    string
+	Color  chess.Color
+	Before int // evaluation before the move, centipawns from White's view (see engine.MateScore)
+	After  int // evaluation after the move, likewise
+	Loss   int // centipawns given away by the mover, at most 1000
+	Class  Class
+	Best   chess.Move   // the engine's choice in the position before the move
+	PV     []chess.Move // the engine's line from the position before the move
+}
+
+// Report is the analysis of one game.
+type Report struct {
+	Game  chess.PGNGame
+	Moves []Move
+	Final int // evaluation of the final position from White's view
+}
+
+// AnalyzeGame analyses the moves played in g.
+func AnalyzeGame(ctx context.Context, g chess.Game, opts Options) (Report, error) {
+	states := append(g.History(), g.State)
+	start, err := chess.NewGameFromFEN(gameFEN(states[0]))
+	if err != nil {
+		return Report{}, err
+	}
+	pg := chess.PGNGame{Tags: map[string]string{}, Start: start, Result: resultOf(g)}
+	for i := 1; i < len(states); i++ {
+		m, ok := moveBetween(states[i-1], states[i])
+		if !ok {
+			return Report{}, fmt.Errorf("analysis: no legal move leads from ply %d to ply %d", i-1, i)
+		}
+		pg.Moves = append(pg.Moves, m)
+	}
+	return Analyze(ctx, pg, opts)
+}
+
+// Analyze searches every position of pg and grades every move. When ctx is
+// cancelled it returns ctx.Err() and the moves graded so far.
+func Analyze(ctx context.Context, pg chess.PGNGame, opts Options) (Report, error) {
+	search := opts.Search
+	if search == nil {
+		search = engine.SearchContext
+	}
+	opts.Inaccuracy = orDefault(opts.Inaccuracy, 50)
+	opts.Mistake = orDefault(opts.Mistake, 100)
+	opts.Blunder = orDefault(opts.Blunder, 300)
+	opts.VariationPlies = orDefault(opts.VariationPlies, 8)
+
+	// Search every position from the start to the final one.
+	games := []chess.Game{pg.Start}
+	for _, m := range pg.Moves {
+		next, err := games[len(games)-1].Apply(m)
+		if err != nil {
+			return Report{Game: pg}, fmt.Errorf("analysis: move %d: %w", len(games), err)
+		}
+		games = append(games, next)
+	}
+	results := make([]engine.SearchResult, len(games))
+	scores := make([]int, len(games)) // from White's view
+	for i, g := range games {
+		results[i], scores[i] = evaluate(ctx, search, g, opts.Limit)
+		if err := ctx.Err(); err != nil {
+			// The interrupted search is incomplete; grade the moves before it.
+			return Report{Game: pg, Moves: grade(pg, games[:i], results, scores, opts)}, err
+		}
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(games))
+		}
+	}
+	return Report{Game: pg, Moves: grade(pg, games, results, scores, opts), Final: scores[len(scores)-1]}, nil
+}
+
+// evaluate searches g and returns the result and its score from White's view.
+// Positions without legal moves are scored as mate or stalemate.
+func evaluate(ctx context.Context, search engine.SearchFunc, g chess.Game, tc engine.TimeControl) (engine.SearchResult, int) {
+	var res engine.SearchResult
+	if len(g.LegalMoves()) == 0 {
+		if g.InCheck() {
+			res.Score = -engine.MateScore
+		}
+	} else {
+		res = search(ctx, g, tc, io.Discard)
+	}
+	if g.State.ActiveColor == chess.Black {
+		return res, -res.Score
+	}
+	return res, res.Score
+}
+
+// grade classifies the moves between the searched positions.
+func grade(pg chess.PGNGame, games []chess.Game, results []engine.SearchResult, scores []int, opts Options) []Move {
+	var moves []Move
+	for i := 0; i+1 < len(games); i++ {
+		g, m := games[i], pg.Moves[i]
+		mv := Move{
+			Ply: i, Move: m, SAN: m.SANString(g), Color: g.State.ActiveColor,
+			Before: scores[i], After: scores[i+1],
+			Best: results[i].BestMove, PV: results[i].PV,
+		}
+		before, after := capped(mv.Before), capped(mv.After)
+		if mv.Color == chess.Black {
+			before, after = -before, -after
+		}
+		mv.Loss = max(0, before-after)
+		switch {
+		case m == mv.Best || mv.Loss == 0:
+			mv.Class, mv.Loss = Best, 0
+		case mv.Loss >= opts.Blunder:
+			mv.Class = Blunder
+		case mv.Loss >= opts.Mistake:
+			mv.Class = Mistake
+		case mv.Loss >= opts.Inaccuracy:
+			mv.Class = Inaccuracy
+		default:
+			mv.Class = Good
+		}
+		if len(mv.PV) > opts.VariationPlies {
+			mv.PV = mv.PV[:opts.VariationPlies]
+		}
+		moves = append(moves, mv)
+	}
+	return moves
+}
+
+// ACPL returns the average centipawn loss of the moves played by c.
+func (r Report) ACPL(c chess.Color) float64 {
+	total, n := 0, 0
+	for _, m := range r.Moves {
+		if m.Color == c {
+			total += m.Loss
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(total) / float64(n)
+}
+
+// Count returns the number of moves of class played by c.
+func (r Report) Count(c chess.Color, class Class) int {
+	n := 0
+	for _, m := range r.Moves {
+		if m.Color == c && m.Class == class {
+			n++
+		}
+	}
+	return n
+}
+
+// Annotated returns the game with every move commented with its evaluation
+// in [%eval] form, inaccuracies, mistakes and blunders marked with "?!", "?"
+// and "??", and the engine's line given as a variation to each of them.
+func (r Report) Annotated() chess.PGNGame {
+	pg := r.Game
+	pg.Tags = make(map[string]string, len(r.Game.Tags)+3)
+	for k, v := range r.Game.Tags {
+		pg.Tags[k] = v
+	}
+	pg.Tags["Annotator"] = "chess-go"
+	pg.Tags["WhiteACPL"] = strconv.Itoa(int(r.ACPL(chess.White) + 0.5))
+	pg.Tags["BlackACPL"] = strconv.Itoa(int(r.ACPL(chess.Black) + 0.5))
+	pg.Annotations = make([]chess.PGNAnnotation, len(r.Moves))
+	s := r.Game.Start.State // the position before move i
+	for i, m := range r.Moves {
+		a := chess.PGNAnnotation{Suffix: m.Class.Suffix()}
+		if abs(m.After) != engine.MateScore { // no evaluation once mate is on the board
+			a.Comment = "[%eval " + FormatEval(m.After) + "]"
+		}
+		if m.Class >= Inaccuracy {
+			name := strings.ToUpper(m.Class.String()[:1]) + m.Class.String()[1:]
+			a.Comment += fmt.Sprintf(" %s (%s -> %s). %s was best.",
+				name, FormatEval(m.Before), FormatEval(m.After), m.Best.SANString(chess.Game{State: s}))
+			if len(m.PV) > 0 {
+				a.Variations = [][]chess.Move{m.PV}
+			}
+		}
+		pg.Annotations[i] = a
+		s = s.Play(r.Game.Moves[i])
+	}
+	return pg
+}
+
+// FormatEval renders a score from White's view as in [%eval] comments:
+// pawns with two decimals, or "#n" / "#-n" for a mate in n moves.
+func FormatEval(score int) string {
+	if s := engine.FormatScore(score); strings.HasPrefix(s, "mate ") {
+		return "#" + strings.TrimPrefix(s, "mate ")
+	}
+	return strconv.FormatFloat(float64(score)/100, 'f', 2, 64)
+}
+
+// moveBetween finds the legal move leading from a to b.
+func moveBetween(a, b chess.GameState) (chess.Move, bool) {
+	for _, m := range a.LegalMoves() {
+		if a.Play(m) == b {
+			return m, true
+		}
+	}
+	return chess.Move{}, false
+}
+
+// gameFEN returns the FEN of a position held only as a GameState.
+func gameFEN(s chess.GameState) string {
+	return chess.Game{State: s}.ToFEN()
+}
+
+// resultOf returns the PGN result marker of g.
+func resultOf(g chess.Game) string {
+	switch g.Result() {
+	case chess.WhiteWins:
+		return "1-0"
+	case chess.BlackWins:
+		return "0-1"
+	case chess.InProgress:
+		return "*"
+	}
+	return "1/2-1/2"
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func capped(score int) int {
+	return min(max(score, -evalCap), evalCap)
+}
+
+func orDefault(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,257 @@
+// Package book reads, writes and builds Polyglot opening books (.bin).
+//
+// A Polyglot book is a flat array of 16-byte big-endian entries sorted by position
+// key: key (8 bytes), move (2), weight (2), learn (4). Keys are computed with Key.
+package book
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+
+	chess "chess_go/internal/chess"
+)
+
+// ErrInvalidBook is returned when a book file is truncated or not sorted by key.
+var ErrInvalidBook = errors.New("invalid polyglot book")
+
+// entrySize is the on-disk size of one Polyglot entry in bytes.
+const entrySize = 16
+
+// Entry is a single raw Polyglot book record.
+type Entry struct {
+	Key    uint64
+	Move   uint16 // Polyglot-encoded move; see EncodeMove
+	Weight uint16
+	Learn  uint32
+}
+
+// Book is an in-memory Polyglot book with entries sorted by key.
+type Book struct {
+	entries []Entry
+}
+
+// Selection chooses how Pick selects among the book moves of a position.
+type Selection uint8
+
+const (
+	// WeightedRandom picks a move with probability proportional to its weight.
+	WeightedRandom Selection = 0
+	// BestMove always picks the highest-weighted move.
+	BestMove Selection = 1
+)
+
+// BookMove is a legal move found in the book together with its weight.
+type BookMove struct {
+	Move   chess.Move
+	Weight uint16
+}
+
+// Open reads a Polyglot book from the file at path.
+func Open(path string) (*Book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read parses a Polyglot book from r.
+// Returns ErrInvalidBook if the data is not a whole number of sorted entries.
+func Read(r io.Reader) (*Book, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%entrySize != 0 {
+		return nil, fmt.Errorf("%w: size %d is not a multiple of %d", ErrInvalidBook, len(data), entrySize)
+	}
+	entries := make([]Entry, len(data)/entrySize)
+	for i := range entries {
+		b := data[i*entrySize:]
+		entries[i] = Entry{
+			Key:    binary.BigEndian.Uint64(b[0:8]),
+			Move:   binary.BigEndian.Uint16(b[8:10]),
+			Weight: binary.BigEndian.Uint16(b[10:12]),
+			Learn:  binary.BigEndian.Uint32(b[12:16]),
+		}
+		if i > 0 && entries[i].Key < entries[i-1].Key {
+			return nil, fmt.Errorf("%w: entry %d out of order", ErrInvalidBook, i)
+		}
+	}
+	return &Book{entries: entries}, nil
+}
+
+// New returns a book holding the given entries, sorted into Polyglot order.
+func New(entries []Entry) *Book {
+	sorted := append([]Entry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Key != sorted[j].Key {
+			return sorted[i].Key < sorted[j].Key
+		}
+		if sorted[i].Weight != sorted[j].Weight {
+			return sorted[i].Weight > sorted[j].Weight
+		}
+		return sorted[i].Move < sorted[j].Move
+	})
+	return &Book{entries: sorted}
+}
+
+// Len returns the number of entries in the book.
+func (b *Book) Len() int { return len(b.entries) }
+
+// Write serialises the book to w in Polyglot format.
+func (b *Book) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	var buf [entrySize]byte
+	for _, e := range b.entries {
+		binary.BigEndian.PutUint64(buf[0:8], e.Key)
+		binary.BigEndian.PutUint16(buf[8:10], e.Move)
+		binary.BigEndian.PutUint16(buf[10:12], e.Weight)
+		binary.BigEndian.PutUint32(buf[12:16], e.Learn)
+		if _, err := bw.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteFile writes the book to the file at path, replacing any existing file.
+func (b *Book) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := b.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries returns the raw entries stored for position s, highest weight first.
+func (b *Book) Entries(s chess.GameState) []Entry {
+	key := Key(s)
+	lo := sort.Search(len(b.entries), func(i int) bool { return b.entries[i].Key >= key })
+	hi := lo
+	for hi < len(b.entries) && b.entries[hi].Key == key {
+		hi++
+	}
+	out := append([]Entry(nil), b.entries[lo:hi]...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Weight > out[j].Weight })
+	return out
+}
+
+// Moves returns the legal book moves for the current position of g, highest weight first.
+// Entries whose move is not legal in g (key collisions, corrupt books) are skipped.
+func (b *Book) Moves(g chess.Game) []BookMove {
+	legal := g.LegalMoves()
+	var out []BookMove
+	for _, e := range b.Entries(g.State) {
+		m := DecodeMove(g.State, e.Move)
+		for _, lm := range legal {
+			if lm == m {
+				out = append(out, BookMove{Move: m, Weight: e.Weight})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Pick selects a book move for g using sel. It returns false when the position is
+// not in the book. rng may be nil for BestMove selection.
+func (b *Book) Pick(g chess.Game, sel Selection, rng *rand.Rand) (chess.Move, bool) {
+	moves := b.Moves(g)
+	if len(moves) == 0 {
+		return chess.Move{}, false
+	}
+	if sel == BestMove || rng == nil {
+		return moves[0].Move, true
+	}
+
+	total := 0
+	for _, bm := range moves {
+		total += int(bm.Weight)
+	}
+	if total == 0 {
+		return moves[rng.Intn(len(moves))].Move, true
+	}
+	n := rng.Intn(total)
+	for _, bm := range moves {
+		n -= int(bm.Weight)
+		if n < 0 {
+			return bm.Move, true
+		}
+	}
+	return moves[0].Move, true
+}
+
+// Promotion pieces indexed by Polyglot promotion code - 1 (bits 12-14 of a move).
+var (
+	whitePromotions = [4]chess.Piece{chess.WhiteKnight, chess.WhiteBishop, chess.WhiteRook, chess.WhiteQueen}
+	blackPromotions = [4]chess.Piece{chess.BlackKnight, chess.BlackBishop, chess.BlackRook, chess.BlackQueen}
+)
+
+// EncodeMove converts a move played in position s to its Polyglot encoding.
+// Castling is encoded as the king capturing its own rook (e1h1, e1a1, e8h8, e8a8).
+func EncodeMove(s chess.GameState, m chess.Move) uint16 {
+	to := m.To
+	p := s.Board[m.From]
+	if (p == chess.WhiteKing || p == chess.BlackKing) && m.IsCastle() {
+		if m.To.File() == 6 {
+			to = chess.SquareOf(7, m.To.Rank())
+		} else {
+			to = chess.SquareOf(0, m.To.Rank())
+		}
+	}
+
+	var promo uint16
+	switch m.Promotion {
+	case chess.WhiteKnight, chess.BlackKnight:
+		promo = 1
+	case chess.WhiteBishop, chess.BlackBishop:
+		promo = 2
+	case chess.WhiteRook, chess.BlackRook:
+		promo = 3
+	case chess.WhiteQueen, chess.BlackQueen:
+		promo = 4
+	}
+	return uint16(to.File()) | uint16(to.Rank())<<3 |
+		uint16(m.From.File())<<6 | uint16(m.From.Rank())<<9 | promo<<12
+}
+
+// DecodeMove converts a Polyglot-encoded move to a chess.Move in position s.
+// The result is not checked for legality.
+func DecodeMove(s chess.GameState, pm uint16) chess.Move {
+	to := chess.SquareOf(int(pm&7), int(pm>>3&7))
+	from := chess.SquareOf(int(pm>>6&7), int(pm>>9&7))
+	m := chess.Move{From: from, To: to}
+
+	if code := int(pm >> 12 & 7); code >= 1 && code <= 4 {
+		if s.ActiveColor == chess.White {
+			m.Promotion = whitePromotions[code-1]
+		} else {
+			m.Promotion = blackPromotions[code-1]
+		}
+	}
+
+	// King "captures" own rook: translate to the two-square king move.
+	switch {
+	case s.Board[from] == chess.WhiteKing && from == chess.E1 && to == chess.H1:
+		m.To = chess.G1
+	case s.Board[from] == chess.WhiteKing && from == chess.E1 && to == chess.A1:
+		m.To = chess.C1
+	case s.Board[from] == chess.BlackKing && from == chess.E8 && to == chess.H8:
+		m.To = chess.G8
+	case s.Board[from] == chess.BlackKing && from == chess.E8 && to == chess.A8:
+		m.To = chess.C8
+	}
+	return m
+}
@@ -0,0 +1,122 @@
+package book
+
+import (
+	"io"
+
+	chess "chess_go/internal/chess"
+)
+
+// BuildOptions controls which positions and moves make it into a built book.
+type BuildOptions struct {
+	MaxPly   int // only record moves played before this ply (0 = no limit)
+	MinGames int // drop moves played in fewer than this many games
+}
+
+// moveStats accumulates results for one (position, move) pair, from the
+// point of view of the side that played the move.
+type moveStats struct {
+	games, wins, draws int
+}
+
+// bookKey identifies a (position, encoded move) pair during building.
+type bookKey struct {
+	key  uint64
+	move uint16
+}
+
+// Builder accumulates games and produces a Polyglot book from them.
+type Builder struct {
+	opts  BuildOptions
+	stats map[bookKey]*moveStats
+	games int
+}
+
+// NewBuilder returns an empty builder with the given options.
+func NewBuilder(opts BuildOptions) *Builder {
+	return &Builder{opts: opts, stats: make(map[bookKey]*moveStats)}
+}
+
+// Games returns the number of games added so far.
+func (b *Builder) Games() int { return b.games }
+
+// AddGame records the opening moves of one game.
+// Games without a decisive or drawn result ("*") are ignored.
+func (b *Builder) AddGame(pg chess.PGNGame) {
+	var winner chess.Color
+	draw := false
+	switch pg.Result {
+	case "1-0":
+		winner = chess.White
+	case "0-1":
+		winner = chess.Black
+	case "1/2-1/2":
+		draw = true
+	default:
+		return
+	}
+	b.games++
+
+	g := pg.Start
+	for ply, m := range pg.Moves {
+		if b.opts.MaxPly > 0 && ply >= b.opts.MaxPly {
+			break
+		}
+		k := bookKey{key: Key(g.State), move: EncodeMove(g.State, m)}
+		st := b.stats[k]
+		if st == nil {
+			st = &moveStats{}
+			b.stats[k] = st
+		}
+		st.games++
+		switch {
+		case draw:
+			st.draws++
+		case g.State.ActiveColor == winner:
+			st.wins++
+		}
+
+		next, err := g.Apply(m)
+		if err != nil {
+			break
+		}
+		g = next
+	}
+}
+
+// AddPGN reads every game from r and adds it to the builder.
+func (b *Builder) AddPGN(r io.Reader) error {
+	sc := chess.NewPGNScanner(r)
+	for sc.Scan() {
+		b.AddGame(sc.Game())
+	}
+	return sc.Err()
+}
+
+// Book returns the book built from the games added so far.
+// Each move is weighted 2 per win and 1 per draw (Polyglot's make-book scoring);
+// moves below MinGames or with a zero score are dropped, and weights are scaled
+// down if needed so that the largest fits in 16 bits.
+func (b *Builder) Book() *Book {
+	maxScore := 0
+	for _, st := range b.stats {
+		if s := 2*st.wins + st.draws; s > maxScore {
+			maxScore = s
+		}
+	}
+
+	entries := make([]Entry, 0, len(b.stats))
+	for k, st := range b.stats {
+		if st.games < b.opts.MinGames {
+			continue
+		}
+		score := 2*st.wins + st.draws
+		if maxScore > 0xFFFF {
+			score = score * 0xFFFF / maxScore
+		}
+		if score == 0 {
+			continue
+		}
+		entries = append(entries, Entry{Key: k.key, Move: k.move, Weight: uint16(score)})
+	}
+	return New(entries)
+}
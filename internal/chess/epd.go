@@ -0,0 +1,191 @@
+package chess
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidEPD is returned when an EPD record cannot be parsed.
+var ErrInvalidEPD = errors.New("invalid EPD")
+
+// EPD is one Extended Position Description record: a position given by the
+// first four FEN fields, and operations such as
+//
+//	bm Qg6; id "WAC.001"; c0 "Qg6=10, Rf7=3"; acd 12;
+type EPD struct {
+	Game Game    // the position; "hmvc" and "fmvn" set its clocks
+	Ops  []EPDOp // operations in the order they appear
+}
+
+// EPDOp is an EPD operation: an opcode and its operands, with quotes removed.
+type EPDOp struct {
+	Opcode   string
+	Operands []string
+}
+
+// ParseEPD parses a single EPD line.
+func ParseEPD(line string) (EPD, error) {
+	rest := strings.TrimSpace(line)
+	var fields [4]string
+	for i := range fields {
+		var ok bool
+		fields[i], rest, ok = strings.Cut(rest, " ")
+		if (!ok && i < 3) || fields[i] == "" {
+			return EPD{}, fmt.Errorf("%w: want four position fields: %q", ErrInvalidEPD, line)
+		}
+		rest = strings.TrimLeft(rest, " \t")
+	}
+
+	var e EPD
+	ops, err := parseEPDOps(rest)
+	if err != nil {
+		return EPD{}, fmt.Errorf("%w: %v: %q", ErrInvalidEPD, err, line)
+	}
+	e.Ops = ops
+	clocks := [2]string{"0", "1"}
+	for i, opcode := range []string{"hmvc", "fmvn"} {
+		if operands, ok := e.Op(opcode); ok && len(operands) > 0 {
+			clocks[i] = operands[0]
+		}
+	}
+	fen := strings.Join(fields[:], " ") + " " + clocks[0] + " " + clocks[1]
+	if e.Game, err = NewGameFromFEN(fen); err != nil {
+		return EPD{}, fmt.Errorf("%w: %v", ErrInvalidEPD, err)
+	}
+	return e, nil
+}
+
+// parseEPDOps splits the operation section into opcodes and operands. Each
+// operation ends with a semicolon; quoted operands may contain spaces and
+// semicolons.
+func parseEPDOps(s string) ([]EPDOp, error) {
+	var ops []EPDOp
+	var op *EPDOp
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == ';':
+			if op == nil {
+				return nil, errors.New("empty operation")
+			}
+			ops = append(ops, *op)
+			op = nil
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 || op == nil {
+				return nil, errors.New("unterminated or misplaced string")
+			}
+			op.Operands = append(op.Operands, s[i+1:i+1+end])
+			i += end + 2
+		default:
+			end := strings.IndexAny(s[i:], " \t;")
+			if end < 0 {
+				end = len(s) - i
+			}
+			tok := s[i : i+end]
+			if op == nil {
+				op = &EPDOp{Opcode: tok}
+			} else {
+				op.Operands = append(op.Operands, tok)
+			}
+			i += end
+		}
+	}
+	if op != nil {
+		return nil, fmt.Errorf("operation %q lacks its semicolon", op.Opcode)
+	}
+	return ops, nil
+}
+
+// ReadEPD reads one record per non-empty line; lines starting with '#' are skipped.
+func ReadEPD(r io.Reader) ([]EPD, error) {
+	var records []EPD
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e, err := ParseEPD(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		records = append(records, e)
+	}
+	return records, sc.Err()
+}
+
+// Op returns the operands of the first operation with the given opcode.
+func (e EPD) Op(opcode string) ([]string, bool) {
+	for _, op := range e.Ops {
+		if op.Opcode == opcode {
+			return op.Operands, true
+		}
+	}
+	return nil, false
+}
+
+// ID returns the "id" operand, or "" when there is none.
+func (e EPD) ID() string {
+	if operands, ok := e.Op("id"); ok && len(operands) > 0 {
+		return operands[0]
+	}
+	return ""
+}
+
+// Moves returns the operands of a move-list opcode such as "bm" or "am",
+// given in SAN (UCI notation is accepted too), as moves of the position.
+func (e EPD) Moves(opcode string) ([]Move, error) {
+	operands, _ := e.Op(opcode)
+	moves := make([]Move, 0, len(operands))
+	for _, s := range operands {
+		m, err := ParseSAN(e.Game, s)
+		if err != nil {
+			if m, err = ParseUCI(e.Game, s); err != nil {
+				return nil, fmt.Errorf("%w: %s %s: not a legal move", ErrInvalidEPD, opcode, s)
+			}
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+// Int returns the first operand of opcode as an integer, e.g. Int("acd").
+func (e EPD) Int(opcode string) (int, bool) {
+	operands, ok := e.Op(opcode)
+	if !ok || len(operands) == 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(operands[0])
+	return n, err == nil
+}
+
+// String returns the record in EPD form. Operands of "id" and the comment
+// opcodes c0-c9 are quoted, as are operands containing spaces or semicolons.
+func (e EPD) String() string {
+	fen := strings.Fields(e.Game.ToFEN())
+	var sb strings.Builder
+	sb.WriteString(strings.Join(fen[:4], " "))
+	for _, op := range e.Ops {
+		sb.WriteString(" " + op.Opcode)
+		for _, operand := range op.Operands {
+			if quotedOpcode(op.Opcode) || operand == "" || strings.ContainsAny(operand, " ;\t") {
+				operand = `"` + operand + `"`
+			}
+			sb.WriteString(" " + operand)
+		}
+		sb.WriteByte(';')
+	}
+	return sb.String()
+}
+
+// quotedOpcode reports whether opcode takes string operands.
+func quotedOpcode(opcode string) bool {
+	return opcode == "id" || len(opcode) == 2 && opcode[0] == 'c' && opcode[1] >= '0' && opcode[1] <= '9'
+}
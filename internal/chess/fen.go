@@ -10,6 +10,9 @@ import (
 // ErrInvalidFEN is returned by NewGameFromFEN when the FEN string is malformed.
 var ErrInvalidFEN = errors.New("invalid FEN")
 
+// StartFEN is the FEN string of the standard starting position.
+const StartFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
 // NewGameFromFEN parses a FEN string and returns a new Game.
 // Returns ErrInvalidFEN if the string is malformed or invalid.
 func NewGameFromFEN(fen string) (Game, error) {
@@ -96,6 +99,9 @@ func parsePiecePlacement(s string) ([64]Piece, error) {
 				if file > 7 {
 					return board, ErrInvalidFEN
 				}
+				if (p == WhitePawn || p == BlackPawn) && (rank == 0 || rank == 7) {
+					return board, fmt.Errorf("%w: pawn on the first or eighth rank", ErrInvalidFEN)
+				}
 				board[SquareOf(file, rank)] = p
 				file++
 			}
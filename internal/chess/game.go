@@ -77,6 +77,62 @@ func (g Game) Result() GameResult {
 	return detectResult(g.State, g.history)
 }
 
+// HasMatingMaterial reports whether c could checkmate by some series of legal
+// moves, however unlikely: it lacks mating material with a lone king, with a
+// king and knight against a lone king, or with king and bishops all on squares
+// of one colour against a king and bishops on squares of that colour. A player
+// whose time runs out draws against an opponent without mating material.
+func (g Game) HasMatingMaterial(c Color) bool {
+	knights, bishops := 0, [2]int{} // bishops by square colour: 0 dark, 1 light
+	opponentOther := false          // the opponent has a pawn, knight, rook or queen
+	opponentBishops := [2]bool{}
+	for sq := Square(0); sq < 64; sq++ {
+		p := g.State.Board[sq]
+		if p == NoPiece || p == WhiteKing || p == BlackKing {
+			continue
+		}
+		shade := (sq.File() + sq.Rank()) % 2
+		if pieceColor(p) != c {
+			if p == WhiteBishop || p == BlackBishop {
+				opponentBishops[shade] = true
+			} else {
+				opponentOther = true
+			}
+			continue
+		}
+		switch p {
+		case WhiteKnight, BlackKnight:
+			knights++
+		case WhiteBishop, BlackBishop:
+			bishops[shade]++
+		default:
+			return true // pawn, rook or queen
+		}
+	}
+	minors := knights + bishops[0] + bishops[1]
+	switch {
+	case minors == 0:
+		return false
+	case minors >= 2 && !(knights == 0 && (bishops[0] == 0 || bishops[1] == 0)):
+		return true
+	case knights == 1:
+		return opponentOther || opponentBishops[0] || opponentBishops[1]
+	}
+	// Bishops all on one colour: the opponent's own pieces must be able to
+	// block its king in, which bishops of that colour cannot do.
+	shade := 0
+	if bishops[1] > 0 {
+		shade = 1
+	}
+	return opponentOther || opponentBishops[1-shade]
+}
+
+// History returns the positions that preceded the current one, oldest first.
+// The returned slice is a copy and may be modified by the caller.
+func (g Game) History() []GameState {
+	return append([]GameState(nil), g.history...)
+}
+
 // ToFEN returns the FEN string for the current game state.
 func (g Game) ToFEN() string {
 	return stateToFEN(g.State)
@@ -87,6 +143,28 @@ func (g Game) ToPGN() string {
 	return buildPGN(g)
 }
 
+// LegalMoves returns all legal moves for the side to move in s.
+func (s GameState) LegalMoves() []Move {
+	return generateLegalMoves(s)
+}
+
+// Play returns the state after m without checking legality. It is the fast path
+// used by search; callers must only pass moves obtained from LegalMoves.
+func (s GameState) Play(m Move) GameState {
+	return applyMove(s, m)
+}
+
+// InCheck returns true if the side to move is in check.
+func (s GameState) InCheck() bool {
+	return isInCheck(s, s.ActiveColor)
+}
+
+// SamePosition reports whether a and b are the same position for repetition
+// purposes (board, side to move, castling rights and en passant square).
+func SamePosition(a, b GameState) bool {
+	return positionsEqual(a, b)
+}
+
 // applyMove applies a move to a GameState and returns the new state.
 // The move is assumed to be legal.
 func applyMove(s GameState, m Move) GameState {
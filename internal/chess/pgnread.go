@@ -0,0 +1,389 @@
+package chess
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPGN is returned when a PGN game cannot be parsed or replayed.
+var ErrInvalidPGN = errors.New("invalid PGN")
+
+// PGNGame is a single game read from a PGN stream.
+type PGNGame struct {
+	Tags   map[string]string // tag pairs, e.g. Tags["White"]
+	Start  Game              // position before the first move (honours SetUp/FEN tags)
+	Moves  []Move            // mainline moves in playing order
+	Result string            // game termination marker: "1-0", "0-1", "1/2-1/2" or "*"
+
+	// Comment is the text of a comment before the first move.
+	Comment string
+	// Annotations, when set, holds commentary for the move with the same
+	// index. It is read from and written to PGN; a game read without any
+	// commentary has none.
+	Annotations []PGNAnnotation
+}
+
+// PGNAnnotation is commentary on one move of a PGNGame.
+type PGNAnnotation struct {
+	Suffix     string   // move suffix annotation: "!", "?", "!!", "??", "!?" or "?!"
+	NAGs       []int    // numeric annotation glyphs, written "$n": 14 is "White is slightly better"
+	Comment    string   // text written in braces after the move
+	Variations [][]Move // alternatives to the move, each played from the position before it
+
+	// VariationAnnotations, when set, holds commentary for the moves of the
+	// variation with the same index, as Annotations does for a game's moves.
+	VariationAnnotations [][]PGNAnnotation
+}
+
+// Final returns the position after all mainline moves have been played.
+func (pg PGNGame) Final() Game {
+	g := pg.Start
+	for _, m := range pg.Moves {
+		next, err := g.Apply(m)
+		if err != nil {
+			break
+		}
+		g = next
+	}
+	return g
+}
+
+// PGNScanner reads games one at a time from a PGN stream, in the manner of bufio.Scanner.
+// Comments, suffixes, NAGs and (nested) variations are kept in the game's
+// Annotations; a variation is cut short at a move that cannot be played.
+//
+//	sc := chess.NewPGNScanner(r)
+//	for sc.Scan() {
+//	    pg := sc.Game()
+//	}
+//	if err := sc.Err(); err != nil { ... }
+type PGNScanner struct {
+	r       *bufio.Reader
+	pending string // tag line read ahead of the previous game's end
+	game    PGNGame
+	err     error
+}
+
+// NewPGNScanner returns a scanner reading PGN text from r.
+func NewPGNScanner(r io.Reader) *PGNScanner {
+	return &PGNScanner{r: bufio.NewReader(r)}
+}
+
+// Scan advances to the next game. It returns false at end of input or on the first error.
+func (sc *PGNScanner) Scan() bool {
+	if sc.err != nil {
+		return false
+	}
+	pg, err := sc.next()
+	if err != nil {
+		if err != io.EOF {
+			sc.err = err
+		}
+		return false
+	}
+	sc.game = pg
+	return true
+}
+
+// Game returns the game read by the most recent call to Scan.
+func (sc *PGNScanner) Game() PGNGame { return sc.game }
+
+// Err returns the first non-EOF error encountered by the scanner.
+func (sc *PGNScanner) Err() error { return sc.err }
+
+// ReadPGN reads every game from r.
+func ReadPGN(r io.Reader) ([]PGNGame, error) {
+	var games []PGNGame
+	sc := NewPGNScanner(r)
+	for sc.Scan() {
+		games = append(games, sc.Game())
+	}
+	return games, sc.Err()
+}
+
+// next parses one game: the tag pair section followed by the movetext.
+func (sc *PGNScanner) next() (PGNGame, error) {
+	tags := map[string]string{}
+	var movetext []string
+
+	sawAny := false
+	inMoves := false
+	for {
+		line, err := sc.readLine()
+		if err != nil && err != io.EOF {
+			return PGNGame{}, err
+		}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "%"):
+			// Escape mechanism: the whole line is ignored.
+		case strings.HasPrefix(trimmed, "["):
+			if inMoves {
+				// A tag after movetext starts the next game; keep it for the next call.
+				sc.pending = line
+				return buildPGNGame(tags, movetext)
+			}
+			name, value, ok := parseTagPair(trimmed)
+			if !ok {
+				return PGNGame{}, fmt.Errorf("%w: malformed tag %q", ErrInvalidPGN, trimmed)
+			}
+			tags[name] = value
+			sawAny = true
+		case trimmed != "":
+			movetext = append(movetext, trimmed)
+			inMoves = true
+			sawAny = true
+			if endsWithResult(trimmed) {
+				return buildPGNGame(tags, movetext)
+			}
+		}
+
+		if err == io.EOF {
+			if !sawAny {
+				return PGNGame{}, io.EOF
+			}
+			return buildPGNGame(tags, movetext)
+		}
+	}
+}
+
+// readLine returns the pending look-ahead line if any, otherwise the next input line.
+func (sc *PGNScanner) readLine() (string, error) {
+	if sc.pending != "" {
+		line := sc.pending
+		sc.pending = ""
+		return line, nil
+	}
+	return sc.r.ReadString('\n')
+}
+
+// parseTagPair parses a line such as `[White "Carlsen, Magnus"]`.
+func parseTagPair(line string) (string, string, bool) {
+	if !strings.HasSuffix(line, "]") {
+		return "", "", false
+	}
+	body := strings.TrimSpace(line[1 : len(line)-1])
+	sp := strings.IndexAny(body, " \t")
+	if sp <= 0 {
+		return "", "", false
+	}
+	name := body[:sp]
+	quoted := strings.TrimSpace(body[sp:])
+	if len(quoted) < 2 || quoted[0] != '"' || quoted[len(quoted)-1] != '"' {
+		return "", "", false
+	}
+	value := strings.ReplaceAll(quoted[1:len(quoted)-1], `\"`, `"`)
+	value = strings.ReplaceAll(value, `\\`, `\`)
+	return name, value, true
+}
+
+// endsWithResult reports whether a movetext line ends with a game termination marker.
+func endsWithResult(line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return false
+	}
+	return isResultToken(fields[len(fields)-1])
+}
+
+// isResultToken reports whether tok is a PGN game termination marker.
+func isResultToken(tok string) bool {
+	return tok == "1-0" || tok == "0-1" || tok == "1/2-1/2" || tok == "*"
+}
+
+// buildPGNGame replays the movetext from the starting position given by the tags.
+func buildPGNGame(tags map[string]string, movetext []string) (PGNGame, error) {
+	start := StartFEN
+	if fen, ok := tags["FEN"]; ok {
+		start = fen
+	}
+	g, err := NewGameFromFEN(start)
+	if err != nil {
+		return PGNGame{}, fmt.Errorf("%w: %v", ErrInvalidPGN, err)
+	}
+
+	pg := PGNGame{Tags: tags, Start: g, Result: tags["Result"]}
+	toks := tokenizeMovetext(strings.Join(movetext, "\n"))
+	var result string
+	pg.Moves, pg.Annotations, pg.Comment, result, err = readMovetext(g, toks, true)
+	if err != nil {
+		return PGNGame{}, err
+	}
+	if result != "" {
+		pg.Result = result
+	}
+	if pg.Result == "" {
+		pg.Result = "*"
+	}
+	if !annotated(pg.Annotations) {
+		pg.Annotations = nil
+	}
+	return pg, nil
+}
+
+// readMovetext reads the moves of a line played from g, with their
+// annotations, from toks, consuming them. The mainline ends at a result marker, which is
+// returned, or at the end of toks; a move it cannot play is an error. A
+// variation ends at its closing parenthesis; a move it cannot play ends it
+// early, dropping the rest. comment is the text of a comment before the
+// first move.
+func readMovetext(g Game, toks *[]pgnToken, main bool) (moves []Move, notes []PGNAnnotation, comment, result string, err error) {
+	before := g // the position before the last move, where its variations start
+	for len(*toks) > 0 {
+		tok := (*toks)[0]
+		*toks = (*toks)[1:]
+		switch tok.kind {
+		case tokenResult:
+			if main {
+				return moves, notes, comment, tok.text, nil
+			}
+		case tokenClose:
+			if !main {
+				return moves, notes, comment, "", nil
+			}
+		case tokenComment:
+			switch {
+			case len(notes) > 0:
+				notes[len(notes)-1].Comment = strings.TrimSpace(notes[len(notes)-1].Comment + " " + tok.text)
+			case main:
+				comment = strings.TrimSpace(comment + " " + tok.text)
+			}
+		case tokenNAG:
+			if len(notes) > 0 {
+				if n, err := strconv.Atoi(tok.text[1:]); err == nil {
+					notes[len(notes)-1].NAGs = append(notes[len(notes)-1].NAGs, n)
+				}
+			}
+		case tokenOpen:
+			vmoves, vnotes, _, _, _ := readMovetext(before, toks, false)
+			if len(notes) > 0 && len(vmoves) > 0 {
+				a := &notes[len(notes)-1]
+				a.Variations = append(a.Variations, vmoves)
+				a.VariationAnnotations = append(a.VariationAnnotations, vnotes)
+			}
+		case tokenMove:
+			m, perr := ParseSAN(g, tok.text)
+			if perr != nil {
+				if main {
+					return nil, nil, "", "", fmt.Errorf("%w: move %d %q: %v", ErrInvalidPGN, len(moves)+1, tok.text, perr)
+				}
+				skipVariation(toks)
+				return moves, notes, comment, "", nil
+			}
+			before = g
+			g, _ = g.Apply(m)
+			moves = append(moves, m)
+			notes = append(notes, PGNAnnotation{Suffix: tok.text[len(strings.TrimRight(tok.text, "!?")):]})
+		}
+	}
+	return moves, notes, comment, "", nil
+}
+
+// skipVariation consumes toks up to the end of the current variation.
+func skipVariation(toks *[]pgnToken) {
+	for depth := 0; len(*toks) > 0; {
+		tok := (*toks)[0]
+		*toks = (*toks)[1:]
+		switch tok.kind {
+		case tokenOpen:
+			depth++
+		case tokenClose:
+			if depth == 0 {
+				return
+			}
+			depth--
+		}
+	}
+}
+
+// annotated reports whether any of notes carries commentary.
+func annotated(notes []PGNAnnotation) bool {
+	for _, a := range notes {
+		if a.Suffix != "" || a.Comment != "" || len(a.NAGs) > 0 || len(a.Variations) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// pgnTokenKind is the kind of a movetext token.
+type pgnTokenKind uint8
+
+const (
+	tokenMove    pgnTokenKind = iota // a SAN move, possibly with a suffix annotation
+	tokenResult                      // a game termination marker
+	tokenComment                     // the text of a {brace} or ;rest-of-line comment
+	tokenNAG                         // a numeric annotation glyph, "$n"
+	tokenOpen                        // "(": a variation starts
+	tokenClose                       // ")": a variation ends
+)
+
+// pgnToken is one token of movetext.
+type pgnToken struct {
+	kind pgnTokenKind
+	text string
+}
+
+// tokenizeMovetext splits movetext into moves, result markers, comments, NAGs
+// and variation parentheses, dropping move numbers.
+func tokenizeMovetext(text string) *[]pgnToken {
+	var tokens []pgnToken
+	for i := 0; i < len(text); {
+		ch := text[i]
+		switch {
+		case ch == '{' || ch == ';':
+			end := "}"
+			if ch == ';' {
+				end = "\n"
+			}
+			n := strings.Index(text[i+1:], end)
+			if n < 0 {
+				n = len(text) - i - 1
+			}
+			if comment := strings.Join(strings.Fields(text[i+1:i+1+n]), " "); comment != "" {
+				tokens = append(tokens, pgnToken{tokenComment, comment})
+			}
+			i += n + 2
+			continue
+		case ch == '(':
+			tokens = append(tokens, pgnToken{kind: tokenOpen})
+			i++
+			continue
+		case ch == ')':
+			tokens = append(tokens, pgnToken{kind: tokenClose})
+			i++
+			continue
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(text) && !strings.ContainsRune(" \t\r\n{}();", rune(text[j])) {
+			j++
+		}
+		tok := text[i:j]
+		i = j
+		switch {
+		case tok[0] == '$':
+			tokens = append(tokens, pgnToken{tokenNAG, tok})
+			continue
+		case isResultToken(tok):
+			tokens = append(tokens, pgnToken{tokenResult, tok})
+			continue
+		}
+		// Strip a leading move number ("12." / "12...") which may be glued to the move.
+		if k := strings.LastIndexByte(tok, '.'); k >= 0 && strings.Trim(tok[:k+1], "0123456789.") == "" {
+			tok = tok[k+1:]
+		}
+		if tok != "" {
+			tokens = append(tokens, pgnToken{tokenMove, tok})
+		}
+	}
+	return &tokens
+}
@@ -0,0 +1,149 @@
+package chess
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sevenTagRoster lists the tags every exported PGN game carries, in order.
+var sevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// pgnLineWidth is the maximum length of an exported movetext line.
+const pgnLineWidth = 79
+
+// String returns the game in PGN export format: the seven tag roster (with "?"
+// for missing values) followed by the remaining tags in alphabetical order,
+// then the movetext in SAN wrapped at 79 columns, carrying the Comment and any
+// Annotations as move suffixes, $NAGs, {comments} and (variations). SetUp and FEN tags are added
+// when the game does not start from the standard position.
+func (pg PGNGame) String() string {
+	tags := make(map[string]string, len(pg.Tags)+2)
+	for k, v := range pg.Tags {
+		tags[k] = v
+	}
+	result := pg.Result
+	if result == "" {
+		result = "*"
+	}
+	tags["Result"] = result
+	if fen := pg.Start.ToFEN(); fen != StartFEN {
+		tags["SetUp"], tags["FEN"] = "1", fen
+	} else {
+		delete(tags, "SetUp")
+		delete(tags, "FEN")
+	}
+
+	var sb strings.Builder
+	for _, name := range sevenTagRoster {
+		value, ok := tags[name]
+		if !ok {
+			value = "?"
+		}
+		writeTag(&sb, name, value)
+	}
+	var extra []string
+	for name := range tags {
+		if !isRosterTag(name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		writeTag(&sb, name, tags[name])
+	}
+	sb.WriteByte('\n')
+
+	var words []string
+	if comment := strings.Fields(strings.ReplaceAll(pg.Comment, "}", ")")); len(comment) > 0 {
+		words = enclose("{", comment, "}")
+	}
+	words = append(words, movetext(pg.Start, pg.Moves, pg.Annotations)...)
+	line := 0
+	for _, word := range append(words, result) {
+		switch {
+		case line == 0:
+		case line+1+len(word) > pgnLineWidth:
+			sb.WriteByte('\n')
+			line = 0
+		default:
+			sb.WriteByte(' ')
+			line++
+		}
+		sb.WriteString(word)
+		line += len(word)
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+// movetext returns the words of the movetext for moves played from g: move
+// numbers, SAN moves with their suffixes and NAGs, comments in braces and
+// variations in parentheses. A Black move is numbered "N..." when it starts
+// the line or follows a comment or variation.
+func movetext(g Game, moves []Move, annotations []PGNAnnotation) []string {
+	var words []string
+	numbered := false
+	for i, m := range moves {
+		white := g.State.ActiveColor == White
+		if white || !numbered {
+			number := strconv.Itoa(int(g.State.FullMoveNumber)) + "."
+			if !white {
+				number += ".."
+			}
+			words = append(words, number)
+		}
+		numbered = true
+		var a PGNAnnotation
+		if i < len(annotations) {
+			a = annotations[i]
+		}
+		words = append(words, m.SANString(g)+a.Suffix)
+		for _, nag := range a.NAGs {
+			words = append(words, "$"+strconv.Itoa(nag))
+		}
+		next, err := g.Apply(m)
+		if err != nil {
+			break
+		}
+		if comment := strings.Fields(strings.ReplaceAll(a.Comment, "}", ")")); len(comment) > 0 {
+			words = append(words, enclose("{", comment, "}")...)
+			numbered = false
+		}
+		for j, v := range a.Variations {
+			if len(v) > 0 {
+				var notes []PGNAnnotation
+				if j < len(a.VariationAnnotations) {
+					notes = a.VariationAnnotations[j]
+				}
+				words = append(words, enclose("(", movetext(g, v, notes), ")")...)
+				numbered = false
+			}
+		}
+		g = next
+	}
+	return words
+}
+
+// enclose attaches open to the first word and close to the last.
+func enclose(open string, words []string, close string) []string {
+	words[0] = open + words[0]
+	words[len(words)-1] += close
+	return words
+}
+
+// writeTag writes one tag pair, escaping quotes and backslashes.
+func writeTag(sb *strings.Builder, name, value string) {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	sb.WriteString("[" + name + ` "` + value + "\"]\n")
+}
+
+func isRosterTag(name string) bool {
+	for _, n := range sevenTagRoster {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidMoveFormat is returned when a move string cannot be parsed as UCI or SAN.
+var ErrInvalidMoveFormat = errors.New("invalid move format")
+
+// SANString returns the Standard Algebraic Notation of m in the position g
+// (e.g. "e4", "Nf3", "exd5", "O-O", "e8=Q+", "Qh4#").
+// The move is assumed to be legal in g.
+func (m Move) SANString(g Game) string {
+	s := g.State
+	piece := s.Board[m.From]
+
+	var sb strings.Builder
+	switch {
+	case (piece == WhiteKing || piece == BlackKing) && m.IsCastle():
+		if m.To.File() == 6 {
+			sb.WriteString("O-O")
+		} else {
+			sb.WriteString("O-O-O")
+		}
+	case piece == WhitePawn || piece == BlackPawn:
+		if m.From.File() != m.To.File() {
+			sb.WriteByte(byte('a' + m.From.File()))
+			sb.WriteByte('x')
+		}
+		writeSquare(&sb, m.To)
+		if m.Promotion != NoPiece {
+			sb.WriteByte('=')
+			sb.WriteByte(upperSymbol(m.Promotion))
+		}
+	default:
+		sb.WriteByte(upperSymbol(piece))
+		sb.WriteString(disambiguation(g, m, piece))
+		if s.Board[m.To] != NoPiece {
+			sb.WriteByte('x')
+		}
+		writeSquare(&sb, m.To)
+	}
+
+	next := applyMove(s, m)
+	if isInCheck(next, next.ActiveColor) {
+		if len(generateLegalMoves(next)) == 0 {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('+')
+		}
+	}
+	return sb.String()
+}
+
+// disambiguation returns the file, rank, or square prefix needed to tell m apart
+// from other legal moves of the same piece type to the same square.
+func disambiguation(g Game, m Move, piece Piece) string {
+	sameFile, sameRank, ambiguous := false, false, false
+	for _, other := range g.LegalMoves() {
+		if other.To != m.To || other.From == m.From || g.State.Board[other.From] != piece {
+			continue
+		}
+		ambiguous = true
+		if other.From.File() == m.From.File() {
+			sameFile = true
+		}
+		if other.From.Rank() == m.From.Rank() {
+			sameRank = true
+		}
+	}
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return string(rune('a' + m.From.File()))
+	case !sameRank:
+		return string(rune('1' + m.From.Rank()))
+	default:
+		return squareName(m.From)
+	}
+}
+
+// ParseSAN resolves a SAN string (e.g. "Nbd7", "exd5", "O-O", "e8=Q+") against the
+// legal moves of g. Check, mate and annotation suffixes ("+", "#", "!", "?") are ignored.
+// Returns ErrInvalidMoveFormat if the string is malformed, or ErrIllegalMove if it
+// does not match exactly one legal move.
+func ParseSAN(g Game, san string) (Move, error) {
+	text := strings.TrimRight(san, "+#!?")
+	if text == "" {
+		return Move{}, fmt.Errorf("%w: %q", ErrInvalidMoveFormat, san)
+	}
+
+	color := g.State.ActiveColor
+	switch text {
+	case "O-O", "0-0":
+		return matchMove(g, san, func(m Move, p Piece) bool {
+			return isKing(p) && m.IsCastle() && m.To.File() == 6
+		})
+	case "O-O-O", "0-0-0":
+		return matchMove(g, san, func(m Move, p Piece) bool {
+			return isKing(p) && m.IsCastle() && m.To.File() == 2
+		})
+	}
+
+	// Promotion suffix: "e8=Q" or "e8Q".
+	promo := NoPiece
+	if n := len(text); n >= 3 && strings.ContainsRune("NBRQ", rune(text[n-1])) {
+		promo = pieceOfColor(text[n-1], color)
+		text = strings.TrimSuffix(text[:n-1], "=")
+	}
+
+	// Piece letter.
+	kind := byte('P')
+	if text != "" && strings.ContainsRune("NBRQK", rune(text[0])) {
+		kind = text[0]
+		text = text[1:]
+	}
+	if len(text) < 2 {
+		return Move{}, fmt.Errorf("%w: %q", ErrInvalidMoveFormat, san)
+	}
+	to, ok := parseSquare(text[len(text)-2:])
+	if !ok {
+		return Move{}, fmt.Errorf("%w: %q", ErrInvalidMoveFormat, san)
+	}
+
+	// Whatever remains is an optional disambiguator and capture marker.
+	fromFile, fromRank := -1, -1
+	for _, ch := range strings.TrimSuffix(text[:len(text)-2], "x") {
+		switch {
+		case ch >= 'a' && ch <= 'h':
+			fromFile = int(ch - 'a')
+		case ch >= '1' && ch <= '8':
+			fromRank = int(ch - '1')
+		default:
+			return Move{}, fmt.Errorf("%w: %q", ErrInvalidMoveFormat, san)
+		}
+	}
+
+	want := pieceOfColor(kind, color)
+	return matchMove(g, san, func(m Move, p Piece) bool {
+		return p == want && m.To == to && m.Promotion == promo &&
+			(fromFile < 0 || m.From.File() == fromFile) &&
+			(fromRank < 0 || m.From.Rank() == fromRank)
+	})
+}
+
+// ParseUCI resolves a UCI move string (e.g. "e2e4", "e7e8q") against the legal moves of g.
+// Returns ErrInvalidMoveFormat if the string is malformed, or ErrIllegalMove if the
+// move is not legal in g.
+func ParseUCI(g Game, uci string) (Move, error) {
+	if len(uci) != 4 && len(uci) != 5 {
+		return Move{}, fmt.Errorf("%w: %q", ErrInvalidMoveFormat, uci)
+	}
+	from, ok1 := parseSquare(uci[0:2])
+	to, ok2 := parseSquare(uci[2:4])
+	if !ok1 || !ok2 {
+		return Move{}, fmt.Errorf("%w: %q", ErrInvalidMoveFormat, uci)
+	}
+	promo := NoPiece
+	if len(uci) == 5 {
+		if !strings.ContainsRune("nbrq", rune(uci[4])) {
+			return Move{}, fmt.Errorf("%w: %q", ErrInvalidMoveFormat, uci)
+		}
+		promo = pieceOfColor(uci[4]-32, g.State.ActiveColor)
+	}
+	m := Move{From: from, To: to, Promotion: promo}
+	for _, lm := range g.LegalMoves() {
+		if lm == m {
+			return m, nil
+		}
+	}
+	return Move{}, fmt.Errorf("%w: %s", ErrIllegalMove, uci)
+}
+
+// matchMove returns the single legal move accepted by pred.
+func matchMove(g Game, san string, pred func(m Move, p Piece) bool) (Move, error) {
+	var found []Move
+	for _, m := range g.LegalMoves() {
+		if pred(m, g.State.Board[m.From]) {
+			found = append(found, m)
+		}
+	}
+	if len(found) != 1 {
+		return Move{}, fmt.Errorf("%w: %s", ErrIllegalMove, san)
+	}
+	return found[0], nil
+}
+
+// pieceOfColor converts an uppercase piece letter to the piece of the given color.
+func pieceOfColor(letter byte, c Color) Piece {
+	if c == Black {
+		letter += 'a' - 'A'
+	}
+	return pieceFromSymbol(letter)
+}
+
+// upperSymbol returns the uppercase letter for a piece regardless of its color.
+func upperSymbol(p Piece) byte {
+	ch := pieceSymbol(p)
+	if ch >= 'a' && ch <= 'z' {
+		ch -= 'a' - 'A'
+	}
+	return ch
+}
+
+// isKing reports whether p is a king of either color.
+func isKing(p Piece) bool { return p == WhiteKing || p == BlackKing }
+
+// parseSquare parses an algebraic square name such as "e4".
+func parseSquare(s string) (Square, bool) {
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return NoSquare, false
+	}
+	return SquareOf(int(s[0]-'a'), int(s[1]-'1')), true
+}
+
+// squareName returns the algebraic name of a square (e.g. "e4").
+func squareName(sq Square) string {
+	return string([]byte{byte('a' + sq.File()), byte('1' + sq.Rank())})
+}
+
+// writeSquare writes the algebraic name of sq to sb.
+func writeSquare(sb *strings.Builder, sq Square) {
+	sb.WriteByte(byte('a' + sq.File()))
+	sb.WriteByte(byte('1' + sq.Rank()))
+}
@@ -0,0 +1,333 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WDL is a Syzygy win/draw/loss value from the point of view of the side to move.
+type WDL int8
+
+const (
+	WDLLoss        WDL = -2 // lost, even with the fifty-move rule
+	WDLBlessedLoss WDL = -1 // lost, but drawn under the fifty-move rule
+	WDLDraw        WDL = 0
+	WDLCursedWin   WDL = 1 // won, but drawn under the fifty-move rule
+	WDLWin         WDL = 2 // won, even with the fifty-move rule
+)
+
+// String returns a human-readable name such as "cursed win".
+func (w WDL) String() string {
+	switch w {
+	case WDLLoss:
+		return "loss"
+	case WDLBlessedLoss:
+		return "blessed loss"
+	case WDLDraw:
+		return "draw"
+	case WDLCursedWin:
+		return "cursed win"
+	case WDLWin:
+		return "win"
+	}
+	return fmt.Sprintf("WDL(%d)", int8(w))
+}
+
+var (
+	// ErrTablebaseMiss is returned when a position cannot be probed: too many pieces,
+	// castling rights, or the table for its material is not available.
+	ErrTablebaseMiss = errors.New("position not in tablebase")
+	// ErrInvalidTablebase is returned when a table file is truncated or malformed.
+	ErrInvalidTablebase = errors.New("invalid syzygy table")
+	// ErrInvalidPosition is returned for a position no table holds, such as one
+	// with a pawn on the first or eighth rank.
+	ErrInvalidPosition = errors.New("position cannot be probed")
+)
+
+// Syzygy probes Syzygy endgame tablebases (.rtbw for WDL, .rtbz for DTZ).
+// Tables are discovered when the Syzygy is opened and loaded on first use.
+// A Syzygy is safe for concurrent use.
+type Syzygy struct {
+	wdl       map[uint64]*tbTable
+	dtz       map[uint64]*tbTable
+	maxPieces int
+	tables    int
+}
+
+// probeState carries the side information of a probe (Stockfish's ProbeState).
+type probeState uint8
+
+const (
+	probeOK              probeState = iota
+	probeZeroingBestMove            // the best move is a capture or pawn move
+	probeChangeSTM                  // the DTZ table stores the other side to move
+)
+
+// OpenSyzygy scans the directories in path, separated by os.PathListSeparator,
+// for Syzygy table files. Files whose names are not a material signature such as
+// "KRPvKR" are ignored; when a table appears in several directories the first wins.
+func OpenSyzygy(path string) (*Syzygy, error) {
+	tb := &Syzygy{wdl: make(map[uint64]*tbTable), dtz: make(map[uint64]*tbTable)}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			ext := filepath.Ext(name)
+			var tables map[uint64]*tbTable
+			switch ext {
+			case ".rtbw":
+				tables = tb.wdl
+			case ".rtbz":
+				tables = tb.dtz
+			default:
+				continue
+			}
+			t, ok := newTBTable(filepath.Join(dir, name), strings.TrimSuffix(name, ext), ext == ".rtbw")
+			if !ok {
+				continue
+			}
+			if _, dup := tables[t.key]; dup {
+				continue
+			}
+			tables[t.key], tables[t.key2] = t, t
+			if t.isWDL {
+				tb.tables++
+				tb.maxPieces = max(tb.maxPieces, t.pieceCount)
+			}
+		}
+	}
+	return tb, nil
+}
+
+// MaxPieces returns the largest piece count (kings included) covered by a WDL table.
+func (tb *Syzygy) MaxPieces() int { return tb.maxPieces }
+
+// Tables returns the number of WDL tables found.
+func (tb *Syzygy) Tables() int { return tb.tables }
+
+// Covers reports whether s has few enough pieces and no castling rights, i.e.
+// whether a probe may succeed. It does not check that the table is present.
+func (tb *Syzygy) Covers(s GameState) bool {
+	_, n := materialKeyOf(s)
+	return n <= tb.maxPieces && s.CastlingRights == NoCastling
+}
+
+// ProbeWDL returns the win/draw/loss value of s for the side to move, assuming
+// the fifty-move counter is zero. En passant rights are taken into account.
+func (tb *Syzygy) ProbeWDL(s GameState) (wdl WDL, err error) {
+	if !tb.Covers(s) {
+		return WDLDraw, ErrTablebaseMiss
+	}
+	if backRankPawn(s) {
+		return WDLDraw, ErrInvalidPosition
+	}
+	defer recoverTablebase(&err)
+	wdl, _, err = tb.searchWDL(s, false)
+	return wdl, err
+}
+
+// ProbeDTZ returns the distance to zeroing in plies for s: the number of plies
+// until the next capture or pawn move with optimal play, positive when the side
+// to move wins and negative when it loses. Cursed wins and blessed losses are
+// offset by 100 in magnitude; draws return 0.
+func (tb *Syzygy) ProbeDTZ(s GameState) (dtz int, err error) {
+	if !tb.Covers(s) {
+		return 0, ErrTablebaseMiss
+	}
+	if backRankPawn(s) {
+		return 0, ErrInvalidPosition
+	}
+	defer recoverTablebase(&err)
+	return tb.probeDTZ(s)
+}
+
+// recoverTablebase turns an out-of-range read in a corrupt table into an error.
+func recoverTablebase(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%w: %v", ErrInvalidTablebase, r)
+	}
+}
+
+// searchWDL resolves captures (and, when zeroing is set, pawn moves) before
+// trusting the table, because tables ignore en passant and store "don't care"
+// values where the best move zeroes the fifty-move counter.
+func (tb *Syzygy) searchWDL(s GameState, zeroing bool) (WDL, probeState, error) {
+	moves := generateLegalMoves(s)
+	best := WDLLoss
+	searched := 0
+	for _, m := range moves {
+		if !isCapture(s, m) && (!zeroing || !isPawn(s.Board[m.From])) {
+			continue
+		}
+		searched++
+		v, _, err := tb.searchWDL(applyMove(s, m), false)
+		if err != nil {
+			return WDLDraw, probeOK, err
+		}
+		if v = -v; v > best {
+			best = v
+			if v >= WDLWin {
+				return v, probeZeroingBestMove, nil
+			}
+		}
+	}
+
+	// With every legal move searched the stored value may be wrong (e.g. the only
+	// moves are en passant captures), so rely on the search alone.
+	noMoreMoves := searched > 0 && searched == len(moves)
+	v := best
+	if !noMoreMoves {
+		raw, _, err := tb.probeTable(s, tb.wdl, WDLDraw)
+		if err != nil {
+			return WDLDraw, probeOK, err
+		}
+		v = WDL(raw)
+	}
+	if best >= v {
+		if best > WDLDraw || noMoreMoves {
+			return best, probeZeroingBestMove, nil
+		}
+		return best, probeOK, nil
+	}
+	return v, probeOK, nil
+}
+
+// probeDTZ implements ProbeDTZ without the coverage check.
+func (tb *Syzygy) probeDTZ(s GameState) (int, error) {
+	wdl, state, err := tb.searchWDL(s, true)
+	if err != nil || wdl == WDLDraw {
+		return 0, err
+	}
+	if state == probeZeroingBestMove {
+		return dtzBeforeZeroing(wdl), nil
+	}
+
+	dtz, state, err := tb.probeTable(s, tb.dtz, wdl)
+	if err != nil {
+		return 0, err
+	}
+	if state != probeChangeSTM {
+		if wdl == WDLCursedWin || wdl == WDLBlessedLoss {
+			dtz += 100
+		}
+		return dtz * wdlSign(wdl), nil
+	}
+
+	// The table only stores the other side to move: search one ply and take the
+	// best DTZ among moves that keep the WDL value.
+	best := 0xFFFF
+	for _, m := range generateLegalMoves(s) {
+		zeroing := isCapture(s, m) || isPawn(s.Board[m.From])
+		next := applyMove(s, m)
+		var d int
+		if zeroing {
+			v, _, err := tb.searchWDL(next, false)
+			if err != nil {
+				return 0, err
+			}
+			d = -dtzBeforeZeroing(v)
+		} else {
+			if d, err = tb.probeDTZ(next); err != nil {
+				return 0, err
+			}
+			d = -d
+		}
+		if d == 1 && isInCheck(next, next.ActiveColor) && len(generateLegalMoves(next)) == 0 {
+			best = 1 // mate
+		}
+		if !zeroing {
+			d += intSign(d)
+		}
+		if d < best && intSign(d) == wdlSign(wdl) {
+			best = d
+		}
+	}
+	if best == 0xFFFF {
+		return -1, nil // no legal moves: mated
+	}
+	return best, nil
+}
+
+// probeTable looks s up in tables. Positions with only the two kings are draws.
+func (tb *Syzygy) probeTable(s GameState, tables map[uint64]*tbTable, wdl WDL) (int, probeState, error) {
+	key, n := materialKeyOf(s)
+	if n == 2 {
+		return int(WDLDraw), probeOK, nil
+	}
+	t := tables[key]
+	if t == nil {
+		return 0, probeOK, ErrTablebaseMiss
+	}
+	if err := t.load(); err != nil {
+		return 0, probeOK, err
+	}
+	v, state := t.probe(s, key, wdl)
+	return v, state, nil
+}
+
+// dtzBeforeZeroing is the DTZ of a position whose best move zeroes the counter.
+func dtzBeforeZeroing(wdl WDL) int {
+	switch wdl {
+	case WDLWin:
+		return 1
+	case WDLCursedWin:
+		return 101
+	case WDLBlessedLoss:
+		return -101
+	case WDLLoss:
+		return -1
+	}
+	return 0
+}
+
+func wdlSign(w WDL) int { return intSign(int(w)) }
+
+func intSign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
+
+// isCapture reports whether m captures in s, including en passant.
+func isCapture(s GameState, m Move) bool {
+	return s.Board[m.To] != NoPiece || (isPawn(s.Board[m.From]) && m.To == s.EnPassantSq)
+}
+
+func isPawn(p Piece) bool { return p == WhitePawn || p == BlackPawn }
+
+// backRankPawn reports whether s has a pawn on the first or eighth rank.
+func backRankPawn(s GameState) bool {
+	for file := 0; file < 8; file++ {
+		if isPawn(s.Board[SquareOf(file, 0)]) || isPawn(s.Board[SquareOf(file, 7)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// materialKeyOf returns the material signature of s and its piece count.
+// Each of the 12 pieces gets a 4-bit counter, white pieces in the low bits.
+func materialKeyOf(s GameState) (uint64, int) {
+	var key uint64
+	n := 0
+	for _, p := range s.Board {
+		if p != NoPiece {
+			key += 1 << (4 * (uint(p) - 1))
+			n++
+		}
+	}
+	return key, n
+}
@@ -0,0 +1,772 @@
+package chess
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// This file decodes the Syzygy on-disk format. The layout and the index scheme
+// follow the reference prober (tbprobe.c by Ronald de Man, as adapted in Stockfish).
+
+// tbPieces is the largest number of pieces a Syzygy table can hold.
+const tbPieces = 7
+
+// Per-table flags stored in the sizes section.
+const (
+	tbSTM         = 1
+	tbMapped      = 2
+	tbWinPlies    = 4
+	tbLossPlies   = 8
+	tbWide        = 16
+	tbSingleValue = 128
+)
+
+var (
+	wdlMagic = []byte{0x71, 0xE8, 0x23, 0x5D}
+	dtzMagic = []byte{0xD7, 0x66, 0x0C, 0xA5}
+)
+
+// tbTable is one .rtbw or .rtbz file. Header fields come from the file name;
+// the rest is filled in by load.
+type tbTable struct {
+	path            string
+	isWDL           bool
+	key, key2       uint64 // material keys with the first named side as white / black
+	pieceCount      int
+	hasPawns        bool
+	hasUniquePieces bool
+	pawnCount       [2]int // leading colour first
+
+	once   sync.Once
+	err    error
+	data   []byte
+	items  [2][4]pairsData // [side to move][leading pawn file]
+	dtzMap int             // offset of the DTZ value map
+}
+
+// pairsData describes one compressed sub-table (Stockfish's PairsData).
+// All offsets are into tbTable.data.
+type pairsData struct {
+	flags           byte
+	pieces          [tbPieces]byte
+	groupLen        [tbPieces + 1]int
+	groupIdx        [tbPieces + 1]uint64
+	sizeofBlock     uint64
+	span            uint64
+	sparseIndex     int
+	sparseIndexSize uint64
+	blockLength     int
+	blockLengthSize uint64
+	blocksNum       uint64
+	blocks          int
+	maxSymLen       int
+	minSymLen       int // also the value of a single-value table
+	lowestSym       int
+	base64          []uint64
+	symlen          []uint8
+	btree           int
+	mapIdx          [4]int
+}
+
+// newTBTable parses a material signature such as "KRPvKR" into a table header.
+func newTBTable(path, code string, isWDL bool) (*tbTable, bool) {
+	var counts [2][6]int
+	side, kings := 0, [2]int{}
+	for i := 0; i < len(code); i++ {
+		if code[i] == 'v' {
+			if side == 1 {
+				return nil, false
+			}
+			side = 1
+			continue
+		}
+		t := bytes.IndexByte([]byte("PNBRQK"), code[i])
+		if t < 0 {
+			return nil, false
+		}
+		counts[side][t]++
+		if t == 5 {
+			kings[side]++
+		}
+	}
+	if side != 1 || kings != [2]int{1, 1} {
+		return nil, false
+	}
+
+	t := &tbTable{path: path, isWDL: isWDL}
+	for c := 0; c < 2; c++ {
+		for p := 0; p < 6; p++ {
+			t.key += uint64(counts[c][p]) << (4 * (6*c + p))
+			t.key2 += uint64(counts[c][p]) << (4 * (6*(1-c) + p))
+			t.pieceCount += counts[c][p]
+			if p < 5 && counts[c][p] == 1 {
+				t.hasUniquePieces = true
+			}
+		}
+	}
+	if t.pieceCount > tbPieces {
+		return nil, false
+	}
+	white, black := counts[0][0], counts[1][0]
+	t.hasPawns = white+black > 0
+	// The leading colour is the one with fewer pawns, white on ties.
+	if black == 0 || (white > 0 && black >= white) {
+		t.pawnCount = [2]int{white, black}
+	} else {
+		t.pawnCount = [2]int{black, white}
+	}
+	return t, true
+}
+
+// load reads and parses the file on first use.
+func (t *tbTable) load() error {
+	t.once.Do(func() {
+		data, err := os.ReadFile(t.path)
+		if err != nil {
+			t.err = err
+			return
+		}
+		t.err = t.init(data)
+	})
+	return t.err
+}
+
+func (t *tbTable) sides() int {
+	if t.isWDL && t.key != t.key2 {
+		return 2
+	}
+	return 1
+}
+
+func (t *tbTable) get(stm, file int) *pairsData {
+	if !t.hasPawns {
+		file = 0
+	}
+	return &t.items[stm%t.sides()][file]
+}
+
+// init parses the header, piece orders, Huffman tables and section offsets.
+func (t *tbTable) init(data []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %s: truncated", ErrInvalidTablebase, t.path)
+		}
+	}()
+	magic := dtzMagic
+	if t.isWDL {
+		magic = wdlMagic
+	}
+	if len(data) < 5 || !bytes.Equal(data[:4], magic) {
+		return fmt.Errorf("%w: %s: bad magic", ErrInvalidTablebase, t.path)
+	}
+	if (data[4]&2 != 0) != t.hasPawns {
+		return fmt.Errorf("%w: %s: pawn flag does not match file name", ErrInvalidTablebase, t.path)
+	}
+	t.data = data
+	pos := 5
+
+	sides := t.sides()
+	maxFile := 0
+	if t.hasPawns {
+		maxFile = 3
+	}
+	pp := t.hasPawns && t.pawnCount[1] > 0 // pawns on both sides
+
+	for f := 0; f <= maxFile; f++ {
+		order := [2][2]int{{int(data[pos] & 0xF), 0xF}, {int(data[pos] >> 4), 0xF}}
+		if pp {
+			order[0][1], order[1][1] = int(data[pos+1]&0xF), int(data[pos+1]>>4)
+			pos++
+		}
+		pos++
+		for k := 0; k < t.pieceCount; k++ {
+			t.items[0][f].pieces[k] = data[pos] & 0xF
+			t.items[1][f].pieces[k] = data[pos] >> 4
+			pos++
+		}
+		for i := 0; i < sides; i++ {
+			t.setGroups(&t.items[i][f], order[i], f)
+		}
+	}
+	pos += pos & 1
+
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			pos = t.items[i][f].setSizes(data, pos)
+		}
+	}
+	if !t.isWDL {
+		pos = t.setDTZMap(data, pos, maxFile)
+	}
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := &t.items[i][f]
+			d.sparseIndex = pos
+			pos += int(d.sparseIndexSize) * 6
+		}
+	}
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := &t.items[i][f]
+			d.blockLength = pos
+			pos += int(d.blockLengthSize) * 2
+		}
+	}
+	for f := 0; f <= maxFile; f++ {
+		for i := 0; i < sides; i++ {
+			d := &t.items[i][f]
+			pos = (pos + 0x3F) &^ 0x3F
+			d.blocks = pos
+			pos += int(d.blocksNum * d.sizeofBlock)
+			if d.blocksNum > 0 && pos > len(data) {
+				return fmt.Errorf("%w: %s: truncated", ErrInvalidTablebase, t.path)
+			}
+		}
+	}
+	return nil
+}
+
+// setGroups splits the piece sequence into encoding groups and computes the
+// multiplier of each group in the position index.
+func (t *tbTable) setGroups(d *pairsData, order [2]int, file int) {
+	n := 0
+	firstLen := 2
+	switch {
+	case t.hasPawns:
+		firstLen = 0
+	case t.hasUniquePieces:
+		firstLen = 3
+	}
+	d.groupLen[0] = 1
+	for i := 1; i < t.pieceCount; i++ {
+		firstLen--
+		if firstLen > 0 || d.pieces[i] == d.pieces[i-1] {
+			d.groupLen[n]++
+		} else {
+			n++
+			d.groupLen[n] = 1
+		}
+	}
+	n++
+	d.groupLen[n] = 0
+
+	pp := t.hasPawns && t.pawnCount[1] > 0
+	next := 1
+	free := 64 - d.groupLen[0]
+	if pp {
+		next = 2
+		free -= d.groupLen[1]
+	}
+	idx := uint64(1)
+	for k := 0; next < n || k == order[0] || k == order[1]; k++ {
+		switch {
+		case k == order[0]: // leading pawns or pieces
+			d.groupIdx[0] = idx
+			switch {
+			case t.hasPawns:
+				idx *= uint64(leadPawnsSize[d.groupLen[0]][file])
+			case t.hasUniquePieces:
+				idx *= 31332
+			default:
+				idx *= 462
+			}
+		case k == order[1]: // remaining pawns
+			d.groupIdx[1] = idx
+			idx *= binomial[d.groupLen[1]][48-d.groupLen[0]]
+		default: // remaining pieces
+			d.groupIdx[next] = idx
+			idx *= binomial[d.groupLen[next]][free]
+			free -= d.groupLen[next]
+			next++
+		}
+	}
+	d.groupIdx[n] = idx
+}
+
+// setSizes reads the block geometry and the canonical Huffman code of d.
+func (d *pairsData) setSizes(data []byte, pos int) int {
+	d.flags = data[pos]
+	pos++
+	if d.flags&tbSingleValue != 0 {
+		d.minSymLen = int(data[pos])
+		return pos + 1
+	}
+
+	n := 0
+	for d.groupLen[n] != 0 {
+		n++
+	}
+	tbSize := d.groupIdx[n]
+
+	d.sizeofBlock = 1 << data[pos]
+	d.span = 1 << data[pos+1]
+	d.sparseIndexSize = (tbSize + d.span - 1) / d.span
+	padding := uint64(data[pos+2])
+	d.blocksNum = uint64(binary.LittleEndian.Uint32(data[pos+3:]))
+	d.blockLengthSize = d.blocksNum + padding
+	d.maxSymLen = int(data[pos+7])
+	d.minSymLen = int(data[pos+8])
+	pos += 9
+	d.lowestSym = pos
+
+	sym := func(i int) uint64 { return uint64(binary.LittleEndian.Uint16(data[d.lowestSym+2*i:])) }
+	d.base64 = make([]uint64, d.maxSymLen-d.minSymLen+1)
+	for i := len(d.base64) - 2; i >= 0; i-- {
+		d.base64[i] = (d.base64[i+1] + sym(i) - sym(i+1)) / 2
+	}
+	for i := range d.base64 {
+		d.base64[i] <<= uint(64 - i - d.minSymLen)
+	}
+	pos += len(d.base64) * 2
+
+	d.symlen = make([]uint8, binary.LittleEndian.Uint16(data[pos:]))
+	pos += 2
+	d.btree = pos
+	visited := make([]bool, len(d.symlen))
+	for s := range d.symlen {
+		if !visited[s] {
+			d.symlen[s] = d.setSymlen(data, s, visited)
+		}
+	}
+	return pos + len(d.symlen)*3 + len(d.symlen)&1
+}
+
+// left and right return the children of a pair symbol; a leaf stores its value in left.
+func (d *pairsData) left(data []byte, s int) int {
+	p := d.btree + 3*s
+	return int(data[p+1]&0xF)<<8 | int(data[p])
+}
+
+func (d *pairsData) right(data []byte, s int) int {
+	p := d.btree + 3*s
+	return int(data[p+2])<<4 | int(data[p+1]>>4)
+}
+
+// setSymlen computes how many values, minus one, symbol s expands to.
+func (d *pairsData) setSymlen(data []byte, s int, visited []bool) uint8 {
+	visited[s] = true
+	sr := d.right(data, s)
+	if sr == 0xFFF {
+		return 0
+	}
+	sl := d.left(data, s)
+	if !visited[sl] {
+		d.symlen[sl] = d.setSymlen(data, sl, visited)
+	}
+	if !visited[sr] {
+		d.symlen[sr] = d.setSymlen(data, sr, visited)
+	}
+	return d.symlen[sl] + d.symlen[sr] + 1
+}
+
+// setDTZMap records where each file's DTZ value map starts.
+func (t *tbTable) setDTZMap(data []byte, pos, maxFile int) int {
+	t.dtzMap = pos
+	for f := 0; f <= maxFile; f++ {
+		d := t.get(0, f)
+		if d.flags&tbMapped == 0 {
+			continue
+		}
+		if d.flags&tbWide != 0 {
+			pos += pos & 1
+			for i := 0; i < 4; i++ {
+				d.mapIdx[i] = (pos-t.dtzMap)/2 + 1
+				pos += 2*int(binary.LittleEndian.Uint16(data[pos:])) + 2
+			}
+		} else {
+			for i := 0; i < 4; i++ {
+				d.mapIdx[i] = pos - t.dtzMap + 1
+				pos += int(data[pos]) + 1
+			}
+		}
+	}
+	return pos + pos&1
+}
+
+// decompress returns the value stored at index idx of d.
+func (t *tbTable) decompress(d *pairsData, idx uint64) int {
+	data := t.data
+	if d.flags&tbSingleValue != 0 {
+		return d.minSymLen
+	}
+
+	// Find the block holding idx, starting from the nearest sparse index entry.
+	k := idx / d.span
+	entry := d.sparseIndex + 6*int(k)
+	block := int(binary.LittleEndian.Uint32(data[entry:]))
+	offset := int(binary.LittleEndian.Uint16(data[entry+4:]))
+	offset += int(idx%d.span) - int(d.span/2)
+
+	blockLen := func(b int) int { return int(binary.LittleEndian.Uint16(data[d.blockLength+2*b:])) }
+	for offset < 0 {
+		block--
+		offset += blockLen(block) + 1
+	}
+	for offset > blockLen(block) {
+		offset -= blockLen(block) + 1
+		block++
+	}
+
+	// Walk the Huffman-coded symbols of the block until the one covering offset.
+	ptr := d.blocks + block*int(d.sizeofBlock)
+	buf := binary.BigEndian.Uint64(data[ptr:])
+	ptr += 8
+	bufSize := 64
+	var sym int
+	for {
+		l := 0
+		for buf < d.base64[l] {
+			l++
+		}
+		sym = int((buf - d.base64[l]) >> uint(64-l-d.minSymLen))
+		sym += int(binary.LittleEndian.Uint16(data[d.lowestSym+2*l:]))
+		if offset < int(d.symlen[sym])+1 {
+			break
+		}
+		offset -= int(d.symlen[sym]) + 1
+		l += d.minSymLen
+		buf <<= uint(l)
+		bufSize -= l
+		if bufSize <= 32 {
+			bufSize += 32
+			var next uint32
+			if ptr+4 <= len(data) {
+				next = binary.BigEndian.Uint32(data[ptr:])
+			}
+			buf |= uint64(next) << uint(64-bufSize)
+			ptr += 4
+		}
+	}
+
+	// Expand the pair symbol down to the leaf holding our value.
+	for d.symlen[sym] != 0 {
+		left := d.left(data, sym)
+		if offset < int(d.symlen[left])+1 {
+			sym = left
+		} else {
+			offset -= int(d.symlen[left]) + 1
+			sym = d.right(data, sym)
+		}
+	}
+	return d.left(data, sym)
+}
+
+// probe computes the index of s in the table and returns the stored value:
+// a WDL value for .rtbw files, a DTZ in plies for .rtbz files.
+// key is the material key of s, which is either t.key or t.key2.
+func (t *tbTable) probe(s GameState, key uint64, wdl WDL) (int, probeState) {
+	d, file, idx, state := t.index(s, key)
+	if state != probeOK {
+		return 0, state
+	}
+	value := t.decompress(d, idx)
+	if t.isWDL {
+		return value - 2, probeOK
+	}
+	return t.mapScore(file, value, wdl), probeOK
+}
+
+// index returns the sub-table holding s, its leading pawn file and the index
+// of s within it. A DTZ table that stores the other side to move reports
+// probeChangeSTM instead.
+func (t *tbTable) index(s GameState, key uint64) (*pairsData, int, uint64, probeState) {
+	var (
+		squares  [tbPieces]int
+		pieces   [tbPieces]byte
+		size     int
+		leadCnt  int
+		leadMask uint64
+		tbFile   int
+	)
+
+	// Tables are stored with the side named first as white; flip colours and ranks
+	// when black holds that material, or for symmetric tables with black to move.
+	flip := key != t.key || (t.key == t.key2 && s.ActiveColor == Black)
+	flipColor, flipSquares := byte(0), 0
+	stm := int(s.ActiveColor)
+	if flip {
+		flipColor, flipSquares = 8, 56
+		stm ^= 1
+	}
+
+	if t.hasPawns {
+		// The first piece of the sequence is a pawn of the leading colour.
+		pawn := WhitePawn
+		if (t.items[0][0].pieces[0]^flipColor)>>3 == 1 {
+			pawn = BlackPawn
+		}
+		for sq := 0; sq < 64; sq++ {
+			if s.Board[sq] == pawn {
+				leadMask |= 1 << sq
+				squares[size] = sq ^ flipSquares
+				size++
+			}
+		}
+		leadCnt = size
+		lead := 0
+		for i := 1; i < leadCnt; i++ {
+			if mapPawns[squares[i]] > mapPawns[squares[lead]] {
+				lead = i
+			}
+		}
+		squares[0], squares[lead] = squares[lead], squares[0]
+		tbFile = squares[0] & 7
+		if tbFile > 3 {
+			tbFile = 7 - tbFile
+		}
+	}
+
+	if !t.isWDL {
+		if d := t.get(0, tbFile); int(d.flags&tbSTM) != stm && (t.key != t.key2 || t.hasPawns) {
+			return nil, 0, 0, probeChangeSTM
+		}
+	}
+
+	for sq := 0; sq < 64; sq++ {
+		if p := s.Board[sq]; p != NoPiece && leadMask&(1<<sq) == 0 {
+			squares[size] = sq ^ flipSquares
+			pieces[size] = syzygyPiece(p) ^ flipColor
+			size++
+		}
+	}
+
+	// Reorder the pieces into the sequence the table was encoded with.
+	d := t.get(stm, tbFile)
+	for i := leadCnt; i < size-1; i++ {
+		for j := i + 1; j < size; j++ {
+			if d.pieces[i] == pieces[j] {
+				pieces[i], pieces[j] = pieces[j], pieces[i]
+				squares[i], squares[j] = squares[j], squares[i]
+				break
+			}
+		}
+	}
+
+	// Mirror so that the leading piece is on files a-d.
+	if squares[0]&7 > 3 {
+		for i := 0; i < size; i++ {
+			squares[i] ^= 7
+		}
+	}
+
+	var idx uint64
+	if t.hasPawns {
+		idx = uint64(leadPawnIdx[leadCnt][squares[0]])
+		rest := squares[1:leadCnt]
+		sort.SliceStable(rest, func(i, j int) bool { return mapPawns[rest[i]] < mapPawns[rest[j]] })
+		for i := 1; i < leadCnt; i++ {
+			idx += binomial[i][mapPawns[squares[i]]]
+		}
+	} else {
+		idx = t.encodePieces(squares[:size], d.groupLen[0])
+	}
+
+	// Encode the remaining groups, each as a combination of its squares.
+	idx *= d.groupIdx[0]
+	start := d.groupLen[0]
+	remainingPawns := t.hasPawns && t.pawnCount[1] > 0
+	for next := 1; d.groupLen[next] != 0; next++ {
+		group := squares[start : start+d.groupLen[next]]
+		sort.Ints(group)
+		var n uint64
+		for i, sq := range group {
+			adjust := 0
+			for _, prev := range squares[:start] {
+				if sq > prev {
+					adjust++
+				}
+			}
+			if remainingPawns {
+				adjust += 8
+			}
+			n += binomial[i+1][sq-adjust]
+		}
+		remainingPawns = false
+		idx += n * d.groupIdx[next]
+		start += d.groupLen[next]
+	}
+	return d, tbFile, idx, probeOK
+}
+
+// encodePieces maps the leading group of a pawnless table to its index, after
+// mirroring the board into the a1-d1-d4 triangle.
+func (t *tbTable) encodePieces(sq []int, leadLen int) uint64 {
+	if sq[0]>>3 > 3 {
+		for i := range sq {
+			sq[i] ^= 56
+		}
+	}
+	for i := 0; i < leadLen; i++ {
+		o := offA1H8(sq[i])
+		if o == 0 {
+			continue
+		}
+		if o > 0 { // mirror in the a1-h8 diagonal
+			for j := i; j < len(sq); j++ {
+				sq[j] = ((sq[j] >> 3) | (sq[j] << 3)) & 63
+			}
+		}
+		break
+	}
+
+	if !t.hasUniquePieces {
+		return uint64(mapKK[mapA1D1D4[sq[0]]][sq[1]])
+	}
+	adjust1 := b2i(sq[1] > sq[0])
+	adjust2 := b2i(sq[2] > sq[0]) + b2i(sq[2] > sq[1])
+	var idx int
+	switch {
+	case offA1H8(sq[0]) != 0:
+		idx = (mapA1D1D4[sq[0]]*63+(sq[1]-adjust1))*62 + sq[2] - adjust2
+	case offA1H8(sq[1]) != 0:
+		idx = (6*63+(sq[0]>>3)*28+mapB1H1H7[sq[1]])*62 + sq[2] - adjust2
+	case offA1H8(sq[2]) != 0:
+		idx = 6*63*62 + 4*28*62 + (sq[0]>>3)*7*28 + ((sq[1]>>3)-adjust1)*28 + mapB1H1H7[sq[2]]
+	default:
+		idx = 6*63*62 + 4*28*62 + 4*7*28 + (sq[0]>>3)*7*6 + ((sq[1]>>3)-adjust1)*6 + (sq[2] >> 3) - adjust2
+	}
+	return uint64(idx)
+}
+
+// mapScore converts a stored DTZ value to plies.
+func (t *tbTable) mapScore(file, value int, wdl WDL) int {
+	d := t.get(0, file)
+	if d.flags&tbMapped != 0 {
+		i := d.mapIdx[[5]int{1, 3, 0, 2, 0}[wdl+2]] + value
+		if d.flags&tbWide != 0 {
+			value = int(binary.LittleEndian.Uint16(t.data[t.dtzMap+2*i:]))
+		} else {
+			value = int(t.data[t.dtzMap+i])
+		}
+	}
+	if (wdl == WDLWin && d.flags&tbWinPlies == 0) ||
+		(wdl == WDLLoss && d.flags&tbLossPlies == 0) ||
+		wdl == WDLCursedWin || wdl == WDLBlessedLoss {
+		value *= 2
+	}
+	return value + 1
+}
+
+// syzygyPiece converts a Piece to the table encoding: white 1-6, black 9-14.
+func syzygyPiece(p Piece) byte {
+	if p >= BlackPawn {
+		return byte(p) + 2
+	}
+	return byte(p)
+}
+
+// offA1H8 is positive above the a1-h8 diagonal, negative below it and zero on it.
+func offA1H8(sq int) int { return sq>>3 - sq&7 }
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Index lookup tables, computed once at start-up.
+var (
+	mapB1H1H7     [64]int
+	mapA1D1D4     [64]int
+	mapKK         [10][64]int
+	mapPawns      [64]int
+	binomial      [tbPieces][64]uint64
+	leadPawnIdx   [6][64]int
+	leadPawnsSize [6][4]int
+)
+
+func init() {
+	code := 0
+	for sq := 0; sq < 64; sq++ {
+		if offA1H8(sq) < 0 {
+			mapB1H1H7[sq] = code
+			code++
+		}
+	}
+
+	// The a1-d1-d4 triangle: squares below the diagonal first, then the diagonal.
+	var diagonal []int
+	code = 0
+	for sq := 0; sq <= 27; sq++ {
+		switch {
+		case offA1H8(sq) < 0 && sq&7 <= 3:
+			mapA1D1D4[sq] = code
+			code++
+		case offA1H8(sq) == 0 && sq&7 <= 3:
+			diagonal = append(diagonal, sq)
+		}
+	}
+	for _, sq := range diagonal {
+		mapA1D1D4[sq] = code
+		code++
+	}
+
+	// The 462 legal placements of two kings with the first in the triangle; if the
+	// first is on the diagonal the second may not be above it.
+	type kk struct{ idx, sq int }
+	var bothOnDiagonal []kk
+	code = 0
+	for idx := 0; idx < 10; idx++ {
+		for s1 := 0; s1 <= 27; s1++ {
+			if mapA1D1D4[s1] != idx || (idx == 0 && s1 != int(B1)) {
+				continue
+			}
+			for s2 := 0; s2 < 64; s2++ {
+				df, dr := s1&7-s2&7, s1>>3-s2>>3
+				switch {
+				case df >= -1 && df <= 1 && dr >= -1 && dr <= 1:
+					continue // kings touching or on the same square
+				case offA1H8(s1) == 0 && offA1H8(s2) > 0:
+					continue
+				case offA1H8(s1) == 0 && offA1H8(s2) == 0:
+					bothOnDiagonal = append(bothOnDiagonal, kk{idx, s2})
+				default:
+					mapKK[idx][s2] = code
+					code++
+				}
+			}
+		}
+	}
+	for _, p := range bothOnDiagonal {
+		mapKK[p.idx][p.sq] = code
+		code++
+	}
+
+	binomial[0][0] = 1
+	for n := 1; n < 64; n++ {
+		for k := 0; k < tbPieces && k <= n; k++ {
+			if k > 0 {
+				binomial[k][n] += binomial[k-1][n-1]
+			}
+			if k < n {
+				binomial[k][n] += binomial[k][n-1]
+			}
+		}
+	}
+
+	// mapPawns numbers a2-h7 so that the leading pawn (nearest the edge, then
+	// lowest rank) has the highest value; leadPawnIdx is cumulative per file.
+	available := 47
+	for cnt := 1; cnt <= 5; cnt++ {
+		for f := 0; f < 4; f++ {
+			idx := 0
+			for r := 1; r <= 6; r++ {
+				sq := r*8 + f
+				if cnt == 1 {
+					mapPawns[sq] = available
+					available--
+					mapPawns[sq^7] = available
+					available--
+				}
+				leadPawnIdx[cnt][sq] = idx
+				idx += int(binomial[cnt-1][mapPawns[sq]])
+			}
+			leadPawnsSize[cnt][f] = idx
+		}
+	}
+}
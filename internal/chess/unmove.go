@@ -0,0 +1,97 @@
+package chess
+
+// Unmoves returns the quiet moves that could have led to s: moves by the side
+// not to move that neither captured nor promoted, nor castled. Each is returned
+// in forward form (From is where the piece came from); Unplay retracts it.
+// Retractions that would leave the side to move in s in check beforehand are
+// excluded, since that side could not have been in check with the other side to move.
+func (s GameState) Unmoves() []Move {
+	prev := White
+	if s.ActiveColor == White {
+		prev = Black
+	}
+	var moves []Move
+	for sq := Square(0); sq < 64; sq++ {
+		p := s.Board[sq]
+		if p == NoPiece || pieceColor(p) != prev {
+			continue
+		}
+		var from []Square
+		switch p {
+		case WhitePawn:
+			if sq.Rank() >= 2 && s.Board[sq-8] == NoPiece {
+				from = append(from, sq-8)
+				if sq.Rank() == 3 && s.Board[sq-16] == NoPiece {
+					from = append(from, sq-16)
+				}
+			}
+		case BlackPawn:
+			if sq.Rank() <= 5 && s.Board[sq+8] == NoPiece {
+				from = append(from, sq+8)
+				if sq.Rank() == 4 && s.Board[sq+16] == NoPiece {
+					from = append(from, sq+16)
+				}
+			}
+		case WhiteKnight, BlackKnight:
+			from = retractSteps(s, sq, [][2]int{{-2, -1}, {-2, 1}, {-1, -2}, {-1, 2}, {1, -2}, {1, 2}, {2, -1}, {2, 1}}, false)
+		case WhiteBishop, BlackBishop:
+			from = retractSteps(s, sq, [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}, true)
+		case WhiteRook, BlackRook:
+			from = retractSteps(s, sq, [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}}, true)
+		case WhiteQueen, BlackQueen:
+			from = retractSteps(s, sq, [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}, true)
+		case WhiteKing, BlackKing:
+			from = retractSteps(s, sq, [][2]int{{0, 1}, {0, -1}, {1, 0}, {-1, 0}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}, false)
+		}
+		for _, f := range from {
+			m := Move{From: f, To: sq}
+			if !isInCheck(s.Unplay(m), s.ActiveColor) {
+				moves = append(moves, m)
+			}
+		}
+	}
+	return moves
+}
+
+// Unplay returns the position before the quiet move m led to s. En passant
+// rights of the earlier position are unknown and left empty.
+func (s GameState) Unplay(m Move) GameState {
+	ns := s
+	ns.Board[m.From] = ns.Board[m.To]
+	ns.Board[m.To] = NoPiece
+	ns.EnPassantSq = NoSquare
+	if ns.HalfMoveClock > 0 {
+		ns.HalfMoveClock--
+	}
+	if s.ActiveColor == White {
+		ns.ActiveColor = Black
+		if ns.FullMoveNumber > 1 {
+			ns.FullMoveNumber--
+		}
+	} else {
+		ns.ActiveColor = White
+	}
+	return ns
+}
+
+// retractSteps returns the empty squares a piece on sq could have come from,
+// stepping once along each direction or, for sliders, along each ray.
+func retractSteps(s GameState, sq Square, dirs [][2]int, slide bool) []Square {
+	var from []Square
+	for _, d := range dirs {
+		r, f := sq.Rank()+d[0], sq.File()+d[1]
+		for r >= 0 && r <= 7 && f >= 0 && f <= 7 {
+			to := SquareOf(f, r)
+			if s.Board[to] != NoPiece {
+				break
+			}
+			from = append(from, to)
+			if !slide {
+				break
+			}
+			r += d[0]
+			f += d[1]
+		}
+	}
+	return from
+}
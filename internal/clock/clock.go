@@ -0,0 +1,306 @@
+// Package clock implements chess clocks for the front-ends: Fischer
+// increment, simple and Bronstein delay, and multi-stage time controls such
+// as 40 moves in 90 minutes followed by 30 minutes for the rest of the game.
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ErrInvalidControl is returned by Parse.
+var ErrInvalidControl = errors.New("invalid time control")
+
+// Stage is one period of a time control.
+type Stage struct {
+	Moves     int           // moves each side makes in the stage; 0 = the rest of the game
+	Time      time.Duration // time each side is given at the start of the stage
+	Increment time.Duration // Fischer increment: added after every move
+	Delay     time.Duration // time each move may take before it is charged to the clock
+	Bronstein bool          // the delay is Bronstein: the time used, up to Delay, is added back after the move
+}
+
+// Control is a time control: its stages in the order they are played. The
+// last stage repeats when it has a move count. A nil Control is no clock.
+type Control []Stage
+
+// Parse reads a time control of comma-separated stages, each
+// "[moves/]minutes" followed by "+seconds" of Fischer increment, "dseconds"
+// of simple delay or "bseconds" of Bronstein delay: "5+3", "15d5",
+// "40/90+30,30+30".
+func Parse(s string) (Control, error) {
+	var c Control
+	for _, part := range strings.Split(s, ",") {
+		st, err := parseStage(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidControl, s)
+		}
+		c = append(c, st)
+	}
+	for _, st := range c[:len(c)-1] {
+		if st.Moves == 0 {
+			return nil, fmt.Errorf("%w: %q: only the last stage may be for the rest of the game", ErrInvalidControl, s)
+		}
+	}
+	return c, nil
+}
+
+// parseStage reads one stage of Parse.
+func parseStage(s string) (Stage, error) {
+	var st Stage
+	if moves, rest, ok := strings.Cut(s, "/"); ok {
+		n, err := strconv.Atoi(moves)
+		if err != nil || n <= 0 {
+			return st, ErrInvalidControl
+		}
+		st.Moves, s = n, rest
+	}
+	base, bonus := s, ""
+	if i := strings.IndexAny(s, "+db"); i >= 0 {
+		base, bonus = s[:i], s[i:]
+	}
+	minutes, err := strconv.ParseFloat(base, 64)
+	if err != nil || minutes <= 0 {
+		return st, ErrInvalidControl
+	}
+	st.Time = time.Duration(minutes * float64(time.Minute))
+	if bonus == "" {
+		return st, nil
+	}
+	seconds, err := strconv.ParseFloat(bonus[1:], 64)
+	if err != nil || seconds < 0 {
+		return st, ErrInvalidControl
+	}
+	d := time.Duration(seconds * float64(time.Second))
+	switch bonus[0] {
+	case '+':
+		st.Increment = d
+	case 'd':
+		st.Delay = d
+	case 'b':
+		st.Delay, st.Bronstein = d, true
+	}
+	return st, nil
+}
+
+// String formats the control as Parse reads it.
+func (c Control) String() string {
+	parts := make([]string, len(c))
+	for i, st := range c {
+		s := strconv.FormatFloat(st.Time.Minutes(), 'f', -1, 64)
+		if st.Moves > 0 {
+			s = strconv.Itoa(st.Moves) + "/" + s
+		}
+		seconds := func(d time.Duration) string { return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) }
+		switch {
+		case st.Delay > 0 && st.Bronstein:
+			s += "b" + seconds(st.Delay)
+		case st.Delay > 0:
+			s += "d" + seconds(st.Delay)
+		case st.Increment > 0:
+			s += "+" + seconds(st.Increment)
+		}
+		parts[i] = s
+	}
+	return strings.Join(parts, ",")
+}
+
+// Clock is a two-sided chess clock. At most one side's time runs at once.
+// A Clock is not safe for concurrent use.
+type Clock struct {
+	control   Control
+	now       func() time.Time
+	remaining [2]time.Duration
+	stage     [2]int // index of each side's current stage
+	moves     [2]int // moves each side has made in its current stage
+	running   bool
+	side      chess.Color // the side whose time runs, when running
+	started   time.Time   // when side's time started to run
+}
+
+// New returns a stopped clock for control, both sides holding the time of
+// the first stage. Time is read from now, or the wall clock if now is nil.
+func New(control Control, now func() time.Time) *Clock {
+	if now == nil {
+		now = time.Now
+	}
+	c := &Clock{control: control, now: now}
+	if len(control) > 0 {
+		c.remaining = [2]time.Duration{control[0].Time, control[0].Time}
+	}
+	return c
+}
+
+// Control returns the clock's time control.
+func (c *Clock) Control() Control { return c.control }
+
+// Start starts side's time, stopping the other side's.
+func (c *Clock) Start(side chess.Color) {
+	c.Stop()
+	c.running, c.side, c.started = true, side, c.now()
+}
+
+// Stop stops the running side's time, charging it for the time used.
+func (c *Clock) Stop() {
+	if c.running {
+		c.remaining[c.side] -= c.charge(c.now().Sub(c.started))
+		c.running = false
+	}
+}
+
+// Press ends the move of the running side: it is charged for the time used
+// and credited its increment or Bronstein delay, enters the next stage once
+// it has made the stage's moves, and the other side's time starts. Press
+// reports false, leaving the clock stopped, when the running side's time ran
+// out before the move was made.
+func (c *Clock) Press() bool {
+	if !c.running {
+		return true
+	}
+	side, used := c.side, c.now().Sub(c.started)
+	c.Stop()
+	if c.remaining[side] <= 0 {
+		return false
+	}
+	st := c.current(side)
+	c.remaining[side] += st.Increment
+	if st.Bronstein {
+		c.remaining[side] += min(used, st.Delay)
+	}
+	c.moves[side]++
+	if st.Moves > 0 && c.moves[side] == st.Moves {
+		if c.stage[side] < len(c.control)-1 {
+			c.stage[side]++
+		}
+		c.moves[side] = 0
+		c.remaining[side] += c.current(side).Time
+	}
+	c.Start(1 - side)
+	return true
+}
+
+// Remaining returns side's time left, counting the time of a running move.
+// It is negative once the side's flag has fallen.
+func (c *Clock) Remaining(side chess.Color) time.Duration {
+	r := c.remaining[side]
+	if c.running && c.side == side {
+		r -= c.charge(c.now().Sub(c.started))
+	}
+	return r
+}
+
+// Running returns the side whose time runs, if either's does.
+func (c *Clock) Running() (chess.Color, bool) { return c.side, c.running }
+
+// Flagged returns the side whose time has run out, if one has.
+func (c *Clock) Flagged() (chess.Color, bool) {
+	for _, side := range []chess.Color{chess.White, chess.Black} {
+		if len(c.control) > 0 && c.Remaining(side) <= 0 {
+			return side, true
+		}
+	}
+	return chess.White, false
+}
+
+// UntilFlag returns how long the running side has until its flag falls, with
+// any delay still to run; 0 when the clock is stopped.
+func (c *Clock) UntilFlag() time.Duration {
+	if !c.running {
+		return 0
+	}
+	left := c.remaining[c.side] - c.now().Sub(c.started)
+	if st := c.current(c.side); !st.Bronstein {
+		left += st.Delay
+	}
+	return max(left, 0)
+}
+
+// MovesToGo returns the moves side has to make before its next stage, or 0
+// in a stage for the rest of the game.
+func (c *Clock) MovesToGo(side chess.Color) int {
+	if st := c.current(side); st.Moves > 0 {
+		return st.Moves - c.moves[side]
+	}
+	return 0
+}
+
+// TimeControl returns the clock as an engine time control for the move of
+// side. The engine has no notion of delay, so a delay is given as an
+// increment: like one, it is time a move may spend without losing any.
+func (c *Clock) TimeControl(side chess.Color) engine.TimeControl {
+	bonus := func(s chess.Color) time.Duration {
+		st := c.current(s)
+		return st.Increment + st.Delay
+	}
+	return engine.TimeControl{
+		WTime:     max(c.Remaining(chess.White), time.Millisecond),
+		BTime:     max(c.Remaining(chess.Black), time.Millisecond),
+		WInc:      bonus(chess.White),
+		BInc:      bonus(chess.Black),
+		MovesToGo: c.MovesToGo(side),
+	}
+}
+
+// current returns side's current stage.
+func (c *Clock) current(side chess.Color) Stage {
+	if len(c.control) == 0 {
+		return Stage{}
+	}
+	return c.control[c.stage[side]]
+}
+
+// charge returns the time charged to the running side for a move that has
+// taken used: all of it, less a simple delay.
+func (c *Clock) charge(used time.Duration) time.Duration {
+	if st := c.current(c.side); !st.Bronstein {
+		return max(used-st.Delay, 0)
+	}
+	return used
+}
+
+// Timeout is the result of a game ended by a flag fall.
+type Timeout struct {
+	Flagged chess.Color // the side whose time ran out
+	Draw    bool        // the opponent could not have mated, so the game is drawn
+}
+
+// TimeoutIn returns the result of g when flagged's time runs out: a loss,
+// unless the opponent has no mating material.
+func TimeoutIn(g chess.Game, flagged chess.Color) Timeout {
+	return Timeout{Flagged: flagged, Draw: !g.HasMatingMaterial(1 - flagged)}
+}
+
+// Score returns the result in PGN notation: "1-0", "0-1" or "1/2-1/2".
+func (t Timeout) Score() string {
+	switch {
+	case t.Draw:
+		return "1/2-1/2"
+	case t.Flagged == chess.White:
+		return "0-1"
+	}
+	return "1-0"
+}
+
+// Format formats remaining time for display as h:mm:ss, m:ss, or under
+// twenty seconds as 0:ss.t with tenths. It rounds up, so that a clock shows
+// zero only once its flag has fallen.
+func Format(d time.Duration) string {
+	if d <= 0 {
+		return "0:00"
+	}
+	if d <= 20*time.Second {
+		tenths := int((d + 100*time.Millisecond - 1) / (100 * time.Millisecond))
+		return fmt.Sprintf("0:%02d.%d", tenths/10, tenths%10)
+	}
+	s := int((d + time.Second - 1) / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
@@ -0,0 +1,34 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"chess_go/internal/book"
+	chess "chess_go/internal/chess"
+)
+
+// WithBook returns a SearchFunc that plays a move from b when the position is in
+// the book and otherwise falls back to search. Book moves are reported with
+// Depth 0 and an "info string book move" line.
+func WithBook(b *book.Book, sel book.Selection, search SearchFunc) SearchFunc {
+	var mu sync.Mutex
+	//nolint:gosec
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	return func(ctx context.Context, g chess.Game, tc TimeControl, info io.Writer) SearchResult {
+		start := time.Now()
+		mu.Lock()
+		m, ok := b.Pick(g, sel, rng)
+		mu.Unlock()
+		if !ok {
+			return search(ctx, g, tc, info)
+		}
+		_, _ = fmt.Fprintf(info, "info string book move %s\n", m.UCIString())
+		return SearchResult{BestMove: m, Elapsed: time.Since(start)}
+	}
+}
@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	chess "chess_go/internal/chess"
+)
+
+// ErrEngine is returned when an external engine misbehaves: it fails to
+// start, answer the handshake, or reply with a move in time, or it exits.
+var ErrEngine = errors.New("external engine failure")
+
+// Engine is a chess engine playing one game at a time, whether the built-in
+// search (Builtin) or an external program (UCIEngine). Stop may be called from
+// any goroutine; the other methods must not be called concurrently.
+type Engine interface {
+	// Name identifies the engine, e.g. in PGN tags.
+	Name() string
+	// NewGame tells the engine that the next position is from another game.
+	NewGame(ctx context.Context) error
+	// SetPosition sets the position to search: start followed by moves.
+	SetPosition(start chess.Game, moves []chess.Move) error
+	// Search searches the position under tc and returns its best move. Info,
+	// when not nil, receives the engine's progress, typically once per depth.
+	// The result's Score is from the side to move's view (see MateScore).
+	Search(ctx context.Context, tc TimeControl, info func(SearchResult)) (SearchResult, error)
+	// Stop ends a running search early; Search then returns its best move.
+	Stop()
+	// SetOption sets an engine option by its UCI name. Unknown names are
+	// reported with ErrUnknownOption.
+	SetOption(name, value string) error
+	// Close stops the engine and releases its resources.
+	Close() error
+}
+
+// Builtin is the Engine running this package's search in-process. It has the
+// options of the UCI front-end (OwnBook, SyzygyPath, EvalFile, Skill Level, ...).
+type Builtin struct {
+	settings *settings
+	game     chess.Game
+
+	mu   sync.Mutex
+	stop context.CancelFunc // cancels the running search
+}
+
+// NewBuiltin returns the built-in engine set up at the start position.
+func NewBuiltin() *Builtin {
+	g, _ := chess.NewGameFromFEN(chess.StartFEN)
+	return &Builtin{settings: newSettings(), game: g}
+}
+
+// Name returns "chess-go".
+func (b *Builtin) Name() string { return "chess-go" }
+
+// NewGame resets the position to the start position.
+func (b *Builtin) NewGame(context.Context) error {
+	b.game, _ = chess.NewGameFromFEN(chess.StartFEN)
+	return nil
+}
+
+// SetPosition replays moves from start.
+func (b *Builtin) SetPosition(start chess.Game, moves []chess.Move) error {
+	g, err := replay(start, moves)
+	if err != nil {
+		return err
+	}
+	b.game = g
+	return nil
+}
+
+// Search runs the search configured by the options. The Skill Level and
+// UCI_Elo options apply unless tc sets a strength of its own.
+func (b *Builtin) Search(ctx context.Context, tc TimeControl, info func(SearchResult)) (SearchResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	b.mu.Lock()
+	b.stop = cancel
+	b.mu.Unlock()
+	defer b.Stop()
+
+	if !tc.Strength.Limited() {
+		tc.Strength = b.settings.strength()
+	}
+	w := &infoWriter{game: b.game, info: info}
+	return b.settings.searchFunc(nil)(ctx, b.game, tc, w), nil
+}
+
+// Stop cancels the running search, if any.
+func (b *Builtin) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stop != nil {
+		b.stop()
+		b.stop = nil
+	}
+}
+
+// SetOption sets one of the options advertised by "chess-go uci".
+func (b *Builtin) SetOption(name, value string) error {
+	_, err := b.settings.options.Set(name, value)
+	return err
+}
+
+// Options returns the option registry.
+func (b *Builtin) Options() *Options { return &b.settings.options }
+
+// Close stops the running search.
+func (b *Builtin) Close() error {
+	b.Stop()
+	return nil
+}
+
+// replay applies moves to start.
+func replay(start chess.Game, moves []chess.Move) (chess.Game, error) {
+	g := start
+	for i, m := range moves {
+		next, err := g.Apply(m)
+		if err != nil {
+			return start, fmt.Errorf("move %d (%s): %w", i+1, m.UCIString(), err)
+		}
+		g = next
+	}
+	return g, nil
+}
+
+// infoWriter turns the UCI info lines written by a search into calls of info.
+type infoWriter struct {
+	game chess.Game
+	info func(SearchResult)
+	buf  []byte
+}
+
+func (w *infoWriter) Write(p []byte) (int, error) {
+	if w.info == nil {
+		return len(p), nil
+	}
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if f := strings.Fields(string(w.buf[:i])); len(f) > 0 && f[0] == "info" {
+			var res SearchResult
+			if parseInfo(w.game, f[1:], &res) {
+				w.info(res)
+			}
+		}
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// parseInfo records the depth, score, nodes, time and principal variation of
+// the fields of an info line, and reports whether it carried a score.
+func parseInfo(g chess.Game, f []string, res *SearchResult) bool {
+	scored := false
+	for i := 0; i+1 < len(f); i++ {
+		n, _ := strconv.ParseInt(f[i+1], 10, 64)
+		switch f[i] {
+		case "depth":
+			res.Depth = int(n)
+		case "multipv":
+			res.MultiPV = int(n)
+		case "nodes":
+			res.Nodes = n
+		case "time":
+			res.Elapsed = time.Duration(n) * time.Millisecond
+		case "score":
+			if i+2 >= len(f) {
+				return scored
+			}
+			n, _ := strconv.Atoi(f[i+2])
+			switch f[i+1] {
+			case "cp":
+				res.Score, scored = n, true
+			case "mate":
+				if n > 0 {
+					res.Score = MateScore - (2*n - 1)
+				} else {
+					res.Score = -MateScore - 2*n
+				}
+				scored = true
+			}
+			i += 2
+		case "pv":
+			res.PV = nil
+			for _, s := range f[i+1:] {
+				m, err := chess.ParseUCI(g, s)
+				if err != nil {
+					break
+				}
+				res.PV = append(res.PV, m)
+				if g, err = g.Apply(m); err != nil {
+					break
+				}
+			}
+			if len(res.PV) > 0 {
+				res.BestMove = res.PV[0]
+			}
+			return scored
+		}
+	}
+	return scored
+}
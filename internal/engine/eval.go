@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	chess "chess_go/internal/chess"
+)
+
+// ErrInvalidWeights is returned when an evaluation weight file cannot be read.
+var ErrInvalidWeights = errors.New("invalid evaluation weights")
+
+// Weights are the parameters of the hand-crafted evaluation, in centipawns.
+// Tables are indexed by piece type (pawn=0 ... king=5). Piece-square tables
+// are from White's point of view, a8 first (as the board is drawn), so a
+// white piece on square sq reads index sq^56. The king has a middlegame
+// table in PieceSquare and an endgame table in KingEndgame, blended by game
+// phase.
+type Weights struct {
+	Material    [6]int     `json:"material"`
+	PieceSquare [6][64]int `json:"pieceSquare"`
+	KingEndgame [64]int    `json:"kingEndgame"`
+}
+
+// Game phase weights per piece type; a full set of pieces totals maxPhase.
+var phaseWeights = [6]int{0, 1, 1, 2, 4, 0}
+
+const maxPhase = 24
+
+// pieceType returns 0 (pawn) ... 5 (king) and the colour of a non-empty piece.
+func pieceType(p chess.Piece) (int, chess.Color) {
+	if p >= chess.BlackPawn {
+		return int(p - chess.BlackPawn), chess.Black
+	}
+	return int(p - chess.WhitePawn), chess.White
+}
+
+// Evaluate returns the static evaluation of s with DefaultWeights.
+func Evaluate(s chess.GameState) int {
+	return DefaultWeights.Evaluate(s)
+}
+
+// Evaluate returns the static evaluation of s in centipawns from the point of
+// view of the side to move: material plus piece-square bonuses, with the king
+// table tapered from middlegame to endgame as pieces come off.
+func (w *Weights) Evaluate(s chess.GameState) int {
+	var score, kingMG, kingEG, phase int
+	for sq := 0; sq < 64; sq++ {
+		p := s.Board[sq]
+		if p == chess.NoPiece {
+			continue
+		}
+		pt, c := pieceType(p)
+		idx, sign := sq^56, 1
+		if c == chess.Black {
+			idx, sign = sq, -1
+		}
+		phase += phaseWeights[pt]
+		if pt == 5 {
+			kingMG += sign * w.PieceSquare[5][idx]
+			kingEG += sign * w.KingEndgame[idx]
+			continue
+		}
+		score += sign * (w.Material[pt] + w.PieceSquare[pt][idx])
+	}
+	phase = min(phase, maxPhase)
+	score += (kingMG*phase + kingEG*(maxPhase-phase)) / maxPhase
+	if s.ActiveColor == chess.Black {
+		return -score
+	}
+	return score
+}
+
+// LoadWeights reads a JSON weight file, as written by "chess-go tune".
+func LoadWeights(path string) (*Weights, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadWeights(f)
+}
+
+// ReadWeights decodes JSON weights. Every table must be complete; a file
+// that leaves entries out is rejected rather than padded with zeros.
+func ReadWeights(r io.Reader) (*Weights, error) {
+	var raw struct {
+		Material    []int   `json:"material"`
+		PieceSquare [][]int `json:"pieceSquare"`
+		KingEndgame []int   `json:"kingEndgame"`
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWeights, err)
+	}
+	var w Weights
+	if len(raw.Material) != len(w.Material) {
+		return nil, fmt.Errorf("%w: material has %d values, want %d", ErrInvalidWeights, len(raw.Material), len(w.Material))
+	}
+	copy(w.Material[:], raw.Material)
+	if len(raw.PieceSquare) != len(w.PieceSquare) {
+		return nil, fmt.Errorf("%w: pieceSquare has %d tables, want %d", ErrInvalidWeights, len(raw.PieceSquare), len(w.PieceSquare))
+	}
+	for pt, table := range raw.PieceSquare {
+		if len(table) != 64 {
+			return nil, fmt.Errorf("%w: pieceSquare table %d has %d values, want 64", ErrInvalidWeights, pt, len(table))
+		}
+		copy(w.PieceSquare[pt][:], table)
+	}
+	if len(raw.KingEndgame) != 64 {
+		return nil, fmt.Errorf("%w: kingEndgame has %d values, want 64", ErrInvalidWeights, len(raw.KingEndgame))
+	}
+	copy(w.KingEndgame[:], raw.KingEndgame)
+	return &w, nil
+}
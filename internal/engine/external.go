@@ -0,0 +1,455 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	chess "chess_go/internal/chess"
+)
+
+const (
+	// handshakeTimeout bounds the uci and isready exchanges.
+	handshakeTimeout = 10 * time.Second
+	// stopGrace is how long an engine may take to answer stop, or to exit
+	// after quit, before it is killed.
+	stopGrace = time.Second
+)
+
+// UCIEngine is an Engine running an external UCI program as a subprocess,
+// driven through its stdin and stdout.
+//
+// Options set with SetOption are checked against the options the program
+// advertised in the handshake and passed through to it. When the program
+// crashes or hangs it is killed, and the next call starts it again, repeats
+// the handshake and sets the options again, so one bad search costs a move
+// rather than the game.
+type UCIEngine struct {
+	path string
+	args []string
+
+	name       string
+	advertised *Options    // the options declared in the handshake
+	options    [][2]string // options set, replayed after a restart
+	start      chess.Game
+	moves      []chess.Move
+	proc       *uciProcess
+	restarts   int
+
+	mu   sync.Mutex
+	stop context.CancelFunc // cancels the running search
+}
+
+// uciProcess is one run of the external program.
+type uciProcess struct {
+	cmd    *exec.Cmd
+	in     io.WriteCloser
+	lines  chan string   // stdout, closed when the program closes it
+	exited chan struct{} // closed with lines
+}
+
+// StartUCIEngine starts the UCI program at path with args and completes the
+// uci handshake.
+func StartUCIEngine(path string, args ...string) (*UCIEngine, error) {
+	g, _ := chess.NewGameFromFEN(chess.StartFEN)
+	e := &UCIEngine{path: path, args: args, start: g}
+	if err := e.launch(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Name returns the name the program gave in "id name", or the base name of
+// its path.
+func (e *UCIEngine) Name() string {
+	if e.name != "" {
+		return e.name
+	}
+	return filepath.Base(e.path)
+}
+
+// Advertised returns the options the program declared in the handshake.
+func (e *UCIEngine) Advertised() []Option { return e.advertised.List() }
+
+// Restarts returns how many times the program has been started again after
+// crashing or hanging.
+func (e *UCIEngine) Restarts() int { return e.restarts }
+
+// NewGame sends ucinewgame and waits for the program to be ready.
+func (e *UCIEngine) NewGame(ctx context.Context) error {
+	if err := e.alive(); err != nil {
+		return err
+	}
+	if err := e.send("ucinewgame"); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+	return e.ready(ctx)
+}
+
+// SetPosition records the position; it is sent with the next search.
+func (e *UCIEngine) SetPosition(start chess.Game, moves []chess.Move) error {
+	if _, err := replay(start, moves); err != nil {
+		return err
+	}
+	e.start, e.moves = start, append([]chess.Move(nil), moves...)
+	return nil
+}
+
+// Search sends the position and a go command and waits for bestmove. When
+// ctx ends or Stop is called the program is told to stop. A program that
+// exits, or that does not answer stop within a second, or that overruns its
+// move time or clock by more than a second, is killed and ErrEngine returned.
+func (e *UCIEngine) Search(ctx context.Context, tc TimeControl, info func(SearchResult)) (SearchResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	e.mu.Lock()
+	e.stop = cancel
+	e.mu.Unlock()
+	defer e.Stop()
+
+	g, _ := replay(e.start, e.moves)
+	if err := e.alive(); err != nil {
+		return SearchResult{}, err
+	}
+	var sb strings.Builder
+	sb.WriteString("position fen " + e.start.ToFEN())
+	if len(e.moves) > 0 {
+		sb.WriteString(" moves")
+		for _, m := range e.moves {
+			sb.WriteString(" " + m.UCIString())
+		}
+	}
+	if err := e.send(sb.String()); err != nil {
+		return SearchResult{}, err
+	}
+	if err := e.send(goCommand(tc)); err != nil {
+		return SearchResult{}, err
+	}
+
+	var overrun <-chan time.Time
+	if limit := tc.hardLimit(g.State.ActiveColor); limit > 0 {
+		t := time.NewTimer(limit + stopGrace)
+		defer t.Stop()
+		overrun = t.C
+	}
+	var res SearchResult
+	stopped := false
+	var grace <-chan time.Time
+	for {
+		select {
+		case line, ok := <-e.proc.lines:
+			if !ok {
+				return res, fmt.Errorf("%w: engine exited during search", ErrEngine)
+			}
+			f := strings.Fields(line)
+			if len(f) == 0 {
+				continue
+			}
+			switch f[0] {
+			case "info":
+				if parseInfo(g, f[1:], &res) && info != nil {
+					info(res)
+				}
+			case "bestmove":
+				if len(f) < 2 {
+					return res, fmt.Errorf("%w: %q", ErrEngine, line)
+				}
+				if f[1] == "0000" || f[1] == "(none)" {
+					res.BestMove = chess.Move{}
+					return res, nil
+				}
+				m, err := chess.ParseUCI(g, f[1])
+				if err != nil {
+					return res, fmt.Errorf("%w: illegal move %q: %v", ErrEngine, f[1], err)
+				}
+				res.BestMove = m
+				if len(res.PV) == 0 || res.PV[0] != m {
+					res.PV = []chess.Move{m}
+				}
+				return res, nil
+			}
+		case <-doneIf(ctx, !stopped):
+			stopped = true
+			if err := e.send("stop"); err != nil {
+				return res, err
+			}
+			grace = time.After(stopGrace)
+		case <-overrun:
+			e.kill()
+			return res, fmt.Errorf("%w: no bestmove %v after the time limit", ErrEngine, stopGrace)
+		case <-grace:
+			e.kill()
+			return res, fmt.Errorf("%w: no bestmove after stop", ErrEngine)
+		}
+	}
+}
+
+// Stop cancels the running search, if any.
+func (e *UCIEngine) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.stop != nil {
+		e.stop()
+		e.stop = nil
+	}
+}
+
+// SetOption passes an option through to the program, which must have
+// advertised it, after checking the value against the declared type.
+func (e *UCIEngine) SetOption(name, value string) error {
+	if _, err := e.advertised.Set(name, value); err != nil {
+		return err
+	}
+	opt, _ := e.advertised.Lookup(name)
+	declared, value := opt.Name, e.advertised.Value(name)
+	if err := e.alive(); err != nil {
+		return err
+	}
+	if err := e.setOption(declared, value); err != nil {
+		return err
+	}
+	if opt.Type != Button {
+		for i, o := range e.options {
+			if o[0] == declared {
+				e.options = append(e.options[:i], e.options[i+1:]...)
+				break
+			}
+		}
+		e.options = append(e.options, [2]string{declared, value})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+	return e.ready(ctx)
+}
+
+// Close asks the program to quit and kills it if it has not exited within
+// stopGrace.
+func (e *UCIEngine) Close() error {
+	e.Stop()
+	if e.proc == nil {
+		return nil
+	}
+	_ = e.send("quit")
+	_ = e.proc.in.Close()
+	go func(p *uciProcess) {
+		for range p.lines {
+		}
+	}(e.proc)
+	select {
+	case <-e.proc.exited:
+	case <-time.After(stopGrace):
+	}
+	e.kill()
+	return nil
+}
+
+// launch starts the program, runs the handshake and sets the options again.
+func (e *UCIEngine) launch() error {
+	cmd := exec.Command(e.path, e.args...)
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%w: %v", ErrEngine, err)
+	}
+	p := &uciProcess{cmd: cmd, in: in, lines: make(chan string, 64), exited: make(chan struct{})}
+	go func() {
+		defer close(p.exited)
+		defer close(p.lines)
+		sc := bufio.NewScanner(out)
+		for sc.Scan() {
+			p.lines <- sc.Text()
+		}
+	}()
+	e.proc = p
+
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+	err = e.handshake(ctx)
+	for _, o := range e.options {
+		if err == nil {
+			_, _ = e.advertised.Set(o[0], o[1])
+			err = e.setOption(o[0], o[1])
+		}
+	}
+	if err == nil {
+		err = e.ready(ctx)
+	}
+	if err != nil {
+		e.kill()
+		return err
+	}
+	return nil
+}
+
+// handshake sends uci and records the name and options until uciok.
+func (e *UCIEngine) handshake(ctx context.Context) error {
+	if err := e.send("uci"); err != nil {
+		return err
+	}
+	e.advertised = &Options{}
+	for {
+		line, err := e.next(ctx, "uciok")
+		if err != nil {
+			return err
+		}
+		f := strings.Fields(line)
+		switch {
+		case len(f) == 0:
+		case f[0] == "uciok":
+			return nil
+		case len(f) > 2 && f[0] == "id" && f[1] == "name":
+			e.name = strings.Join(f[2:], " ")
+		case f[0] == "option":
+			// Options that cannot be parsed or are declared twice are not
+			// offered; the engine remains usable without them.
+			if opt, err := ParseOption(f[1:]); err == nil {
+				_ = e.advertised.declare(opt)
+			}
+		}
+	}
+}
+
+// alive starts the program again if it has exited or was killed.
+func (e *UCIEngine) alive() error {
+	if e.proc != nil {
+		select {
+		case <-e.proc.exited:
+			e.kill()
+		default:
+			return nil
+		}
+	}
+	e.restarts++
+	return e.launch()
+}
+
+// kill ends the program and reaps it; the next call starts it again.
+func (e *UCIEngine) kill() {
+	if e.proc == nil {
+		return
+	}
+	_ = e.proc.cmd.Process.Kill()
+	_ = e.proc.cmd.Wait()
+	e.proc = nil
+}
+
+func (e *UCIEngine) setOption(name, value string) error {
+	if value == "" {
+		return e.send("setoption name " + name)
+	}
+	return e.send("setoption name " + name + " value " + value)
+}
+
+// send writes one command line.
+func (e *UCIEngine) send(cmd string) error {
+	if e.proc == nil {
+		return fmt.Errorf("%w: engine not running", ErrEngine)
+	}
+	if _, err := io.WriteString(e.proc.in, cmd+"\n"); err != nil {
+		return fmt.Errorf("%w: %v", ErrEngine, err)
+	}
+	return nil
+}
+
+// ready runs the isready/readyok exchange.
+func (e *UCIEngine) ready(ctx context.Context) error {
+	if err := e.send("isready"); err != nil {
+		return err
+	}
+	for {
+		line, err := e.next(ctx, "readyok")
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(line) == "readyok" {
+			return nil
+		}
+	}
+}
+
+// next returns the next output line; awaiting names the reply expected, for errors.
+func (e *UCIEngine) next(ctx context.Context, awaiting string) (string, error) {
+	select {
+	case line, ok := <-e.proc.lines:
+		if !ok {
+			return "", fmt.Errorf("%w: engine exited waiting for %s", ErrEngine, awaiting)
+		}
+		return line, nil
+	case <-ctx.Done():
+		e.kill()
+		return "", fmt.Errorf("%w: no %s", ErrEngine, awaiting)
+	}
+}
+
+// doneIf returns ctx.Done() while armed, and nil (blocking forever) after.
+func doneIf(ctx context.Context, armed bool) <-chan struct{} {
+	if !armed {
+		return nil
+	}
+	return ctx.Done()
+}
+
+// hardLimit returns the longest a search under tc may take: the move time,
+// or all the time left on the side's clock; 0 when only depth or nodes bound it.
+func (tc TimeControl) hardLimit(side chess.Color) time.Duration {
+	remaining := tc.WTime
+	if side == chess.Black {
+		remaining = tc.BTime
+	}
+	switch {
+	case tc.Infinite:
+		return 0
+	case tc.MoveTime > 0:
+		return tc.MoveTime
+	case remaining > 0:
+		return remaining
+	}
+	return tc.Limits(side, 0).Hard
+}
+
+// goCommand renders a time control as a UCI go command. A time control without
+// any limit searches for the built-in search's default move time, since many
+// engines take a bare "go" to mean "go infinite".
+func goCommand(tc TimeControl) string {
+	if tc.Infinite {
+		return "go infinite"
+	}
+	if tc.MoveTime == 0 && tc.WTime == 0 && tc.BTime == 0 && tc.Depth == 0 && tc.Nodes == 0 {
+		tc.MoveTime = defaultMoveTime
+	}
+	var sb strings.Builder
+	sb.WriteString("go")
+	ms := func(name string, d time.Duration) {
+		if d > 0 {
+			fmt.Fprintf(&sb, " %s %d", name, d.Milliseconds())
+		}
+	}
+	ms("movetime", tc.MoveTime)
+	ms("wtime", tc.WTime)
+	ms("btime", tc.BTime)
+	ms("winc", tc.WInc)
+	ms("binc", tc.BInc)
+	if tc.MovesToGo > 0 {
+		fmt.Fprintf(&sb, " movestogo %d", tc.MovesToGo)
+	}
+	if tc.Depth > 0 {
+		fmt.Fprintf(&sb, " depth %d", tc.Depth)
+	}
+	if tc.Nodes > 0 {
+		fmt.Fprintf(&sb, " nodes %d", tc.Nodes)
+	}
+	return sb.String()
+}
@@ -0,0 +1,263 @@
+package engine
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	chess "chess_go/internal/chess"
+)
+
+// ErrInvalidNetwork is returned when a network file cannot be read.
+var ErrInvalidNetwork = errors.New("invalid network file")
+
+// Network is an efficiently updatable neural network (NNUE) evaluation:
+// 768 inputs per perspective (own/opponent × piece type × square), a hidden
+// layer of size Hidden shared by both perspectives, clipped ReLU, and one
+// output. Inputs are seen from each side's own point of view, so the
+// perspective of the side to move always feeds the first half of the output
+// weights.
+//
+// A network file is a 16-byte header followed by the int16 parameters in the
+// order a trainer quantising with QA=255 and QB=64 writes them, all
+// little-endian:
+//
+//	magic "CGNN", version uint32 = 1, inputs uint32 = 768, hidden uint32 = H
+//	feature weights [768][H]int16, input-major
+//	feature biases  [H]int16
+//	output weights  [2H]int16, side to move's half first
+//	output bias     int16
+//
+// Up to 63 zero bytes of padding may follow. Without the header, the same
+// parameters are the "quantised.bin" a trainer such as bullet writes for a
+// (768->H)x2->1 network; ReadQuantised reads those given H, and LoadNetwork
+// works H out from the file size.
+type Network struct {
+	Hidden         int
+	FeatureWeights []int16 // [768][Hidden]; see Feature for the input numbering
+	FeatureBiases  []int16 // [Hidden]
+	OutputWeights  []int16 // [2*Hidden], side to move's neurons first
+	OutputBias     int16
+}
+
+// Quantisation and output scale of network files.
+const (
+	nnueQA     = 255
+	nnueQB     = 64
+	nnueScale  = 400
+	nnueInputs = 768
+	nnueMagic  = "CGNN"
+)
+
+// NewNetwork returns a network with the given hidden size and all
+// parameters zero.
+func NewNetwork(hidden int) *Network {
+	return &Network{
+		Hidden:         hidden,
+		FeatureWeights: make([]int16, nnueInputs*hidden),
+		FeatureBiases:  make([]int16, hidden),
+		OutputWeights:  make([]int16, 2*hidden),
+	}
+}
+
+// LoadNetwork reads a network file, with or without the header. The hidden
+// size of a headerless file is the one its size allows.
+func LoadNetwork(path string) (*Network, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(f)
+	if magic, err := r.Peek(len(nnueMagic)); err == nil && string(magic) == nnueMagic {
+		return ReadNetwork(r)
+	}
+	// 771 int16 per hidden neuron and the output bias, padded to 64 bytes.
+	hidden := (info.Size()/2 - 1) / (nnueInputs + 3)
+	return ReadQuantised(r, int(hidden))
+}
+
+// ReadNetwork decodes a network in the file format described on Network.
+func ReadNetwork(r io.Reader) (*Network, error) {
+	var hdr struct {
+		Magic   [4]byte
+		Version uint32
+		Inputs  uint32
+		Hidden  uint32
+	}
+	if err := binary.Read(r, binary.LittleEndian, &hdr); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidNetwork, err)
+	}
+	switch {
+	case string(hdr.Magic[:]) != nnueMagic:
+		return nil, fmt.Errorf("%w: bad magic %q", ErrInvalidNetwork, hdr.Magic[:])
+	case hdr.Version != 1:
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidNetwork, hdr.Version)
+	case hdr.Inputs != nnueInputs:
+		return nil, fmt.Errorf("%w: %d inputs, want %d", ErrInvalidNetwork, hdr.Inputs, nnueInputs)
+	}
+	return ReadQuantised(r, int(hdr.Hidden))
+}
+
+// ReadQuantised decodes the parameters of a network with the given hidden
+// size, laid out as in a network file but without the header.
+func ReadQuantised(r io.Reader, hidden int) (*Network, error) {
+	if hidden <= 0 || hidden > 4096 {
+		return nil, fmt.Errorf("%w: hidden layer of %d", ErrInvalidNetwork, hidden)
+	}
+	n := NewNetwork(hidden)
+	for _, part := range []any{n.FeatureWeights, n.FeatureBiases, n.OutputWeights, &n.OutputBias} {
+		if err := binary.Read(r, binary.LittleEndian, part); err != nil {
+			return nil, fmt.Errorf("%w: truncated parameters: %v", ErrInvalidNetwork, err)
+		}
+	}
+	var pad [64]byte
+	k, err := io.ReadFull(r, pad[:])
+	if err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidNetwork)
+	}
+	for _, b := range pad[:k] {
+		if b != 0 {
+			return nil, fmt.Errorf("%w: trailing data", ErrInvalidNetwork)
+		}
+	}
+	return n, nil
+}
+
+// WriteTo writes the network in the file format described on Network.
+func (n *Network) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	hdr := []any{[]byte(nnueMagic), uint32(1), uint32(nnueInputs), uint32(n.Hidden),
+		n.FeatureWeights, n.FeatureBiases, n.OutputWeights, n.OutputBias}
+	for _, part := range hdr {
+		if err := binary.Write(cw, binary.LittleEndian, part); err != nil {
+			return cw.n, err
+		}
+	}
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	k, err := c.w.Write(p)
+	c.n += int64(k)
+	return k, err
+}
+
+// Accumulator holds the hidden layer sums of a position from both
+// perspectives, indexed by colour.
+type Accumulator struct {
+	v [2][]int16
+}
+
+// Feature returns the input index of piece p on square sq from the
+// perspective of persp: own/opponent*384 + piece type*64 + square, with
+// the board flipped for Black so that each side sees itself at the bottom.
+func Feature(persp chess.Color, p chess.Piece, sq chess.Square) int {
+	pt, c := pieceType(p)
+	rel := 0
+	if c != persp {
+		rel = 1
+	}
+	if persp == chess.Black {
+		sq ^= 56
+	}
+	return rel*384 + pt*64 + int(sq)
+}
+
+// Refresh computes acc from scratch for s.
+func (n *Network) Refresh(acc *Accumulator, s chess.GameState) {
+	for persp := range acc.v {
+		if len(acc.v[persp]) != n.Hidden {
+			acc.v[persp] = make([]int16, n.Hidden)
+		}
+		copy(acc.v[persp], n.FeatureBiases)
+	}
+	for sq, p := range s.Board {
+		if p != chess.NoPiece {
+			n.add(acc, p, sq)
+		}
+	}
+}
+
+// Update sets dst to the accumulator of after, given src for before. It
+// compares the two boards square by square and updates the columns of the
+// squares whose contents differ, two to four for a move, instead of
+// refreshing. dst and src may be the same.
+func (n *Network) Update(dst, src *Accumulator, before, after chess.GameState) {
+	if dst != src {
+		for persp := range dst.v {
+			if len(dst.v[persp]) != n.Hidden {
+				dst.v[persp] = make([]int16, n.Hidden)
+			}
+			copy(dst.v[persp], src.v[persp])
+		}
+	}
+	for sq := range before.Board {
+		old, cur := before.Board[sq], after.Board[sq]
+		if old == cur {
+			continue
+		}
+		if old != chess.NoPiece {
+			n.sub(dst, old, sq)
+		}
+		if cur != chess.NoPiece {
+			n.add(dst, cur, sq)
+		}
+	}
+}
+
+func (n *Network) add(acc *Accumulator, p chess.Piece, sq int) {
+	for persp := range acc.v {
+		col := n.FeatureWeights[Feature(chess.Color(persp), p, chess.Square(sq))*n.Hidden:][:n.Hidden]
+		a := acc.v[persp]
+		for j, w := range col {
+			a[j] += w
+		}
+	}
+}
+
+func (n *Network) sub(acc *Accumulator, p chess.Piece, sq int) {
+	for persp := range acc.v {
+		col := n.FeatureWeights[Feature(chess.Color(persp), p, chess.Square(sq))*n.Hidden:][:n.Hidden]
+		a := acc.v[persp]
+		for j, w := range col {
+			a[j] -= w
+		}
+	}
+}
+
+// Output returns the evaluation in centipawns from stm's point of view.
+func (n *Network) Output(acc *Accumulator, stm chess.Color) int {
+	sum := 0
+	for j, v := range acc.v[stm] {
+		sum += crelu(v) * int(n.OutputWeights[j])
+	}
+	for j, v := range acc.v[stm^1] {
+		sum += crelu(v) * int(n.OutputWeights[n.Hidden+j])
+	}
+	return (sum/nnueQA + int(n.OutputBias)) * nnueScale / (nnueQA * nnueQB)
+}
+
+// Evaluate returns the network's evaluation of s in centipawns from the
+// point of view of the side to move.
+func (n *Network) Evaluate(s chess.GameState) int {
+	var acc Accumulator
+	n.Refresh(&acc, s)
+	return n.Output(&acc, s.ActiveColor)
+}
+
+func crelu(v int16) int {
+	return min(max(int(v), 0), nnueQA)
+}
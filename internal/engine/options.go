@@ -0,0 +1,331 @@
+package engine
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidOption is returned when an option is set to a value its type does
+// not allow, or a config file line cannot be parsed.
+var ErrInvalidOption = errors.New("invalid option value")
+
+// OptionType is the UCI type of an option.
+type OptionType int
+
+const (
+	Check  OptionType = iota // a boolean, "true" or "false"
+	Spin                     // an integer between Min and Max
+	Combo                    // one of Vars
+	String                   // any text; "<empty>" in UCI stands for ""
+	Button                   // an action without a value
+)
+
+var optionTypeNames = [...]string{"check", "spin", "combo", "string", "button"}
+
+func (t OptionType) String() string { return optionTypeNames[t] }
+
+// Option declares one engine option.
+type Option struct {
+	Name     string
+	Type     OptionType
+	Default  string   // the initial value; unused for buttons
+	Min, Max int      // bounds of a spin
+	Vars     []string // values of a combo
+	Usage    string   // help text of the command-line flag
+	// OnChange applies a new value, already validated, whenever the option is
+	// set. A non-empty note describes what was loaded, for "info string". On
+	// an error the option keeps its value, so OnChange should then leave the
+	// engine as it was.
+	OnChange func(value string) (note string, err error)
+}
+
+// Options is a registry of option declarations and their current values. It
+// advertises the options over UCI, validates setoption, and backs the
+// command-line flags and config files of the engine, so that every option is
+// declared in one place.
+type Options struct {
+	decls  []Option
+	values []string
+	index  map[string]int // by lower-case name
+}
+
+// Declare adds an option with its default value. It panics if the name is
+// taken or the default is not a valid value, as both are programming errors.
+func (o *Options) Declare(opt Option) {
+	if err := o.declare(opt); err != nil {
+		panic("engine: " + err.Error())
+	}
+}
+
+func (o *Options) declare(opt Option) error {
+	key := strings.ToLower(opt.Name)
+	if _, dup := o.index[key]; dup {
+		return fmt.Errorf("option %s declared twice", opt.Name)
+	}
+	value := ""
+	if opt.Type != Button {
+		var err error
+		if value, err = opt.normalize(opt.Default); err != nil {
+			return fmt.Errorf("option %s: bad default: %w", opt.Name, err)
+		}
+	}
+	if o.index == nil {
+		o.index = map[string]int{}
+	}
+	o.index[key] = len(o.decls)
+	o.decls = append(o.decls, opt)
+	o.values = append(o.values, value)
+	return nil
+}
+
+// Lookup returns the declaration of the named option; names are case-insensitive.
+func (o *Options) Lookup(name string) (Option, bool) {
+	i, ok := o.index[strings.ToLower(name)]
+	if !ok {
+		return Option{}, false
+	}
+	return o.decls[i], true
+}
+
+// List returns the declarations in the order they were declared.
+func (o *Options) List() []Option {
+	return append([]Option(nil), o.decls...)
+}
+
+// Set validates value, calls the option's OnChange and records the value
+// once OnChange accepts it. Unknown names are reported with ErrUnknownOption
+// and bad values with ErrInvalidOption; on these and OnChange's errors the
+// value is left unchanged.
+func (o *Options) Set(name, value string) (note string, err error) {
+	i, ok := o.index[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownOption, name)
+	}
+	opt := o.decls[i]
+	if opt.Type != Button {
+		if value, err = opt.normalize(value); err != nil {
+			return "", err
+		}
+	}
+	if opt.OnChange != nil {
+		if note, err = opt.OnChange(value); err != nil {
+			return "", err
+		}
+	}
+	if opt.Type != Button {
+		o.values[i] = value
+	}
+	return note, nil
+}
+
+// Value returns the current value of the named option, or "" if there is none.
+func (o *Options) Value(name string) string {
+	if i, ok := o.index[strings.ToLower(name)]; ok {
+		return o.values[i]
+	}
+	return ""
+}
+
+// normalize checks value against the option's type and returns it in
+// canonical form: "true"/"false", a decimal integer, the declared spelling of
+// a combo value, or "" for an empty string.
+func (opt Option) normalize(value string) (string, error) {
+	switch opt.Type {
+	case Check:
+		switch strings.ToLower(value) {
+		case "true", "false":
+			return strings.ToLower(value), nil
+		}
+		return "", fmt.Errorf("%w: %s wants true or false, got %q", ErrInvalidOption, opt.Name, value)
+	case Spin:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < opt.Min || n > opt.Max {
+			return "", fmt.Errorf("%w: %s wants an integer from %d to %d, got %q", ErrInvalidOption, opt.Name, opt.Min, opt.Max, value)
+		}
+		return strconv.Itoa(n), nil
+	case Combo:
+		for _, v := range opt.Vars {
+			if strings.EqualFold(v, value) {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("%w: %s wants one of %s, got %q", ErrInvalidOption, opt.Name, strings.Join(opt.Vars, ", "), value)
+	case String:
+		if value == "<empty>" {
+			return "", nil
+		}
+		return value, nil
+	}
+	return value, nil
+}
+
+// WriteUCI writes the "option name ... type ..." line of every option. The
+// current values are given as the defaults, so that a GUI shows the values
+// set by flags and config files.
+func (o *Options) WriteUCI(w io.Writer) {
+	for i, opt := range o.decls {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "option name %s type %s", opt.Name, opt.Type)
+		switch value := o.values[i]; opt.Type {
+		case Check, Combo:
+			fmt.Fprintf(&sb, " default %s", value)
+		case Spin:
+			fmt.Fprintf(&sb, " default %s min %d max %d", value, opt.Min, opt.Max)
+		case String:
+			if value == "" {
+				value = "<empty>"
+			}
+			fmt.Fprintf(&sb, " default %s", value)
+		}
+		for _, v := range opt.Vars {
+			fmt.Fprintf(&sb, " var %s", v)
+		}
+		_, _ = fmt.Fprintln(w, sb.String())
+	}
+}
+
+// ParseOption parses the fields of an "option name <id> type <t> [default
+// <x>] [min <x>] [max <x>] [var <x>]..." line after "option".
+func ParseOption(f []string) (Option, error) {
+	var opt Option
+	var key string
+	var value []string
+	typed := false
+	flush := func() error {
+		v := strings.Join(value, " ")
+		var err error
+		switch key {
+		case "name":
+			opt.Name = v
+		case "type":
+			err = fmt.Errorf("%w: unknown option type %q", ErrInvalidOption, v)
+			for t, name := range optionTypeNames {
+				if name == v {
+					opt.Type, typed, err = OptionType(t), true, nil
+				}
+			}
+		case "default":
+			opt.Default = v
+		case "min":
+			opt.Min, err = strconv.Atoi(v)
+		case "max":
+			opt.Max, err = strconv.Atoi(v)
+		case "var":
+			opt.Vars = append(opt.Vars, v)
+		}
+		value = value[:0]
+		return err
+	}
+	for _, s := range f {
+		switch s {
+		case "name", "type", "default", "min", "max", "var":
+			if key != "name" || s == "type" {
+				if err := flush(); err != nil {
+					return Option{}, err
+				}
+				key = s
+				continue
+			}
+		}
+		value = append(value, s)
+	}
+	if err := flush(); err != nil {
+		return Option{}, err
+	}
+	if opt.Name == "" || !typed {
+		return Option{}, fmt.Errorf("%w: option without a name or type", ErrInvalidOption)
+	}
+	if opt.Type == String && opt.Default == "<empty>" {
+		opt.Default = ""
+	}
+	return opt, nil
+}
+
+// FlagName returns the command-line flag of an option: its name in lower
+// case with spaces and underscores as hyphens ("Skill Level" is -skill-level).
+func FlagName(option string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' {
+			return '-'
+		}
+		return r
+	}, strings.ToLower(option))
+}
+
+// RegisterFlags defines a flag on fs for every option, which sets the option
+// when parsed. Flags of check and button options may be given without a value.
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	for _, opt := range o.decls {
+		usage := opt.Usage
+		if usage == "" {
+			usage = "UCI option " + opt.Name
+		}
+		set := func(value string) error {
+			_, err := o.Set(opt.Name, value)
+			return err
+		}
+		switch opt.Type {
+		case Check:
+			fs.BoolFunc(FlagName(opt.Name), usage+" (default "+opt.Default+")", set)
+		case Button:
+			fs.BoolFunc(FlagName(opt.Name), usage, func(string) error { return set("") })
+		default:
+			if opt.Default != "" {
+				usage += " (default " + opt.Default + ")"
+			}
+			fs.Func(FlagName(opt.Name), usage, set)
+		}
+	}
+}
+
+// Setting is one "Name = Value" line of a config file.
+type Setting struct {
+	Name, Value string
+}
+
+// LoadConfig reads the config file at path.
+func LoadConfig(path string) ([]Setting, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadConfig(f)
+}
+
+// ReadConfig reads option settings, one "Name = Value" per line, with
+// option names as in UCI. Blank lines and lines starting with '#' are
+// ignored; a button is pressed by naming it without "= Value".
+func ReadConfig(r io.Reader) ([]Setting, error) {
+	var settings []Setting
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		name, value, _ := strings.Cut(line, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if name == "" {
+			return nil, fmt.Errorf("%w: line %d: missing option name", ErrInvalidOption, n)
+		}
+		settings = append(settings, Setting{name, value})
+	}
+	return settings, sc.Err()
+}
+
+// Apply sets each of settings in turn, stopping at the first error.
+func (o *Options) Apply(settings []Setting) error {
+	for _, s := range settings {
+		if _, err := o.Set(s.Name, s.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,11 +1,15 @@
 // Package engine implements chess search and time management.
-// For the walking skeleton, Search selects a random legal move.
-// Alpha-beta with iterative deepening is implemented in US-14.
+// Search is iterative-deepening negamax alpha-beta with quiescence search,
+// MVV-LVA and killer move ordering (ADR-002).
 package engine
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"math/rand"
+	"sort"
+	"strings"
 	"time"
 
 	chess "chess_go/internal/chess"
@@ -13,36 +17,686 @@ import (
 
 // TimeControl specifies how long the engine may think.
 type TimeControl struct {
-	MoveTime time.Duration // exact time for this move; 0 = use wtime/btime
-	WTime    time.Duration // White remaining time
-	BTime    time.Duration // Black remaining time
-	WInc     time.Duration // White increment per move
-	BInc     time.Duration // Black increment per move
+	MoveTime  time.Duration // exact time for this move; 0 = use wtime/btime
+	WTime     time.Duration // White remaining time
+	BTime     time.Duration // Black remaining time
+	WInc      time.Duration // White increment per move
+	BInc      time.Duration // Black increment per move
+	MovesToGo int           // moves until the next time control; 0 = rest of the game
+	Depth     int           // maximum search depth; 0 = no limit
+	Nodes     int64         // maximum nodes to search; 0 = no limit
+	Infinite  bool          // search until cancelled; the other fields are ignored
+	Strength  Strength      // playing-strength limit; the zero value is full strength
 }
 
 // SearchResult holds the result of a search.
 type SearchResult struct {
 	BestMove chess.Move
-	Score    int
+	Score    int // centipawns from the side to move's view; see MateScore
 	Depth    int
 	Nodes    int64
 	Elapsed  time.Duration
+	PV       []chess.Move // principal variation, starting with BestMove
+	MultiPV  int          // the line's rank when several are searched, from 1; 0 otherwise
 }
 
-// Search selects a random legal move (skeleton implementation).
-// Replace with alpha-beta in US-14.
+// SearchFunc is the signature shared by SearchContext and its decorators (e.g. WithBook).
+// Implementations must return promptly once ctx is cancelled.
+type SearchFunc func(ctx context.Context, g chess.Game, tc TimeControl, info io.Writer) SearchResult
+
+// Config holds search settings that are not part of the time control.
+// The zero value searches without tablebases, with the default evaluation.
+type Config struct {
+	Tablebase        *chess.Syzygy // Syzygy tables probed in search; nil disables probing
+	Syzygy50MoveRule bool          // score cursed wins and blessed losses as draws
+	Weights          *Weights      // evaluation weights; nil selects DefaultWeights
+	Network          *Network      // NNUE evaluation used instead of Weights; nil disables it
+	Contempt         int           // centipawns the side to move at the root gives up to avoid a draw
+	MoveOverhead     time.Duration // time lost per move outside the search, held back from the clock
+	MultiPV          int           // best lines reported per iteration; 0 or 1 for the best only
+	Trace            *Tracer       // records the search tree; nil disables tracing
+}
+
+const (
+	// MateScore is the score of delivering mate at the root; a mate found n plies
+	// from the root scores MateScore-n.
+	MateScore = 100000
+	// maxPly bounds the search stack (check extensions included).
+	maxPly = 128
+	// tbWinScore scores a tablebase win; it sits below every mate score.
+	tbWinScore = MateScore - 2*maxPly
+	infinity   = MateScore + 1
+
+	// defaultMoveTime is used when the time control sets no limit at all.
+	defaultMoveTime = time.Second
+)
+
+// Search selects a move for the side to move in g.
+// It is SearchContext without external cancellation.
 func Search(g chess.Game, tc TimeControl, info io.Writer) SearchResult {
+	return SearchContext(context.Background(), g, tc, info)
+}
+
+// SearchContext searches g with the default Config.
+func SearchContext(ctx context.Context, g chess.Game, tc TimeControl, info io.Writer) SearchResult {
+	return Config{}.Search(ctx, g, tc, info)
+}
+
+// Search runs an iterative-deepening search on g until tc is exhausted or ctx is
+// cancelled and returns the best move of the last completed iteration. An info
+// line is written to info after every iteration, one per line with MultiPV
+// above 1, which a limited strength ignores. Its signature matches SearchFunc.
+func (c Config) Search(ctx context.Context, g chess.Game, tc TimeControl, info io.Writer) SearchResult {
 	start := time.Now()
 	moves := g.LegalMoves()
 	if len(moves) == 0 {
 		return SearchResult{Elapsed: time.Since(start)}
 	}
-	//nolint:gosec
-	best := moves[rand.Intn(len(moves))]
-	return SearchResult{
-		BestMove: best,
-		Depth:    1,
-		Nodes:    int64(len(moves)),
-		Elapsed:  time.Since(start),
+
+	tm := NewTimeManager(tc, g.State.ActiveColor, c.MoveOverhead)
+	if hard := tm.Limits().Hard; hard > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, start.Add(hard))
+		defer cancel()
+	}
+
+	s := &searcher{ctx: ctx, cfg: c, weights: c.Weights, maxNodes: tc.Nodes, trace: c.Trace, path: append(g.History(), g.State)}
+	if s.weights == nil {
+		s.weights = &DefaultWeights
+	}
+	if c.Network != nil {
+		s.acc = make([]Accumulator, maxPly+1)
+		c.Network.Refresh(&s.acc[0], g.State)
+	}
+	maxDepth := maxPly - 1
+	if tc.Depth > 0 {
+		maxDepth = min(tc.Depth, maxDepth)
+	}
+	limited := tc.Strength.Limited()
+	if limited {
+		maxDepth = min(maxDepth, tc.Strength.maxDepth())
+		s.rootScores = make([]int, len(moves))
+	}
+
+	// Tablebase positions: DTZ ranks the root moves. Wins and losses are decided
+	// by DTZ alone; in a drawn position the search picks among the drawing moves.
+	if tb := c.Tablebase; tb != nil && tb.Covers(g.State) {
+		if ranked, ok := c.rankRootMoves(g, moves); ok {
+			best := ranked[0]
+			s.tbHits = int64(len(ranked))
+			if best.score != 0 {
+				res := SearchResult{BestMove: best.move, Score: best.score, Depth: 1,
+					PV: []chess.Move{best.move}, Elapsed: time.Since(start)}
+				writeInfo(info, res, s.tbHits)
+				return res
+			}
+			moves = moves[:0]
+			for _, rm := range ranked {
+				if rm.rank == best.rank {
+					moves = append(moves, rm.move)
+				}
+			}
+		}
+	}
+
+	res := SearchResult{BestMove: moves[0], PV: []chess.Move{moves[0]}}
+	multiPV := 0
+	if c.MultiPV > 1 && !limited {
+		multiPV = min(c.MultiPV, len(moves))
+		res.MultiPV = 1
+	}
+	var scored []scoredMove
+	for depth := 1; depth <= maxDepth; depth++ {
+		if s.trace != nil {
+			s.trace.begin(g, depth)
+		}
+		score, completed := s.root(g.State, moves, depth)
+		if s.trace != nil {
+			s.trace.end(score, completed)
+		}
+		if !completed {
+			break
+		}
+		if limited {
+			scored = scored[:0]
+			for i, m := range moves {
+				scored = append(scored, scoredMove{m, s.rootScores[i]})
+			}
+			// The node cap applies once depth 1 has scored every move.
+			if cap := tc.Strength.maxNodes(); s.maxNodes == 0 || s.maxNodes > cap {
+				s.maxNodes = cap
+			}
+		}
+		res.Score, res.Depth = score, depth
+		res.PV = append([]chess.Move(nil), s.pv[0][:s.pvLen[0]]...)
+		res.BestMove = res.PV[0]
+		res.Nodes, res.Elapsed = s.nodes, time.Since(start)
+		writeInfo(info, res, s.tbHits)
+		if multiPV > 1 {
+			s.lines(g.State, moves, res, multiPV, start, info)
+		}
+
+		// Bring the best move to the front so the next iteration searches it first.
+		for i, m := range moves {
+			if m == res.BestMove {
+				copy(moves[1:i+1], moves[:i])
+				moves[0] = m
+				break
+			}
+		}
+		if len(moves) == 1 && !tc.Infinite {
+			break
+		}
+		if abs(score) >= MateScore-maxPly && MateScore-abs(score) <= depth {
+			break // forced mate found within the searched depth
+		}
+		if tm.Iteration(res.BestMove, score); !tm.Continue(time.Since(start)) {
+			break
+		}
+	}
+	if len(scored) > 0 {
+		//nolint:gosec
+		rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+		if pick := tc.Strength.pick(scored, rng); pick.move != res.BestMove {
+			res.BestMove, res.Score, res.PV = pick.move, pick.score, []chess.Move{pick.move}
+		}
+	}
+	res.Nodes, res.Elapsed = s.nodes, time.Since(start)
+	return res
+}
+
+// searcher holds the state of one search call; it is not shared between goroutines.
+type searcher struct {
+	ctx      context.Context
+	cfg      Config
+	weights  *Weights
+	acc      []Accumulator // NNUE accumulator of the position at each ply; nil without a network
+	nodes    int64
+	maxNodes int64
+	tbHits   int64
+	stopped  bool
+	trace    *Tracer // nil unless tracing; every hook is behind a nil check
+
+	path       []chess.GameState // game history then the current search line
+	rootScores []int             // exact score of each root move; nil unless strength is limited
+	killers    [maxPly][2]chess.Move
+	pv         [maxPly][maxPly]chess.Move
+	pvLen      [maxPly]int
+}
+
+// root searches every root move to depth and reports whether the iteration
+// completed. With rootScores set, every move gets a full window so that its
+// score is exact rather than a bound.
+func (s *searcher) root(pos chess.GameState, moves []chess.Move, depth int) (int, bool) {
+	alpha, best := -infinity, -infinity
+	s.pvLen[0] = 0
+	for i, m := range moves {
+		window := alpha
+		if s.rootScores != nil {
+			window = -infinity
+		}
+		score := -s.child(pos, m, depth-1, 1, -infinity, -window)
+		if s.stopped {
+			return best, false
+		}
+		if s.rootScores != nil {
+			s.rootScores[i] = score
+		}
+		if score > best {
+			best = score
+			s.updatePV(0, m)
+		}
+		alpha = max(alpha, score)
+	}
+	return best, true
+}
+
+// lines searches the root moves to depth again for the best lines after best,
+// the first, up to n in all, leaving out the moves of the lines found before
+// each, and writes an info line for each. It stops when the search is stopped.
+func (s *searcher) lines(pos chess.GameState, moves []chess.Move, best SearchResult, n int, start time.Time, info io.Writer) {
+	rest := make([]chess.Move, 0, len(moves))
+	for _, m := range moves {
+		if m != best.BestMove {
+			rest = append(rest, m)
+		}
+	}
+	// The trace follows the search for the best line only.
+	trace := s.trace
+	s.trace = nil
+	defer func() { s.trace = trace }()
+
+	for k := 2; k <= n && len(rest) > 0; k++ {
+		score, completed := s.root(pos, rest, best.Depth)
+		if !completed {
+			return
+		}
+		line := SearchResult{Score: score, Depth: best.Depth, MultiPV: k,
+			PV: append([]chess.Move(nil), s.pv[0][:s.pvLen[0]]...), Nodes: s.nodes, Elapsed: time.Since(start)}
+		line.BestMove = line.PV[0]
+		writeInfo(info, line, s.tbHits)
+		for i, m := range rest {
+			if m == line.BestMove {
+				rest = append(rest[:i], rest[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// child plays m and searches the resulting position.
+func (s *searcher) child(pos chess.GameState, m chess.Move, depth, ply, alpha, beta int) int {
+	next := pos.Play(m)
+	if s.acc != nil {
+		s.cfg.Network.Update(&s.acc[ply], &s.acc[ply-1], pos, next)
+	}
+	s.path = append(s.path, next)
+	if s.trace != nil {
+		s.trace.push(m, ply, depth, alpha, beta)
+	}
+	score := s.negamax(next, depth, ply, alpha, beta)
+	if s.trace != nil {
+		s.trace.pop(score)
+	}
+	s.path = s.path[:len(s.path)-1]
+	return score
+}
+
+// negamax is a fail-soft alpha-beta search of pos to depth.
+func (s *searcher) negamax(pos chess.GameState, depth, ply, alpha, beta int) int {
+	s.pvLen[ply] = ply
+	if s.tick() {
+		s.cut(CutoffStopped)
+		return 0
+	}
+	if s.isDraw(pos) {
+		s.cut(CutoffDraw)
+		return s.drawScore(ply)
+	}
+	if score, ok := s.probeWDL(pos, ply); ok {
+		s.cut(CutoffTablebase)
+		return score
+	}
+
+	inCheck := pos.InCheck()
+	if inCheck {
+		depth++
+		if s.trace != nil {
+			s.trace.extend(1)
+		}
+	}
+	if depth <= 0 || ply >= maxPly-1 {
+		return s.quiesce(pos, ply, alpha, beta)
+	}
+
+	moves := pos.LegalMoves()
+	if len(moves) == 0 {
+		if inCheck {
+			s.cut(CutoffMate)
+			return -MateScore + ply
+		}
+		s.cut(CutoffDraw)
+		return s.drawScore(ply)
+	}
+	s.orderMoves(pos, moves, ply)
+
+	best := -infinity
+	for _, m := range moves {
+		score := -s.child(pos, m, depth-1, ply+1, -beta, -alpha)
+		if s.stopped {
+			s.cut(CutoffStopped)
+			return 0
+		}
+		if score > best {
+			best = score
+			if score > alpha {
+				alpha = score
+				s.updatePV(ply, m)
+				if score >= beta {
+					if !isCapture(pos, m) && m.Promotion == chess.NoPiece && s.killers[ply][0] != m {
+						s.killers[ply][1] = s.killers[ply][0]
+						s.killers[ply][0] = m
+					}
+					s.cut(CutoffBeta)
+					break
+				}
+			}
+		}
+	}
+	return best
+}
+
+// quiesce searches captures and promotions until the position is quiet.
+func (s *searcher) quiesce(pos chess.GameState, ply, alpha, beta int) int {
+	s.pvLen[ply] = ply
+	if s.trace != nil {
+		s.trace.quiesce()
+	}
+	if s.tick() {
+		s.cut(CutoffStopped)
+		return 0
+	}
+	best := s.evaluate(pos, ply)
+	if best >= beta || ply >= maxPly-1 {
+		s.cut(CutoffStandPat)
+		return best
+	}
+	alpha = max(alpha, best)
+
+	all := pos.LegalMoves()
+	moves := all[:0]
+	for _, m := range all {
+		if isCapture(pos, m) || m.Promotion != chess.NoPiece {
+			moves = append(moves, m)
+		}
+	}
+	s.orderMoves(pos, moves, ply)
+	for _, m := range moves {
+		next := pos.Play(m)
+		if s.acc != nil {
+			s.cfg.Network.Update(&s.acc[ply+1], &s.acc[ply], pos, next)
+		}
+		if s.trace != nil {
+			s.trace.push(m, ply+1, 0, -beta, -alpha)
+		}
+		score := -s.quiesce(next, ply+1, -beta, -alpha)
+		if s.trace != nil {
+			s.trace.pop(-score)
+		}
+		if s.stopped {
+			s.cut(CutoffStopped)
+			return 0
+		}
+		if score > best {
+			best = score
+			if score > alpha {
+				alpha = score
+				s.updatePV(ply, m)
+				if score >= beta {
+					s.cut(CutoffBeta)
+					break
+				}
+			}
+		}
+	}
+	return best
+}
+
+// evaluate returns the static evaluation of pos, the position at ply: the
+// network's when one is configured, kept clear of tablebase and mate scores,
+// otherwise the hand-crafted evaluation.
+func (s *searcher) evaluate(pos chess.GameState, ply int) int {
+	if s.acc == nil {
+		return s.weights.Evaluate(pos)
+	}
+	const bound = tbWinScore - maxPly - 1
+	return min(max(s.cfg.Network.Output(&s.acc[ply], pos.ActiveColor), -bound), bound)
+}
+
+// cut records in the trace why the current node returned.
+func (s *searcher) cut(c Cutoff) {
+	if s.trace != nil {
+		s.trace.cut(c)
+	}
+}
+
+// tick counts a node and reports whether the search must stop.
+// The context is polled every 1024 nodes to keep the check cheap.
+func (s *searcher) tick() bool {
+	s.nodes++
+	if s.maxNodes > 0 && s.nodes >= s.maxNodes {
+		s.stopped = true
+	}
+	if s.nodes&1023 == 0 && s.ctx.Err() != nil {
+		s.stopped = true
+	}
+	return s.stopped
+}
+
+// drawScore scores a draw at ply from the side to move's view: Contempt below
+// zero for the side to move at the root, and above zero for its opponent.
+func (s *searcher) drawScore(ply int) int {
+	if ply%2 == 0 {
+		return -s.cfg.Contempt
+	}
+	return s.cfg.Contempt
+}
+
+// isDraw detects the fifty-move rule and repetitions since the last irreversible
+// move; inside the search a single repetition is scored as a draw.
+func (s *searcher) isDraw(pos chess.GameState) bool {
+	if pos.HalfMoveClock >= 100 {
+		return true
+	}
+	n := len(s.path) - 1
+	for i := n - 2; i >= 0 && i >= n-int(pos.HalfMoveClock); i -= 2 {
+		if chess.SamePosition(s.path[i], pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeWDL scores pos from the tablebases right after a capture or pawn move,
+// where the fifty-move counter is zero and the WDL value is exact.
+func (s *searcher) probeWDL(pos chess.GameState, ply int) (int, bool) {
+	tb := s.cfg.Tablebase
+	if tb == nil || pos.HalfMoveClock != 0 || !tb.Covers(pos) {
+		return 0, false
+	}
+	wdl, err := tb.ProbeWDL(pos)
+	if err != nil {
+		return 0, false
+	}
+	s.tbHits++
+	return s.cfg.wdlScore(wdl, ply), true
+}
+
+// wdlScore converts a WDL value found ply plies from the root into a score.
+func (c Config) wdlScore(wdl chess.WDL, ply int) int {
+	draw := chess.WDLDraw
+	if c.Syzygy50MoveRule {
+		draw = chess.WDLCursedWin
+	}
+	switch {
+	case wdl > draw:
+		return tbWinScore - ply
+	case wdl < -draw:
+		return -tbWinScore + ply
+	}
+	return int(wdl) // ±1 for cursed wins and blessed losses, 0 for draws
+}
+
+// rankedMove is a root move ranked by tablebase DTZ.
+type rankedMove struct {
+	move  chess.Move
+	dtz   int
+	rank  int
+	score int
+}
+
+// rankRootMoves ranks the root moves by DTZ, best first, taking the fifty-move
+// counter into account. It returns false if any probe fails.
+func (c Config) rankRootMoves(g chess.Game, moves []chess.Move) ([]rankedMove, bool) {
+	tb := c.Tablebase
+	cnt50 := int(g.State.HalfMoveClock)
+	bound := 1
+	if c.Syzygy50MoveRule {
+		bound = 900
+	}
+	ranked := make([]rankedMove, 0, len(moves))
+	for _, m := range moves {
+		next := g.State.Play(m)
+		var dtz int
+		if next.HalfMoveClock == 0 {
+			wdl, err := tb.ProbeWDL(next)
+			if err != nil {
+				return nil, false
+			}
+			// A zeroing move's DTZ is 1 (or 101 if cursed) with the sign of the result.
+			switch wdl {
+			case chess.WDLLoss:
+				dtz = 1
+			case chess.WDLBlessedLoss:
+				dtz = 101
+			case chess.WDLCursedWin:
+				dtz = -101
+			case chess.WDLWin:
+				dtz = -1
+			}
+		} else {
+			d, err := tb.ProbeDTZ(next)
+			if err != nil {
+				return nil, false
+			}
+			dtz = -d
+			switch {
+			case dtz > 0:
+				dtz++
+			case dtz < 0:
+				dtz--
+			}
+		}
+		if dtz == 2 && next.InCheck() && len(next.LegalMoves()) == 0 {
+			dtz = 1 // mate
+		}
+
+		// Certain wins rank equally; losses too unless a fifty-move draw is in sight.
+		r := 0
+		switch {
+		case dtz > 0 && dtz+cnt50 <= 99:
+			r = 1000
+		case dtz > 0:
+			r = 1000 - (dtz + cnt50)
+		case dtz < 0 && -dtz*2+cnt50 < 100:
+			r = -1000
+		case dtz < 0:
+			r = -1000 + (-dtz + cnt50)
+		}
+		score := 0
+		switch {
+		case r >= bound:
+			score = tbWinScore
+		case r > 0:
+			score = max(3, r-800) * 100 / 200
+		case r < 0 && r > -bound:
+			score = min(-3, r+800) * 100 / 200
+		case r < 0:
+			score = -tbWinScore
+		}
+		ranked = append(ranked, rankedMove{move: m, dtz: dtz, rank: r, score: score})
+	}
+
+	// Best rank first; among equal wins the fastest, among equal losses the slowest.
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.rank != b.rank {
+			return a.rank > b.rank
+		}
+		if a.dtz > 0 && b.dtz > 0 {
+			return a.dtz < b.dtz
+		}
+		return a.dtz < b.dtz && b.dtz < 0
+	})
+	return ranked, true
+}
+
+// updatePV records m as the best move at ply followed by the child's PV.
+func (s *searcher) updatePV(ply int, m chess.Move) {
+	s.pv[ply][ply] = m
+	n := ply + 1
+	if ply+1 < maxPly {
+		for i := ply + 1; i < s.pvLen[ply+1]; i++ {
+			s.pv[ply][i] = s.pv[ply+1][i]
+		}
+		n = max(n, s.pvLen[ply+1])
+	}
+	s.pvLen[ply] = n
+}
+
+// orderMoves sorts captures by MVV-LVA, then promotions, killers and quiet moves.
+func (s *searcher) orderMoves(pos chess.GameState, moves []chess.Move, ply int) {
+	scores := make([]int, len(moves))
+	for i, m := range moves {
+		switch {
+		case isCapture(pos, m):
+			victim := s.weights.Material[0]
+			if p := pos.Board[m.To]; p != chess.NoPiece {
+				vt, _ := pieceType(p)
+				victim = s.weights.Material[vt]
+			}
+			at, _ := pieceType(pos.Board[m.From])
+			scores[i] = 1_000_000 + victim*10 - at
+		case m.Promotion != chess.NoPiece:
+			scores[i] = 900_000
+		case ply < maxPly && m == s.killers[ply][0]:
+			scores[i] = 800_000
+		case ply < maxPly && m == s.killers[ply][1]:
+			scores[i] = 700_000
+		}
+	}
+	sort.Stable(byScore{moves, scores})
+}
+
+type byScore struct {
+	moves  []chess.Move
+	scores []int
+}
+
+func (b byScore) Len() int           { return len(b.moves) }
+func (b byScore) Less(i, j int) bool { return b.scores[i] > b.scores[j] }
+func (b byScore) Swap(i, j int) {
+	b.moves[i], b.moves[j] = b.moves[j], b.moves[i]
+	b.scores[i], b.scores[j] = b.scores[j], b.scores[i]
+}
+
+// isCapture reports whether m captures in pos, including en passant.
+func isCapture(pos chess.GameState, m chess.Move) bool {
+	if pos.Board[m.To] != chess.NoPiece {
+		return true
+	}
+	p := pos.Board[m.From]
+	return (p == chess.WhitePawn || p == chess.BlackPawn) && m.To == pos.EnPassantSq
+}
+
+// writeInfo emits a UCI info line for a completed iteration.
+func writeInfo(w io.Writer, res SearchResult, tbHits int64) {
+	ms := res.Elapsed.Milliseconds()
+	nps := int64(0)
+	if res.Elapsed > 0 {
+		nps = int64(float64(res.Nodes) / res.Elapsed.Seconds())
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "info depth %d", res.Depth)
+	if res.MultiPV > 0 {
+		fmt.Fprintf(&sb, " multipv %d", res.MultiPV)
+	}
+	fmt.Fprintf(&sb, " score %s nodes %d nps %d time %d", FormatScore(res.Score), res.Nodes, nps, ms)
+	if tbHits > 0 {
+		fmt.Fprintf(&sb, " tbhits %d", tbHits)
+	}
+	sb.WriteString(" pv")
+	for _, m := range res.PV {
+		sb.WriteString(" " + m.UCIString())
+	}
+	_, _ = fmt.Fprintln(w, sb.String())
+}
+
+// FormatScore renders a score in UCI notation: "cp <n>" or "mate <moves>",
+// negative mate distances meaning the side to move is being mated.
+func FormatScore(score int) string {
+	if abs(score) < MateScore-maxPly {
+		return fmt.Sprintf("cp %d", score)
+	}
+	if score > 0 {
+		return fmt.Sprintf("mate %d", (MateScore-score+1)/2)
+	}
+	return fmt.Sprintf("mate %d", -(MateScore+score)/2)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
 	}
+	return n
 }
@@ -0,0 +1,156 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"chess_go/internal/book"
+	chess "chess_go/internal/chess"
+)
+
+// ErrUnknownOption is returned when an option is set that the engine does
+// not have.
+var ErrUnknownOption = errors.New("unknown option")
+
+// settings holds the options of the built-in engine shared by the UCI
+// handler and Builtin: book, tablebases, evaluation and playing strength.
+// The registry declares them; its callbacks keep the fields below current.
+type settings struct {
+	options Options
+
+	cfg      Config
+	ownBook  bool
+	bookBest bool
+	book     *book.Book
+
+	skill         int  // "Skill Level"
+	limitStrength bool // UCI_LimitStrength: play at UCI_Elo
+	elo           int  // UCI_Elo
+}
+
+func newSettings() *settings {
+	st := &settings{cfg: Config{Syzygy50MoveRule: true, MoveOverhead: DefaultMoveOverhead}, skill: MaxSkill, elo: MinElo}
+	o := &st.options
+	// The search runs on one thread, which Threads advertises to GUIs that
+	// set it at startup. Hash is left out until there is a hash table.
+	o.Declare(Option{Name: "Threads", Type: Spin, Default: "1", Min: 1, Max: 1,
+		Usage: "search threads; the search runs on one"})
+	o.Declare(Option{Name: "OwnBook", Type: Check, Default: "false",
+		Usage:    "play from the opening book in BookFile",
+		OnChange: func(v string) (string, error) { st.ownBook = v == "true"; return "", nil }})
+	o.Declare(Option{Name: "BookFile", Type: String,
+		Usage: "Polyglot opening book",
+		OnChange: func(v string) (string, error) {
+			if v == "" {
+				st.book = nil
+				return "", nil
+			}
+			b, err := book.Open(v)
+			if err != nil {
+				return "", fmt.Errorf("cannot load book: %w", err)
+			}
+			st.book = b
+			return "", nil
+		}})
+	o.Declare(Option{Name: "BookBestMove", Type: Check, Default: "false",
+		Usage:    "always play the book move with the highest weight",
+		OnChange: func(v string) (string, error) { st.bookBest = v == "true"; return "", nil }})
+	o.Declare(Option{Name: "SyzygyPath", Type: String,
+		Usage: "directory of Syzygy tablebases",
+		OnChange: func(v string) (string, error) {
+			if v == "" {
+				st.cfg.Tablebase = nil
+				return "", nil
+			}
+			tb, err := chess.OpenSyzygy(v)
+			if err != nil {
+				return "", fmt.Errorf("cannot open tablebases: %w", err)
+			}
+			st.cfg.Tablebase = tb
+			return fmt.Sprintf("found %d tablebases, up to %d pieces", tb.Tables(), tb.MaxPieces()), nil
+		}})
+	o.Declare(Option{Name: "Syzygy50MoveRule", Type: Check, Default: "true",
+		Usage:    "score tablebase wins spoilt by the fifty-move rule as draws",
+		OnChange: func(v string) (string, error) { st.cfg.Syzygy50MoveRule = v == "true"; return "", nil }})
+	o.Declare(Option{Name: "EvalWeights", Type: String,
+		Usage: "JSON evaluation weights written by chess-go tune",
+		OnChange: func(v string) (string, error) {
+			if v == "" {
+				st.cfg.Weights = nil
+				return "", nil
+			}
+			weights, err := LoadWeights(v)
+			if err != nil {
+				return "", fmt.Errorf("cannot load weights: %w", err)
+			}
+			st.cfg.Weights = weights
+			return "", nil
+		}})
+	o.Declare(Option{Name: "EvalFile", Type: String,
+		Usage: "NNUE network file",
+		OnChange: func(v string) (string, error) {
+			if v == "" {
+				st.cfg.Network = nil
+				return "", nil
+			}
+			nn, err := LoadNetwork(v)
+			switch {
+			case err != nil && st.cfg.Network == nil:
+				return "", fmt.Errorf("cannot load network, using the hand-crafted evaluation: %w", err)
+			case err != nil:
+				return "", fmt.Errorf("cannot load network, keeping the one loaded: %w", err)
+			}
+			st.cfg.Network = nn
+			return fmt.Sprintf("NNUE evaluation using %s (768x%d)", v, nn.Hidden), nil
+		}})
+	o.Declare(Option{Name: "Contempt", Type: Spin, Default: "0", Min: -100, Max: 100,
+		Usage:    "centipawns the engine gives up to avoid a draw; negative values seek one",
+		OnChange: func(v string) (string, error) { st.cfg.Contempt, _ = strconv.Atoi(v); return "", nil }})
+	o.Declare(Option{Name: "Move Overhead", Type: Spin, Default: strconv.Itoa(int(DefaultMoveOverhead.Milliseconds())), Min: 0, Max: 5000,
+		Usage: "milliseconds lost per move to communication, held back from the clock",
+		OnChange: func(v string) (string, error) {
+			ms, _ := strconv.Atoi(v)
+			st.cfg.MoveOverhead = time.Duration(ms) * time.Millisecond
+			return "", nil
+		}})
+	o.Declare(Option{Name: "MultiPV", Type: Spin, Default: "1", Min: 1, Max: 64,
+		Usage:    "best lines searched and reported, for analysis",
+		OnChange: func(v string) (string, error) { st.cfg.MultiPV, _ = strconv.Atoi(v); return "", nil }})
+	o.Declare(Option{Name: "Skill Level", Type: Spin, Default: strconv.Itoa(MaxSkill), Min: 0, Max: MaxSkill,
+		Usage:    "playing strength, 0 (weakest) to 20 (full strength)",
+		OnChange: func(v string) (string, error) { st.skill, _ = strconv.Atoi(v); return "", nil }})
+	o.Declare(Option{Name: "UCI_LimitStrength", Type: Check, Default: "false",
+		Usage:    "play at the rating in UCI_Elo instead of the Skill Level",
+		OnChange: func(v string) (string, error) { st.limitStrength = v == "true"; return "", nil }})
+	o.Declare(Option{Name: "UCI_Elo", Type: Spin, Default: strconv.Itoa(MinElo), Min: MinElo, Max: MaxElo,
+		Usage:    "rating to play at with UCI_LimitStrength",
+		OnChange: func(v string) (string, error) { st.elo, _ = strconv.Atoi(v); return "", nil }})
+	return st
+}
+
+// searchFunc returns search, or the built-in search when it is nil, consulting
+// the opening book first when OwnBook is enabled and a book is loaded.
+func (st *settings) searchFunc(search SearchFunc) SearchFunc {
+	if search == nil {
+		search = st.cfg.Search
+	}
+	if !st.ownBook || st.book == nil {
+		return search
+	}
+	sel := book.WeightedRandom
+	if st.bookBest {
+		sel = book.BestMove
+	}
+	return WithBook(st.book, sel, search)
+}
+
+// strength returns the playing strength set by the options: UCI_Elo when
+// UCI_LimitStrength is on, otherwise the Skill Level.
+func (st *settings) strength() Strength {
+	if st.limitStrength {
+		return EloStrength(st.elo)
+	}
+	return SkillLevel(st.skill)
+}
@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"time"
+
+	chess "chess_go/internal/chess"
+)
+
+// DefaultMoveOverhead is the default of the Move Overhead option.
+const DefaultMoveOverhead = 30 * time.Millisecond
+
+const (
+	// horizonMoves is the number of moves a clock is planned over when the
+	// next time control is further away or there is none.
+	horizonMoves = 50
+	// emergencyTime is the usable time below which a clock that the increment
+	// cannot refill puts the time manager in emergency mode.
+	emergencyTime = time.Second
+	// minThink is the least time a search is given.
+	minThink = time.Millisecond
+)
+
+// TimeLimits is the time a search may spend on one move.
+type TimeLimits struct {
+	// Soft is the target: no new iteration is started once it is near,
+	// although an unstable search may stretch it up to Hard.
+	Soft time.Duration
+	// Hard is the deadline at which the search is stopped; 0 means none.
+	Hard time.Duration
+	// Emergency reports that the clock is nearly out: the search thinks
+	// briefly and does not stretch the soft limit.
+	Emergency bool
+}
+
+// Limits allots time to the move of side. Overhead, the time lost to
+// communication and the GUI per move, is held back from the clock.
+//
+// A fixed move time, less the overhead, is both limits. A clock is planned
+// over the moves to the next time control, or 50 moves: the time left plus
+// the increments to come, less the overhead of every one of those moves, is
+// shared equally for the soft limit. The hard limit is three times that, but
+// never more than 40% of the clock (75% with one move to go). With under a
+// second left and too small an increment to refill the clock, the emergency
+// limits are a fraction of that. Depth and node limits alone set no time
+// limit; no limit at all searches for one second.
+func (tc TimeControl) Limits(side chess.Color, overhead time.Duration) TimeLimits {
+	remaining, inc := tc.WTime, tc.WInc
+	if side == chess.Black {
+		remaining, inc = tc.BTime, tc.BInc
+	}
+	switch {
+	case tc.Infinite:
+		return TimeLimits{}
+	case tc.MoveTime > 0:
+		t := max(tc.MoveTime-overhead, minThink)
+		return TimeLimits{Soft: t, Hard: t}
+	case remaining <= 0 && (tc.Depth > 0 || tc.Nodes > 0):
+		return TimeLimits{}
+	case remaining <= 0:
+		return TimeLimits{Soft: defaultMoveTime, Hard: defaultMoveTime}
+	}
+
+	horizon, fraction := horizonMoves, 0.4
+	if tc.MovesToGo > 0 {
+		horizon = min(tc.MovesToGo, horizonMoves)
+		if tc.MovesToGo == 1 {
+			fraction = 0.75
+		}
+	}
+	usable := max(remaining-overhead, 0)
+	if usable < emergencyTime && inc < usable/4 {
+		soft := usable/time.Duration(2*horizon) + inc/2
+		hard := min(2*soft, usable/5)
+		return TimeLimits{Soft: max(min(soft, hard), minThink), Hard: max(hard, minThink), Emergency: true}
+	}
+	n := time.Duration(horizon)
+	avail := max(remaining+inc*(n-1)-overhead*(n+2), 0)
+	soft := avail / n
+	hard := min(3*soft, time.Duration(float64(remaining)*fraction)-overhead)
+	return TimeLimits{Soft: max(min(soft, hard), minThink), Hard: max(hard, minThink)}
+}
+
+// TimeManager decides between the iterations of a search whether to go
+// deeper. The soft limit is stretched while the best move keeps changing or
+// the score is falling, since more time is most valuable then, and never
+// beyond the hard limit.
+type TimeManager struct {
+	limits      TimeLimits
+	iterations  int
+	best        chess.Move
+	score       int
+	instability float64 // decaying count of best-move changes
+	drop        int     // score lost in the last iteration, in centipawns
+}
+
+// NewTimeManager returns the time manager for a search by side under tc.
+func NewTimeManager(tc TimeControl, side chess.Color, overhead time.Duration) *TimeManager {
+	return &TimeManager{limits: tc.Limits(side, overhead)}
+}
+
+// Limits returns the soft and hard limits of the search.
+func (tm *TimeManager) Limits() TimeLimits { return tm.limits }
+
+// Iteration records the result of a completed iteration.
+func (tm *TimeManager) Iteration(best chess.Move, score int) {
+	tm.instability /= 2
+	tm.drop = 0
+	if tm.iterations > 0 {
+		if best != tm.best {
+			tm.instability++
+		}
+		tm.drop = max(tm.score-score, 0)
+	}
+	tm.iterations++
+	tm.best, tm.score = best, score
+}
+
+// Soft returns the soft limit as stretched by the instability of the search.
+func (tm *TimeManager) Soft() time.Duration {
+	if tm.limits.Emergency || tm.limits.Soft == tm.limits.Hard {
+		return tm.limits.Soft
+	}
+	scale := (1 + tm.instability) * (1 + float64(min(tm.drop, 100))/200)
+	return min(time.Duration(float64(tm.limits.Soft)*scale), tm.limits.Hard)
+}
+
+// Continue reports whether another iteration should start after elapsed.
+// As an iteration takes about twice as long as all before it, none is
+// started past 60% of the soft limit.
+func (tm *TimeManager) Continue(elapsed time.Duration) bool {
+	if tm.limits.Hard == 0 {
+		return true
+	}
+	return elapsed < tm.Soft()*3/5
+}
@@ -0,0 +1,251 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	chess "chess_go/internal/chess"
+)
+
+// Default limits of a Tracer built by NewTracer with zero arguments.
+const (
+	DefaultTracePly   = 4
+	DefaultTraceNodes = 20000
+)
+
+// Cutoff is the reason a traced node returned.
+type Cutoff string
+
+// The reasons a node returns. A node that searched all its moves without a
+// beta cutoff has no reason.
+const (
+	CutoffNone      Cutoff = ""
+	CutoffBeta      Cutoff = "beta"      // a move scored at least beta
+	CutoffStandPat  Cutoff = "stand-pat" // quiescence: the static evaluation reached beta
+	CutoffDraw      Cutoff = "draw"      // repetition, fifty-move rule or stalemate
+	CutoffMate      Cutoff = "mate"      // the side to move is checkmated
+	CutoffTablebase Cutoff = "tablebase" // scored by a tablebase probe
+	CutoffStopped   Cutoff = "stopped"   // the search ran out of time or nodes
+)
+
+// TraceNode is one node of a traced search tree. Alpha and Beta are the
+// window the node was searched with and Score its result, all from the view
+// of the side to move at the node.
+type TraceNode struct {
+	Move       chess.Move // move leading to the node; zero at the root
+	Ply        int
+	Depth      int // remaining depth on entry, before any extension
+	Extension  int // plies added to the depth, for check
+	Alpha      int
+	Beta       int
+	Score      int
+	Cutoff     Cutoff
+	Quiescence bool // the node was searched by the quiescence search
+	Children   []*TraceNode
+}
+
+// Trace is the search tree of one iteration.
+type Trace struct {
+	FEN       string
+	Depth     int // the iteration's depth
+	Nodes     int // nodes recorded
+	Truncated bool
+	Root      *TraceNode
+}
+
+// Tracer records the search tree of every iteration of the searches it is
+// attached to (Config.Trace) and keeps the last completed one. Only the nodes
+// up to MaxPly plies from the root are recorded, and at most MaxNodes of them;
+// the rest are searched as usual. A Tracer must not be used by two searches
+// at once, though Last may be called during a search.
+type Tracer struct {
+	MaxPly   int
+	MaxNodes int
+
+	mu   sync.Mutex
+	last *Trace
+
+	cur   *Trace
+	stack []*TraceNode // path to the current node; nil entries are not recorded
+}
+
+// NewTracer returns a Tracer recording up to maxPly plies and maxNodes nodes;
+// zero selects DefaultTracePly and DefaultTraceNodes.
+func NewTracer(maxPly, maxNodes int) *Tracer {
+	if maxPly <= 0 {
+		maxPly = DefaultTracePly
+	}
+	if maxNodes <= 0 {
+		maxNodes = DefaultTraceNodes
+	}
+	return &Tracer{MaxPly: maxPly, MaxNodes: maxNodes}
+}
+
+// Last returns the tree of the last completed iteration, or nil before one.
+func (t *Tracer) Last() *Trace {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last
+}
+
+// begin starts the tree of an iteration to depth from g.
+func (t *Tracer) begin(g chess.Game, depth int) {
+	root := &TraceNode{Depth: depth, Alpha: -infinity, Beta: infinity}
+	t.cur = &Trace{FEN: g.ToFEN(), Depth: depth, Nodes: 1, Root: root}
+	t.stack = append(t.stack[:0], root)
+}
+
+// end completes the iteration with its score; an incomplete iteration is dropped.
+func (t *Tracer) end(score int, completed bool) {
+	if completed {
+		t.cur.Root.Score = score
+		t.mu.Lock()
+		t.last = t.cur
+		t.mu.Unlock()
+	}
+	t.cur, t.stack = nil, t.stack[:0]
+}
+
+// push enters the node reached by m.
+func (t *Tracer) push(m chess.Move, ply, depth, alpha, beta int) {
+	parent := t.stack[len(t.stack)-1]
+	if parent == nil || ply > t.MaxPly || t.cur.Nodes >= t.MaxNodes {
+		if parent != nil {
+			t.cur.Truncated = true
+		}
+		t.stack = append(t.stack, nil)
+		return
+	}
+	n := &TraceNode{Move: m, Ply: ply, Depth: depth, Alpha: alpha, Beta: beta}
+	parent.Children = append(parent.Children, n)
+	t.cur.Nodes++
+	t.stack = append(t.stack, n)
+}
+
+// pop leaves the current node with its score.
+func (t *Tracer) pop(score int) {
+	if n := t.stack[len(t.stack)-1]; n != nil {
+		n.Score = score
+	}
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+// node returns the current node, nil if it is not recorded.
+func (t *Tracer) node() *TraceNode { return t.stack[len(t.stack)-1] }
+
+// cut records why the current node returned.
+func (t *Tracer) cut(c Cutoff) {
+	if n := t.node(); n != nil {
+		n.Cutoff = c
+	}
+}
+
+// extend records an extension of the current node.
+func (t *Tracer) extend(plies int) {
+	if n := t.node(); n != nil {
+		n.Extension += plies
+	}
+}
+
+// quiesce marks the current node as searched by the quiescence search.
+func (t *Tracer) quiesce() {
+	if n := t.node(); n != nil {
+		n.Quiescence = true
+	}
+}
+
+// traceNodeJSON is the JSON form of a TraceNode, with the move in UCI notation.
+type traceNodeJSON struct {
+	Move       string           `json:"move,omitempty"`
+	Ply        int              `json:"ply"`
+	Depth      int              `json:"depth"`
+	Extension  int              `json:"extension,omitempty"`
+	Alpha      int              `json:"alpha"`
+	Beta       int              `json:"beta"`
+	Score      int              `json:"score"`
+	Cutoff     Cutoff           `json:"cutoff,omitempty"`
+	Quiescence bool             `json:"quiescence,omitempty"`
+	Children   []*traceNodeJSON `json:"children,omitempty"`
+}
+
+func (n *TraceNode) toJSON() *traceNodeJSON {
+	j := &traceNodeJSON{Ply: n.Ply, Depth: n.Depth, Extension: n.Extension,
+		Alpha: n.Alpha, Beta: n.Beta, Score: n.Score, Cutoff: n.Cutoff, Quiescence: n.Quiescence}
+	if n.Move != (chess.Move{}) {
+		j.Move = n.Move.UCIString()
+	}
+	for _, c := range n.Children {
+		j.Children = append(j.Children, c.toJSON())
+	}
+	return j
+}
+
+// WriteJSON writes the trace as an indented JSON object. Scores are in
+// centipawns; a window bound of ±100001 is unbounded.
+func (tr *Trace) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		FEN       string         `json:"fen"`
+		Depth     int            `json:"depth"`
+		Nodes     int            `json:"nodes"`
+		Truncated bool           `json:"truncated,omitempty"`
+		Root      *traceNodeJSON `json:"root"`
+	}{tr.FEN, tr.Depth, tr.Nodes, tr.Truncated, tr.Root.toJSON()})
+}
+
+// WriteDOT writes the trace as a Graphviz digraph, one box per node labelled
+// with its move, window, score, extension and cutoff.
+// Beta cutoffs are drawn red and quiescence nodes dashed.
+func (tr *Trace) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "digraph search {\n\tlabel=%q;\n\tnode [shape=box, fontname=monospace];\n",
+		fmt.Sprintf("%s depth %d", tr.FEN, tr.Depth))
+	id := 0
+	var walk func(n *TraceNode) int
+	walk = func(n *TraceNode) int {
+		me := id
+		id++
+		name := "root"
+		if n.Move != (chess.Move{}) {
+			name = n.Move.UCIString()
+		}
+		label := fmt.Sprintf("%s\nd=%d [%s, %s]\n%s", name, n.Depth, traceBound(n.Alpha), traceBound(n.Beta), traceBound(n.Score))
+		if n.Extension > 0 {
+			label += fmt.Sprintf(" +%d", n.Extension)
+		}
+		if n.Cutoff != CutoffNone {
+			label += " " + string(n.Cutoff)
+		}
+		var attrs string
+		switch {
+		case n.Cutoff == CutoffBeta:
+			attrs = ", color=red"
+		case n.Quiescence:
+			attrs = ", style=dashed"
+		}
+		fmt.Fprintf(&sb, "\tn%d [label=%q%s];\n", me, label, attrs)
+		for _, c := range n.Children {
+			fmt.Fprintf(&sb, "\tn%d -> n%d;\n", me, walk(c))
+		}
+		return me
+	}
+	walk(tr.Root)
+	sb.WriteString("}\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// traceBound formats a score or window bound for a DOT label.
+func traceBound(v int) string {
+	switch {
+	case v >= infinity:
+		return "+inf"
+	case v <= -infinity:
+		return "-inf"
+	}
+	return FormatScore(v)
+}
@@ -0,0 +1,379 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	chess "chess_go/internal/chess"
+)
+
+// UCIHandler speaks the Universal Chess Interface over a line-oriented reader and writer.
+// Commands are read on a separate goroutine so that stop and quit are handled
+// while a search is running (ADR-004).
+type UCIHandler struct {
+	search   SearchFunc
+	settings *settings
+	game     chess.Game
+	tracer   *Tracer // set by "trace on"; kept after "trace off" for dumping
+	debug    bool    // "debug on": report commands that are ignored
+}
+
+// NewUCIHandler returns a handler that runs search for every go command.
+// A nil search selects the built-in search, configured through setoption.
+func NewUCIHandler(search SearchFunc) *UCIHandler {
+	g, _ := chess.NewGameFromFEN(chess.StartFEN)
+	return &UCIHandler{search: search, settings: newSettings(), game: g}
+}
+
+// syncWriter serialises writes from the dispatcher and the search goroutine.
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// Run processes commands from r until quit or end of input, writing responses to w.
+func (h *UCIHandler) Run(r io.Reader, w io.Writer) {
+	out := &syncWriter{w: w}
+
+	lines := make(chan string)
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			select {
+			case lines <- sc.Text():
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	var running *runningSearch
+	for {
+		select {
+		case res := <-running.results():
+			running.cancel()
+			running = nil
+			writeBestMove(out, res.BestMove)
+		case line, ok := <-lines:
+			if !ok {
+				if running != nil {
+					writeBestMove(out, running.stop().BestMove)
+				}
+				return
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			switch fields[0] {
+			case "uci":
+				h.writeID(out)
+			case "isready":
+				_, _ = fmt.Fprintln(out, "readyok")
+			case "ucinewgame":
+				h.game, _ = chess.NewGameFromFEN(chess.StartFEN)
+			case "setoption":
+				h.setOption(out, fields[1:])
+			case "debug":
+				if len(fields) > 1 && (fields[1] == "on" || fields[1] == "off") {
+					h.debug = fields[1] == "on"
+				}
+			case "trace":
+				h.trace(out, fields[1:])
+			case "position":
+				if g, err := parsePosition(fields[1:]); err != nil {
+					_, _ = fmt.Fprintf(out, "info string %v\n", err)
+				} else {
+					h.game = g
+				}
+			case "go":
+				if running != nil {
+					writeBestMove(out, running.stop().BestMove)
+				}
+				tc := parseGo(fields[1:])
+				tc.Strength = h.settings.strength()
+				running = startSearch(h.settings.searchFunc(h.search), h.game, tc, out)
+			case "stop":
+				if running != nil {
+					writeBestMove(out, running.stop().BestMove)
+					running = nil
+				}
+			case "quit":
+				if running != nil {
+					running.stop()
+				}
+				return
+			default:
+				if h.debug {
+					_, _ = fmt.Fprintf(out, "info string unknown command %q\n", fields[0])
+				}
+			}
+		}
+	}
+}
+
+// runningSearch is a search goroutine launched by a go command.
+type runningSearch struct {
+	cancel context.CancelFunc
+	done   chan SearchResult
+}
+
+// startSearch runs search on its own goroutine. In infinite mode the result is
+// held back until the search is stopped, as UCI forbids an early bestmove.
+func startSearch(search SearchFunc, g chess.Game, tc TimeControl, info io.Writer) *runningSearch {
+	ctx, cancel := context.WithCancel(context.Background())
+	rs := &runningSearch{cancel: cancel, done: make(chan SearchResult, 1)}
+	go func() {
+		res := search(ctx, g, tc, info)
+		if tc.Infinite {
+			<-ctx.Done()
+		}
+		rs.done <- res
+	}()
+	return rs
+}
+
+// results returns the channel delivering the search result; nil when no search runs,
+// so that selecting on it blocks.
+func (rs *runningSearch) results() <-chan SearchResult {
+	if rs == nil {
+		return nil
+	}
+	return rs.done
+}
+
+// stop cancels the search and waits for its result.
+func (rs *runningSearch) stop() SearchResult {
+	rs.cancel()
+	return <-rs.done
+}
+
+// writeID answers the uci command with identification, options and uciok.
+func (h *UCIHandler) writeID(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "id name chess-go")
+	_, _ = fmt.Fprintln(w, "id author the chess-go authors")
+	h.settings.options.WriteUCI(w)
+	_, _ = fmt.Fprintln(w, "uciok")
+}
+
+// Options returns the option registry, for command-line flags and config
+// files; values set there are in effect before the first command.
+func (h *UCIHandler) Options() *Options { return &h.settings.options }
+
+// setOption handles "setoption name <id> [value <x>]".
+func (h *UCIHandler) setOption(w io.Writer, args []string) {
+	note, err := h.settings.options.Set(parseSetOption(args))
+	switch {
+	case err != nil:
+		_, _ = fmt.Fprintf(w, "info string %v\n", err)
+	case note != "":
+		_, _ = fmt.Fprintf(w, "info string %s\n", note)
+	}
+}
+
+// trace handles "trace on [ply N] [nodes N]", which traces the search tree of
+// the following searches up to N plies and N nodes, "trace off", and
+// "trace dump [json|dot] [file]", which writes the tree of the last completed
+// iteration to file, or to w. Tracing applies to the built-in search only.
+// trace is not a UCI command; GUIs that do not know it never send it.
+func (h *UCIHandler) trace(w io.Writer, args []string) {
+	if len(args) == 0 {
+		_, _ = fmt.Fprintln(w, "info string trace: want on, off or dump")
+		return
+	}
+	switch args[0] {
+	case "on":
+		var ply, nodes int
+		for i := 1; i+1 < len(args); i += 2 {
+			n, err := strconv.Atoi(args[i+1])
+			switch {
+			case err != nil:
+				_, _ = fmt.Fprintf(w, "info string trace: invalid %s %q\n", args[i], args[i+1])
+				return
+			case args[i] == "ply":
+				ply = n
+			case args[i] == "nodes":
+				nodes = n
+			}
+		}
+		h.tracer = NewTracer(ply, nodes)
+		h.settings.cfg.Trace = h.tracer
+		_, _ = fmt.Fprintf(w, "info string tracing up to %d plies and %d nodes\n", h.tracer.MaxPly, h.tracer.MaxNodes)
+	case "off":
+		h.settings.cfg.Trace = nil
+	case "dump":
+		h.dumpTrace(w, args[1:])
+	default:
+		_, _ = fmt.Fprintf(w, "info string trace: unknown argument %q\n", args[0])
+	}
+}
+
+// dumpTrace writes the last traced iteration for "trace dump".
+func (h *UCIHandler) dumpTrace(w io.Writer, args []string) {
+	var tr *Trace
+	if h.tracer != nil {
+		tr = h.tracer.Last()
+	}
+	if tr == nil {
+		_, _ = fmt.Fprintln(w, "info string trace: no search traced; send trace on first")
+		return
+	}
+	write := tr.WriteJSON
+	if len(args) > 0 && (args[0] == "json" || args[0] == "dot") {
+		if args[0] == "dot" {
+			write = tr.WriteDOT
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		if err := write(w); err != nil {
+			_, _ = fmt.Fprintf(w, "info string trace: %v\n", err)
+		}
+		return
+	}
+	path := strings.Join(args, " ")
+	f, err := os.Create(path)
+	if err == nil {
+		err = write(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "info string trace: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "info string wrote depth %d trace of %d nodes to %s\n", tr.Depth, tr.Nodes, path)
+}
+
+// parseSetOption splits setoption arguments into option name and value.
+// Names and values may contain spaces.
+func parseSetOption(args []string) (string, string) {
+	var name, value []string
+	var cur *[]string
+	for _, a := range args {
+		switch a {
+		case "name":
+			cur = &name
+		case "value":
+			cur = &value
+		default:
+			if cur != nil {
+				*cur = append(*cur, a)
+			}
+		}
+	}
+	return strings.Join(name, " "), strings.Join(value, " ")
+}
+
+// parsePosition handles "startpos [moves ...]" and "fen <fen> [moves ...]".
+func parsePosition(args []string) (chess.Game, error) {
+	if len(args) == 0 {
+		return chess.Game{}, fmt.Errorf("position: missing argument")
+	}
+	var (
+		g    chess.Game
+		err  error
+		rest []string
+	)
+	switch args[0] {
+	case "startpos":
+		g, err = chess.NewGameFromFEN(chess.StartFEN)
+		rest = args[1:]
+	case "fen":
+		end := len(args)
+		for i, a := range args {
+			if a == "moves" {
+				end = i
+				break
+			}
+		}
+		g, err = chess.NewGameFromFEN(strings.Join(args[1:end], " "))
+		rest = args[end:]
+	default:
+		return chess.Game{}, fmt.Errorf("position: unknown argument %q", args[0])
+	}
+	if err != nil {
+		return chess.Game{}, err
+	}
+	if len(rest) > 0 && rest[0] == "moves" {
+		for _, s := range rest[1:] {
+			m, err := chess.ParseUCI(g, s)
+			if err != nil {
+				return chess.Game{}, err
+			}
+			g, _ = g.Apply(m)
+		}
+	}
+	return g, nil
+}
+
+// parseGo converts "go" arguments into a TimeControl.
+func parseGo(args []string) TimeControl {
+	var tc TimeControl
+	ms := func(i int) time.Duration {
+		if i+1 >= len(args) {
+			return 0
+		}
+		n, err := strconv.Atoi(args[i+1])
+		if err != nil {
+			return 0
+		}
+		return time.Duration(n) * time.Millisecond
+	}
+	count := func(i int) int64 {
+		if i+1 >= len(args) {
+			return 0
+		}
+		n, _ := strconv.ParseInt(args[i+1], 10, 64)
+		return n
+	}
+	for i, a := range args {
+		switch a {
+		case "movetime":
+			tc.MoveTime = ms(i)
+		case "wtime":
+			tc.WTime = ms(i)
+		case "btime":
+			tc.BTime = ms(i)
+		case "winc":
+			tc.WInc = ms(i)
+		case "binc":
+			tc.BInc = ms(i)
+		case "movestogo":
+			tc.MovesToGo = int(count(i))
+		case "depth":
+			tc.Depth = int(count(i))
+		case "nodes":
+			tc.Nodes = count(i)
+		case "infinite":
+			tc.Infinite = true
+		}
+	}
+	return tc
+}
+
+// writeBestMove emits the bestmove line; "0000" is the UCI null move.
+func writeBestMove(w io.Writer, m chess.Move) {
+	if m == (chess.Move{}) {
+		_, _ = fmt.Fprintln(w, "bestmove 0000")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "bestmove %s\n", m.UCIString())
+}
@@ -0,0 +1,423 @@
+package engine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	chess "chess_go/internal/chess"
+)
+
+// XBoardHandler speaks the Chess Engine Communication Protocol (CECP, version
+// 2) used by XBoard and WinBoard. It drives the same SearchFunc as UCIHandler;
+// only the command set and the thinking output differ.
+type XBoardHandler struct {
+	search SearchFunc
+	cfg    Config
+
+	game    chess.Game
+	history []chess.Game // positions before each move, for undo and remove
+	force   bool         // force mode: the engine plays neither side
+	engine  chess.Color  // the side the engine plays when not in force mode
+	post    bool         // emit thinking output
+	analyze bool         // analyze mode: search the current position until told otherwise
+
+	mps      int           // moves per time control period; 0 = whole game
+	base     time.Duration // time per period
+	inc      time.Duration // increment per move
+	moveTime time.Duration // st: exact time per move
+	depth    int           // sd: depth limit
+	clock    time.Duration // engine's remaining time, from "time"
+	moves    int           // engine moves played, for moves-to-go with mps
+}
+
+// NewXBoardHandler returns a handler that runs search for every move the engine
+// makes. A nil search selects the built-in search.
+func NewXBoardHandler(search SearchFunc) *XBoardHandler {
+	g, _ := chess.NewGameFromFEN(chess.StartFEN)
+	return &XBoardHandler{search: search, cfg: Config{Syzygy50MoveRule: true, MoveOverhead: DefaultMoveOverhead}, game: g, engine: chess.Black}
+}
+
+// Run processes commands from r until quit or end of input, writing responses to w.
+func (h *XBoardHandler) Run(r io.Reader, w io.Writer) {
+	out := &syncWriter{w: w}
+
+	lines := make(chan string)
+	quit := make(chan struct{})
+	defer close(quit)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(r)
+		for sc.Scan() {
+			select {
+			case lines <- sc.Text():
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	var running *runningSearch
+	// stop cancels a running search and discards its move.
+	stop := func() {
+		if running != nil {
+			running.stop()
+			running = nil
+		}
+	}
+	// think starts the engine's search for its move, or an analysis.
+	think := func() {
+		stop()
+		if h.game.Result() != chess.InProgress {
+			return
+		}
+		tc := h.timeControl()
+		var info io.Writer = io.Discard
+		if h.post || h.analyze {
+			info = &thinkingWriter{w: out}
+		}
+		running = startSearch(h.searchFunc(), h.game, tc, info)
+	}
+	// resume restarts analysis, or lets the engine move if it is on move.
+	resume := func() {
+		if h.analyze || (!h.force && h.game.State.ActiveColor == h.engine) {
+			think()
+		}
+	}
+
+	for {
+		select {
+		case res := <-running.results():
+			running.cancel()
+			running = nil
+			h.play(out, res.BestMove)
+			resume()
+		case line, ok := <-lines:
+			if !ok {
+				stop()
+				return
+			}
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			args := fields[1:]
+			switch fields[0] {
+			case "xboard", "accepted", "rejected", "random", "hard", "easy",
+				"computer", "name", "rating", "ics", "white", "black", "draw", "bk", "hint":
+			case "protover":
+				h.writeFeatures(out)
+			case "new":
+				stop()
+				h.game, _ = chess.NewGameFromFEN(chess.StartFEN)
+				h.history = nil
+				h.force, h.engine, h.depth, h.moves = false, chess.Black, 0, 0
+				resume()
+			case "setboard":
+				stop()
+				g, err := chess.NewGameFromFEN(strings.Join(args, " "))
+				if err != nil {
+					_, _ = fmt.Fprintln(out, "tellusererror Illegal position")
+					continue
+				}
+				h.game, h.history = g, nil
+				resume()
+			case "usermove":
+				if len(args) == 0 {
+					continue
+				}
+				stop()
+				if !h.userMove(out, args[0]) {
+					continue
+				}
+				resume()
+			case "go":
+				h.force = false
+				h.engine = h.game.State.ActiveColor
+				think()
+			case "force":
+				stop()
+				h.force = true
+			case "playother":
+				stop()
+				h.force = false
+				h.engine = 1 - h.game.State.ActiveColor
+			case "?":
+				if running != nil && !h.analyze {
+					h.play(out, running.stop().BestMove)
+					running = nil
+					resume()
+				}
+			case "level":
+				h.setLevel(args)
+			case "st":
+				if n, err := strconv.Atoi(arg(args, 0)); err == nil {
+					h.moveTime = time.Duration(n) * time.Second
+				}
+			case "sd":
+				h.depth, _ = strconv.Atoi(arg(args, 0))
+			case "time":
+				h.clock = centiseconds(arg(args, 0))
+			case "otim":
+				// The opponent's clock does not influence the engine's budget.
+			case "undo", "remove":
+				stop()
+				n := 1
+				if fields[0] == "remove" {
+					n = 2
+				}
+				for ; n > 0 && len(h.history) > 0; n-- {
+					h.game = h.history[len(h.history)-1]
+					h.history = h.history[:len(h.history)-1]
+				}
+				if h.analyze {
+					think()
+				}
+			case "post":
+				h.post = true
+			case "nopost":
+				h.post = false
+			case "analyze":
+				h.analyze = true
+				think()
+			case "exit":
+				stop()
+				h.analyze = false
+			case ".":
+				// Periodic status requests in analyze mode are not answered.
+			case "result":
+				stop()
+				h.force = true
+			case "ping":
+				_, _ = fmt.Fprintf(out, "pong %s\n", arg(args, 0))
+			case "egtpath":
+				h.setEGTPath(out, args)
+			case "quit":
+				stop()
+				return
+			default:
+				// CECP lets the GUI send a bare move when usermove=1 was rejected.
+				if m, err := chess.ParseUCI(h.game, fields[0]); err == nil {
+					stop()
+					h.apply(m)
+					resume()
+					continue
+				}
+				_, _ = fmt.Fprintf(out, "Error (unknown command): %s\n", fields[0])
+			}
+		}
+	}
+}
+
+// writeFeatures answers protover 2.
+func (h *XBoardHandler) writeFeatures(w io.Writer) {
+	_, _ = fmt.Fprintln(w, `feature done=0`)
+	_, _ = fmt.Fprintln(w, `feature myname="chess-go" setboard=1 usermove=1 ping=1 analyze=1 colors=0 playother=1`+
+		` sigint=0 sigterm=0 reuse=1 san=0 time=1 draw=0 variants="normal" egt="syzygy"`)
+	_, _ = fmt.Fprintln(w, `feature done=1`)
+}
+
+// userMove applies a move in coordinate notation, reporting illegal moves.
+func (h *XBoardHandler) userMove(w io.Writer, s string) bool {
+	m, err := chess.ParseUCI(h.game, s)
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "Illegal move: %s\n", s)
+		return false
+	}
+	h.apply(m)
+	return true
+}
+
+// apply plays m, remembering the previous position for undo.
+func (h *XBoardHandler) apply(m chess.Move) {
+	next, err := h.game.Apply(m)
+	if err != nil {
+		return
+	}
+	h.history = append(h.history, h.game)
+	h.game = next
+}
+
+// play makes the engine's move and claims the result if the game is over.
+func (h *XBoardHandler) play(w io.Writer, m chess.Move) {
+	if h.analyze || m == (chess.Move{}) {
+		return
+	}
+	h.apply(m)
+	h.moves++
+	_, _ = fmt.Fprintf(w, "move %s\n", m.UCIString())
+	if claim := resultClaim(h.game); claim != "" {
+		_, _ = fmt.Fprintln(w, claim)
+	}
+}
+
+// resultClaim returns the CECP result line for a finished game, or "".
+func resultClaim(g chess.Game) string {
+	switch g.Result() {
+	case chess.WhiteWins:
+		return "1-0 {White mates}"
+	case chess.BlackWins:
+		return "0-1 {Black mates}"
+	case chess.Stalemate:
+		return "1/2-1/2 {Stalemate}"
+	case chess.DrawFiftyMove:
+		return "1/2-1/2 {Fifty move rule}"
+	case chess.DrawThreefoldRepetition:
+		return "1/2-1/2 {Threefold repetition}"
+	case chess.DrawInsufficientMaterial:
+		return "1/2-1/2 {Insufficient material}"
+	}
+	return ""
+}
+
+// setLevel handles "level MPS BASE INC", where BASE is minutes or minutes:seconds.
+func (h *XBoardHandler) setLevel(args []string) {
+	if len(args) < 3 {
+		return
+	}
+	h.mps, _ = strconv.Atoi(args[0])
+	minutes, seconds, _ := strings.Cut(args[1], ":")
+	m, _ := strconv.Atoi(minutes)
+	s, _ := strconv.Atoi(seconds)
+	h.base = time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	inc, _ := strconv.ParseFloat(args[2], 64)
+	h.inc = time.Duration(inc * float64(time.Second))
+	h.moveTime, h.moves = 0, 0
+	h.clock = h.base
+}
+
+// timeControl converts the CECP clock settings into a TimeControl for the
+// side the engine is about to search for.
+func (h *XBoardHandler) timeControl() TimeControl {
+	tc := TimeControl{Depth: h.depth}
+	switch {
+	case h.analyze:
+		tc.Infinite = true
+	case h.moveTime > 0:
+		tc.MoveTime = h.moveTime
+	case h.clock > 0:
+		if h.game.State.ActiveColor == chess.White {
+			tc.WTime, tc.WInc = h.clock, h.inc
+		} else {
+			tc.BTime, tc.BInc = h.clock, h.inc
+		}
+		if h.mps > 0 {
+			tc.MovesToGo = h.mps - h.moves%h.mps
+		}
+	}
+	return tc
+}
+
+// setEGTPath handles "egtpath syzygy <path>".
+func (h *XBoardHandler) setEGTPath(w io.Writer, args []string) {
+	if len(args) < 2 || args[0] != "syzygy" {
+		return
+	}
+	tb, err := chess.OpenSyzygy(strings.Join(args[1:], " "))
+	if err != nil {
+		_, _ = fmt.Fprintf(w, "telluser cannot open tablebases: %v\n", err)
+		return
+	}
+	h.cfg.Tablebase = tb
+}
+
+// searchFunc returns the search to run: the injected one or the built-in search.
+func (h *XBoardHandler) searchFunc() SearchFunc {
+	if h.search != nil {
+		return h.search
+	}
+	return h.cfg.Search
+}
+
+// thinkingWriter turns the UCI info lines written by the search into CECP
+// thinking output: "ply score time nodes pv", with time in centiseconds and
+// mates as ±(100000 + moves).
+type thinkingWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (t *thinkingWriter) Write(p []byte) (int, error) {
+	t.buf = append(t.buf, p...)
+	for {
+		i := strings.IndexByte(string(t.buf), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(t.buf[:i])
+		t.buf = t.buf[i+1:]
+		if s, ok := thinkingLine(line); ok {
+			if _, err := fmt.Fprintln(t.w, s); err != nil {
+				return len(p), err
+			}
+		}
+	}
+}
+
+// thinkingLine converts one UCI info line; other lines are dropped.
+func thinkingLine(line string) (string, bool) {
+	f := strings.Fields(line)
+	if len(f) == 0 || f[0] != "info" {
+		return "", false
+	}
+	var depth, score, cs, nodes int64
+	var pv []string
+	var haveScore bool
+	for i := 1; i < len(f); i++ {
+		next := func() int64 {
+			if i+1 >= len(f) {
+				return 0
+			}
+			i++
+			n, _ := strconv.ParseInt(f[i], 10, 64)
+			return n
+		}
+		switch f[i] {
+		case "depth":
+			depth = next()
+		case "nodes":
+			nodes = next()
+		case "time":
+			cs = next() / 10
+		case "score":
+			if i+1 < len(f) {
+				kind := f[i+1]
+				i++
+				n := next()
+				switch {
+				case kind == "mate" && n > 0:
+					score = 100000 + n
+				case kind == "mate":
+					score = -100000 + n
+				default:
+					score = n
+				}
+				haveScore = true
+			}
+		case "pv":
+			pv = f[i+1:]
+			i = len(f)
+		}
+	}
+	if !haveScore {
+		return "", false
+	}
+	return fmt.Sprintf("%d %d %d %d %s", depth, score, cs, nodes, strings.Join(pv, " ")), true
+}
+
+// arg returns args[i], or "" when it is missing.
+func arg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+// centiseconds parses a CECP clock value.
+func centiseconds(s string) time.Duration {
+	n, _ := strconv.Atoi(s)
+	return time.Duration(n) * 10 * time.Millisecond
+}
@@ -0,0 +1,280 @@
+package match
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ErrInvalidTimeControl is returned by ParseTimeControl.
+var ErrInvalidTimeControl = errors.New("invalid time control")
+
+// TimeControl is the time control of every game in a match. Clocked games
+// use Base, Inc and Moves; otherwise each move is limited by MoveTime, Depth
+// or Nodes.
+type TimeControl struct {
+	Base  time.Duration // time per period; 0 = no clock
+	Inc   time.Duration // increment added after every move
+	Moves int           // moves per period, after which Base is added again; 0 = whole game
+
+	MoveTime time.Duration // fixed time per move
+	Depth    int           // fixed depth per move
+	Nodes    int64         // fixed nodes per move
+
+	// Margin is how far an engine may overrun its clock before it loses on time.
+	Margin time.Duration
+}
+
+// ParseTimeControl parses a cutechess-style "[moves/]seconds[+increment]"
+// clock such as "10+0.1", "60" or "40/120".
+func ParseTimeControl(s string) (TimeControl, error) {
+	var tc TimeControl
+	rest := s
+	if moves, after, ok := strings.Cut(rest, "/"); ok {
+		n, err := strconv.Atoi(moves)
+		if err != nil || n <= 0 {
+			return tc, fmt.Errorf("%w: %q", ErrInvalidTimeControl, s)
+		}
+		tc.Moves, rest = n, after
+	}
+	base, inc, hasInc := strings.Cut(rest, "+")
+	seconds := func(v string) (time.Duration, bool) {
+		f, err := strconv.ParseFloat(v, 64)
+		return time.Duration(f * float64(time.Second)), err == nil && f >= 0
+	}
+	var ok bool
+	if tc.Base, ok = seconds(base); !ok || tc.Base == 0 {
+		return tc, fmt.Errorf("%w: %q", ErrInvalidTimeControl, s)
+	}
+	if hasInc {
+		if tc.Inc, ok = seconds(inc); !ok {
+			return tc, fmt.Errorf("%w: %q", ErrInvalidTimeControl, s)
+		}
+	}
+	return tc, nil
+}
+
+// String returns the PGN TimeControl tag value, e.g. "40/120", "10+0.1" or "-".
+func (tc TimeControl) String() string {
+	if tc.Base == 0 {
+		return "-"
+	}
+	s := strconv.FormatFloat(tc.Base.Seconds(), 'f', -1, 64)
+	if tc.Moves > 0 {
+		s = strconv.Itoa(tc.Moves) + "/" + s
+	}
+	if tc.Inc > 0 {
+		s += "+" + strconv.FormatFloat(tc.Inc.Seconds(), 'f', -1, 64)
+	}
+	return s
+}
+
+// Adjudication ends games early once their outcome is clear. Zero fields
+// disable the corresponding rule.
+type Adjudication struct {
+	// A game is drawn once both engines have scored it within DrawScore
+	// centipawns for DrawMoves consecutive moves each, from move DrawMoveNumber on.
+	DrawMoveNumber int
+	DrawMoves      int
+	DrawScore      int
+	// A side resigns once its engine has scored its position at ResignScore
+	// centipawns or more below zero for ResignMoves consecutive moves, and its
+	// opponent agreed on every move in between.
+	ResignMoves int
+	ResignScore int
+	// MaxPlies draws games that are still running after that many plies.
+	MaxPlies int
+}
+
+// Game is a finished match game.
+type Game struct {
+	Round       int // 1-based game number
+	White       string
+	Black       string
+	Start       chess.Game
+	Moves       []chess.Move // opening moves included
+	Result      string       // "1-0", "0-1" or "1/2-1/2"
+	Termination string       // why the game ended, e.g. "checkmate" or "time forfeit"
+}
+
+// PGN returns the game as a PGN game; tc and event fill the matching tags.
+func (g Game) PGN(event string, tc TimeControl) chess.PGNGame {
+	return chess.PGNGame{
+		Tags: map[string]string{
+			"Event":       event,
+			"Site":        "?",
+			"Date":        time.Now().Format("2006.01.02"),
+			"Round":       strconv.Itoa(g.Round),
+			"White":       g.White,
+			"Black":       g.Black,
+			"TimeControl": tc.String(),
+			"Termination": g.Termination,
+		},
+		Start:  g.Start,
+		Moves:  g.Moves,
+		Result: g.Result,
+	}
+}
+
+// errAborted marks a game interrupted by the match being cancelled.
+var errAborted = errors.New("game aborted")
+
+// stopGrace is how long a player may keep thinking past its flag and margin
+// before it is stopped.
+const stopGrace = time.Second
+
+// play plays one game from op between white and black. A player that returns
+// an error or an illegal move loses; the error is returned alongside the game
+// so the caller can replace the player. errAborted means ctx was cancelled.
+// moved, when not nil, is called after every move played by either player.
+func play(ctx context.Context, white, black Player, op Opening, tc TimeControl, adj Adjudication, moved func(Position, engine.SearchResult)) (Game, error) {
+	g := Game{Start: op.Start}
+	pos := Position{Start: op.Start, Game: op.Start}
+	for _, m := range op.Moves {
+		next, err := pos.Game.Apply(m)
+		if err != nil {
+			return g, fmt.Errorf("opening: %w", err)
+		}
+		pos.Game, pos.Moves = next, append(pos.Moves, m)
+	}
+	for _, p := range []Player{white, black} {
+		if err := p.NewGame(ctx); err != nil {
+			return g, err
+		}
+	}
+
+	clock := [2]time.Duration{tc.Base, tc.Base}
+	moves := [2]int{} // moves made by each side, for Moves-per-period clocks
+	var drawRun, resignRun [2]int
+	finish := func(winner chess.Color, draw bool, why string) (Game, error) {
+		g.Moves = pos.Moves
+		g.Termination = why
+		switch {
+		case draw:
+			g.Result = "1/2-1/2"
+		case winner == chess.White:
+			g.Result = "1-0"
+		default:
+			g.Result = "0-1"
+		}
+		return g, nil
+	}
+
+	for ply := 0; ; ply++ {
+		if r := pos.Game.Result(); r != chess.InProgress {
+			return finish(winnerOf(r), r != chess.WhiteWins && r != chess.BlackWins, terminationOf(r))
+		}
+		if adj.MaxPlies > 0 && ply >= adj.MaxPlies {
+			return finish(0, true, "adjudication: move limit")
+		}
+		side := pos.Game.State.ActiveColor
+		player := white
+		if side == chess.Black {
+			player = black
+		}
+
+		etc := engine.TimeControl{MoveTime: tc.MoveTime, Depth: tc.Depth, Nodes: tc.Nodes}
+		moveCtx, cancel := ctx, context.CancelFunc(func() {})
+		if tc.Base > 0 {
+			// A clock overrun within the margin must not read as "no clock".
+			etc.WTime, etc.BTime = max(clock[chess.White], time.Millisecond), max(clock[chess.Black], time.Millisecond)
+			etc.WInc, etc.BInc = tc.Inc, tc.Inc
+			if tc.Moves > 0 {
+				etc.MovesToGo = tc.Moves - moves[side]%tc.Moves
+			}
+			// A player still thinking well past its flag is stopped.
+			moveCtx, cancel = context.WithTimeout(ctx, clock[side]+tc.Margin+stopGrace)
+		}
+		start := time.Now()
+		res, err := player.Move(moveCtx, pos, etc)
+		elapsed := time.Since(start)
+		cancel()
+		if ctx.Err() != nil {
+			return g, errAborted
+		}
+		if err != nil {
+			g, _ = finish(1-side, false, "disconnect")
+			return g, err
+		}
+		next, err := pos.Game.Apply(res.BestMove)
+		if err != nil {
+			return finish(1-side, false, "illegal move "+res.BestMove.UCIString())
+		}
+		if tc.Base > 0 {
+			if elapsed > clock[side]+tc.Margin {
+				return finish(1-side, false, "time forfeit")
+			}
+			moves[side]++
+			clock[side] += tc.Inc - elapsed
+			if tc.Moves > 0 && moves[side]%tc.Moves == 0 {
+				clock[side] += tc.Base
+			}
+		}
+		pos.Game, pos.Moves = next, append(pos.Moves, res.BestMove)
+		if moved != nil {
+			moved(pos, res)
+		}
+
+		// Adjudication counts consecutive moves per side.
+		score := res.Score
+		if adj.DrawMoves > 0 && int(pos.Game.State.FullMoveNumber) >= adj.DrawMoveNumber && abs(score) <= adj.DrawScore {
+			drawRun[side]++
+		} else {
+			drawRun[side] = 0
+		}
+		if adj.DrawMoves > 0 && drawRun[0] >= adj.DrawMoves && drawRun[1] >= adj.DrawMoves {
+			return finish(0, true, "adjudication: draw")
+		}
+		switch {
+		case adj.ResignMoves == 0:
+		case score <= -adj.ResignScore:
+			resignRun[side]++
+		case score >= adj.ResignScore:
+			// The winning side's score backs up a resignation by the other.
+			resignRun[side] = 0
+		default:
+			resignRun[side] = 0
+			resignRun[1-side] = 0
+		}
+		if adj.ResignMoves > 0 && resignRun[side] >= adj.ResignMoves {
+			return finish(1-side, false, "adjudication: resignation")
+		}
+	}
+}
+
+// winnerOf returns the winner of a decisive result.
+func winnerOf(r chess.GameResult) chess.Color {
+	if r == chess.BlackWins {
+		return chess.Black
+	}
+	return chess.White
+}
+
+// terminationOf names a game-ending result of the chess package.
+func terminationOf(r chess.GameResult) string {
+	switch r {
+	case chess.WhiteWins, chess.BlackWins:
+		return "checkmate"
+	case chess.Stalemate:
+		return "stalemate"
+	case chess.DrawFiftyMove:
+		return "fifty-move rule"
+	case chess.DrawThreefoldRepetition:
+		return "threefold repetition"
+	default:
+		return "insufficient material"
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
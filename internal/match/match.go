@@ -0,0 +1,186 @@
+// Package match plays engine-versus-engine matches for regression testing.
+//
+// Each contender is either an engine.SearchFunc run in-process or an external
+// UCI engine run as a subprocess. Games start from an opening suite, each
+// opening played twice with colours reversed, and run concurrently, each with
+// its own pair of players. The result is reported as an Elo difference with a
+// 95% confidence interval and, optionally, a sequential probability ratio test
+// that stops the match once either hypothesis is accepted.
+package match
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// Match describes a match between contenders A and B.
+type Match struct {
+	Event        string
+	A, B         Contender
+	Openings     []Opening // cycled through; empty plays from the standard position
+	Games        int       // games to play, rounded up to an even number
+	Concurrency  int       // games played at once; 0 means 1
+	TimeControl  TimeControl
+	Adjudication Adjudication
+	SPRT         *SPRT     // stop early once the test is decided; nil plays every game
+	PGN          io.Writer // receives every finished game; may be nil
+
+	// Progress, when set, is called after every game with the report so far.
+	Progress func(Game, Report)
+
+	// Move, when set, is called after every move of every game with the
+	// game's round, the position the move led to and the search that chose
+	// it. With Concurrency above 1 it is called from several goroutines.
+	Move func(round int, pos Position, res engine.SearchResult)
+}
+
+// Report is the state of a match, scored from A's point of view.
+type Report struct {
+	Score    Score
+	LLR      float64  // SPRT log-likelihood ratio; 0 without an SPRT
+	Decision Decision // Continue until the SPRT accepts a hypothesis
+}
+
+// outcome is a game finished by a worker, or the error that stopped it.
+type outcome struct {
+	game   Game
+	aWhite bool
+	err    error
+}
+
+// Run plays the match until all games are played, the SPRT is decided, or ctx
+// is cancelled, and returns the report. Games still running when the match
+// stops are abandoned and not counted. An error is returned when a contender
+// cannot be started or the PGN cannot be written.
+func (m Match) Run(ctx context.Context) (Report, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	openings := m.Openings
+	if len(openings) == 0 {
+		start, _ := chess.NewGameFromFEN(chess.StartFEN)
+		openings = []Opening{{Start: start}}
+	}
+	games := m.Games + m.Games%2
+
+	jobs := make(chan int)
+	results := make(chan outcome)
+	var wg sync.WaitGroup
+	for w := 0; w < max(m.Concurrency, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.worker(ctx, openings, jobs, results)
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := 0; i < games; i++ {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var rep Report
+	var firstErr error
+	for o := range results {
+		if o.err != nil {
+			if firstErr == nil {
+				firstErr = o.err
+			}
+			cancel()
+			continue
+		}
+		if firstErr != nil || rep.Decision != Continue {
+			continue
+		}
+		points := map[string]float64{"1-0": 1, "0-1": 0, "1/2-1/2": 0.5}[o.game.Result]
+		if !o.aWhite {
+			points = 1 - points
+		}
+		rep.Score.add(points)
+		if m.SPRT != nil {
+			rep.LLR = m.SPRT.LLR(rep.Score)
+			if rep.Decision = m.SPRT.Decide(rep.Score); rep.Decision != Continue {
+				cancel()
+			}
+		}
+		if m.PGN != nil {
+			if _, err := io.WriteString(m.PGN, o.game.PGN(m.Event, m.TimeControl).String()+"\n"); err != nil {
+				firstErr = fmt.Errorf("writing PGN: %w", err)
+				cancel()
+				continue
+			}
+		}
+		if m.Progress != nil {
+			m.Progress(o.game, rep)
+		}
+	}
+	return rep, firstErr
+}
+
+// worker plays the games it receives with its own players, replacing a player
+// whose engine failed before the next game.
+func (m Match) worker(ctx context.Context, openings []Opening, jobs <-chan int, results chan<- outcome) {
+	var a, b Player
+	closeAll := func() {
+		for _, p := range []*Player{&a, &b} {
+			if *p != nil {
+				_ = (*p).Close()
+				*p = nil
+			}
+		}
+	}
+	defer closeAll()
+
+	for i := range jobs {
+		var err error
+		if a == nil {
+			a, err = m.A.New()
+		}
+		if err == nil && b == nil {
+			b, err = m.B.New()
+		}
+		if err != nil {
+			results <- outcome{err: fmt.Errorf("starting engine: %w", err)}
+			return
+		}
+
+		aWhite := i%2 == 0
+		white, black, wName, bName := a, b, m.A.Name, m.B.Name
+		if !aWhite {
+			white, black, wName, bName = b, a, m.B.Name, m.A.Name
+		}
+		var moved func(Position, engine.SearchResult)
+		if m.Move != nil {
+			round := i + 1
+			moved = func(pos Position, res engine.SearchResult) { m.Move(round, pos, res) }
+		}
+		g, err := play(ctx, white, black, openings[i/2%len(openings)], m.TimeControl, m.Adjudication, moved)
+		if errors.Is(err, errAborted) || ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			closeAll()
+			if g.Result == "" {
+				results <- outcome{err: err}
+				return
+			}
+		}
+		g.Round, g.White, g.Black = i+1, wName, bName
+		results <- outcome{game: g, aWhite: aWhite}
+	}
+}
@@ -0,0 +1,70 @@
+package match
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	chess "chess_go/internal/chess"
+)
+
+// ErrInvalidOpening is returned for opening suites that cannot be read.
+var ErrInvalidOpening = errors.New("invalid opening suite")
+
+// Opening is the start of a match game: a position, and moves played from it
+// before the engines take over.
+type Opening struct {
+	Start chess.Game
+	Moves []chess.Move
+}
+
+// OpenOpenings reads an opening suite, choosing the format by extension:
+// ".epd" for EPD positions, anything else for PGN games.
+func OpenOpenings(path string) ([]Opening, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if strings.EqualFold(filepath.Ext(path), ".epd") {
+		return ReadEPDOpenings(f)
+	}
+	return ReadPGNOpenings(f)
+}
+
+// ReadEPDOpenings reads one opening per EPD record. Only the position is
+// used; operations such as "id" or "bm" are ignored.
+func ReadEPDOpenings(r io.Reader) ([]Opening, error) {
+	records, err := chess.ReadEPD(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOpening, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no positions", ErrInvalidOpening)
+	}
+	ops := make([]Opening, len(records))
+	for i, e := range records {
+		ops[i] = Opening{Start: e.Game}
+	}
+	return ops, nil
+}
+
+// ReadPGNOpenings reads one opening per PGN game: its start position and
+// mainline moves.
+func ReadPGNOpenings(r io.Reader) ([]Opening, error) {
+	games, err := chess.ReadPGN(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOpening, err)
+	}
+	if len(games) == 0 {
+		return nil, fmt.Errorf("%w: no games", ErrInvalidOpening)
+	}
+	ops := make([]Opening, len(games))
+	for i, pg := range games {
+		ops[i] = Opening{Start: pg.Start, Moves: pg.Moves}
+	}
+	return ops, nil
+}
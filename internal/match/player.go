@@ -0,0 +1,55 @@
+package match
+
+import (
+	"context"
+	"io"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// Position is the game so far: the position the game started from, the moves
+// played since, and the resulting game with its history.
+type Position struct {
+	Start chess.Game
+	Moves []chess.Move
+	Game  chess.Game
+}
+
+// Player is one engine instance taking part in a match. A player plays one
+// game at a time; concurrent games each get their own player.
+type Player interface {
+	// NewGame prepares the player for a game from a new opening.
+	NewGame(ctx context.Context) error
+	// Move searches pos under tc. The result's Score is from the side to move's
+	// view, in engine units (see engine.MateScore).
+	Move(ctx context.Context, pos Position, tc engine.TimeControl) (engine.SearchResult, error)
+	// Close releases the player's resources.
+	Close() error
+}
+
+// Contender is one side of a match: a name for reports and PGN tags, and a
+// constructor called once for each concurrent game slot, and again after a
+// player fails.
+type Contender struct {
+	Name string
+	New  func() (Player, error)
+}
+
+// InProcess returns a contender that runs search inside this process.
+func InProcess(name string, search engine.SearchFunc) Contender {
+	return Contender{Name: name, New: func() (Player, error) { return searchPlayer{search}, nil }}
+}
+
+// searchPlayer runs a SearchFunc.
+type searchPlayer struct {
+	search engine.SearchFunc
+}
+
+func (searchPlayer) NewGame(context.Context) error { return nil }
+
+func (p searchPlayer) Move(ctx context.Context, pos Position, tc engine.TimeControl) (engine.SearchResult, error) {
+	return p.search(ctx, pos.Game, tc, io.Discard), nil
+}
+
+func (searchPlayer) Close() error { return nil }
@@ -0,0 +1,130 @@
+package match
+
+import (
+	"fmt"
+	"math"
+)
+
+// Score is a match result from the first contender's point of view.
+type Score struct {
+	Wins, Draws, Losses int
+}
+
+// Games returns the number of games played.
+func (s Score) Games() int { return s.Wins + s.Draws + s.Losses }
+
+// Points returns the points scored, a draw counting one half.
+func (s Score) Points() float64 { return float64(s.Wins) + float64(s.Draws)/2 }
+
+// add counts a game scored 1, 0.5 or 0.
+func (s *Score) add(points float64) {
+	switch points {
+	case 1:
+		s.Wins++
+	case 0:
+		s.Losses++
+	default:
+		s.Draws++
+	}
+}
+
+// String returns e.g. "+12 =30 -8".
+func (s Score) String() string {
+	return fmt.Sprintf("+%d =%d -%d", s.Wins, s.Draws, s.Losses)
+}
+
+// mean returns the average score per game and its per-game variance. The
+// variance counts one extra virtual win, draw and loss, so that a short run of
+// identical results does not look certain.
+func (s Score) mean() (mu, variance float64) {
+	n := float64(s.Games())
+	if n == 0 {
+		return 0.5, 0.25
+	}
+	mu = s.Points() / n
+	w, d, l := float64(s.Wins+1), float64(s.Draws+1), float64(s.Losses+1)
+	variance = (w*(1-mu)*(1-mu) + d*(0.5-mu)*(0.5-mu) + l*mu*mu) / (n + 3)
+	return mu, variance
+}
+
+// Elo returns the Elo difference implied by the score and the half-width of
+// its 95% confidence interval.
+func (s Score) Elo() (diff, margin float64) {
+	mu, variance := s.mean()
+	if s.Games() == 0 {
+		return 0, math.Inf(1)
+	}
+	dev := 1.959964 * math.Sqrt(variance/float64(s.Games()))
+	return eloOf(mu), (eloOf(mu+dev) - eloOf(mu-dev)) / 2
+}
+
+// eloOf converts an expected score into an Elo difference. Scores are clamped
+// to [0.001, 0.999], about ±1200 Elo.
+func eloOf(score float64) float64 {
+	score = min(max(score, 0.001), 0.999)
+	return -400 * math.Log10(1/score-1)
+}
+
+// scoreOf converts an Elo difference into an expected score.
+func scoreOf(elo float64) float64 {
+	return 1 / (1 + math.Pow(10, -elo/400))
+}
+
+// SPRT is a sequential probability ratio test of H0: the Elo difference is
+// Elo0, against H1: it is Elo1, with false positive rate Alpha and false
+// negative rate Beta. The log-likelihood ratio uses the normal approximation
+// of the game results (the "logistic Elo" GSPRT of Fishtest).
+type SPRT struct {
+	Elo0, Elo1  float64
+	Alpha, Beta float64
+}
+
+// Decision is the state of an SPRT.
+type Decision int
+
+const (
+	Continue Decision = iota // neither bound reached
+	AcceptH0                 // no improvement of Elo1: reject the change
+	AcceptH1                 // an improvement of at least Elo1: accept the change
+)
+
+// String returns "continue", "H0 accepted" or "H1 accepted".
+func (d Decision) String() string {
+	switch d {
+	case AcceptH0:
+		return "H0 accepted"
+	case AcceptH1:
+		return "H1 accepted"
+	default:
+		return "continue"
+	}
+}
+
+// Bounds returns the LLR thresholds at which H0 and H1 are accepted.
+func (t SPRT) Bounds() (lower, upper float64) {
+	return math.Log(t.Beta / (1 - t.Alpha)), math.Log((1 - t.Beta) / t.Alpha)
+}
+
+// LLR returns the log-likelihood ratio of H1 against H0 given s.
+func (t SPRT) LLR(s Score) float64 {
+	if s.Games() == 0 {
+		return 0
+	}
+	mu, variance := s.mean()
+	s0, s1 := scoreOf(t.Elo0), scoreOf(t.Elo1)
+	return (s1 - s0) * (2*mu - s0 - s1) / (2 * variance / float64(s.Games()))
+}
+
+// Decide returns the outcome of the test after s.
+func (t SPRT) Decide(s Score) Decision {
+	llr := t.LLR(s)
+	lower, upper := t.Bounds()
+	switch {
+	case llr >= upper:
+		return AcceptH1
+	case llr <= lower:
+		return AcceptH0
+	default:
+		return Continue
+	}
+}
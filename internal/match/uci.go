@@ -0,0 +1,52 @@
+package match
+
+import (
+	"context"
+	"sort"
+
+	engine "chess_go/internal/engine"
+)
+
+// ErrEngine is returned when an external engine misbehaves: it fails to
+// start, answer the handshake, or reply with a move in time, or it exits.
+var ErrEngine = engine.ErrEngine
+
+// UCI returns a contender that launches the UCI engine at path with args as a
+// subprocess, sets the given options after the handshake, and plays through
+// its stdin and stdout.
+func UCI(name, path string, args []string, options map[string]string) Contender {
+	return Contender{Name: name, New: func() (Player, error) {
+		e, err := engine.StartUCIEngine(path, args...)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, 0, len(options))
+		for name := range options {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := e.SetOption(name, options[name]); err != nil {
+				_ = e.Close()
+				return nil, err
+			}
+		}
+		return enginePlayer{e}, nil
+	}}
+}
+
+// enginePlayer plays through an engine.Engine.
+type enginePlayer struct {
+	e engine.Engine
+}
+
+func (p enginePlayer) NewGame(ctx context.Context) error { return p.e.NewGame(ctx) }
+
+func (p enginePlayer) Move(ctx context.Context, pos Position, tc engine.TimeControl) (engine.SearchResult, error) {
+	if err := p.e.SetPosition(pos.Start, pos.Moves); err != nil {
+		return engine.SearchResult{}, err
+	}
+	return p.e.Search(ctx, tc, nil)
+}
+
+func (p enginePlayer) Close() error { return p.e.Close() }
@@ -0,0 +1,134 @@
+package suite
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	engine "chess_go/internal/engine"
+)
+
+// JUnit XML elements, in the subset understood by common CI systems.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the reports as one JUnit XML document, one testsuite per
+// report and one testcase per position. Unsolved positions are failures,
+// records with invalid moves are errors, and unscored records and unsolved
+// positions that are expected to fail are skipped.
+func WriteJUnit(w io.Writer, reports []Report) error {
+	doc := junitSuites{Name: "chess-go epd"}
+	var total time.Duration
+	for _, rep := range reports {
+		s := junitSuite{Name: rep.Name, Time: seconds(rep.Elapsed)}
+		solved, scored := rep.Solved()
+		s.Properties = append(s.Properties, junitProperty{"solved", fmt.Sprintf("%d/%d", solved, scored)})
+		if points, possible := rep.Points(); possible > 0 {
+			s.Properties = append(s.Properties, junitProperty{"points", fmt.Sprintf("%d/%d", points, possible)})
+		}
+		for _, res := range rep.Results {
+			c := junitCase{
+				Name:      res.ID,
+				ClassName: strings.TrimSuffix(rep.Name, ".epd"),
+				Time:      seconds(res.Search.Elapsed),
+				SystemOut: fmt.Sprintf("%s\nplayed %s, depth %d, score %s, nodes %d",
+					res.Record, res.SAN, res.Search.Depth, engine.FormatScore(res.Search.Score), res.Search.Nodes),
+			}
+			switch {
+			case res.Err != nil:
+				c.Error = &junitMessage{Message: res.Err.Error()}
+				s.Failures++
+			case !res.Scored:
+				c.Skipped = &junitMessage{Message: "no bm, am or c0 points to score against"}
+				s.Skipped++
+			case !res.Solved && res.ExpectFail:
+				c.Skipped = &junitMessage{Message: "expected to fail: " + res.Expected()}
+				s.Skipped++
+			case !res.Solved:
+				c.Failure = &junitMessage{Message: res.Expected()}
+				s.Failures++
+			}
+			s.Cases = append(s.Cases, c)
+		}
+		s.Tests = len(s.Cases)
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+		doc.Skipped += s.Skipped
+		total += rep.Elapsed
+		doc.Suites = append(doc.Suites, s)
+	}
+	doc.Time = seconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Expected describes what the position asked for, e.g.
+// "played Rf7, expected bm Qg6" or "played Nxe5 (3 of 10 points)".
+func (r Result) Expected() string {
+	var parts []string
+	for _, opcode := range []string{"bm", "am"} {
+		if operands, ok := r.Record.Op(opcode); ok {
+			parts = append(parts, opcode+" "+strings.Join(operands, " "))
+		}
+	}
+	s := "played " + r.SAN
+	if r.Max > 0 {
+		s += fmt.Sprintf(" (%d of %d points)", r.Points, r.Max)
+	}
+	if len(parts) > 0 {
+		s += ", expected " + strings.Join(parts, ", ")
+	}
+	return s
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
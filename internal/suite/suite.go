@@ -0,0 +1,191 @@
+// Package suite runs EPD test suites such as WAC, ECM and STS against the
+// engine and scores the answers.
+//
+// A position is solved when the engine plays one of its "bm" (best move)
+// moves and none of its "am" (avoid move) moves. STS-style records also list
+// points per move in a "c0" comment such as "Nf4=10, Bc2=4, a3=2"; the points
+// of the move played are added to the suite's score. Reports can be written
+// as JUnit XML so that CI systems show every unsolved position as a failed
+// test.
+package suite
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// Options configures a suite run.
+type Options struct {
+	// Search runs each position; nil selects engine.SearchContext.
+	Search engine.SearchFunc
+	// Limit is the search limit for each position. When it sets neither a
+	// depth, a move time nor a node count, a record's "acd" operation gives
+	// the depth; positions without one use the engine's default move time.
+	Limit engine.TimeControl
+	// ExpectFail lists the ids of positions known to be unsolved. They are
+	// still searched and scored, but an unsolved one is not a regression.
+	ExpectFail []string
+	// Progress, when set, is called after each position.
+	Progress func(Result)
+}
+
+// Result is the outcome of one position.
+type Result struct {
+	Index  int // 1-based position number
+	ID     string
+	Record chess.EPD
+	Move   chess.Move
+	SAN    string
+	Search engine.SearchResult
+	Scored bool // the record has bm, am or c0 points to score against
+	Solved bool
+	Points int // c0 points of the move played
+	Max    int // best c0 points of the position
+	// ExpectFail is set for positions listed in Options.ExpectFail.
+	ExpectFail bool
+	Err        error
+}
+
+// Regressed reports whether the result should fail a run: the record cannot
+// be scored, or the position is unsolved without being expected to fail.
+func (r Result) Regressed() bool {
+	return r.Err != nil || r.Scored && !r.Solved && !r.ExpectFail
+}
+
+// Report is the outcome of a suite.
+type Report struct {
+	Name    string
+	Results []Result
+	Elapsed time.Duration
+}
+
+// Solved returns the number of solved positions and the number scored.
+func (r Report) Solved() (solved, scored int) {
+	for _, res := range r.Results {
+		if res.Scored {
+			scored++
+			if res.Solved {
+				solved++
+			}
+		}
+	}
+	return solved, scored
+}
+
+// Points returns the c0 points scored and the most that could be scored.
+func (r Report) Points() (points, total int) {
+	for _, res := range r.Results {
+		points += res.Points
+		total += res.Max
+	}
+	return points, total
+}
+
+// Run searches every record and scores the moves played. It stops early,
+// returning the results so far, when ctx is cancelled.
+func Run(ctx context.Context, name string, records []chess.EPD, opts Options) Report {
+	search := opts.Search
+	if search == nil {
+		search = engine.SearchContext
+	}
+	start := time.Now()
+	rep := Report{Name: name}
+	for i, e := range records {
+		if ctx.Err() != nil {
+			break
+		}
+		tc := opts.Limit
+		if tc.Depth == 0 && tc.MoveTime == 0 && tc.Nodes == 0 && !tc.Infinite {
+			if acd, ok := e.Int("acd"); ok && acd > 0 {
+				tc.Depth = acd
+			}
+		}
+		res := Result{Index: i + 1, ID: e.ID(), Record: e}
+		if res.ID == "" {
+			res.ID = "position " + strconv.Itoa(i+1)
+		}
+		for _, id := range opts.ExpectFail {
+			res.ExpectFail = res.ExpectFail || id == res.ID
+		}
+		res.Search = search(ctx, e.Game, tc, io.Discard)
+		res.Move = res.Search.BestMove
+		res.SAN = res.Move.SANString(e.Game)
+		res.score()
+		rep.Results = append(rep.Results, res)
+		if opts.Progress != nil {
+			opts.Progress(res)
+		}
+	}
+	rep.Elapsed = time.Since(start)
+	return rep
+}
+
+// score fills in Scored, Solved, Points and Max.
+func (r *Result) score() {
+	best, err := r.Record.Moves("bm")
+	if err != nil {
+		r.Err = err
+		return
+	}
+	avoid, err := r.Record.Moves("am")
+	if err != nil {
+		r.Err = err
+		return
+	}
+	points := movePoints(r.Record)
+	r.Scored = len(best) > 0 || len(avoid) > 0 || len(points) > 0
+	if !r.Scored {
+		return
+	}
+	r.Solved = (len(best) == 0 || contains(best, r.Move)) && !contains(avoid, r.Move)
+	for m, p := range points {
+		r.Max = max(r.Max, p)
+		if m == r.Move {
+			r.Points = p
+		}
+	}
+	if len(best) == 0 && len(avoid) == 0 {
+		r.Solved = r.Points == r.Max
+	}
+}
+
+// movePoints parses an STS "c0" comment of "move=points" pairs. Comments in
+// any other form give no points.
+func movePoints(e chess.EPD) map[chess.Move]int {
+	operands, ok := e.Op("c0")
+	if !ok || len(operands) == 0 {
+		return nil
+	}
+	points := map[chess.Move]int{}
+	for _, item := range strings.Split(operands[0], ",") {
+		san, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return nil
+		}
+		m, err := chess.ParseSAN(e.Game, san)
+		if err != nil {
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil
+		}
+		points[m] = n
+	}
+	return points
+}
+
+func contains(moves []chess.Move, m chess.Move) bool {
+	for _, x := range moves {
+		if x == m {
+			return true
+		}
+	}
+	return false
+}
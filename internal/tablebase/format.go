@@ -0,0 +1,134 @@
+package tablebase
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrInvalidTable is returned when a table file is truncated or corrupt.
+var ErrInvalidTable = errors.New("invalid tablebase file")
+
+// File format: the magic "CGTB", a version byte, the material string
+// prefixed by its length, the number of entries as a big-endian uint32, then
+// the entries (one byte per position, see encode) zlib-compressed. Long runs of
+// draws and illegal positions make the compressed tables small.
+var magic = [4]byte{'C', 'G', 'T', 'B'}
+
+const (
+	formatVersion = 1
+	// Ext is the file extension of stored tables.
+	Ext = ".cgtb"
+)
+
+// Write serialises the table to w.
+func (t *Table) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.Write(magic[:])
+	bw.WriteByte(formatVersion)
+	bw.WriteByte(byte(len(t.material)))
+	bw.WriteString(t.material)
+	var n [4]byte
+	binary.BigEndian.PutUint32(n[:], uint32(len(t.values)))
+	bw.Write(n[:])
+	zw := zlib.NewWriter(bw)
+	if _, err := zw.Write(t.values); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// WriteFile writes the table to the file at path, replacing any existing file.
+func (t *Table) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := t.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read parses a table from r.
+// Returns ErrInvalidTable if the header or the entry count do not match.
+func Read(r io.Reader) (*Table, error) {
+	br := bufio.NewReader(r)
+	var head [6]byte
+	if _, err := io.ReadFull(br, head[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTable, err)
+	}
+	if !bytes.Equal(head[:4], magic[:]) || head[4] != formatVersion {
+		return nil, fmt.Errorf("%w: bad magic or version", ErrInvalidTable)
+	}
+	rest := make([]byte, int(head[5])+4)
+	if _, err := io.ReadFull(br, rest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTable, err)
+	}
+	material := string(rest[:head[5]])
+	t, err := newTable(material)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTable, err)
+	}
+	if t.material != material || binary.BigEndian.Uint32(rest[head[5]:]) != uint32(len(t.values)) {
+		return nil, fmt.Errorf("%w: %s: header does not match material", ErrInvalidTable, material)
+	}
+	zr, err := zlib.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTable, err)
+	}
+	if _, err := io.ReadFull(zr, t.values); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTable, material, err)
+	}
+	if err := zr.Close(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidTable, material, err)
+	}
+	return t, nil
+}
+
+// Open reads a table from the file at path.
+func Open(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// OpenDir loads every table file (*.cgtb) in dir.
+func OpenDir(dir string) (*Tablebase, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return nil, err
+	}
+	tb := New()
+	for _, path := range paths {
+		t, err := Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		tb.Add(t)
+	}
+	return tb, nil
+}
+
+// WriteDir writes every table to dir as <material>.cgtb.
+func (tb *Tablebase) WriteDir(dir string) error {
+	for _, t := range tb.tables {
+		if err := t.WriteFile(filepath.Join(dir, t.material+Ext)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+package tablebase
+
+import (
+	"fmt"
+
+	chess "chess_go/internal/chess"
+)
+
+// Tablebase is a set of DTM tables keyed by material.
+type Tablebase struct {
+	tables map[string]*Table
+}
+
+// New returns an empty tablebase.
+func New() *Tablebase {
+	return &Tablebase{tables: make(map[string]*Table)}
+}
+
+// Add stores t, replacing any table of the same material.
+func (tb *Tablebase) Add(t *Table) { tb.tables[t.material] = t }
+
+// Table returns the table for material, or nil when it is not loaded.
+func (tb *Tablebase) Table(material string) *Table {
+	norm, _, err := parseMaterial(material)
+	if err != nil {
+		return nil
+	}
+	return tb.tables[norm]
+}
+
+// Tables returns every loaded table.
+func (tb *Tablebase) Tables() []*Table {
+	out := make([]*Table, 0, len(tb.tables))
+	for _, t := range tb.tables {
+		out = append(out, t)
+	}
+	return out
+}
+
+// Probe returns the value of s from the table of its material.
+func (tb *Tablebase) Probe(s chess.GameState) (Result, error) {
+	t, ok := tb.tables[materialOf(s)]
+	if !ok {
+		return Result{}, ErrNotCovered
+	}
+	return t.Probe(s)
+}
+
+// BestMove returns a move that keeps the value of the current position of g:
+// the fastest mate when winning, the longest resistance when losing, and a
+// move that holds the draw otherwise. It also returns the position's value.
+func (tb *Tablebase) BestMove(g chess.Game) (chess.Move, Result, error) {
+	r, err := tb.Probe(g.State)
+	if err != nil {
+		return chess.Move{}, Result{}, err
+	}
+	for _, m := range g.LegalMoves() {
+		child, err := tb.Probe(g.State.Play(m))
+		if err != nil {
+			return chess.Move{}, Result{}, err
+		}
+		if after(child) == r {
+			return m, r, nil
+		}
+	}
+	return chess.Move{}, r, fmt.Errorf("tablebase: %s has no legal moves", r)
+}
+
+// Generate builds the table for material and adds it to tb, first generating
+// every table reachable from it by captures and promotions that tb does not
+// already hold. At most one side may have pawns.
+func (tb *Tablebase) Generate(material string) (*Table, error) {
+	norm, _, err := parseMaterial(material)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := tb.tables[norm]; ok {
+		return t, nil
+	}
+	t, err := newTable(norm)
+	if err != nil {
+		return nil, err
+	}
+	g := &generator{tb: tb, t: t}
+	if err := g.run(); err != nil {
+		return nil, err
+	}
+	tb.Add(t)
+	return t, nil
+}
+
+// Verify checks every position of the table for material against its
+// children with the forward move generator: the stored value must equal the
+// best value over all legal moves. It reports the first mismatch.
+func (tb *Tablebase) Verify(material string) error {
+	t := tb.Table(material)
+	if t == nil {
+		return fmt.Errorf("tablebase: %s: %w", material, ErrNotCovered)
+	}
+	for idx := range t.values {
+		s, ok := t.position(idx)
+		if !ok {
+			continue
+		}
+		want := Result{Outcome: Draw}
+		if moves := s.LegalMoves(); len(moves) == 0 {
+			if s.InCheck() {
+				want = Result{Outcome: Loss}
+			}
+		} else {
+			want = Result{Outcome: Loss, DTM: -1}
+			for _, m := range moves {
+				child, err := tb.Probe(s.Play(m))
+				if err != nil {
+					return fmt.Errorf("tablebase: %s: probing after %s: %w", t.material, m.UCIString(), err)
+				}
+				if v := after(child); better(v, want) {
+					want = v
+				}
+			}
+		}
+		if got := decode(t.values[idx]); got != want {
+			return fmt.Errorf("tablebase: %s: %s stores %s, children give %s",
+				t.material, chess.Game{State: s}.ToFEN(), got, want)
+		}
+	}
+	return nil
+}
+
+// after returns the value of a position for the side that moved into a
+// position of value r.
+func after(r Result) Result {
+	switch r.Outcome {
+	case Win:
+		return Result{Outcome: Loss, DTM: r.DTM + 1}
+	case Loss:
+		return Result{Outcome: Win, DTM: r.DTM + 1}
+	default:
+		return r
+	}
+}
+
+// better reports whether a is preferable to b for the side to move: wins
+// beat draws beat losses, faster wins and slower losses are preferred.
+func better(a, b Result) bool {
+	if a.Outcome != b.Outcome {
+		return a.Outcome > b.Outcome
+	}
+	switch a.Outcome {
+	case Win:
+		return a.DTM < b.DTM
+	case Loss:
+		return a.DTM > b.DTM
+	}
+	return false
+}
+
+// generator holds the working state of one retrograde analysis.
+type generator struct {
+	tb  *Tablebase
+	t   *Table
+	dtm *Table // in a DTZ pass, the finished DTM table of the same material
+
+	state    []uint8 // unknown, resolved or illegal
+	count    []uint8 // quiet moves whose value is not yet a win for the opponent
+	exitWin  []uint8 // fastest win by a capture or promotion, 0 if none
+	exitLoss []uint8 // slowest loss by a capture or promotion, 0 if none
+	exitDraw []bool  // a capture or promotion holds at least a draw
+	buckets  [maxDTM + 2][]int32
+}
+
+const (
+	unknown uint8 = iota
+	resolved
+	illegal
+)
+
+// run fills the table. Positions are resolved in order of distance to mate:
+// each loss at ply n makes its predecessors wins at ply n+1, and each win at
+// ply n removes one escape from its predecessors, which become losses once
+// none is left. Positions still unknown at the end are draws.
+func (g *generator) run() error {
+	n := len(g.t.values)
+	g.state = make([]uint8, n)
+	g.count = make([]uint8, n)
+	g.exitWin = make([]uint8, n)
+	g.exitLoss = make([]uint8, n)
+	g.exitDraw = make([]bool, n)
+
+	for idx := 0; idx < n; idx++ {
+		if err := g.initialize(idx); err != nil {
+			return err
+		}
+	}
+	for ply := 0; ply < len(g.buckets); ply++ {
+		for _, idx := range g.buckets[ply] {
+			if g.state[idx] != unknown {
+				continue
+			}
+			if ply > maxDTM {
+				return fmt.Errorf("tablebase: %s: mate longer than %d plies", g.t.material, maxDTM)
+			}
+			g.state[idx] = resolved
+			r := Result{Outcome: Win, DTM: ply}
+			if g.dtm != nil {
+				r.Outcome = decode(g.dtm.values[idx]).Outcome
+			} else if ply%2 == 0 {
+				r.Outcome = Loss
+			}
+			g.t.values[idx] = encode(r)
+			g.retract(int(idx), r)
+		}
+		g.buckets[ply] = nil
+	}
+	return nil
+}
+
+// initialize counts the quiet moves of the position at idx and resolves its
+// captures and promotions through the tables they lead to.
+func (g *generator) initialize(idx int) error {
+	s, ok := g.t.position(idx)
+	if !ok {
+		g.state[idx] = illegal
+		return nil
+	}
+	moves := s.LegalMoves()
+	if len(moves) == 0 {
+		if s.InCheck() {
+			g.schedule(idx, 0)
+		} else {
+			g.state[idx] = resolved
+		}
+		return nil
+	}
+	for _, m := range moves {
+		if !m.IsPromotion() && s.Board[m.To] == chess.NoPiece && !(g.dtm != nil && isPawn(s.Board[m.From])) {
+			g.count[idx]++
+			continue
+		}
+		child := s.Play(m)
+		t, ok := g.tb.tables[materialOf(child)]
+		if !ok {
+			var err error
+			if t, err = g.tb.Generate(materialOf(child)); err != nil {
+				return err
+			}
+		}
+		cidx, _ := t.index(child)
+		c := decode(t.values[cidx])
+		if g.dtm != nil {
+			c.DTM = 0 // the move zeroes the fifty-move counter
+		}
+		switch v := after(c); v.Outcome {
+		case Win:
+			if g.exitWin[idx] == 0 || v.DTM < int(g.exitWin[idx]) {
+				g.exitWin[idx] = uint8(min(v.DTM, maxDTM+1))
+			}
+		case Loss:
+			g.exitLoss[idx] = uint8(min(max(v.DTM, int(g.exitLoss[idx])), maxDTM+1))
+		default:
+			g.exitDraw[idx] = true
+		}
+	}
+	switch {
+	case g.exitWin[idx] > 0:
+		g.schedule(idx, int(g.exitWin[idx]))
+	case g.count[idx] > 0:
+	case g.exitDraw[idx]:
+		g.state[idx] = resolved
+	default:
+		g.schedule(idx, int(g.exitLoss[idx]))
+	}
+	return nil
+}
+
+// retract propagates the newly resolved position at idx to its predecessors.
+func (g *generator) retract(idx int, r Result) {
+	s, _ := g.t.position(idx)
+	for _, m := range s.Unmoves() {
+		if g.dtm != nil && isPawn(s.Board[m.To]) {
+			continue // a pawn move zeroes the counter: not a quiet predecessor
+		}
+		prev, ok := g.t.index(s.Unplay(m))
+		if !ok || g.state[prev] != unknown {
+			continue
+		}
+		if r.Outcome == Loss {
+			g.schedule(prev, r.DTM+1)
+			continue
+		}
+		g.count[prev]--
+		if g.count[prev] == 0 && g.exitWin[prev] == 0 && !g.exitDraw[prev] {
+			g.schedule(prev, max(r.DTM+1, int(g.exitLoss[prev])))
+		}
+	}
+}
+
+// schedule queues idx to be resolved at the given ply.
+func (g *generator) schedule(idx, ply int) {
+	ply = min(ply, len(g.buckets)-1)
+	g.buckets[ply] = append(g.buckets[ply], int32(idx))
+}
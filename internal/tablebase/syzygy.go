@@ -0,0 +1,104 @@
+package tablebase
+
+import (
+	"fmt"
+	"strings"
+
+	chess "chess_go/internal/chess"
+)
+
+// SyzygyName returns the Syzygy signature of a material string: "KBNK"
+// becomes "KBNvK".
+func SyzygyName(material string) (string, error) {
+	norm, _, err := parseMaterial(material)
+	if err != nil {
+		return "", err
+	}
+	split := strings.LastIndexByte(norm, 'K')
+	return norm[:split] + "v" + norm[split:], nil
+}
+
+// SyzygyValue returns the WDL and DTZ of a legal position, as chess.Syzygy's
+// ProbeWDL and ProbeDTZ report them.
+type SyzygyValue func(s chess.GameState) (chess.WDL, int)
+
+// VerifySyzygy probes every legal position of material in the Syzygy tables
+// found in dir and reports the first whose WDL or DTZ differs from the value
+// Syzygy gives it.
+func (tb *Tablebase) VerifySyzygy(dir, material string) error {
+	_, value, err := tb.Syzygy(material)
+	if err != nil {
+		return err
+	}
+	syzygy, err := chess.OpenSyzygy(dir)
+	if err != nil {
+		return err
+	}
+	t := tb.Table(material)
+	for idx := range t.values {
+		s, ok := t.position(idx)
+		if !ok {
+			continue
+		}
+		wantWDL, wantDTZ := value(s)
+		wdl, err := syzygy.ProbeWDL(s)
+		if err == nil && wdl == wantWDL && wdl != chess.WDLDraw {
+			var dtz int
+			if dtz, err = syzygy.ProbeDTZ(s); err == nil && dtz != wantDTZ {
+				err = fmt.Errorf("DTZ is %d, want %d", dtz, wantDTZ)
+			}
+		} else if err == nil && wdl != wantWDL {
+			err = fmt.Errorf("WDL is %v, want %v", wdl, wantWDL)
+		}
+		if err != nil {
+			return fmt.Errorf("tablebase: %s: %s: %w", t.material, chess.Game{State: s}.ToFEN(), err)
+		}
+	}
+	return nil
+}
+
+// Syzygy generates the table for material, as Generate does, and returns its
+// Syzygy signature and the WDL and DTZ of its positions, from which Syzygy
+// table files can be written. The WDL comes from the DTM table; the distance
+// to zeroing from a second retrograde pass in which captures, promotions and
+// pawn moves all leave the table.
+func (tb *Tablebase) Syzygy(material string) (string, SyzygyValue, error) {
+	t, err := tb.Generate(material)
+	if err != nil {
+		return "", nil, err
+	}
+	name, err := SyzygyName(t.material)
+	if err != nil {
+		return "", nil, err
+	}
+	dtz, err := newTable(t.material)
+	if err != nil {
+		return "", nil, err
+	}
+	g := &generator{tb: tb, t: dtz, dtm: t}
+	if err := g.run(); err != nil {
+		return "", nil, err
+	}
+
+	return name, func(s chess.GameState) (chess.WDL, int) {
+		// The outcome comes from the DTM table: a loss at an odd ply (when
+		// only zeroing moves are left) breaks the parity decode relies on.
+		idx, _ := t.index(s)
+		z := int(dtz.values[idx])
+		switch decode(t.values[idx]).Outcome {
+		case Win:
+			if z > 100 {
+				return chess.WDLCursedWin, z
+			}
+			return chess.WDLWin, z
+		case Loss:
+			if z -= 2; z > 100 {
+				return chess.WDLBlessedLoss, -z
+			}
+			return chess.WDLLoss, -max(z, 1) // a checkmated side has a DTZ of -1
+		}
+		return chess.WDLDraw, 0
+	}, nil
+}
+
+func isPawn(p chess.Piece) bool { return p == chess.WhitePawn || p == chess.BlackPawn }
@@ -0,0 +1,354 @@
+// Package tablebase generates, stores and probes exact depth-to-mate (DTM)
+// endgame tables for small material sets such as KQK, KRK, KPK and KBNK.
+//
+// Tables are built by retrograde analysis: checkmates are found with the
+// chess package's forward move generator, and results are propagated back
+// through chess.GameState.Unmoves. Captures and promotions leave a table; their
+// values come from the tables of the resulting material, which are generated
+// first. Every table holds one byte per position for each side to move.
+package tablebase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	chess "chess_go/internal/chess"
+)
+
+// MaxPieces is the largest number of pieces, kings included, a table may hold.
+const MaxPieces = 4
+
+var (
+	// ErrInvalidMaterial is returned for material strings that cannot be
+	// generated, such as "KQ" or sets with more than MaxPieces pieces.
+	ErrInvalidMaterial = errors.New("invalid tablebase material")
+	// ErrNotCovered is returned when no loaded table holds the position:
+	// its material is missing, it has castling rights, or it is illegal.
+	ErrNotCovered = errors.New("position not covered by tablebase")
+)
+
+// Outcome is the game-theoretic value of a position for the side to move.
+type Outcome int8
+
+const (
+	Loss Outcome = -1
+	Draw Outcome = 0
+	Win  Outcome = 1
+)
+
+// String returns "win", "draw" or "loss".
+func (o Outcome) String() string {
+	switch o {
+	case Win:
+		return "win"
+	case Loss:
+		return "loss"
+	default:
+		return "draw"
+	}
+}
+
+// Result is a probed position: its outcome and, for wins and losses, the
+// number of plies to checkmate with best play. A checkmated position is a
+// loss with DTM 0; a mate in one is a win with DTM 1.
+type Result struct {
+	Outcome Outcome
+	DTM     int
+}
+
+// String returns e.g. "win in 19 plies", "loss in 0 plies" or "draw".
+func (r Result) String() string {
+	if r.Outcome == Draw {
+		return "draw"
+	}
+	return fmt.Sprintf("%s in %d plies", r.Outcome, r.DTM)
+}
+
+// Table is the DTM table of one material set.
+type Table struct {
+	material string
+	pieces   []chess.Piece // index order: white king, white men, black king, black men
+	slots    [13]int       // index of the first piece of each kind, len(pieces) if absent
+	pawns    bool
+	values   []uint8 // see encode
+}
+
+// pieceOrder is the order pieces are listed in a normalized material string.
+const pieceOrder = "KQRBNP"
+
+// Material returns the normalized material string, e.g. "KBNK".
+func (t *Table) Material() string { return t.material }
+
+// Probe returns the value of s, which must be a legal position with the
+// table's material and no castling rights. En passant rights are ignored:
+// tables only hold material sets where at most one side has pawns, so no en
+// passant capture can exist.
+func (t *Table) Probe(s chess.GameState) (Result, error) {
+	if s.CastlingRights != chess.NoCastling {
+		return Result{}, ErrNotCovered
+	}
+	idx, ok := t.index(s)
+	if !ok {
+		return Result{}, ErrNotCovered
+	}
+	if _, legal := t.position(idx); !legal {
+		return Result{}, fmt.Errorf("%w: side not to move is in check", ErrNotCovered)
+	}
+	return decode(t.values[idx]), nil
+}
+
+// Longest returns a position of the table with the longest forced mate for
+// side to move, and its value. It returns a draw when side never wins.
+func (t *Table) Longest(side chess.Color) (chess.GameState, Result) {
+	half := len(t.values) / 2
+	best, at := Result{Outcome: Draw}, -1
+	for idx := int(side) * half; idx < (int(side)+1)*half; idx++ {
+		if r := decode(t.values[idx]); r.Outcome == Win && r.DTM > best.DTM {
+			best, at = r, idx
+		}
+	}
+	if at < 0 {
+		return chess.GameState{}, best
+	}
+	s, _ := t.position(at)
+	return s, best
+}
+
+// newTable returns an empty table for a normalized material string.
+func newTable(material string) (*Table, error) {
+	norm, pieces, err := parseMaterial(material)
+	if err != nil {
+		return nil, err
+	}
+	t := &Table{material: norm, pieces: pieces}
+	for p := range t.slots {
+		t.slots[p] = len(pieces)
+	}
+	var white, black bool
+	for i := len(pieces) - 1; i >= 0; i-- {
+		p := pieces[i]
+		t.slots[p] = i
+		switch p {
+		case chess.WhitePawn:
+			white = true
+		case chess.BlackPawn:
+			black = true
+		}
+	}
+	if white && black {
+		return nil, fmt.Errorf("%w: %s: only one side may have pawns", ErrInvalidMaterial, material)
+	}
+	t.pawns = white || black
+	t.values = make([]uint8, t.size())
+	return t, nil
+}
+
+// parseMaterial normalizes a material string such as "knbk" or "KBNK" and
+// returns the pieces in index order.
+func parseMaterial(material string) (string, []chess.Piece, error) {
+	m := strings.ToUpper(material)
+	split := strings.Index(m[min(1, len(m)):], "K") + 1
+	if !strings.HasPrefix(m, "K") || split == 0 || len(m) > MaxPieces || strings.Count(m, "K") != 2 {
+		return "", nil, fmt.Errorf("%w: %q", ErrInvalidMaterial, material)
+	}
+	sides := [2]string{m[:split], m[split:]}
+	var norm strings.Builder
+	var pieces []chess.Piece
+	for c, side := range sides {
+		men := []byte(side[1:])
+		sort.Slice(men, func(i, j int) bool {
+			return strings.IndexByte(pieceOrder, men[i]) < strings.IndexByte(pieceOrder, men[j])
+		})
+		norm.WriteByte('K')
+		norm.Write(men)
+		pieces = append(pieces, pieceFor('K', chess.Color(c)))
+		for _, ch := range men {
+			p := pieceFor(ch, chess.Color(c))
+			if p == chess.NoPiece {
+				return "", nil, fmt.Errorf("%w: %q", ErrInvalidMaterial, material)
+			}
+			pieces = append(pieces, p)
+		}
+	}
+	return norm.String(), pieces, nil
+}
+
+// pieceFor maps an upper-case piece letter and a colour to a piece.
+func pieceFor(ch byte, c chess.Color) chess.Piece {
+	i := strings.IndexByte("PNBRQK", ch)
+	if i < 0 {
+		return chess.NoPiece
+	}
+	p := chess.WhitePawn + chess.Piece(i)
+	if c == chess.Black {
+		p += chess.BlackPawn - chess.WhitePawn
+	}
+	return p
+}
+
+// materialOf returns the normalized material string of a position.
+func materialOf(s chess.GameState) string {
+	var counts [13]int
+	for _, p := range s.Board {
+		counts[p]++
+	}
+	var b strings.Builder
+	for _, c := range []chess.Color{chess.White, chess.Black} {
+		for i := 0; i < len(pieceOrder); i++ {
+			b.WriteString(strings.Repeat(pieceOrder[i:i+1], counts[pieceFor(pieceOrder[i], c)]))
+		}
+	}
+	return b.String()
+}
+
+// Values are stored one byte per position: 0 is a draw (and any illegal
+// position), an odd value v is a win in v plies, and an even value v is a loss
+// in v-2 plies.
+func encode(r Result) uint8 {
+	switch r.Outcome {
+	case Win:
+		return uint8(r.DTM)
+	case Loss:
+		return uint8(r.DTM + 2)
+	default:
+		return 0
+	}
+}
+
+func decode(v uint8) Result {
+	switch {
+	case v == 0:
+		return Result{Outcome: Draw}
+	case v%2 == 1:
+		return Result{Outcome: Win, DTM: int(v)}
+	default:
+		return Result{Outcome: Loss, DTM: int(v) - 2}
+	}
+}
+
+// maxDTM is the longest mate a byte value can record.
+const maxDTM = 253
+
+// ─── Indexing ─────────────────────────────────────────────────────────────────
+//
+// The white king is mirrored onto files a-d, and for pawnless tables onto
+// ranks 1-4 as well. Diagonal symmetry is deliberately not used: with only
+// mirrors no square is fixed, so every position has exactly one index and the
+// retrograde move counters stay exact. The index is then
+//
+//	side, king region square, then one board square per remaining piece,
+//
+// with identical pieces stored in ascending square order.
+
+// kingRegion returns the number of squares the white king is mirrored onto.
+func (t *Table) kingRegion() int {
+	if t.pawns {
+		return 32
+	}
+	return 16
+}
+
+// size returns the number of entries in the table.
+func (t *Table) size() int {
+	n := 2 * t.kingRegion()
+	for range t.pieces[1:] {
+		n *= 64
+	}
+	return n
+}
+
+// mirror returns the transform that brings king into the king region:
+// bit 0 flips files, bit 1 flips ranks.
+func (t *Table) mirror(king chess.Square) int {
+	tr := 0
+	if king.File() > 3 {
+		tr |= 1
+	}
+	if !t.pawns && king.Rank() > 3 {
+		tr |= 2
+	}
+	return tr
+}
+
+func transform(sq chess.Square, tr int) chess.Square {
+	f, r := sq.File(), sq.Rank()
+	if tr&1 != 0 {
+		f = 7 - f
+	}
+	if tr&2 != 0 {
+		r = 7 - r
+	}
+	return chess.SquareOf(f, r)
+}
+
+// index returns the table index of s, or false when the material of s does
+// not match the table.
+func (t *Table) index(s chess.GameState) (int, bool) {
+	var squares [MaxPieces]chess.Square
+	fill := t.slots
+	n := 0
+	for sq, p := range s.Board {
+		if p == chess.NoPiece {
+			continue
+		}
+		i := fill[p]
+		if i >= len(t.pieces) || t.pieces[i] != p {
+			return 0, false
+		}
+		squares[i] = chess.Square(sq)
+		fill[p]++
+		n++
+	}
+	if n != len(t.pieces) {
+		return 0, false
+	}
+	tr := t.mirror(squares[0])
+	for i := range n {
+		squares[i] = transform(squares[i], tr)
+		for j := i; j > 0 && t.pieces[j] == t.pieces[j-1] && squares[j] < squares[j-1]; j-- {
+			squares[j], squares[j-1] = squares[j-1], squares[j]
+		}
+	}
+	k := squares[0]
+	idx := int(s.ActiveColor)*t.kingRegion() + k.Rank()*4 + k.File()
+	for _, sq := range squares[1:n] {
+		idx = idx*64 + int(sq)
+	}
+	return idx, true
+}
+
+// position returns the position at idx, or false when idx does not describe a
+// legal position in canonical form.
+func (t *Table) position(idx int) (chess.GameState, bool) {
+	var squares [MaxPieces]chess.Square
+	for i := len(t.pieces) - 1; i > 0; i-- {
+		squares[i] = chess.Square(idx % 64)
+		idx /= 64
+	}
+	region := t.kingRegion()
+	squares[0] = chess.SquareOf(idx%region%4, idx%region/4)
+	side := chess.Color(idx / region)
+
+	s := chess.GameState{ActiveColor: side, EnPassantSq: chess.NoSquare, FullMoveNumber: 1}
+	for i, p := range t.pieces {
+		sq := squares[i]
+		if s.Board[sq] != chess.NoPiece {
+			return s, false
+		}
+		if i > 0 && p == t.pieces[i-1] && sq < squares[i-1] {
+			return s, false
+		}
+		if (p == chess.WhitePawn || p == chess.BlackPawn) && (sq.Rank() == 0 || sq.Rank() == 7) {
+			return s, false
+		}
+		s.Board[sq] = p
+	}
+	other := s
+	other.ActiveColor = 1 - side
+	if other.InCheck() {
+		return s, false
+	}
+	return s, true
+}
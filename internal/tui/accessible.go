@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	chess "chess_go/internal/chess"
+)
+
+// pieceNames names the kinds of piece, pawn to king.
+var pieceNames = [6]string{"pawn", "knight", "bishop", "rook", "queen", "king"}
+
+// pieceName returns the kind of p in words, such as "knight".
+func pieceName(p chess.Piece) string {
+	return pieceNames[(p-chess.WhitePawn)%6]
+}
+
+// sideName returns "White" or "Black".
+func sideName(c chess.Color) string {
+	if c == chess.Black {
+		return "Black"
+	}
+	return "White"
+}
+
+// describeMove describes m, played in g, in words for a screen reader, as in
+// "White knight from g1 to f3, check" or "Black pawn from d4 takes pawn en
+// passant on e3".
+func describeMove(g chess.Game, m chess.Move) string {
+	p := g.State.Board[m.From]
+	if p == chess.NoPiece {
+		return m.UCIString()
+	}
+	side := chess.White
+	if p >= chess.BlackPawn {
+		side = chess.Black
+	}
+	name := pieceName(p)
+	var text string
+	switch {
+	case name == "king" && m.To.File()-m.From.File() == 2:
+		text = sideName(side) + " castles kingside"
+	case name == "king" && m.From.File()-m.To.File() == 2:
+		text = sideName(side) + " castles queenside"
+	default:
+		text = fmt.Sprintf("%s %s from %s", sideName(side), name, squareName(m.From))
+		switch taken := g.State.Board[m.To]; {
+		case taken != chess.NoPiece:
+			text += fmt.Sprintf(" takes %s on %s", pieceName(taken), squareName(m.To))
+		case name == "pawn" && m.From.File() != m.To.File():
+			text += " takes pawn en passant on " + squareName(m.To)
+		default:
+			text += " to " + squareName(m.To)
+		}
+		if m.Promotion != chess.NoPiece {
+			text += ", promotes to " + pieceName(m.Promotion)
+		}
+	}
+	if next, err := g.Apply(m); err == nil && next.InCheck() {
+		if r := next.Result(); r == chess.WhiteWins || r == chess.BlackWins {
+			return text + ", checkmate"
+		}
+		return text + ", check"
+	}
+	return text
+}
+
+// describePieces lists every piece on the board of g in words, a line for
+// each side, kings first, as in "White: king on e1, rooks on a1 and h1".
+func describePieces(g chess.Game) string {
+	var sb strings.Builder
+	for _, side := range []chess.Color{chess.White, chess.Black} {
+		var groups []string
+		for kind := len(pieceNames) - 1; kind >= 0; kind-- {
+			var squares []string
+			for sq := chess.Square(0); sq < 64; sq++ {
+				p := g.State.Board[sq]
+				if p != chess.NoPiece && (p >= chess.BlackPawn) == (side == chess.Black) && pieceName(p) == pieceNames[kind] {
+					squares = append(squares, squareName(sq))
+				}
+			}
+			name := pieceNames[kind]
+			if len(squares) > 1 {
+				name += "s"
+			}
+			if len(squares) > 0 {
+				groups = append(groups, name+" on "+listWords(squares))
+			}
+		}
+		fmt.Fprintf(&sb, "%s: %s.\n", sideName(side), strings.Join(groups, ", "))
+	}
+	return sb.String()
+}
+
+// listWords joins words as a list is read aloud: "a", "a and b", "a, b and c".
+func listWords(words []string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " and " + words[len(words)-1]
+}
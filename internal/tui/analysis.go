@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// analysisWidth is the width the lines of the analysis are clipped to on a
+// terminal, so that each stays on one row and can be redrawn in place.
+const analysisWidth = 79
+
+// analysisHelp lists what the player can type on the analysis board.
+const analysisHelp = `Type a move (e2e4 or Nf3) to play it, a FEN to set up a position,
+undo to take back the last move, or quit to leave.`
+
+// AnalysisFunc searches g until ctx is cancelled or the search ends, passing
+// each line it finds to info: with several lines per depth, each ranked by
+// its MultiPV.
+type AnalysisFunc func(ctx context.Context, g chess.Game, info func(engine.SearchResult))
+
+// Analysis is an analysis board: the engine searches the position on the
+// board until the player changes it, showing its best lines as they improve.
+type Analysis struct {
+	in       *bufio.Reader
+	w        io.Writer
+	search   AnalysisFunc
+	start    chess.Game
+	lines    int // lines shown on a terminal
+	style    Style
+	terminal bool // w is a terminal: the lines are redrawn in place
+}
+
+// NewAnalysis returns an analysis board from start, reading the player's
+// input from r and drawing on w, analysed by search with one line shown.
+func NewAnalysis(r io.Reader, w io.Writer, search AnalysisFunc, start chess.Game) Analysis {
+	return Analysis{in: bufio.NewReader(r), w: w, search: search, start: start, lines: 1}
+}
+
+// WithLines returns the board showing the engine's best n lines, as many as
+// it is set to search.
+func (a Analysis) WithLines(n int) Analysis {
+	a.lines = max(n, 1)
+	return a
+}
+
+// WithStyle returns the board drawn in style s.
+func (a Analysis) WithStyle(s Style) Analysis {
+	a.style = s
+	return a
+}
+
+// WithTerminal returns the board drawing on a terminal, or not. On a terminal
+// the lines below the board are redrawn in place as the search deepens;
+// otherwise each line is written as it arrives.
+func (a Analysis) WithTerminal(terminal bool) Analysis {
+	a.terminal = terminal
+	return a
+}
+
+// Run shows the analysis of the start position, then of each position the
+// player sets up, until the player quits or the input ends. Each new position
+// stops the search of the last and starts another.
+func (a Analysis) Run() {
+	a.w = &lineCounter{w: a.w}
+	p := newPlayed(a.start)
+	var pending chan input // a read of the player's input in progress
+	for {
+		RenderWith(p.game, a.w, RenderOptions{Style: a.style, LastMove: p.last()})
+		if !a.analyse(&p, &pending) {
+			return
+		}
+	}
+}
+
+// analyse searches p's position while reading the player's input, until the
+// input changes the position, reporting true, or ends it, reporting false.
+// A read still pending is kept in *pending.
+func (a Analysis) analyse(p *played, pending *chan input) bool {
+	out := a.w.(*lineCounter)
+	game := p.game
+	block, shown := out.lines, make([]engine.SearchResult, a.lines)
+	if r := game.Result(); r != chess.InProgress {
+		_, _ = fmt.Fprintln(a.w, outcome(r))
+		block = -1
+	} else if a.terminal {
+		_, _ = fmt.Fprint(a.w, "Analysing...\n"+strings.Repeat("\n", a.lines-1))
+	}
+	prompt := func() { _, _ = fmt.Fprint(a.w, "Move, FEN, undo or quit: ") }
+	prompt()
+	open := true // the prompt is the last thing written
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mu sync.Mutex
+	var found []engine.SearchResult // lines not yet shown
+	updated, done := make(chan struct{}, 1), make(chan struct{})
+	if block >= 0 {
+		go func() {
+			defer close(done)
+			a.search(ctx, game, func(res engine.SearchResult) {
+				mu.Lock()
+				found = append(found, res)
+				mu.Unlock()
+				select {
+				case updated <- struct{}{}:
+				default:
+				}
+			})
+		}()
+	} else {
+		close(done)
+	}
+	show := func() {
+		mu.Lock()
+		lines := found
+		found = nil
+		mu.Unlock()
+		for _, res := range lines {
+			text := analysisLine(game, res)
+			if !a.terminal {
+				if open {
+					_, _ = fmt.Fprintln(a.w)
+					open = false
+				}
+				_, _ = fmt.Fprintln(a.w, text)
+				continue
+			}
+			if rank := max(res.MultiPV, 1); rank <= len(shown) {
+				shown[rank-1] = res
+				up := out.lines - block - (rank - 1)
+				if up <= liveLines {
+					_, _ = fmt.Fprintf(out.w, "\x1b7\x1b[%dA\r%s\x1b[K\x1b8", up, clip(text, analysisWidth))
+				}
+			}
+		}
+	}
+	stop := func() {
+		cancel()
+		<-done
+		show()
+	}
+
+	searching := done
+	for {
+		if *pending == nil {
+			ch := make(chan input, 1)
+			go func() {
+				line, err := readLine(a.in)
+				ch <- input{line, err}
+			}()
+			*pending = ch
+		}
+		select {
+		case <-updated:
+			show()
+		case <-searching:
+			searching = nil
+			show()
+		case in := <-*pending:
+			*pending = nil
+			if a.terminal {
+				out.lines++ // the player's Enter
+			}
+			if in.err != nil {
+				stop()
+				_, _ = fmt.Fprintln(a.w)
+				return false
+			}
+			changed, quit := a.input(p, in.line)
+			if changed || quit {
+				stop()
+				return !quit
+			}
+			prompt()
+			open = true
+		}
+	}
+}
+
+// input carries out line, typed on the analysis board showing p, and reports
+// whether it changed the position or asked to quit. Input that does neither
+// is answered with a message.
+func (a Analysis) input(p *played, line string) (changed, quit bool) {
+	switch strings.ToLower(line) {
+	case "":
+		return false, false
+	case "quit", "exit":
+		return false, true
+	case "undo":
+		if !p.back() {
+			_, _ = fmt.Fprintln(a.w, "Nothing to undo.")
+			return false, false
+		}
+		return true, false
+	case "help":
+		_, _ = fmt.Fprintln(a.w, analysisHelp)
+		return false, false
+	}
+	if strings.Contains(line, "/") {
+		g, err := chess.NewGameFromFEN(line)
+		if err != nil {
+			_, _ = fmt.Fprintf(a.w, "Cannot read the FEN: %v\n", err)
+			return false, false
+		}
+		*p = newPlayed(g)
+		return true, false
+	}
+	m, err := parseMove(p.game, line)
+	if err != nil {
+		if errors.Is(err, chess.ErrIllegalMove) {
+			_, _ = fmt.Fprintf(a.w, "Illegal move: %s\n", line)
+		} else {
+			_, _ = fmt.Fprintf(a.w, "Invalid input: %q (type help)\n", line)
+		}
+		return false, false
+	}
+	next, _ := p.game.Apply(m)
+	p.push(m, next)
+	return true, false
+}
+
+// analysisLine formats a line of the engine's analysis of g: its rank, the
+// evaluation from White's view, the depth and the moves in SAN, as in
+// " 1. +0.35  d12  1.e4 e5 2.Nf3".
+func analysisLine(g chess.Game, res engine.SearchResult) string {
+	return fmt.Sprintf("%2d. %-6s d%-3d %s", max(res.MultiPV, 1), evalText(whiteScore(g, res.Score)),
+		res.Depth, strings.Join(sanLine(g, res.PV), " "))
+}
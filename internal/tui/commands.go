@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/clock"
+	engine "chess_go/internal/engine"
+)
+
+// hintTime is how long the engine thinks for a hint or a draw offer.
+const hintTime = 200 * time.Millisecond
+
+// helpText lists the commands the player can type instead of a move.
+const helpText = `Commands:
+  undo           take back your last move and the engine's reply
+  hint           suggest a move
+  flip           turn the board around
+  save FILE      save the game as PGN
+  load FILE|FEN  continue from the end of a PGN file's first game, or a FEN
+  fen            print the position as FEN
+  resign         resign the game
+  draw           offer the engine a draw
+  pieces         list every piece on the board
+  help           show this list
+Moves are typed as e2e4, e7e8q or Nf3.`
+
+// hotSeatHelpText lists the commands of a hot-seat game, where two players
+// take turns and the commands act for the side to move.
+const hotSeatHelpText = `Commands:
+  undo           take back the last move
+  hint           suggest a move, when an engine is at hand
+  flip           turn the board around
+  save FILE      save the game as PGN
+  load FILE|FEN  continue from the end of a PGN file's first game, or a FEN
+  fen            print the position as FEN
+  resign         resign the game for the side to move
+  draw           offer the other player a draw
+  pieces         list every piece on the board
+  help           show this list
+Moves are typed as e2e4, e7e8q or Nf3.`
+
+// played is a game in progress: its position, and the positions and moves
+// that led there from the start of play.
+type played struct {
+	start chess.Game
+	game  chess.Game
+	past  []chess.Game // the position before each move
+	moves []chess.Move
+}
+
+// newPlayed returns a game in progress from start.
+func newPlayed(start chess.Game) played {
+	return played{start: start, game: start}
+}
+
+// push records m, played in the current position, leading to next.
+func (p *played) push(m chess.Move, next chess.Game) {
+	p.past, p.moves, p.game = append(p.past, p.game), append(p.moves, m), next
+}
+
+// last returns the move last played, or the zero Move before the first.
+func (p *played) last() chess.Move {
+	if len(p.moves) == 0 {
+		return chess.Move{}
+	}
+	return p.moves[len(p.moves)-1]
+}
+
+// undo takes back side's last move and any moves after it, reporting false
+// when side has made no move to take back.
+func (p *played) undo(side chess.Color) bool {
+	for i := len(p.moves) - 1; i >= 0; i-- {
+		if p.past[i].State.ActiveColor == side {
+			p.game, p.past, p.moves = p.past[i], p.past[:i], p.moves[:i]
+			return true
+		}
+	}
+	return false
+}
+
+// back takes back the last move, reporting false before the first.
+func (p *played) back() bool {
+	n := len(p.moves)
+	if n == 0 {
+		return false
+	}
+	p.game, p.past, p.moves = p.past[n-1], p.past[:n-1], p.moves[:n-1]
+	return true
+}
+
+// pgn returns the game played as s describes as PGN, with the player and the
+// engine, or the two players of a hot-seat game, as the sides and result as
+// its result.
+func (p *played) pgn(s Setup, result string) chess.PGNGame {
+	tags := map[string]string{
+		"Event": "chess-go game",
+		"Date":  time.Now().Format("2006.01.02"),
+		"White": "Player",
+		"Black": "chess-go",
+	}
+	switch {
+	case s.HotSeat:
+		tags["White"], tags["Black"] = "Player 1", "Player 2"
+	case s.Human == chess.Black:
+		tags["White"], tags["Black"] = tags["Black"], tags["White"]
+	}
+	return chess.PGNGame{Tags: tags, Start: p.start, Moves: p.moves, Result: result}
+}
+
+// commandOutcome is what follows a command.
+type commandOutcome uint8
+
+const (
+	commandDone      commandOutcome = iota // the player is asked again
+	commandRedraw                          // the position changed: the board is drawn again
+	commandFlip                            // the board is turned around and drawn again
+	commandEnded                           // the game is over
+	commandDrawOffer                       // hot seat: the player not to move is asked to take a draw
+)
+
+// parseCommand splits line into a command and its argument, reporting false
+// when line is not a command.
+func parseCommand(line string) (name, arg string, ok bool) {
+	name, arg, _ = strings.Cut(line, " ")
+	name = strings.ToLower(name)
+	switch name {
+	case "undo", "hint", "flip", "save", "load", "fen", "resign", "draw", "pieces", "help":
+		return name, strings.TrimSpace(arg), true
+	}
+	return "", "", false
+}
+
+// command carries out the player's command name with its argument arg on p,
+// the game being played with clock clk, if it has one.
+func (g Game) command(name, arg string, p *played, clk *clock.Clock) commandOutcome {
+	switch name {
+	case "undo":
+		if g.setup.HotSeat && !p.back() || !g.setup.HotSeat && !p.undo(g.setup.Human) {
+			_, _ = fmt.Fprintln(g.w, "Nothing to undo.")
+			return commandDone
+		}
+		if clk != nil {
+			clk.Start(p.game.State.ActiveColor)
+		}
+		return commandRedraw
+	case "hint":
+		if g.engineFn == nil && g.search == nil {
+			_, _ = fmt.Fprintln(g.w, "No hint: there is no engine in this game.")
+			return commandDone
+		}
+		m := g.think(p.game, engine.TimeControl{MoveTime: hintTime}, nil).BestMove
+		if !isLegal(p.game, m) {
+			_, _ = fmt.Fprintln(g.w, "No hint: the engine found no move.")
+			return commandDone
+		}
+		_, _ = fmt.Fprintf(g.w, "Hint: %s\n", m.SANString(p.game))
+	case "flip":
+		return commandFlip
+	case "save":
+		if arg == "" {
+			_, _ = fmt.Fprintln(g.w, "Usage: save FILE")
+			return commandDone
+		}
+		if err := os.WriteFile(arg, []byte(p.pgn(g.setup, "*").String()), 0o644); err != nil {
+			_, _ = fmt.Fprintf(g.w, "Cannot save the game: %v\n", err)
+			return commandDone
+		}
+		_, _ = fmt.Fprintf(g.w, "Saved the game to %s\n", arg)
+	case "load":
+		if arg == "" {
+			_, _ = fmt.Fprintln(g.w, "Usage: load FILE.pgn or load FEN")
+			return commandDone
+		}
+		start, err := LoadStart(arg)
+		if err != nil {
+			_, _ = fmt.Fprintf(g.w, "Cannot load %s: %v\n", arg, err)
+			return commandDone
+		}
+		*p = newPlayed(start)
+		if clk != nil {
+			clk.Start(start.State.ActiveColor)
+		}
+		return commandRedraw
+	case "fen":
+		_, _ = fmt.Fprintln(g.w, p.game.ToFEN())
+	case "resign":
+		loser := g.setup.Human
+		if g.setup.HotSeat {
+			loser = p.game.State.ActiveColor
+		}
+		score := "0-1"
+		if loser == chess.Black {
+			score = "1-0"
+		}
+		who := "You resign"
+		if g.setup.HotSeat {
+			who = sideName(loser) + " resigns"
+		}
+		_, _ = fmt.Fprintf(g.w, "%s. %s wins. %s\n", who, sideName(1-loser), score)
+		return commandEnded
+	case "draw":
+		if g.setup.HotSeat {
+			return commandDrawOffer
+		}
+		if g.acceptsDraw(p.game) {
+			_, _ = fmt.Fprintln(g.w, "The engine accepts the draw. 1/2-1/2")
+			return commandEnded
+		}
+		_, _ = fmt.Fprintln(g.w, "The engine declines the draw.")
+	case "pieces":
+		_, _ = fmt.Fprint(g.w, describePieces(p.game))
+	case "help":
+		if g.setup.HotSeat {
+			_, _ = fmt.Fprintln(g.w, hotSeatHelpText)
+		} else {
+			_, _ = fmt.Fprintln(g.w, helpText)
+		}
+	}
+	return commandDone
+}
+
+// drawQuestion returns the question to side, the player not to move in a
+// hot-seat game, after a draw offer. Run reads the answer as it reads moves,
+// so that the clock still runs and can fall meanwhile.
+func drawQuestion(side chess.Color) string {
+	return sideName(side) + ", do you accept a draw? (y/n): "
+}
+
+// answerDraw reports side's answer to a draw offer, reporting true when side
+// takes the draw and the game is over.
+func (g Game) answerDraw(side chess.Color, answer string) bool {
+	if a := strings.ToLower(answer); a == "y" || a == "yes" {
+		_, _ = fmt.Fprintln(g.w, "Draw agreed. 1/2-1/2")
+		return true
+	}
+	_, _ = fmt.Fprintf(g.w, "%s declines the draw.\n", sideName(side))
+	return false
+}
+
+// acceptsDraw reports whether the engine takes a draw offered in game, with
+// the player to move: it does when a short search finds it no better off
+// than even. An engine that reports no evaluation declines.
+func (g Game) acceptsDraw(game chess.Game) bool {
+	res := g.think(game, engine.TimeControl{MoveTime: hintTime}, nil)
+	if res.Depth == 0 {
+		return false
+	}
+	return res.Score >= 0 // the player's view, so the engine's is at most even
+}
@@ -1,49 +1,401 @@
 package tui
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	chess "chess_go/internal/chess"
+	"chess_go/internal/clock"
 	engine "chess_go/internal/engine"
 )
 
 // EngineFunc is the type of the engine callback injected into the TUI game loop.
 type EngineFunc func(g chess.Game, tc engine.TimeControl) chess.Move
 
+// SearchFunc is an engine callback that reports its search: it passes
+// progress to info, when info is not nil, and returns the final result.
+type SearchFunc func(g chess.Game, tc engine.TimeControl, info func(engine.SearchResult)) engine.SearchResult
+
+// defaultThinkTime is the engine's time per move in the default setup.
+const defaultThinkTime = 100 * time.Millisecond
+
 // Game manages the TUI game loop with injected I/O for testability.
+// The player has the side chosen in the setup; the engine plays the other.
 type Game struct {
-	r        io.Reader
+	in       *bufio.Reader
 	w        io.Writer
 	engineFn EngineFunc
+	search   SearchFunc // the engine with its search reports; nil to use engineFn
+	setup    Setup
+	terminal bool     // w is a terminal: running clocks are redrawn in place
+	style    Style    // how the board is drawn
+	term     Terminal // the terminal of a full-screen game; nil for line input
 }
 
-// NewGame constructs a TUI Game with the given I/O and engine function.
+// NewGame constructs a TUI Game with the default setup: the player has White
+// from the starting position.
 func NewGame(r io.Reader, w io.Writer, engineFn EngineFunc) Game {
-	return Game{r: r, w: w, engineFn: engineFn}
+	return NewGameWithSetup(r, w, engineFn, DefaultSetup())
 }
 
-// Run executes the walking skeleton game loop:
-// 1. Load the starting position.
-// 2. Call engineFn to select a move.
-// 3. Apply the move.
-// 4. Render the resulting board.
-//
-// For the walking skeleton, this executes one move and exits.
-// Full interactive loop is implemented in US-24/US-25.
-func (g Game) Run() {
-	game, err := chess.NewGameFromFEN("rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+// NewGameFromFEN constructs a TUI Game that starts from fen, with the player
+// as White. When Black is to move there, the engine moves first.
+func NewGameFromFEN(r io.Reader, w io.Writer, engineFn EngineFunc, fen string) (Game, error) {
+	start, err := chess.NewGameFromFEN(fen)
 	if err != nil {
-		return
+		return Game{}, err
 	}
+	s := DefaultSetup()
+	s.Start = start
+	return NewGameWithSetup(r, w, engineFn, s), nil
+}
 
-	tc := engine.TimeControl{MoveTime: 100 * time.Millisecond}
-	move := g.engineFn(game, tc)
+// NewGameWithSetup constructs a TUI Game played as s describes.
+func NewGameWithSetup(r io.Reader, w io.Writer, engineFn EngineFunc, s Setup) Game {
+	return Game{in: bufio.NewReader(r), w: w, engineFn: engineFn, setup: s}
+}
 
-	newGame, err := game.Apply(move)
-	if err != nil {
+// Setup returns how the game is played.
+func (g Game) Setup() Setup { return g.setup }
+
+// WithTerminal returns the game writing to a terminal, or not. On a terminal
+// the clocks beside the board tick while the player thinks, redrawn in place
+// with ANSI cursor movement.
+func (g Game) WithTerminal(terminal bool) Game {
+	g.terminal = terminal
+	return g
+}
+
+// WithStyle returns the game drawing its board in style s.
+func (g Game) WithStyle(s Style) Game {
+	g.style = s
+	return g
+}
+
+// WithSearch returns the game played against search, which reports the
+// engine's evaluation and principal variation for the full-screen panel.
+func (g Game) WithSearch(search SearchFunc) Game {
+	g.search = search
+	return g
+}
+
+// WithFullScreen returns the game played full-screen on t, from OpenTerminal,
+// with the keys read from the game's reader and the screen drawn on its writer.
+func (g Game) WithFullScreen(t Terminal) Game {
+	g.term = t
+	return g
+}
+
+// input is a line read from the player, or the error that ended the input.
+type input struct {
+	line string
+	err  error
+}
+
+// Run plays the game: it renders the board from the player's side, reads the
+// player's moves from the reader and answers each with the engine's,
+// redrawing after every move, until the game ends or the input does. Bad
+// input is reported and the player asked again. In place of a move the
+// player may type a command (see helpText). With a clock, the side to
+// move's time runs, shown beside the board, and a side whose time runs out
+// loses, or draws if the other side could not have mated.
+func (g Game) Run() {
+	if g.term != nil {
+		g.runScreen()
 		return
 	}
+	out := &lineCounter{w: g.w}
+	g.w = out
+	p := newPlayed(g.setup.Start)
+	var clk *clock.Clock
+	if g.setup.Clock != nil {
+		clk = clock.New(g.setup.Clock, nil)
+		clk.Start(p.game.State.ActiveColor)
+	}
+	board := g.render(p.game, clk, chess.Move{})
+	var pending chan input // a read of the player's input in progress
+	for {
+		game := p.game
+		if r := game.Result(); r != chess.InProgress {
+			_, _ = fmt.Fprintln(g.w, outcome(r))
+			return
+		}
+
+		side := game.State.ActiveColor
+		var m chess.Move
+		if g.human(side) {
+			_, _ = fmt.Fprint(g.w, g.prompt(side))
+			in, ok := g.await(&pending, clk, board)
+			if !ok {
+				_, _ = fmt.Fprintln(g.w)
+				_, _ = fmt.Fprintln(g.w, timeout(clock.TimeoutIn(game, side)))
+				return
+			}
+			if in.err != nil {
+				_, _ = fmt.Fprintln(g.w)
+				return
+			}
+			if in.line == "" {
+				continue
+			}
+			if name, arg, ok := parseCommand(in.line); ok {
+				switch g.command(name, arg, &p, clk) {
+				case commandEnded:
+					return
+				case commandDrawOffer:
+					_, _ = fmt.Fprint(g.w, drawQuestion(1-side))
+					in, ok := g.await(&pending, clk, board)
+					if !ok {
+						_, _ = fmt.Fprintln(g.w)
+						_, _ = fmt.Fprintln(g.w, timeout(clock.TimeoutIn(game, side)))
+						return
+					}
+					if in.err != nil {
+						_, _ = fmt.Fprintln(g.w)
+						return
+					}
+					if g.answerDraw(1-side, in.line) {
+						return
+					}
+				case commandFlip:
+					g.style.Flipped = !g.style.Flipped
+					board = g.render(p.game, clk, p.last())
+				case commandRedraw:
+					board = g.render(p.game, clk, p.last())
+				}
+				continue
+			}
+			var err error
+			if m, err = parseMove(game, in.line); err != nil {
+				if errors.Is(err, chess.ErrIllegalMove) {
+					_, _ = fmt.Fprintf(g.w, "Illegal move: %s\n", in.line)
+				} else {
+					_, _ = fmt.Fprintf(g.w, "Invalid move format: %q (use e.g. e2e4, e7e8q or Nf3, or type help)\n", in.line)
+				}
+				continue
+			}
+		} else {
+			tc := g.setup.Think
+			if clk != nil {
+				tc = clk.TimeControl(side)
+			}
+			_, _ = fmt.Fprintln(g.w, "Engine thinking...")
+			m = g.think(game, tc, nil).BestMove
+			if !isLegal(game, m) {
+				_, _ = fmt.Fprintf(g.w, "The engine failed to return a legal move (%s); game abandoned.\n", m.UCIString())
+				return
+			}
+		}
+
+		if clk != nil && !clk.Press() {
+			_, _ = fmt.Fprintln(g.w, timeout(clock.TimeoutIn(game, side)))
+			return
+		}
+		switch {
+		case !g.human(side) && g.style.Accessible:
+			_, _ = fmt.Fprintf(g.w, "Engine plays %s\n", describeMove(game, m))
+		case !g.human(side):
+			_, _ = fmt.Fprintf(g.w, "Engine plays %s\n", m.SANString(game))
+		case g.style.Accessible:
+			_, _ = fmt.Fprintln(g.w, describeMove(game, m))
+		}
+		next, err := game.Apply(m)
+		if err != nil {
+			_, _ = fmt.Fprintf(g.w, "Illegal move: %s\n", m.UCIString())
+			continue
+		}
+		p.push(m, next)
+		board = g.render(next, clk, m)
+		if next.InCheck() && next.Result() == chess.InProgress && !g.style.Accessible {
+			_, _ = fmt.Fprintln(g.w, "Check!")
+		}
+	}
+}
+
+// human reports whether side is played at the terminal: the player's side,
+// or both sides in a hot-seat game.
+func (g Game) human(side chess.Color) bool {
+	return g.setup.HotSeat || side == g.setup.Human
+}
+
+// prompt returns the prompt for side's move: in a hot-seat game it names the
+// side, so that the players know whose turn it is.
+func (g Game) prompt(side chess.Color) string {
+	if g.setup.HotSeat {
+		return sideName(side) + "'s move: "
+	}
+	return "Your move: "
+}
+
+// think asks the engine for its move in game. A game without an engine, such
+// as a hot-seat game, returns the zero result.
+func (g Game) think(game chess.Game, tc engine.TimeControl, info func(engine.SearchResult)) engine.SearchResult {
+	switch {
+	case g.search != nil:
+		return g.search(game, tc, info)
+	case g.engineFn != nil:
+		return engine.SearchResult{BestMove: g.engineFn(game, tc)}
+	}
+	return engine.SearchResult{}
+}
 
-	Render(newGame, g.w)
+// render draws the board from the player's side, in the game's style with
+// last highlighted and the clocks beside it if the game has them, and returns
+// the output line the board starts on.
+func (g Game) render(game chess.Game, clk *clock.Clock, last chess.Move) int {
+	start := g.w.(*lineCounter).lines
+	RenderWith(game, g.w, g.renderOptions(game.State.ActiveColor, clk, last))
+	return start
+}
+
+// renderOptions returns how the game's board is drawn with turn to move: from
+// the player's side, or from turn's in a hot-seat game with AutoFlip.
+func (g Game) renderOptions(turn chess.Color, clk *clock.Clock, last chess.Move) RenderOptions {
+	side := g.setup.Human
+	if g.setup.HotSeat && g.setup.AutoFlip {
+		side = turn
+	}
+	return RenderOptions{Style: g.style, Side: side, LastMove: last, Clock: clk}
+}
+
+// liveLines is how far back the board may be from the prompt for the clocks
+// to be redrawn in place: about a terminal's height.
+const liveLines = 23
+
+// await waits for the player's next line of input, reading it in the
+// background so that it can give up, reporting false, when the player's flag
+// falls. A read still pending then is kept in *pending for the next call. On
+// a terminal, the running clock beside the board that starts at output line
+// board is redrawn every tenth of a second.
+func (g Game) await(pending *chan input, clk *clock.Clock, board int) (input, bool) {
+	if *pending == nil {
+		ch := make(chan input, 1)
+		go func() {
+			line, err := g.readLine()
+			ch <- input{line, err}
+		}()
+		*pending = ch
+	}
+	var flag, tick <-chan time.Time
+	if clk != nil {
+		t := time.NewTimer(clk.UntilFlag())
+		defer t.Stop()
+		flag = t.C
+		if g.terminal && !g.style.Accessible {
+			tk := time.NewTicker(100 * time.Millisecond)
+			defer tk.Stop()
+			tick = tk.C
+		}
+	}
+	for {
+		select {
+		case in := <-*pending:
+			*pending = nil
+			return in, true
+		case <-flag:
+			if _, flagged := clk.Flagged(); flagged {
+				return input{}, false
+			}
+			// The timer may fire a moment before the clock reads zero.
+			flag = time.After(max(clk.UntilFlag(), time.Millisecond))
+		case <-tick:
+			g.redrawClock(clk, board)
+		}
+	}
+}
+
+// redrawClock rewrites the clocks beside the board that starts at output line
+// board, keeping the cursor where the player is typing. It leaves a board
+// scrolled too far up alone.
+func (g Game) redrawClock(clk *clock.Clock, board int) {
+	out := g.w.(*lineCounter)
+	turn, _ := clk.Running()
+	o := g.renderOptions(turn, clk, chess.Move{})
+	l := o.layout()
+	for _, row := range []int{0, 7} {
+		up := out.lines - board - l.rowLine(row)
+		if up > liveLines {
+			continue
+		}
+		_, _ = fmt.Fprintf(out.w, "\x1b7\x1b[%dA\r\x1b[%dC%s\x1b[K\x1b8", up, l.width, o.aside(row))
+	}
+}
+
+// lineCounter is a writer counting the lines written through it.
+type lineCounter struct {
+	w     io.Writer
+	lines int
+}
+
+func (lc *lineCounter) Write(p []byte) (int, error) {
+	lc.lines += bytes.Count(p, []byte{'\n'})
+	return lc.w.Write(p)
+}
+
+// readLine returns the player's next line of input; see readLine.
+func (g Game) readLine() (string, error) { return readLine(g.in) }
+
+// readLine returns the next line of in without surrounding space. A last
+// line without a newline is returned before io.EOF.
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// timeout describes a game ended by a flag fall.
+func timeout(t clock.Timeout) string {
+	flagged, other := "White", "Black"
+	if t.Flagged == chess.Black {
+		flagged, other = other, flagged
+	}
+	if t.Draw {
+		return fmt.Sprintf("%s ran out of time, but %s cannot checkmate. Draw. %s", flagged, other, t.Score())
+	}
+	return fmt.Sprintf("%s lost on time. %s", flagged, t.Score())
+}
+
+// parseMove reads a move in coordinate notation, or failing that in SAN.
+func parseMove(game chess.Game, text string) (chess.Move, error) {
+	m, err := chess.ParseUCI(game, text)
+	if errors.Is(err, chess.ErrInvalidMoveFormat) {
+		return chess.ParseSAN(game, text)
+	}
+	return m, err
+}
+
+// isLegal reports whether m is a legal move in game.
+func isLegal(game chess.Game, m chess.Move) bool {
+	for _, lm := range game.LegalMoves() {
+		if lm == m {
+			return true
+		}
+	}
+	return false
+}
+
+// outcome describes a finished game.
+func outcome(r chess.GameResult) string {
+	switch r {
+	case chess.WhiteWins:
+		return "Checkmate! White wins. 1-0"
+	case chess.BlackWins:
+		return "Checkmate! Black wins. 0-1"
+	case chess.Stalemate:
+		return "Stalemate. The game is drawn. 1/2-1/2"
+	case chess.DrawFiftyMove:
+		return "Draw by the fifty-move rule. 1/2-1/2"
+	case chess.DrawThreefoldRepetition:
+		return "Draw by threefold repetition. 1/2-1/2"
+	case chess.DrawInsufficientMaterial:
+		return "Draw by insufficient material. 1/2-1/2"
+	}
+	return ""
 }
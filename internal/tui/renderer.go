@@ -1,11 +1,13 @@
-// Package tui implements the terminal game loop and ASCII board renderer.
+// Package tui implements the terminal game loop and board renderer.
 package tui
 
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	chess "chess_go/internal/chess"
+	"chess_go/internal/clock"
 )
 
 // pieceASCII maps a Piece to its ASCII character for display.
@@ -39,29 +41,364 @@ func pieceASCII(p chess.Piece) byte {
 	return '.'
 }
 
-// Render writes an ASCII board representation of g to w.
+// pieceGlyph maps a Piece to its Unicode chess glyph: outlined for White and
+// solid for Black, or solid for both when colour tells them apart.
+func pieceGlyph(p chess.Piece, solid bool) string {
+	if p == chess.NoPiece {
+		return "·"
+	}
+	i := int(p - chess.WhitePawn)
+	if solid && i < 6 {
+		i += 6
+	}
+	return string([]rune("♙♘♗♖♕♔♟♞♝♜♛♚")[i])
+}
+
+// ColorMode is the colour support a board is drawn with.
+type ColorMode uint8
+
+const (
+	NoColor   ColorMode = iota // no escape sequences: the board is drawn as a grid
+	Color256                   // ANSI 256-colour square backgrounds
+	TrueColor                  // 24-bit square backgrounds
+)
+
+// Style is how boards are drawn.
+type Style struct {
+	Unicode        bool      // chess glyphs in place of letters, and box-drawing lines
+	Color          ColorMode // coloured squares in place of the grid, with highlights
+	AllCoordinates bool      // rank and file labels on all four sides, not just left and bottom
+	Flipped        bool      // the board is turned: the player's side at the top
+	Accessible     bool      // for screen readers: the position in words, without a drawn board
+}
+
+// DetectStyle returns the style for output to a terminal, or not: ASCII
+// without colour when the output is not a terminal, the NO_COLOR variable is
+// set or TERM is "dumb"; otherwise chess glyphs on 256-colour squares, or
+// 24-bit colour when COLORTERM announces it. Variables are read with getenv.
+func DetectStyle(terminal bool, getenv func(string) string) Style {
+	if !terminal || getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return Style{}
+	}
+	s := Style{Unicode: true, Color: Color256}
+	if ct := getenv("COLORTERM"); ct == "truecolor" || ct == "24bit" {
+		s.Color = TrueColor
+	}
+	return s
+}
+
+// RenderOptions is how RenderWith draws a position and what it shows with it.
+type RenderOptions struct {
+	Style
+	Side     chess.Color  // the side at the bottom of the board, unless Flipped
+	LastMove chess.Move   // highlighted when coloured; the zero Move for none
+	Clock    *clock.Clock // drawn beside the board; nil for none
+	Marks    [64]Mark     // the player's cursor, picked-up piece and its destinations
+}
+
+// Mark is a set of marks on a square for a player choosing a move.
+type Mark uint8
+
+const (
+	MarkCursor Mark = 1 << iota // the square under the cursor
+	MarkPicked                  // the piece picked up
+	MarkTarget                  // a legal destination of the piece picked up
+)
+
+// Render writes an ASCII board representation of g to w, from White's side.
 // Ranks are displayed 8 (top) to 1 (bottom); files a-h left to right.
+func Render(g chess.Game, w io.Writer) {
+	RenderWith(g, w, RenderOptions{})
+}
+
+// RenderFrom writes an ASCII board representation of g to w with side at the
+// bottom: from Black's side ranks run 1 (top) to 8 and files h-a.
+func RenderFrom(g chess.Game, w io.Writer, side chess.Color) {
+	RenderWith(g, w, RenderOptions{Side: side})
+}
+
+// RenderWithClock is RenderFrom with the clocks beside the board: the
+// opponent's by the top rank and side's by the bottom rank, the running one
+// marked with an asterisk.
+func RenderWithClock(g chess.Game, w io.Writer, side chess.Color, c *clock.Clock) {
+	RenderWith(g, w, RenderOptions{Side: side, Clock: c})
+}
+
+// RenderWith writes the board of g to w as o describes, then the side to move.
+// Coloured boards highlight the last move's squares and a king in check. An
+// accessible style draws no board: it writes the side to move, whether it is
+// in check and the clocks, in words.
 // Write errors are intentionally ignored: if the writer fails (e.g. closed pipe),
 // partial output is acceptable for a terminal renderer.
-func Render(g chess.Game, w io.Writer) {
-	s := g.State
-
-	_, _ = fmt.Fprintln(w, "  +---+---+---+---+---+---+---+---+")
-	for rank := 7; rank >= 0; rank-- {
-		_, _ = fmt.Fprintf(w, "%d |", rank+1)
-		for file := 0; file <= 7; file++ {
-			p := s.Board[chess.SquareOf(file, rank)]
-			_, _ = fmt.Fprintf(w, " %c |", pieceASCII(p))
+func RenderWith(g chess.Game, w io.Writer, o RenderOptions) {
+	if o.Accessible {
+		renderAccessible(g, w, o)
+		return
+	}
+	var sb strings.Builder
+	bottom := o.bottom()
+	files := "abcdefgh"
+	if bottom == chess.Black {
+		files = "hgfedcba"
+	}
+	cell := "  %c "
+	if o.Color != NoColor {
+		cell = " %c "
+	}
+	fileLabels := func() {
+		labels := "  "
+		for _, f := range files {
+			labels += fmt.Sprintf(cell, f)
 		}
-		_, _ = fmt.Fprintln(w)
-		_, _ = fmt.Fprintln(w, "  +---+---+---+---+---+---+---+---+")
+		sb.WriteString(strings.TrimRight(labels, " ") + "\n")
+	}
+	rule := func(left, mid, right string) {
+		switch {
+		case o.Color != NoColor:
+		case o.Unicode:
+			sb.WriteString("  " + left + strings.Repeat("───"+mid, 7) + "───" + right + "\n")
+		default:
+			sb.WriteString("  +---+---+---+---+---+---+---+---+\n")
+		}
+	}
+	checked := chess.NoSquare
+	if g.InCheck() {
+		checked = kingSquare(g.State, g.State.ActiveColor)
 	}
-	_, _ = fmt.Fprintln(w, "    a   b   c   d   e   f   g   h")
+
+	if o.AllCoordinates {
+		fileLabels()
+	}
+	rule("┌", "┬", "┐")
+	for row := 0; row < 8; row++ {
+		rank := 7 - row
+		if bottom == chess.Black {
+			rank = row
+		}
+		fmt.Fprintf(&sb, "%d ", rank+1)
+		for col := 0; col < 8; col++ {
+			file := col
+			if bottom == chess.Black {
+				file = 7 - col
+			}
+			sq := chess.SquareOf(file, rank)
+			p := g.State.Board[sq]
+			mark := o.Marks[sq]
+			switch {
+			case o.Color != NoColor:
+				hl := highlightNone
+				switch {
+				case mark&MarkCursor != 0:
+					hl = highlightCursor
+				case mark&MarkPicked != 0:
+					hl = highlightPicked
+				case mark&MarkTarget != 0:
+					hl = highlightTarget
+				case sq == checked:
+					hl = highlightCheck
+				case o.LastMove != (chess.Move{}) && (sq == o.LastMove.From || sq == o.LastMove.To):
+					hl = highlightMove
+				}
+				sb.WriteString(o.Color.square((file+rank)%2 == 1, hl, p))
+				switch {
+				case p != chess.NoPiece:
+					sb.WriteString(" " + pieceGlyph(p, true) + " ")
+				case mark&MarkTarget != 0:
+					sb.WriteString(" • ")
+				default:
+					sb.WriteString("   ")
+				}
+			case o.Unicode:
+				if col == 0 {
+					sb.WriteString("│")
+				}
+				glyph := pieceGlyph(p, false)
+				if p == chess.NoPiece && mark&MarkTarget != 0 {
+					glyph = "•"
+				}
+				sb.WriteString(markedCell(mark, p, glyph) + "│")
+			default:
+				if col == 0 {
+					sb.WriteString("|")
+				}
+				glyph := string(pieceASCII(p))
+				if p == chess.NoPiece && mark&MarkTarget != 0 {
+					glyph = "*"
+				}
+				sb.WriteString(markedCell(mark, p, glyph) + "|")
+			}
+		}
+		if o.Color != NoColor {
+			sb.WriteString(ansiReset)
+		}
+		if o.AllCoordinates {
+			fmt.Fprintf(&sb, " %d", rank+1)
+		}
+		sb.WriteString(o.aside(row) + "\n")
+		if row < 7 {
+			rule("├", "┼", "┤")
+		}
+	}
+	rule("└", "┴", "┘")
+	fileLabels()
 
 	// Side to move.
-	if s.ActiveColor == chess.White {
-		_, _ = fmt.Fprintln(w, "White to move")
+	if g.State.ActiveColor == chess.White {
+		sb.WriteString("White to move\n")
 	} else {
-		_, _ = fmt.Fprintln(w, "Black to move")
+		sb.WriteString("Black to move\n")
+	}
+	_, _ = io.WriteString(w, sb.String())
+}
+
+// renderAccessible writes the position of g for a screen reader: the side to
+// move, in check or not, and the clocks if o has them.
+func renderAccessible(g chess.Game, w io.Writer, o RenderOptions) {
+	side := g.State.ActiveColor
+	text := sideName(side) + " to move"
+	if g.InCheck() {
+		text += ", in check"
+	}
+	text += "\n"
+	if c := o.Clock; c != nil {
+		text += fmt.Sprintf("Clocks: White %s, Black %s\n", clock.Format(c.Remaining(chess.White)), clock.Format(c.Remaining(chess.Black)))
+	}
+	_, _ = io.WriteString(w, text)
+}
+
+// markedCell returns a grid square showing glyph, bracketed to show its
+// marks: [x] under the cursor, <x> picked up and (x) a capture it can make.
+func markedCell(mark Mark, p chess.Piece, glyph string) string {
+	switch {
+	case mark&MarkCursor != 0:
+		return "[" + glyph + "]"
+	case mark&MarkPicked != 0:
+		return "<" + glyph + ">"
+	case mark&MarkTarget != 0 && p != chess.NoPiece:
+		return "(" + glyph + ")"
+	}
+	return " " + glyph + " "
+}
+
+// bottom returns the side drawn at the bottom of the board.
+func (o RenderOptions) bottom() chess.Color {
+	if o.Flipped {
+		return 1 - o.Side
+	}
+	return o.Side
+}
+
+// aside returns the text beside row of the board: the clocks by the top and
+// bottom ranks.
+func (o RenderOptions) aside(row int) string {
+	if o.Clock == nil {
+		return ""
+	}
+	switch row {
+	case 0:
+		return clockText(o.Clock, 1-o.bottom())
+	case 7:
+		return clockText(o.Clock, o.bottom())
+	}
+	return ""
+}
+
+// boardLayout is where a board's ranks are in its output.
+type boardLayout struct {
+	first int // line of the top rank, counting from 0
+	step  int // lines from one rank to the next
+	width int // columns of a rank line, where text beside it starts
+}
+
+// layout returns where RenderWith puts the ranks of a board drawn with o.
+func (o RenderOptions) layout() boardLayout {
+	l := boardLayout{first: 1, step: 2, width: 35}
+	if o.Color != NoColor {
+		l = boardLayout{first: 0, step: 1, width: 26}
+	}
+	if o.AllCoordinates {
+		l.first++
+		l.width += 2
+	}
+	return l
+}
+
+// rowLine returns the line of the board's output that shows row, counting from the top.
+func (l boardLayout) rowLine(row int) int { return l.first + l.step*row }
+
+// clockText formats side's clock for display beside the board.
+func clockText(c *clock.Clock, side chess.Color) string {
+	name := "White"
+	if side == chess.Black {
+		name = "Black"
+	}
+	s := fmt.Sprintf("   %s %s", name, clock.Format(c.Remaining(side)))
+	if running, ok := c.Running(); ok && running == side {
+		s += " *"
+	}
+	return s
+}
+
+// kingSquare returns the square of side's king.
+func kingSquare(s chess.GameState, side chess.Color) chess.Square {
+	king := chess.WhiteKing
+	if side == chess.Black {
+		king = chess.BlackKing
+	}
+	for sq := chess.Square(0); sq < 64; sq++ {
+		if s.Board[sq] == king {
+			return sq
+		}
+	}
+	return chess.NoSquare
+}
+
+// highlight is why a square is highlighted.
+type highlight uint8
+
+const (
+	highlightNone   highlight = iota
+	highlightMove             // a square of the last move
+	highlightCheck            // the king in check
+	highlightTarget           // a legal destination of the piece picked up
+	highlightPicked           // the piece picked up
+	highlightCursor           // the square under the cursor
+)
+
+const ansiReset = "\x1b[0m"
+
+// rgb is a colour as 24-bit components and its nearest of the 256 colours.
+type rgb struct {
+	r, g, b uint8
+	ansi    uint8
+}
+
+// squareColors are the square backgrounds, light and dark, by highlight.
+var squareColors = [6][2]rgb{
+	highlightNone:   {{240, 217, 181, 223}, {181, 136, 99, 137}},
+	highlightMove:   {{205, 210, 106, 186}, {170, 162, 58, 142}},
+	highlightCheck:  {{235, 97, 80, 203}, {200, 70, 60, 167}},
+	highlightTarget: {{170, 200, 150, 151}, {120, 155, 100, 107}},
+	highlightPicked: {{246, 236, 110, 228}, {200, 185, 50, 178}},
+	highlightCursor: {{130, 175, 225, 117}, {80, 130, 190, 68}},
+}
+
+// pieceColors are the glyph colours of White's and Black's pieces.
+var pieceColors = [2]rgb{{255, 255, 255, 231}, {0, 0, 0, 16}}
+
+// square returns the escape sequence starting a square of the given shade
+// and highlight that holds p.
+func (m ColorMode) square(light bool, hl highlight, p chess.Piece) string {
+	bg := squareColors[hl][1]
+	if light {
+		bg = squareColors[hl][0]
+	}
+	fg := pieceColors[0]
+	if p >= chess.BlackPawn {
+		fg = pieceColors[1]
+	}
+	if m == TrueColor {
+		return fmt.Sprintf("\x1b[1;38;2;%d;%d;%d;48;2;%d;%d;%dm", fg.r, fg.g, fg.b, bg.r, bg.g, bg.b)
 	}
+	return fmt.Sprintf("\x1b[1;38;5;%d;48;5;%dm", fg.ansi, bg.ansi)
 }
@@ -0,0 +1,691 @@
+package tui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"chess_go/internal/analysis"
+	chess "chess_go/internal/chess"
+	"chess_go/internal/clock"
+	engine "chess_go/internal/engine"
+)
+
+// Terminal is the terminal a full-screen game takes over.
+type Terminal interface {
+	Size() (width, height int) // in characters
+	Resized() <-chan struct{}  // receives a value after each change of size
+	Restore() error            // undoes OpenTerminal's raw mode
+}
+
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l" // the alternate screen, the cursor hidden
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+)
+
+// panelGap is the space between the board and the side panel, and
+// panelWidth the narrowest panel drawn beside the board rather than below it.
+const (
+	panelGap   = 3
+	panelWidth = 24
+)
+
+// key is a key the player pressed: a character, or one of the keys below.
+type key rune
+
+const (
+	keyUp key = -1 - iota
+	keyDown
+	keyLeft
+	keyRight
+	keyEscape
+	keyIgnored // a key sequence of no use to the game
+	keyEOF     // the input ended
+
+	keyInterrupt key = 3 // Ctrl-C
+)
+
+// readKey reads the next key from in. Arrow keys arrive as ANSI sequences; an
+// escape with nothing after it is the Escape key itself.
+func readKey(in *bufio.Reader) key {
+	r, _, err := in.ReadRune()
+	if err != nil {
+		return keyEOF
+	}
+	switch r {
+	case '\x1b':
+		if in.Buffered() == 0 {
+			return keyEscape
+		}
+		if b, _ := in.ReadByte(); b != '[' && b != 'O' {
+			_ = in.UnreadByte()
+			return keyEscape
+		}
+		for {
+			b, err := in.ReadByte()
+			if err != nil {
+				return keyEOF
+			}
+			if b < 0x40 || b > 0x7e {
+				continue // a parameter byte
+			}
+			switch b {
+			case 'A':
+				return keyUp
+			case 'B':
+				return keyDown
+			case 'C':
+				return keyRight
+			case 'D':
+				return keyLeft
+			}
+			return keyIgnored
+		}
+	case '\n':
+		return '\r'
+	}
+	return key(r)
+}
+
+// thought is the engine's answer, or the panic that ended its search.
+type thought struct {
+	res      engine.SearchResult
+	panicked any
+}
+
+// screen is the state of a full-screen game.
+type screen struct {
+	g        Game
+	start    chess.Game
+	game     chess.Game
+	clk      *clock.Clock
+	cursor   chess.Square
+	picked   chess.Square // the piece picked up; NoSquare for none
+	promos   []chess.Move // the promotions to choose from, while choosing
+	last     chess.Move
+	sans     []string         // the moves played since start
+	captured [2][]chess.Piece // the pieces taken by White and by Black
+	eval     engine.SearchResult
+	evalFrom chess.Game // the position eval is for
+	thinking bool
+	status   string // a message for the player, until the next key
+	over     bool
+	final    string // how the game ended, printed once the terminal is restored
+}
+
+// runScreen plays the game full-screen on its terminal: the player moves a
+// cursor with the arrow keys and picks up and puts down pieces with Enter or
+// Space, while panels beside the board show the moves, captured pieces,
+// clocks and the engine's evaluation. The terminal is restored however the
+// game ends, a panic included.
+func (g Game) runScreen() {
+	cursor := chess.SquareOf(4, 1)
+	if g.setup.Human == chess.Black {
+		cursor = chess.SquareOf(4, 6)
+	}
+	s := &screen{g: g, start: g.setup.Start, game: g.setup.Start, cursor: cursor, picked: chess.NoSquare}
+	_, _ = io.WriteString(g.w, enterScreen)
+	defer func() {
+		_, _ = io.WriteString(g.w, leaveScreen)
+		_ = g.term.Restore()
+		if s.final != "" {
+			_, _ = fmt.Fprintln(g.w, s.final)
+		}
+	}()
+	s.loop()
+}
+
+// loop runs the game until the player leaves it. Keys pressed while the
+// engine thinks are kept for the player's turn, except those that quit.
+func (s *screen) loop() {
+	keys := make(chan key, 16)
+	go func() {
+		for {
+			k := readKey(s.g.in)
+			keys <- k
+			if k == keyEOF {
+				return
+			}
+		}
+	}()
+	var tick <-chan time.Time
+	if s.g.setup.Clock != nil {
+		s.clk = clock.New(s.g.setup.Clock, nil)
+		s.clk.Start(s.game.State.ActiveColor)
+		t := time.NewTicker(100 * time.Millisecond)
+		defer t.Stop()
+		tick = t.C
+	}
+	info := make(chan engine.SearchResult, 1)
+	var thinking chan thought
+	var queued []key
+	for {
+		if r := s.game.Result(); !s.over && r != chess.InProgress {
+			s.end(outcome(r))
+		}
+		ours := s.over || s.g.human(s.game.State.ActiveColor)
+		if !ours && thinking == nil {
+			thinking = s.think(info)
+		}
+		s.draw()
+		if ours && len(queued) > 0 {
+			k := queued[0]
+			queued = queued[1:]
+			if !s.press(k) {
+				return
+			}
+			continue
+		}
+		var flag <-chan time.Time
+		if !s.over && s.clk != nil {
+			flag = time.After(max(s.clk.UntilFlag(), time.Millisecond))
+		}
+		select {
+		case k := <-keys:
+			switch {
+			case k == keyInterrupt, k == 'q' && s.promos == nil:
+				s.quit()
+				return
+			case !ours:
+				queued = append(queued, k)
+			case !s.press(k):
+				return
+			}
+		case t := <-thinking:
+			thinking, s.thinking = nil, false
+			if t.panicked != nil {
+				panic(t.panicked)
+			}
+			s.engineMoved(t.res)
+		case res := <-info:
+			if thinking != nil {
+				s.eval = res
+			}
+		case <-flag:
+			if side, flagged := s.clk.Flagged(); flagged {
+				s.end(timeout(clock.TimeoutIn(s.game, side)))
+			}
+		case <-tick:
+		case <-s.g.term.Resized():
+		}
+	}
+}
+
+// think starts the engine on the current position, reporting its progress
+// to info, and returns where its answer will arrive.
+func (s *screen) think(info chan engine.SearchResult) chan thought {
+	g, game, tc := s.g, s.game, s.g.setup.Think
+	if s.clk != nil {
+		tc = s.clk.TimeControl(game.State.ActiveColor)
+	}
+	s.thinking, s.eval, s.evalFrom = true, engine.SearchResult{}, game
+	report := func(res engine.SearchResult) {
+		select {
+		case <-info:
+		default:
+		}
+		info <- res
+	}
+	done := make(chan thought, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- thought{panicked: p}
+			}
+		}()
+		done <- thought{res: g.think(game, tc, report)}
+	}()
+	return done
+}
+
+// engineMoved plays the engine's answer, unless the game ended while it
+// thought.
+func (s *screen) engineMoved(res engine.SearchResult) {
+	if s.over {
+		return
+	}
+	if res.Depth > 0 || len(res.PV) > 0 {
+		s.eval = res
+	}
+	if !isLegal(s.game, res.BestMove) {
+		s.end(fmt.Sprintf("The engine failed to return a legal move (%s); game abandoned.", res.BestMove.UCIString()))
+		return
+	}
+	s.play(res.BestMove)
+}
+
+// press handles a key on the player's turn, or once the game is over, and
+// reports whether the player stays.
+func (s *screen) press(k key) bool {
+	if s.over || k == keyEOF {
+		s.quit()
+		return false
+	}
+	s.status = ""
+	switch k {
+	case keyUp, keyDown, keyLeft, keyRight:
+		s.moveCursor(k)
+	case '\r', ' ':
+		if s.promos != nil {
+			s.promote('q')
+		} else {
+			s.choose()
+		}
+	case keyEscape:
+		s.picked, s.promos = chess.NoSquare, nil
+	default:
+		if s.promos != nil {
+			s.promote(k)
+		}
+	}
+	return true
+}
+
+// moveCursor moves the cursor a square in the direction of k as the board is
+// drawn, stopping at the edge.
+func (s *screen) moveCursor(k key) {
+	df, dr := 0, 0
+	switch k {
+	case keyUp:
+		dr = 1
+	case keyDown:
+		dr = -1
+	case keyLeft:
+		df = -1
+	case keyRight:
+		df = 1
+	}
+	if s.options().bottom() == chess.Black {
+		df, dr = -df, -dr
+	}
+	f, r := s.cursor.File()+df, s.cursor.Rank()+dr
+	if f >= 0 && f < 8 && r >= 0 && r < 8 {
+		s.cursor = chess.SquareOf(f, r)
+	}
+}
+
+// choose picks up the piece under the cursor, or puts the piece picked up
+// down there: on a legal destination that plays the move, on its own square
+// it cancels, and on another of the player's pieces it picks that one up.
+func (s *screen) choose() {
+	if s.picked != chess.NoSquare {
+		var moves []chess.Move
+		for _, m := range s.game.LegalMoves() {
+			if m.From == s.picked && m.To == s.cursor {
+				moves = append(moves, m)
+			}
+		}
+		switch {
+		case len(moves) == 1:
+			s.play(moves[0])
+			return
+		case len(moves) > 1:
+			s.promos = moves
+			s.status = "Promote to: q, r, b or n"
+			return
+		case s.cursor == s.picked:
+			s.picked = chess.NoSquare
+			return
+		}
+	}
+	for _, m := range s.game.LegalMoves() {
+		if m.From == s.cursor {
+			s.picked = s.cursor
+			return
+		}
+	}
+	if s.picked != chess.NoSquare {
+		s.status = fmt.Sprintf("Illegal move: %s%s", squareName(s.picked), squareName(s.cursor))
+	} else {
+		s.status = fmt.Sprintf("No move from %s: pick up one of your pieces", squareName(s.cursor))
+	}
+}
+
+// promote plays the promotion to the piece whose letter is k.
+func (s *screen) promote(k key) {
+	for _, m := range s.promos {
+		if uci := m.UCIString(); key(uci[len(uci)-1]) == k {
+			s.play(m)
+			return
+		}
+	}
+	s.status = "Promote to: q, r, b or n"
+}
+
+// play plays m for the side to move, pressing the clock.
+func (s *screen) play(m chess.Move) {
+	side := s.game.State.ActiveColor
+	if s.clk != nil && !s.clk.Press() {
+		s.end(timeout(clock.TimeoutIn(s.game, side)))
+		return
+	}
+	san := m.SANString(s.game)
+	taken := capturedBy(s.game.State, m)
+	next, err := s.game.Apply(m)
+	if err != nil {
+		s.status = "Illegal move: " + m.UCIString()
+		return
+	}
+	if taken != chess.NoPiece {
+		s.captured[side] = append(s.captured[side], taken)
+	}
+	s.game, s.last, s.sans = next, m, append(s.sans, san)
+	s.picked, s.promos = chess.NoSquare, nil
+	if next.InCheck() && next.Result() == chess.InProgress {
+		s.status = "Check!"
+	}
+}
+
+// end ends the game with msg.
+func (s *screen) end(msg string) {
+	s.over, s.final, s.status = true, msg, ""
+	s.picked, s.promos = chess.NoSquare, nil
+	if s.clk != nil {
+		s.clk.Stop()
+	}
+}
+
+// quit leaves the game, abandoning it if it is not over.
+func (s *screen) quit() {
+	if !s.over {
+		s.final = "Game abandoned."
+	}
+}
+
+// options returns how the board is drawn: the game's style, with the last
+// move, the cursor and the piece picked up with its destinations.
+func (s *screen) options() RenderOptions {
+	o := s.g.renderOptions(s.game.State.ActiveColor, nil, s.last)
+	if s.over {
+		return o
+	}
+	o.Marks[s.cursor] |= MarkCursor
+	if s.picked != chess.NoSquare {
+		o.Marks[s.picked] |= MarkPicked
+		for _, m := range s.game.LegalMoves() {
+			if m.From == s.picked {
+				o.Marks[m.To] |= MarkTarget
+			}
+		}
+	}
+	return o
+}
+
+// draw redraws the screen: the board with the panel beside it, or below it
+// when the terminal is too narrow, and the status lines at the bottom.
+func (s *screen) draw() {
+	width, height := s.g.term.Size()
+	var buf bytes.Buffer
+	RenderWith(s.game, &buf, s.options())
+	board := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	board = board[:len(board)-1] // the side to move is in the status line
+	boardWidth := 0
+	for _, l := range board {
+		boardWidth = max(boardWidth, visibleWidth(l))
+	}
+	status := s.statusLines()
+	var frame []string
+	switch {
+	case width < boardWidth || height < len(board)+len(status):
+		frame = []string{fmt.Sprintf("The terminal is too small: the board needs %dx%d.", boardWidth, len(board)+len(status))}
+	case width >= boardWidth+panelGap+panelWidth:
+		panel := s.panel(width-boardWidth-panelGap, height-len(status))
+		for i := range max(len(board), len(panel)) {
+			line := ""
+			if i < len(board) {
+				line = board[i]
+			}
+			if i < len(panel) {
+				line += strings.Repeat(" ", boardWidth-visibleWidth(line)+panelGap) + panel[i]
+			}
+			frame = append(frame, line)
+		}
+		frame = append(frame, status...)
+	default:
+		frame = append(board, s.panel(width, height-len(board)-len(status))...)
+		frame = append(frame, status...)
+	}
+	var sb strings.Builder
+	sb.WriteString("\x1b[H")
+	for i, line := range frame {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(clip(line, width) + "\x1b[K")
+	}
+	sb.WriteString("\x1b[J")
+	_, _ = io.WriteString(s.g.w, sb.String())
+}
+
+// statusLines returns the lines under the board: whose move it is with any
+// message, or how the game ended, and the keys.
+func (s *screen) statusLines() []string {
+	if s.over {
+		return []string{s.final, "Press any key to exit."}
+	}
+	line := "White to move"
+	if s.game.State.ActiveColor == chess.Black {
+		line = "Black to move"
+	}
+	if s.thinking {
+		line += " · Engine thinking..."
+	}
+	if s.status != "" {
+		line += " · " + s.status
+	}
+	return []string{line, "Arrows move · Enter or Space picks up and puts down · Esc cancels · q quits"}
+}
+
+// panel returns at most height lines of at most width characters: the
+// clocks, the captured pieces, the moves, the latest of them when they do
+// not all fit, and the engine's evaluation and principal variation.
+func (s *screen) panel(width, height int) []string {
+	if height <= 0 {
+		return nil
+	}
+	var p []string
+	if s.clk != nil {
+		bottom := s.options().bottom()
+		p = append(p, strings.TrimSpace(clockText(s.clk, 1-bottom)), strings.TrimSpace(clockText(s.clk, bottom)), "")
+	}
+	p = append(p, "Captured", s.capturedLine(chess.White), s.capturedLine(chess.Black), "")
+	eval := s.evalLines(width)
+	rows := s.moveRows()
+	room := max(height-len(p)-1-len(eval)-1, 0)
+	if len(rows) > room {
+		rows = rows[len(rows)-room:]
+	}
+	p = append(p, "Moves")
+	p = append(p, rows...)
+	if len(eval) > 0 {
+		p = append(p, "")
+		p = append(p, eval...)
+	}
+	for i := range p {
+		p[i] = clip(p[i], width)
+	}
+	return p[:min(len(p), height)]
+}
+
+// capturedLine lists the pieces side has taken, most valuable first, with
+// its lead in material if it has one.
+func (s *screen) capturedLine(side chess.Color) string {
+	name := "White"
+	if side == chess.Black {
+		name = "Black"
+	}
+	taken := append([]chess.Piece(nil), s.captured[side]...)
+	sort.SliceStable(taken, func(i, j int) bool { return pieceValue(taken[i]) > pieceValue(taken[j]) })
+	line := name + ":"
+	lead := 0
+	for _, p := range taken {
+		if s.g.style.Unicode {
+			line += " " + pieceGlyph(p, false)
+		} else {
+			line += " " + string(pieceASCII(p))
+		}
+		lead += pieceValue(p)
+	}
+	for _, p := range s.captured[1-side] {
+		lead -= pieceValue(p)
+	}
+	if lead > 0 {
+		line += fmt.Sprintf("  +%d", lead)
+	}
+	return line
+}
+
+// moveRows returns the moves played, a numbered row for each move pair.
+func (s *screen) moveRows() []string {
+	n, i := int(s.start.State.FullMoveNumber), 0
+	var rows []string
+	if s.start.State.ActiveColor == chess.Black && len(s.sans) > 0 {
+		rows = append(rows, fmt.Sprintf("%3d. %-7s %s", n, "...", s.sans[0]))
+		i, n = 1, n+1
+	}
+	for ; i < len(s.sans); i, n = i+2, n+1 {
+		black := ""
+		if i+1 < len(s.sans) {
+			black = s.sans[i+1]
+		}
+		rows = append(rows, strings.TrimRight(fmt.Sprintf("%3d. %-7s %s", n, s.sans[i], black), " "))
+	}
+	return rows
+}
+
+// evalLines returns the engine's latest evaluation, from White's view, and
+// its principal variation wrapped to width; none before it reports one.
+func (s *screen) evalLines(width int) []string {
+	if s.eval.Depth == 0 && len(s.eval.PV) == 0 {
+		return nil
+	}
+	lines := []string{fmt.Sprintf("Engine %s  depth %d", evalText(whiteScore(s.evalFrom, s.eval.Score)), s.eval.Depth)}
+	return append(lines, wrap(sanLine(s.evalFrom, s.eval.PV), width)...)
+}
+
+// whiteScore returns score, from the view of the side to move in g, from
+// White's view.
+func whiteScore(g chess.Game, score int) int {
+	if g.State.ActiveColor == chess.Black {
+		return -score
+	}
+	return score
+}
+
+// evalText formats score, from White's view, in pawns with its sign, as in
+// "+1.20", or as a mate, as in "#3".
+func evalText(score int) string {
+	ev := analysis.FormatEval(score)
+	if score > 0 && !strings.HasPrefix(ev, "#") {
+		ev = "+" + ev
+	}
+	return ev
+}
+
+// sanLine returns moves played from g in SAN with move numbers, as in
+// "12...Nc6 13.Bb5 a6", stopping at a move that is not legal.
+func sanLine(g chess.Game, moves []chess.Move) []string {
+	var words []string
+	for i, m := range moves {
+		if !isLegal(g, m) {
+			break
+		}
+		san, n := m.SANString(g), g.State.FullMoveNumber
+		switch {
+		case g.State.ActiveColor == chess.White:
+			san = fmt.Sprintf("%d.%s", n, san)
+		case i == 0:
+			san = fmt.Sprintf("%d...%s", n, san)
+		}
+		words = append(words, san)
+		g, _ = g.Apply(m)
+	}
+	return words
+}
+
+// wrap joins words into lines of at most width characters.
+func wrap(words []string, width int) []string {
+	var lines []string
+	line := ""
+	for _, w := range words {
+		switch {
+		case line == "":
+			line = w
+		case visibleWidth(line)+1+visibleWidth(w) <= width:
+			line += " " + w
+		default:
+			lines = append(lines, line)
+			line = w
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// capturedBy returns the piece m takes in s, en passant included, or NoPiece.
+func capturedBy(s chess.GameState, m chess.Move) chess.Piece {
+	if p := s.Board[m.To]; p != chess.NoPiece {
+		return p
+	}
+	if p := s.Board[m.From]; m.To == s.EnPassantSq && (p == chess.WhitePawn || p == chess.BlackPawn) {
+		return s.Board[chess.SquareOf(m.To.File(), m.From.Rank())]
+	}
+	return chess.NoPiece
+}
+
+// pieceValue is p's material value in pawns.
+func pieceValue(p chess.Piece) int {
+	return [...]int{1, 3, 3, 5, 9, 0}[(p-chess.WhitePawn)%6]
+}
+
+// squareName returns sq's name, such as "e4".
+func squareName(sq chess.Square) string {
+	return fmt.Sprintf("%c%d", 'a'+sq.File(), sq.Rank()+1)
+}
+
+// visibleWidth returns the columns s takes on a terminal, leaving out ANSI
+// escape sequences.
+func visibleWidth(s string) int {
+	n, escape := 0, false
+	for _, r := range s {
+		switch {
+		case escape:
+			escape = r < 0x40 || r > 0x7e || r == '['
+		case r == '\x1b':
+			escape = true
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// clip cuts s to width visible columns, keeping escape sequences whole.
+func clip(s string, width int) string {
+	if visibleWidth(s) <= width {
+		return s
+	}
+	var sb strings.Builder
+	n, escape := 0, false
+	for _, r := range s {
+		switch {
+		case escape:
+			escape = r < 0x40 || r > 0x7e || r == '['
+		case r == '\x1b':
+			escape = true
+		default:
+			if n == width {
+				continue
+			}
+			n++
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
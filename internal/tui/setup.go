@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/clock"
+	engine "chess_go/internal/engine"
+)
+
+// ErrSetup is returned for a side, start position, engine limit or clock
+// that cannot be understood.
+var ErrSetup = errors.New("invalid game setup")
+
+// Setup is how a game is played: the player's side, the position it starts
+// from and how long the engine thinks, or two players taking turns.
+type Setup struct {
+	Human    chess.Color        // the player's side; the engine has the other
+	Start    chess.Game         // position to play from, with the moves that led to it
+	Think    engine.TimeControl // the engine's limit per move without a clock: MoveTime or Depth
+	Clock    clock.Control      // time control of both sides; nil plays without a clock
+	HotSeat  bool               // two players share the terminal, one for each side; Human is drawn at the bottom
+	AutoFlip bool               // with HotSeat, the board turns to put the side to move at the bottom
+}
+
+// DefaultSetup is the player with White from the starting position against
+// an engine thinking 100ms a move, without a clock.
+func DefaultSetup() Setup {
+	start, _ := chess.NewGameFromFEN(chess.StartFEN)
+	return Setup{Human: chess.White, Start: start, Think: engine.TimeControl{MoveTime: defaultThinkTime}}
+}
+
+// ParseSide reads the player's side: "white", "black" or "random", or their
+// first letters. Random picks a side by coin toss.
+func ParseSide(s string) (chess.Color, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "w", "white":
+		return chess.White, nil
+	case "b", "black":
+		return chess.Black, nil
+	case "r", "random":
+		if rand.Intn(2) == 0 {
+			return chess.White, nil
+		}
+		return chess.Black, nil
+	}
+	return chess.White, fmt.Errorf("%w: side %q, want white, black or random", ErrSetup, s)
+}
+
+// ParseThink reads the engine's limit per move: a duration such as "2s" or
+// "500ms", or a depth such as "d6".
+func ParseThink(s string) (engine.TimeControl, error) {
+	s = strings.TrimSpace(s)
+	if d, ok := strings.CutPrefix(s, "d"); ok {
+		if n, err := strconv.Atoi(d); err == nil && n > 0 {
+			return engine.TimeControl{Depth: n}, nil
+		}
+	}
+	if t, err := time.ParseDuration(s); err == nil && t > 0 {
+		return engine.TimeControl{MoveTime: t}, nil
+	}
+	return engine.TimeControl{}, fmt.Errorf("%w: engine limit %q, want a time such as 2s or a depth such as d6", ErrSetup, s)
+}
+
+// LoadStart reads a start position: a FEN, or the name of a PGN file whose
+// first game is continued from its last position.
+func LoadStart(s string) (chess.Game, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(strings.ToLower(s), ".pgn") {
+		return LoadPGN(s)
+	}
+	g, err := chess.NewGameFromFEN(s)
+	if err != nil {
+		return chess.Game{}, fmt.Errorf("%w: %v", ErrSetup, err)
+	}
+	return g, nil
+}
+
+// LoadPGN returns the position at the end of the first game in the PGN file
+// at path, with the moves that led to it.
+func LoadPGN(path string) (chess.Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return chess.Game{}, err
+	}
+	defer f.Close()
+	sc := chess.NewPGNScanner(f)
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return chess.Game{}, fmt.Errorf("%s: %w", path, err)
+		}
+		return chess.Game{}, fmt.Errorf("%w: %s holds no game", ErrSetup, path)
+	}
+	return sc.Game().Final(), nil
+}
+
+// Menu asks the player how to set up the game, offering the current setup as
+// the default of each question, and returns the game with the answers. It
+// asks again after an answer it cannot use; at the end of input it returns
+// the error io.EOF.
+func (g Game) Menu() (Game, error) {
+	_, _ = fmt.Fprintln(g.w, "New game")
+	s := g.setup
+	ask := func(question, def string, use func(string) error) error {
+		for {
+			_, _ = fmt.Fprintf(g.w, "  %s [%s]: ", question, def)
+			line, err := g.readLine()
+			if err != nil {
+				return err
+			}
+			if line == "" {
+				return nil
+			}
+			if err := use(line); err != nil {
+				_, _ = fmt.Fprintf(g.w, "  %v\n", err)
+				continue
+			}
+			return nil
+		}
+	}
+
+	side := "white"
+	if s.Human == chess.Black {
+		side = "black"
+	}
+	think := s.Think.MoveTime.String()
+	if s.Think.Depth > 0 {
+		think = "d" + strconv.Itoa(s.Think.Depth)
+	}
+	start := "standard"
+	if fen := s.Start.ToFEN(); fen != chess.StartFEN {
+		start = fen
+	}
+	control := "none"
+	if s.Clock != nil {
+		control = s.Clock.String()
+	}
+	if err := ask("Play white, black or random?", side, func(a string) (err error) {
+		s.Human, err = ParseSide(a)
+		return err
+	}); err != nil {
+		return g, err
+	}
+	if err := ask("Start from a FEN or PGN file, or the standard position?", start, func(a string) (err error) {
+		s.Start, err = LoadStart(a)
+		return err
+	}); err != nil {
+		return g, err
+	}
+	if err := ask("Engine time per move (e.g. 2s) or depth (e.g. d6)?", think, func(a string) (err error) {
+		s.Think, err = ParseThink(a)
+		return err
+	}); err != nil {
+		return g, err
+	}
+	if err := ask("Clock (e.g. 5+3, 15d5 or 40/90+30,30+30), or none?", control, func(a string) (err error) {
+		if strings.EqualFold(a, "none") {
+			s.Clock = nil
+			return nil
+		}
+		s.Clock, err = clock.Parse(a)
+		return err
+	}); err != nil {
+		return g, err
+	}
+	g.setup = s
+	return g, nil
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd)
+
+package tui
+
+import (
+	"errors"
+	"os"
+)
+
+// OpenTerminal puts the terminal in into raw mode for a full-screen game
+// drawn on out. Raw mode is implemented for Linux, macOS and the BSDs other
+// than OpenBSD, which only allows system calls through its libc; elsewhere it
+// fails and the line-based game is the one to play.
+func OpenTerminal(in, out *os.File) (Terminal, error) {
+	return nil, errors.New("full-screen mode is not supported on this platform")
+}
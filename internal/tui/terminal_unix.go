@@ -0,0 +1,107 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd
+
+package tui
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// rawTerminal is a terminal put into raw mode by OpenTerminal.
+type rawTerminal struct {
+	in, out *os.File
+	saved   syscall.Termios
+	resized chan struct{}
+	signals chan os.Signal
+	once    sync.Once
+}
+
+// OpenTerminal puts the terminal in into raw mode for a full-screen game
+// drawn on out: keys arrive one at a time, unechoed, with Ctrl-C as a key
+// rather than a signal. Output processing stays on, so "\n" still starts a
+// new line. The terminal is restored by Restore, or on SIGTERM or SIGHUP
+// before the process exits.
+func OpenTerminal(in, out *os.File) (Terminal, error) {
+	t := &rawTerminal{in: in, out: out, resized: make(chan struct{}, 1), signals: make(chan os.Signal, 1)}
+	if err := ioctl(in.Fd(), getTermios, unsafe.Pointer(&t.saved)); err != nil {
+		return nil, errors.New("full-screen mode needs a terminal")
+	}
+	if _, _, err := t.winsize(); err != nil {
+		return nil, errors.New("full-screen mode needs a terminal")
+	}
+	raw := t.saved
+	raw.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP |
+		syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	raw.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	raw.Cflag &^= syscall.CSIZE | syscall.PARENB
+	raw.Cflag |= syscall.CS8
+	raw.Cc[syscall.VMIN], raw.Cc[syscall.VTIME] = 1, 0
+	if err := ioctl(in.Fd(), setTermios, unsafe.Pointer(&raw)); err != nil {
+		return nil, err
+	}
+	signal.Notify(t.signals, syscall.SIGWINCH, syscall.SIGTERM, syscall.SIGHUP)
+	go t.watch()
+	return t, nil
+}
+
+// watch passes on resizes, and restores the terminal and exits when the
+// process is told to stop.
+func (t *rawTerminal) watch() {
+	for sig := range t.signals {
+		if sig != syscall.SIGWINCH {
+			_ = t.Restore()
+			_, _ = io.WriteString(t.out, leaveScreen)
+			os.Exit(128 + int(sig.(syscall.Signal)))
+		}
+		select {
+		case t.resized <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *rawTerminal) Size() (width, height int) {
+	w, h, err := t.winsize()
+	if err != nil {
+		return 80, 24
+	}
+	return w, h
+}
+
+func (t *rawTerminal) Resized() <-chan struct{} { return t.resized }
+
+// Restore puts the terminal back into the mode it was in before
+// OpenTerminal. Calls after the first do nothing.
+func (t *rawTerminal) Restore() error {
+	var err error
+	t.once.Do(func() {
+		signal.Stop(t.signals)
+		close(t.signals)
+		err = ioctl(t.in.Fd(), setTermios, unsafe.Pointer(&t.saved))
+	})
+	return err
+}
+
+// winsize returns the terminal's width and height in characters.
+func (t *rawTerminal) winsize() (int, int, error) {
+	var ws struct{ rows, cols, x, y uint16 }
+	if err := ioctl(t.out.Fd(), syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return 0, 0, err
+	}
+	if ws.cols == 0 || ws.rows == 0 {
+		return 0, 0, errors.New("terminal size unknown")
+	}
+	return int(ws.cols), int(ws.rows), nil
+}
+
+func ioctl(fd uintptr, req uint, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, uintptr(req), uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
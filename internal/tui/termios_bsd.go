@@ -0,0 +1,11 @@
+//go:build darwin || dragonfly || freebsd || netbsd
+
+package tui
+
+import "syscall"
+
+// The ioctl requests that get and set a terminal's termios.
+const (
+	getTermios = syscall.TIOCGETA
+	setTermios = syscall.TIOCSETA
+)
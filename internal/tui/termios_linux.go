@@ -0,0 +1,9 @@
+package tui
+
+import "syscall"
+
+// The ioctl requests that get and set a terminal's termios.
+const (
+	getTermios = syscall.TCGETS
+	setTermios = syscall.TCSETS
+)
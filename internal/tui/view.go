@@ -0,0 +1,374 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// viewHelp lists the viewer's commands.
+const viewHelp = `Commands:
+  n or Enter     next move
+  p              previous move
+  start, end     the start or end of the line
+  N              jump to ply N of the line (0 is its start)
+  v N            play variation N of the next move
+  up             leave the variation for the line it branches from
+  tags           show the game's PGN tags
+  list [TEXT]    choose another game, searching players, event and result
+  flip           turn the board around
+  pieces         list every piece on the board
+  play           play on from here against the engine (resign to come back)
+  help           show this list
+  quit           leave the viewer`
+
+// variationWords is how many moves of a variation are listed before "...".
+const variationWords = 6
+
+// nags names the common numeric annotation glyphs: their symbol and meaning.
+var nags = map[int][2]string{
+	1:  {"!", "good move"},
+	2:  {"?", "mistake"},
+	3:  {"!!", "brilliant move"},
+	4:  {"??", "blunder"},
+	5:  {"!?", "interesting move"},
+	6:  {"?!", "dubious move"},
+	7:  {"[]", "only move"},
+	10: {"=", "equal position"},
+	13: {"~", "unclear position"},
+	14: {"+=", "White is slightly better"},
+	15: {"=+", "Black is slightly better"},
+	16: {"+/-", "White is better"},
+	17: {"-/+", "Black is better"},
+	18: {"+-", "White is winning"},
+	19: {"-+", "Black is winning"},
+}
+
+// Viewer replays the games of a PGN file move by move, with their tags,
+// comments, NAGs and variations, and optionally plays on against the engine.
+type Viewer struct {
+	in       *bufio.Reader
+	w        io.Writer
+	games    []chess.PGNGame
+	style    Style
+	engineFn EngineFunc // plays on from a position; nil disables play
+	think    engine.TimeControl
+}
+
+// NewViewer returns a viewer of games, reading commands from r and drawing on w.
+func NewViewer(r io.Reader, w io.Writer, games []chess.PGNGame) Viewer {
+	return Viewer{in: bufio.NewReader(r), w: w, games: games}
+}
+
+// WithStyle returns the viewer drawing its board in style s.
+func (v Viewer) WithStyle(s Style) Viewer {
+	v.style = s
+	return v
+}
+
+// WithEngine returns the viewer letting the player play on from any position
+// against engineFn, searching for think per move.
+func (v Viewer) WithEngine(engineFn EngineFunc, think engine.TimeControl) Viewer {
+	v.engineFn, v.think = engineFn, think
+	return v
+}
+
+// line is a line of moves being replayed, the mainline or a variation, with
+// the moves' annotations.
+type line struct {
+	start chess.Game
+	moves []chess.Move
+	notes []chess.PGNAnnotation
+	ply   int // moves played from start
+}
+
+// position returns the position after the moves played.
+func (l line) position() chess.Game {
+	g := l.start
+	for _, m := range l.moves[:l.ply] {
+		g, _ = g.Apply(m)
+	}
+	return g
+}
+
+// note returns the annotation of move i, empty when it has none.
+func (l line) note(i int) chess.PGNAnnotation {
+	if i < 0 || i >= len(l.notes) {
+		return chess.PGNAnnotation{}
+	}
+	return l.notes[i]
+}
+
+// Run asks which game to view when there are several, then shows it from its
+// start and carries out the player's commands (see viewHelp) until the player
+// quits or the input ends.
+func (v Viewer) Run() {
+	if len(v.games) == 0 {
+		_, _ = fmt.Fprintln(v.w, "No games to view.")
+		return
+	}
+	current := 0
+	if len(v.games) > 1 {
+		var ok bool
+		if current, ok = v.choose(current, ""); !ok {
+			return
+		}
+	}
+	path := v.open(current)
+	for {
+		l := &path[len(path)-1]
+		_, _ = fmt.Fprint(v.w, "View (n, p, help): ")
+		text, err := readLine(v.in)
+		if err != nil {
+			_, _ = fmt.Fprintln(v.w)
+			return
+		}
+		name, arg, _ := strings.Cut(text, " ")
+		arg = strings.TrimSpace(arg)
+		switch strings.ToLower(name) {
+		case "", "n", "next":
+			if l.ply == len(l.moves) {
+				_, _ = fmt.Fprintln(v.w, "End of the line.")
+				continue
+			}
+			l.ply++
+		case "p", "prev", "back":
+			switch {
+			case l.ply > 1 || l.ply == 1 && len(path) == 1:
+				l.ply--
+			case len(path) > 1:
+				path = path[:len(path)-1]
+			default:
+				_, _ = fmt.Fprintln(v.w, "Start of the game.")
+				continue
+			}
+		case "start":
+			path = path[:1]
+			path[0].ply = 0
+		case "end":
+			l.ply = len(l.moves)
+		case "v", "var":
+			n, err := strconv.Atoi(arg)
+			a := l.note(l.ply)
+			if err != nil || n < 1 || n > len(a.Variations) {
+				_, _ = fmt.Fprintf(v.w, "No variation %q here; the next move has %d.\n", arg, len(a.Variations))
+				continue
+			}
+			var notes []chess.PGNAnnotation
+			if n <= len(a.VariationAnnotations) {
+				notes = a.VariationAnnotations[n-1]
+			}
+			path = append(path, line{start: l.position(), moves: a.Variations[n-1], notes: notes, ply: 1})
+		case "up":
+			if len(path) == 1 {
+				_, _ = fmt.Fprintln(v.w, "Not in a variation.")
+				continue
+			}
+			path = path[:len(path)-1]
+		case "tags":
+			v.tags(current)
+			continue
+		case "list", "games":
+			next, ok := v.choose(current, arg)
+			if !ok {
+				return
+			}
+			if next != current {
+				current, path = next, v.open(next)
+				continue
+			}
+		case "flip":
+			v.style.Flipped = !v.style.Flipped
+		case "pieces":
+			_, _ = fmt.Fprint(v.w, describePieces(l.position()))
+			continue
+		case "play":
+			if v.engineFn == nil {
+				_, _ = fmt.Fprintln(v.w, "No engine to play against.")
+				continue
+			}
+			s := DefaultSetup()
+			s.Start = l.position()
+			s.Human, s.Think = s.Start.State.ActiveColor, v.think
+			NewGameWithSetup(v.in, v.w, v.engineFn, s).WithStyle(v.style).Run()
+			_, _ = fmt.Fprintln(v.w, "Back to the game.")
+		case "help":
+			_, _ = fmt.Fprintln(v.w, viewHelp)
+			continue
+		case "quit", "exit", "q":
+			return
+		default:
+			n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(text), "ply "))
+			if err != nil {
+				_, _ = fmt.Fprintf(v.w, "Unknown command %q (type help)\n", text)
+				continue
+			}
+			if n < 0 || n > len(l.moves) {
+				_, _ = fmt.Fprintf(v.w, "No ply %d: the line has plies 0 to %d.\n", n, len(l.moves))
+				continue
+			}
+			l.ply = n
+		}
+		v.show(path, current)
+	}
+}
+
+// open shows game i from its start, with its players, event and result, and
+// returns the path to its start: the mainline alone.
+func (v Viewer) open(i int) []line {
+	pg := v.games[i]
+	_, _ = fmt.Fprintf(v.w, "Game %d of %d: %s\n", i+1, len(v.games), summary(pg))
+	if pg.Comment != "" {
+		_, _ = fmt.Fprintf(v.w, "{%s}\n", pg.Comment)
+	}
+	path := []line{{start: pg.Start, moves: pg.Moves, notes: pg.Annotations}}
+	v.show(path, i)
+	return path
+}
+
+// show draws the position at the end of path, the line being replayed
+// innermost, in game i, then the move that led there with its annotations and
+// the variations of the next move.
+func (v Viewer) show(path []line, i int) {
+	l := path[len(path)-1]
+	g := l.position()
+	var last chess.Move
+	if l.ply > 0 {
+		last = l.moves[l.ply-1]
+	}
+	RenderWith(g, v.w, RenderOptions{Style: v.style, LastMove: last})
+
+	where := fmt.Sprintf("ply %d of %d", l.ply, len(l.moves))
+	if len(path) > 1 {
+		where = fmt.Sprintf("variation, %s", where)
+	}
+	if l.ply == 0 {
+		_, _ = fmt.Fprintf(v.w, "Start (%s)\n", where)
+	} else {
+		a := l.note(l.ply - 1)
+		before := path[len(path)-1]
+		before.ply--
+		text := sanLine(before.position(), []chess.Move{last})[0] + a.Suffix
+		if v.style.Accessible {
+			text = describeMove(before.position(), last) + " (" + text + ")"
+		}
+		var meanings []string
+		for _, n := range a.NAGs {
+			nag, ok := nags[n]
+			if !ok {
+				nag = [2]string{"$" + strconv.Itoa(n), ""}
+			}
+			if n > 6 {
+				text += " " // the glyphs of moves' quality are written as suffixes
+			}
+			text += nag[0]
+			if nag[1] != "" {
+				meanings = append(meanings, nag[1])
+			}
+		}
+		if len(meanings) > 0 {
+			text += " (" + strings.Join(meanings, ", ") + ")"
+		}
+		_, _ = fmt.Fprintf(v.w, "%s  [%s]\n", text, where)
+		if a.Comment != "" {
+			_, _ = fmt.Fprintf(v.w, "{%s}\n", a.Comment)
+		}
+	}
+	for n, alt := range l.note(l.ply).Variations {
+		words := sanLine(g, alt)
+		if len(words) > variationWords {
+			words = append(words[:variationWords], "...")
+		}
+		_, _ = fmt.Fprintf(v.w, "Variation %d: %s\n", n+1, strings.Join(words, " "))
+	}
+	if len(path) == 1 && l.ply == len(l.moves) {
+		_, _ = fmt.Fprintf(v.w, "End of the game: %s\n", v.games[i].Result)
+	}
+}
+
+// tags writes the PGN tags of game i, the players and event first.
+func (v Viewer) tags(i int) {
+	tags := v.games[i].Tags
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	order := map[string]int{"Event": 1, "Site": 2, "Date": 3, "Round": 4, "White": 5, "Black": 6, "Result": 7}
+	sort.Slice(names, func(a, b int) bool {
+		oa, ob := order[names[a]], order[names[b]]
+		switch {
+		case oa != 0 && ob != 0:
+			return oa < ob
+		case oa != 0 || ob != 0:
+			return oa != 0
+		}
+		return names[a] < names[b]
+	})
+	for _, name := range names {
+		_, _ = fmt.Fprintf(v.w, "[%s %q]\n", name, tags[name])
+	}
+}
+
+// choose lists the games matching query and asks for one, narrowing the list
+// by any text typed instead of a number. It returns the game chosen, or
+// current for an empty answer, and reports false at the end of input.
+func (v Viewer) choose(current int, query string) (int, bool) {
+	for {
+		matched := 0
+		for i, pg := range v.games {
+			if matches(pg, query) {
+				_, _ = fmt.Fprintf(v.w, "%3d. %s\n", i+1, summary(pg))
+				matched++
+			}
+		}
+		if matched == 0 {
+			_, _ = fmt.Fprintf(v.w, "No game matches %q.\n", query)
+		}
+		_, _ = fmt.Fprintf(v.w, "Game number, text to search, or Enter for game %d: ", current+1)
+		text, err := readLine(v.in)
+		switch n, nerr := strconv.Atoi(text); {
+		case err != nil:
+			_, _ = fmt.Fprintln(v.w)
+			return current, false
+		case text == "":
+			return current, true
+		case nerr == nil && n >= 1 && n <= len(v.games):
+			return n - 1, true
+		case nerr == nil:
+			_, _ = fmt.Fprintf(v.w, "No game %d: there are %d.\n", n, len(v.games))
+		default:
+			query = text
+		}
+	}
+}
+
+// matches reports whether every word of query appears, ignoring case, in the
+// players, event or result of pg.
+func matches(pg chess.PGNGame, query string) bool {
+	text := strings.ToLower(strings.Join([]string{pg.Tags["White"], pg.Tags["Black"], pg.Tags["Event"], pg.Result}, " "))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// summary describes pg in one line, as in
+// "Carlsen - Caruana, World Championship, 2018.11.09  1/2-1/2 (34 moves)".
+func summary(pg chess.PGNGame) string {
+	tag := func(name string) string {
+		if v := pg.Tags[name]; v != "" {
+			return v
+		}
+		return "?"
+	}
+	return fmt.Sprintf("%s - %s, %s, %s  %s (%d moves)",
+		tag("White"), tag("Black"), tag("Event"), tag("Date"), pg.Result, (len(pg.Moves)+1)/2)
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// evalBarWidth is the number of cells in the eval bar.
+const evalBarWidth = 30
+
+// Spectator draws engine-versus-engine games move by move for someone
+// watching: the board after each move with an eval bar and the principal
+// variation of the search that chose it.
+type Spectator struct {
+	w        io.Writer
+	style    Style
+	delay    time.Duration
+	terminal bool
+}
+
+// NewSpectator returns a spectator drawing on w and pausing for delay after
+// each move and each result.
+func NewSpectator(w io.Writer, delay time.Duration) Spectator {
+	return Spectator{w: w, delay: delay}
+}
+
+// WithStyle returns the spectator drawing its boards in style s.
+func (s Spectator) WithStyle(st Style) Spectator {
+	s.style = st
+	return s
+}
+
+// WithTerminal returns the spectator writing to a terminal, or not. On a
+// terminal each move is drawn over the last, from the top of the screen.
+func (s Spectator) WithTerminal(terminal bool) Spectator {
+	s.terminal = terminal
+	return s
+}
+
+// Show draws the position after m, played in before by a search with result
+// res, under title, then pauses. The evaluation is shown from White's view;
+// a search that reports none shows no eval bar.
+func (s Spectator) Show(title string, before chess.Game, m chess.Move, res engine.SearchResult) {
+	after, err := before.Apply(m)
+	if err != nil {
+		return
+	}
+	var sb strings.Builder
+	if s.terminal {
+		sb.WriteString("\x1b[H\x1b[2J")
+	}
+	sb.WriteString(title + "\n")
+	RenderWith(after, &sb, RenderOptions{Style: s.style, LastMove: m})
+	fmt.Fprintf(&sb, "Last move: %s\n", strings.Join(sanLine(before, []chess.Move{m}), " "))
+	if res.Depth > 0 || len(res.PV) > 0 {
+		score := whiteScore(before, res.Score)
+		fmt.Fprintf(&sb, "Eval %s %s  depth %d\n", s.evalBar(score), evalText(score), res.Depth)
+		if pv := sanLine(before, res.PV); len(pv) > 0 {
+			fmt.Fprintf(&sb, "PV: %s\n", strings.Join(pv, " "))
+		}
+	}
+	_, _ = io.WriteString(s.w, sb.String())
+	time.Sleep(s.delay)
+}
+
+// Announce writes line, such as a game's result, then pauses.
+func (s Spectator) Announce(line string) {
+	_, _ = fmt.Fprintln(s.w, line)
+	time.Sleep(s.delay)
+}
+
+// evalBar returns a bar whose filled share is White's expected score for
+// score, in centipawns from White's view: half-filled for an even position,
+// nearly full or empty a few pawns either way.
+func (s Spectator) evalBar(score int) string {
+	expected := 1 / (1 + math.Pow(10, -float64(score)/400))
+	filled := int(math.Round(expected * evalBarWidth))
+	white, black := "#", "-"
+	if s.style.Unicode {
+		white, black = "█", "░"
+	}
+	return "[" + strings.Repeat(white, filled) + strings.Repeat(black, evalBarWidth-filled) + "]"
+}
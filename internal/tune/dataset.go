@@ -0,0 +1,157 @@
+package tune
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	chess "chess_go/internal/chess"
+)
+
+// ErrInvalidData is returned for training data that cannot be read.
+var ErrInvalidData = errors.New("invalid tuning data")
+
+// Dataset holds positions labelled with game results. Each piece of a
+// position is stored as a single uint16 feature, so that millions of
+// positions fit in memory.
+type Dataset struct {
+	features []uint16
+	samples  []sample
+}
+
+// sample is one position: a run of features, the game phase and the result.
+type sample struct {
+	off    uint32
+	n      uint8
+	phase  uint8 // 0 (bare kings) ... maxPhase (all pieces), as in engine.Evaluate
+	result uint8 // half points scored by White: 0, 1 or 2
+}
+
+// A feature is piece type*64 + table index (a8 first from the piece owner's
+// side, as in engine.Weights), with blackFeature set for Black's pieces.
+const blackFeature = 0x8000
+
+// Game phase weights per piece type, as in engine.Evaluate.
+var phaseWeights = [6]int{0, 1, 1, 2, 4, 0}
+
+const maxPhase = 24
+
+// Len returns the number of positions.
+func (d *Dataset) Len() int { return len(d.samples) }
+
+// Add adds a position with the result of its game, from White's point of
+// view: 1 for a White win, 0.5 for a draw and 0 for a loss.
+func (d *Dataset) Add(s chess.GameState, result float64) {
+	smp := sample{off: uint32(len(d.features)), result: uint8(result*2 + 0.5)}
+	phase := 0
+	for sq := 0; sq < 64; sq++ {
+		p := s.Board[sq]
+		if p == chess.NoPiece {
+			continue
+		}
+		f := uint16(sq ^ 56)
+		pt := int(p - chess.WhitePawn)
+		if p >= chess.BlackPawn {
+			f, pt = uint16(sq)|blackFeature, int(p-chess.BlackPawn)
+		}
+		d.features = append(d.features, uint16(pt*64)+f)
+		phase += phaseWeights[pt]
+		smp.n++
+	}
+	smp.phase = uint8(min(phase, maxPhase))
+	d.samples = append(d.samples, smp)
+}
+
+// ReadEPD adds every labelled record of an EPD stream and returns how many
+// were added. The result is read from a "c9" operation, as in the
+// "quiet-labeled" data sets, or from a "result" operation; records without
+// one are skipped.
+func (d *Dataset) ReadEPD(r io.Reader) (int, error) {
+	added := 0
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		e, err := chess.ParseEPD(line)
+		if err != nil {
+			return added, fmt.Errorf("%w: line %d: %v", ErrInvalidData, n, err)
+		}
+		for _, opcode := range []string{"c9", "result"} {
+			operands, ok := e.Op(opcode)
+			if !ok || len(operands) == 0 {
+				continue
+			}
+			result, ok := parseResult(operands[0])
+			if !ok {
+				return added, fmt.Errorf("%w: line %d: unknown result %q", ErrInvalidData, n, operands[0])
+			}
+			d.Add(e.Game.State, result)
+			added++
+			break
+		}
+	}
+	return added, sc.Err()
+}
+
+// PGNOptions selects the positions taken from PGN games.
+type PGNOptions struct {
+	SkipPlies int // opening plies ignored in every game, e.g. book moves
+}
+
+// ReadPGN adds the quiet positions of every finished game in a PGN stream,
+// labelled with the game's result, and returns how many were added. A
+// position is quiet when the side to move is not in check, the move played
+// from it is neither a capture nor a promotion, and it was not reached by a
+// capture, so that its static evaluation is not in the middle of an exchange.
+func (d *Dataset) ReadPGN(r io.Reader, opts PGNOptions) (int, error) {
+	added := 0
+	sc := chess.NewPGNScanner(r)
+	for sc.Scan() {
+		pg := sc.Game()
+		result, ok := parseResult(pg.Result)
+		if !ok {
+			continue
+		}
+		s := pg.Start.State
+		recapture := false
+		for ply, m := range pg.Moves {
+			capture := isCapture(s, m)
+			if ply >= opts.SkipPlies && !recapture && !capture && m.Promotion == chess.NoPiece && !s.InCheck() {
+				d.Add(s, result)
+				added++
+			}
+			recapture = capture
+			s = s.Play(m)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return added, fmt.Errorf("%w: %v", ErrInvalidData, err)
+	}
+	return added, nil
+}
+
+// parseResult reads a game result as White's score.
+func parseResult(s string) (float64, bool) {
+	switch s {
+	case "1-0", "1", "1.0":
+		return 1, true
+	case "1/2-1/2", "0.5", "1/2":
+		return 0.5, true
+	case "0-1", "0", "0.0":
+		return 0, true
+	}
+	return 0, false
+}
+
+// isCapture reports whether m captures in s, including en passant.
+func isCapture(s chess.GameState, m chess.Move) bool {
+	if s.Board[m.To] != chess.NoPiece {
+		return true
+	}
+	p := s.Board[m.From]
+	return (p == chess.WhitePawn || p == chess.BlackPawn) && m.To == s.EnPassantSq
+}
@@ -0,0 +1,281 @@
+// Package tune fits the evaluation weights of the engine to game results
+// ("Texel tuning").
+//
+// Every position of a Dataset is labelled with the result of the game it
+// comes from. The evaluation is mapped to an expected score by the sigmoid
+//
+//	1 / (1 + 10^(-K*eval/400))
+//
+// and the weights are moved by gradient descent (Adam) to minimise the mean
+// squared difference between expected scores and results. The evaluation is
+// linear in the weights, so the gradient is exact; it is computed over the
+// whole data set every epoch, split between all CPU cores.
+package tune
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"sync"
+
+	engine "chess_go/internal/engine"
+)
+
+// Layout of the parameter vector: material, then the six piece-square
+// tables (the king's being its middlegame table), then the king endgame table.
+const (
+	materialParams = 0
+	pstParams      = materialParams + 6
+	kingMGParams   = pstParams + 5*64
+	kingEGParams   = pstParams + 6*64
+	numParams      = kingEGParams + 64
+)
+
+// Options configures Tune.
+type Options struct {
+	// K scales evaluations in the sigmoid; 0 fits it to the starting weights
+	// first, and it is then held fixed.
+	K float64
+	// Epochs is the number of gradient steps; 0 selects 500.
+	Epochs int
+	// Rate is the Adam step size in centipawns; 0 selects 1.
+	Rate float64
+	// Workers is the number of goroutines computing the gradient; 0 uses
+	// every CPU.
+	Workers int
+	// Progress, when set, is called after every epoch with the error of
+	// the weights before that epoch's step.
+	Progress func(epoch int, err float64)
+}
+
+// Result summarises a tuning run.
+type Result struct {
+	K      float64
+	Start  float64 // mean squared error of the starting weights
+	Final  float64 // mean squared error of the tuned weights
+	Epochs int     // epochs completed
+}
+
+// Tune fits weights to d, starting from start. It stops after opts.Epochs
+// or when ctx is cancelled, returning the weights reached so far.
+func Tune(ctx context.Context, d *Dataset, start engine.Weights, opts Options) (engine.Weights, Result, error) {
+	if d.Len() == 0 {
+		return start, Result{}, errors.New("tune: no positions")
+	}
+	if opts.Epochs <= 0 {
+		opts.Epochs = 500
+	}
+	if opts.Rate <= 0 {
+		opts.Rate = 1
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	k := opts.K
+	if k <= 0 {
+		k = d.fitK(toParams(start), opts.Workers)
+	}
+
+	const beta1, beta2, epsilon = 0.9, 0.999, 1e-8
+	p := toParams(start)
+	m := make([]float64, numParams)
+	v := make([]float64, numParams)
+	res := Result{K: k}
+	for epoch := 1; epoch <= opts.Epochs && ctx.Err() == nil; epoch++ {
+		mse, grad := d.gradient(p, k, opts.Workers)
+		if epoch == 1 {
+			res.Start = mse
+		}
+		for i, g := range grad {
+			m[i] = beta1*m[i] + (1-beta1)*g
+			v[i] = beta2*v[i] + (1-beta2)*g*g
+			mHat := m[i] / (1 - math.Pow(beta1, float64(epoch)))
+			vHat := v[i] / (1 - math.Pow(beta2, float64(epoch)))
+			p[i] -= opts.Rate * mHat / (math.Sqrt(vHat) + epsilon)
+		}
+		res.Epochs = epoch
+		if opts.Progress != nil {
+			opts.Progress(epoch, mse)
+		}
+	}
+	tuned := fromParams(p)
+	res.Final = d.Error(&tuned, k)
+	if res.Epochs == 0 {
+		res.Start = res.Final
+	}
+	return tuned, res, nil
+}
+
+// Error returns the mean squared error of w on d, with evaluations scaled by k.
+func (d *Dataset) Error(w *engine.Weights, k float64) float64 {
+	mse, _ := d.gradient(toParams(*w), k, runtime.NumCPU())
+	return mse
+}
+
+// FitK returns the sigmoid scale that minimises the error of w on d.
+func (d *Dataset) FitK(w *engine.Weights) float64 {
+	return d.fitK(toParams(*w), runtime.NumCPU())
+}
+
+// fitK minimises the error over K by golden-section search; the error is
+// unimodal in K for any sensible evaluation.
+func (d *Dataset) fitK(p []float64, workers int) float64 {
+	errAt := func(k float64) float64 {
+		sum := 0.0
+		d.parallel(workers, func(lo, hi int, _ []float64) float64 {
+			s := 0.0
+			for _, smp := range d.samples[lo:hi] {
+				diff := float64(smp.result)/2 - sigmoid(k, d.eval(smp, p))
+				s += diff * diff
+			}
+			return s
+		}, func(s float64, _ []float64) { sum += s })
+		return sum
+	}
+	phi := (math.Sqrt(5) - 1) / 2
+	a, b := 0.01, 10.0
+	c, e := b-phi*(b-a), a+phi*(b-a)
+	fc, fe := errAt(c), errAt(e)
+	for b-a > 1e-4 {
+		if fc < fe {
+			b, e, fe = e, c, fc
+			c = b - phi*(b-a)
+			fc = errAt(c)
+		} else {
+			a, c, fc = c, e, fe
+			e = a + phi*(b-a)
+			fe = errAt(e)
+		}
+	}
+	return (a + b) / 2
+}
+
+// gradient returns the mean squared error of p on d and its gradient.
+func (d *Dataset) gradient(p []float64, k float64, workers int) (float64, []float64) {
+	total := 0.0
+	grad := make([]float64, numParams)
+	scale := math.Ln10 * k / 400
+	d.parallel(workers, func(lo, hi int, g []float64) float64 {
+		s := 0.0
+		for _, smp := range d.samples[lo:hi] {
+			sig := sigmoid(k, d.eval(smp, p))
+			diff := float64(smp.result)/2 - sig
+			s += diff * diff
+			d.accumulate(smp, g, -2*diff*sig*(1-sig)*scale)
+		}
+		return s
+	}, func(s float64, g []float64) {
+		total += s
+		for i, x := range g {
+			grad[i] += x
+		}
+	})
+	n := float64(d.Len())
+	for i := range grad {
+		grad[i] /= n
+	}
+	return total / n, grad
+}
+
+// parallel splits the samples between workers. Each worker runs part on its
+// range with a private gradient buffer; merge then combines the results one
+// worker at a time.
+func (d *Dataset) parallel(workers int, part func(lo, hi int, g []float64) float64, merge func(float64, []float64)) {
+	workers = max(1, min(workers, d.Len()))
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	chunk := (d.Len() + workers - 1) / workers
+	for lo := 0; lo < d.Len(); lo += chunk {
+		hi := min(lo+chunk, d.Len())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g := make([]float64, numParams)
+			s := part(lo, hi, g)
+			mu.Lock()
+			merge(s, g)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+}
+
+// eval returns the evaluation of smp from White's point of view. It is
+// engine.Weights.Evaluate without integer rounding.
+func (d *Dataset) eval(smp sample, p []float64) float64 {
+	mg := float64(smp.phase) / maxPhase
+	v := 0.0
+	for _, f := range d.features[smp.off : smp.off+uint32(smp.n)] {
+		sign := 1.0
+		if f&blackFeature != 0 {
+			sign, f = -1, f&^blackFeature
+		}
+		pt, idx := int(f/64), int(f%64)
+		if pt == 5 {
+			v += sign * (p[kingMGParams+idx]*mg + p[kingEGParams+idx]*(1-mg))
+			continue
+		}
+		v += sign * (p[materialParams+pt] + p[pstParams+int(f)])
+	}
+	return v
+}
+
+// accumulate adds c times the derivative of eval(smp) to g.
+func (d *Dataset) accumulate(smp sample, g []float64, c float64) {
+	mg := float64(smp.phase) / maxPhase
+	for _, f := range d.features[smp.off : smp.off+uint32(smp.n)] {
+		sc := c
+		if f&blackFeature != 0 {
+			sc, f = -c, f&^blackFeature
+		}
+		pt, idx := int(f/64), int(f%64)
+		if pt == 5 {
+			g[kingMGParams+idx] += sc * mg
+			g[kingEGParams+idx] += sc * (1 - mg)
+			continue
+		}
+		g[materialParams+pt] += sc
+		g[pstParams+int(f)] += sc
+	}
+}
+
+func sigmoid(k, eval float64) float64 {
+	return 1 / (1 + math.Pow(10, -k*eval/400))
+}
+
+// toParams flattens w into the parameter vector.
+func toParams(w engine.Weights) []float64 {
+	p := make([]float64, numParams)
+	for pt, v := range w.Material {
+		p[materialParams+pt] = float64(v)
+	}
+	for pt := range w.PieceSquare {
+		for i, v := range w.PieceSquare[pt] {
+			p[pstParams+pt*64+i] = float64(v)
+		}
+	}
+	for i, v := range w.KingEndgame {
+		p[kingEGParams+i] = float64(v)
+	}
+	return p
+}
+
+// fromParams rounds the parameter vector to weights.
+func fromParams(p []float64) engine.Weights {
+	var w engine.Weights
+	for pt := range w.Material {
+		w.Material[pt] = int(math.Round(p[materialParams+pt]))
+	}
+	for pt := range w.PieceSquare {
+		for i := range w.PieceSquare[pt] {
+			w.PieceSquare[pt][i] = int(math.Round(p[pstParams+pt*64+i]))
+		}
+	}
+	for i := range w.KingEndgame {
+		w.KingEndgame[i] = int(math.Round(p[kingEGParams+i]))
+	}
+	return w
+}
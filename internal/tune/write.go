@@ -0,0 +1,86 @@
+package tune
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+
+	engine "chess_go/internal/engine"
+)
+
+var tableNames = [6]string{"pawn", "knight", "bishop", "rook", "queen", "king, middlegame"}
+
+// WriteJSON writes w as a weight file for engine.LoadWeights, with the
+// tables laid out rank by rank.
+func WriteJSON(out io.Writer, w engine.Weights) error {
+	var b bytes.Buffer
+	b.WriteString("{\n")
+	fmt.Fprintf(&b, "  \"material\": [%s],\n", joinInts(w.Material[:]))
+	b.WriteString("  \"pieceSquare\": [\n")
+	for pt := range w.PieceSquare {
+		b.WriteString("    [\n")
+		writeRanks(&b, "      ", w.PieceSquare[pt][:], false)
+		if pt < len(w.PieceSquare)-1 {
+			b.WriteString("    ],\n")
+		} else {
+			b.WriteString("    ]\n")
+		}
+	}
+	b.WriteString("  ],\n  \"kingEndgame\": [\n")
+	writeRanks(&b, "    ", w.KingEndgame[:], false)
+	b.WriteString("  ]\n}\n")
+	_, err := out.Write(b.Bytes())
+	return err
+}
+
+// WriteGo writes w as a Go source file for package engine defining
+// DefaultWeights, to replace internal/engine/weights.go. The note, if any,
+// is added to the doc comment, e.g. to record the data the weights were
+// tuned on.
+func WriteGo(out io.Writer, w engine.Weights, note string) error {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by chess-go tune. DO NOT EDIT.\n\npackage engine\n\n")
+	b.WriteString("// DefaultWeights are the built-in evaluation weights.\n")
+	if note = strings.TrimSpace(note); note != "" {
+		b.WriteString("// " + strings.ReplaceAll(note, "\n", "\n// ") + "\n")
+	}
+	b.WriteString("var DefaultWeights = Weights{\n")
+	fmt.Fprintf(&b, "Material: [6]int{%s},\n", joinInts(w.Material[:]))
+	b.WriteString("PieceSquare: [6][64]int{\n")
+	for pt := range w.PieceSquare {
+		b.WriteString("{ // " + tableNames[pt] + "\n")
+		writeRanks(&b, "", w.PieceSquare[pt][:], true)
+		b.WriteString("},\n")
+	}
+	b.WriteString("},\nKingEndgame: [64]int{\n")
+	writeRanks(&b, "", w.KingEndgame[:], true)
+	b.WriteString("},\n}\n")
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(src)
+	return err
+}
+
+// writeRanks writes a 64-entry table eight values to a line. Go syntax ends
+// every line with a comma; JSON every line but the last.
+func writeRanks(b *bytes.Buffer, indent string, table []int, goSyntax bool) {
+	for rank := 0; rank < 8; rank++ {
+		b.WriteString(indent + joinInts(table[rank*8:rank*8+8]))
+		if rank < 7 || goSyntax {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+}
+
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = fmt.Sprint(v)
+	}
+	return strings.Join(s, ", ")
+}
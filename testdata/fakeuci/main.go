@@ -0,0 +1,108 @@
+// Command fakeuci is a minimal UCI engine for the acceptance tests of
+// external engines. It plays the legal move whose index is its Pick option,
+// answers "go infinite" only when stopped, and can be told to misbehave:
+//
+//	fakeuci [-fail crash|hang] [-once FILE]
+//
+// -fail crash exits on go; -fail hang never answers go or stop. With -once,
+// it misbehaves only while FILE does not exist, creating it first, so that
+// the engine started again after the failure behaves.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	chess "chess_go/internal/chess"
+)
+
+func main() {
+	fail := flag.String("fail", "", "misbehave on go: crash or hang")
+	once := flag.String("once", "", "misbehave only if this file does not exist yet")
+	flag.Parse()
+	if *once != "" && *fail != "" {
+		if _, err := os.Stat(*once); err == nil {
+			*fail = ""
+		} else {
+			_ = os.WriteFile(*once, nil, 0o644)
+		}
+	}
+
+	g, _ := chess.NewGameFromFEN(chess.StartFEN)
+	pick := 0
+	pending := "" // bestmove held back until stop
+	sc := bufio.NewScanner(os.Stdin)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 {
+			continue
+		}
+		switch f[0] {
+		case "uci":
+			fmt.Println("id name fakeuci")
+			fmt.Println("option name Pick type spin default 0 min 0 max 255")
+			fmt.Println("option name Clear Hash type button")
+			fmt.Println("uciok")
+		case "isready":
+			fmt.Println("readyok")
+		case "setoption":
+			if len(f) == 5 && f[2] == "Pick" {
+				pick, _ = strconv.Atoi(f[4])
+			}
+		case "position":
+			g = position(f[1:])
+		case "go":
+			switch *fail {
+			case "crash":
+				os.Exit(3)
+			case "hang":
+				continue
+			}
+			moves := g.LegalMoves()
+			move := "0000"
+			if len(moves) > 0 {
+				move = moves[pick%len(moves)].UCIString()
+			}
+			fmt.Printf("info depth 1 score cp 12 nodes 1 time 0 pv %s\n", move)
+			if len(f) > 1 && f[1] == "infinite" {
+				pending = move
+				continue
+			}
+			fmt.Println("bestmove " + move)
+		case "stop":
+			if pending != "" && *fail == "" {
+				fmt.Println("bestmove " + pending)
+				pending = ""
+			}
+		case "quit":
+			return
+		}
+	}
+}
+
+// position handles "fen <fen> [moves ...]" and "startpos [moves ...]".
+func position(args []string) chess.Game {
+	fen, rest := chess.StartFEN, args[1:]
+	if args[0] == "fen" {
+		end := len(args)
+		for i, a := range args {
+			if a == "moves" {
+				end = i
+				break
+			}
+		}
+		fen, rest = strings.Join(args[1:end], " "), args[end:]
+	}
+	g, _ := chess.NewGameFromFEN(fen)
+	if len(rest) > 0 && rest[0] == "moves" {
+		for _, s := range rest[1:] {
+			m, _ := chess.ParseUCI(g, s)
+			g, _ = g.Apply(m)
+		}
+	}
+	return g
+}
@@ -0,0 +1,64 @@
+//go:build ignore
+
+// gen writes tiny.nnue, the 768x8 test network used by the acceptance tests.
+//
+//	go run testdata/nnue/gen.go
+//
+// Neurons 0-4 count the perspective's own pawns, knights, bishops, rooks and
+// queens, so the network scores material roughly as pawn 97, knight 300,
+// bishop 310, rook 400 and queen 800. Neurons 5-7 hold small deterministic
+// pseudo-random weights so that every input feature matters. The opponent's
+// output weights are the negation of the side to move's, which makes the
+// evaluation of the start position zero.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+func main() {
+	const hidden = 8
+	n := engine.NewNetwork(hidden)
+
+	own := [5]chess.Piece{chess.WhitePawn, chess.WhiteKnight, chess.WhiteBishop, chess.WhiteRook, chess.WhiteQueen}
+	count := [5]int16{31, 100, 100, 127, 255}
+	value := [5]int16{32554, 31212, 32252, 32767, 32767}
+	for j, p := range own {
+		for sq := chess.Square(0); sq < 64; sq++ {
+			n.FeatureWeights[engine.Feature(chess.White, p, sq)*hidden+j] = count[j]
+		}
+		n.OutputWeights[j], n.OutputWeights[hidden+j] = value[j], -value[j]
+	}
+
+	seed := uint32(2024)
+	random := func(limit int) int16 {
+		seed = seed*1664525 + 1013904223
+		return int16(int(seed>>16)%(2*limit+1) - limit)
+	}
+	for j := 5; j < hidden; j++ {
+		n.FeatureBiases[j] = 60
+		for f := 0; f < 768; f++ {
+			n.FeatureWeights[f*hidden+j] = random(12)
+		}
+		ow := random(3000)
+		n.OutputWeights[j], n.OutputWeights[hidden+j] = ow, -ow
+	}
+
+	f, err := os.Create("testdata/nnue/tiny.nnue")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if _, err := n.WriteTo(f); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,526 @@
+package main
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/tablebase"
+)
+
+// This file lays tables out in the Syzygy format. Runs of values are merged
+// into pair symbols, which are Huffman-coded into fixed-size blocks.
+
+// Limits of the encoder. Symbols are 12-bit numbers, 0xFFF marking a leaf; a
+// pair symbol expands to at most 256 values and a block holds at most 32768
+// so that sparse index offsets fit in 16 bits.
+const (
+	tbMaxSymbols     = 0xFFF
+	tbMaxSymbolLen   = 256
+	tbMaxBlockValues = 1 << 15
+	tbBlockBits      = 6 // 64-byte blocks
+	tbMaxCodeLen     = 24
+	tbMinPairCount   = 8 // pairs seen less often are not worth a symbol
+	tbPairsPerRound  = 64
+)
+
+// Sub-table flags of the format.
+const (
+	tbMapped      = 2
+	tbWinPlies    = 4
+	tbLossPlies   = 8
+	tbSingleValue = 128
+)
+
+var (
+	wdlMagic = []byte{0x71, 0xE8, 0x23, 0x5D}
+	dtzMagic = []byte{0xD7, 0x66, 0x0C, 0xA5}
+)
+
+// encodeTable returns the .rtbw file of name, a signature such as "KBNvK",
+// or with wdl false its .rtbz file. value is called for every legal position.
+// WDL tables store both sides to move; DTZ tables store White to move, in
+// plies, through a value map.
+func encodeTable(name string, wdl bool, value tablebase.SyzygyValue) ([]byte, error) {
+	l, err := newLayout(name)
+	if err != nil {
+		return nil, err
+	}
+	sides := 1
+	if wdl {
+		sides = 2
+	}
+	values, err := l.collect(wdl, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	var flags byte
+	maps := make([][]byte, l.files())
+	if !wdl {
+		flags = tbMapped | tbWinPlies | tbLossPlies
+		for f := range maps {
+			maps[f] = mapDTZ(values[f][0])
+		}
+	}
+	enc := make([][]tbEncoded, l.files())
+	for f := range enc {
+		for side := 0; side < sides; side++ {
+			enc[f] = append(enc[f], compressValues(flags, values[f][side]))
+		}
+	}
+
+	buf := append([]byte(nil), dtzMagic...)
+	if wdl {
+		buf = append([]byte(nil), wdlMagic...)
+	}
+	buf = append(buf, byte(2*b2i(l.pawns > 0)|b2i(sides == 2)))
+	for range enc {
+		buf = append(buf, 0) // group order: the leading group first
+		for _, p := range l.pieces {
+			code := byte(p)
+			if p >= chess.BlackPawn {
+				code += 2 // black pieces are 9-14
+			}
+			buf = append(buf, code|code<<4)
+		}
+	}
+	buf = padTo(buf, 2)
+	for _, e := range enc {
+		for _, e := range e {
+			buf = append(buf, e.sizes...)
+		}
+	}
+	if !wdl {
+		for _, m := range maps {
+			buf = append(buf, m...)
+		}
+		buf = padTo(buf, 2)
+	}
+	for _, e := range enc {
+		for _, e := range e {
+			buf = append(buf, e.sparse...)
+		}
+	}
+	for _, e := range enc {
+		for _, e := range e {
+			buf = append(buf, e.lengths...)
+		}
+	}
+	for _, e := range enc {
+		for _, e := range e {
+			buf = padTo(buf, 64)
+			buf = append(buf, e.blocks...)
+		}
+	}
+	return buf, nil
+}
+
+// collect places the pieces of l on every square, the first of them on files
+// a-d (and ranks 1-4 without pawns) as the index mirrors it there, and
+// returns the value stored at each index of each sub-table, by file and side
+// to move: WDL + 2 for WDL tables, the signed DTZ for DTZ tables. Indices no
+// legal position reaches repeat the value before them.
+func (l *layout) collect(wdl bool, value tablebase.SyzygyValue) ([][][]int, error) {
+	const unset = 1 << 30
+	values := make([][][]int, l.files())
+	for f := range values {
+		size := l.mult[f][len(l.mult[f])-1]
+		for side := 0; side < 1+b2i(wdl); side++ {
+			vs := make([]int, size)
+			for k := range vs {
+				vs[k] = unset
+			}
+			values[f] = append(values[f], vs)
+		}
+	}
+
+	s := chess.GameState{EnPassantSq: chess.NoSquare, CastlingRights: chess.NoCastling, FullMoveNumber: 1}
+	squares := make([]int, len(l.pieces))
+	scratch := make([]int, len(l.pieces))
+	var err error
+	var place func(i int)
+	place = func(i int) {
+		if err != nil {
+			return
+		}
+		if i == len(l.pieces) {
+			for side, stm := range []chess.Color{chess.White, chess.Black} {
+				if !wdl && stm == chess.Black {
+					break
+				}
+				// The side that just moved may not be in check.
+				s.ActiveColor = 1 - stm
+				if (chess.Game{State: s}).InCheck() {
+					continue
+				}
+				s.ActiveColor = stm
+				copy(scratch, squares)
+				file, idx := l.index(scratch)
+				w, dtz := value(s)
+				v := int(w) + 2
+				switch {
+				case w == chess.WDLCursedWin || w == chess.WDLBlessedLoss:
+					err = fmt.Errorf("%s: cursed results are not supported", chess.Game{State: s}.ToFEN())
+					return
+				case !wdl && w == chess.WDLDraw:
+					continue // never read: draws are not probed for a DTZ
+				case !wdl:
+					v = dtz
+				}
+				values[file][side][idx] = v
+			}
+			return
+		}
+		for sq := 0; sq < 64; sq++ {
+			p := l.pieces[i]
+			switch {
+			case s.Board[sq] != chess.NoPiece:
+			case i == 0 && (sq&7 > 3 || l.pawns == 0 && sq>>3 > 3):
+			case p == chess.WhitePawn && (sq < 8 || sq >= 56):
+			default:
+				s.Board[sq], squares[i] = p, sq
+				place(i + 1)
+				s.Board[sq] = chess.NoPiece
+			}
+		}
+	}
+	place(0)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range values {
+		for _, vs := range file {
+			last := unset
+			for _, v := range vs {
+				if v != unset {
+					last = v
+					break
+				}
+			}
+			if last == unset {
+				last = 1 // a loss, or a DTZ of 1: any value will do
+			}
+			for k, v := range vs {
+				if v == unset {
+					vs[k] = last
+				}
+				last = vs[k]
+			}
+		}
+	}
+	return values, nil
+}
+
+// mapDTZ replaces each signed DTZ in values by its position in the sorted
+// list of distances of its sign, and returns the four lists as the DTZ map
+// stores them: wins, losses, cursed wins and blessed losses.
+func mapDTZ(values []int) []byte {
+	var distances [2]map[int]bool
+	for i := range distances {
+		distances[i] = make(map[int]bool)
+	}
+	for _, v := range values {
+		distances[b2i(v < 0)][max(v, -v)-1] = true
+	}
+	var lists [2][]int
+	pos := [2]map[int]int{{}, {}}
+	for i, ds := range distances {
+		for d := range ds {
+			lists[i] = append(lists[i], d)
+		}
+		sort.Ints(lists[i])
+		for k, d := range lists[i] {
+			pos[i][d] = k
+		}
+	}
+	for k, v := range values {
+		values[k] = pos[b2i(v < 0)][max(v, -v)-1]
+	}
+
+	var out []byte
+	for _, list := range [][]int{lists[0], lists[1], nil, nil} {
+		out = append(out, byte(len(list)))
+		for _, d := range list {
+			out = append(out, byte(d))
+		}
+	}
+	return out
+}
+
+// tbEncoded is one compressed sub-table: its sizes section (flags, block
+// geometry, Huffman code and symbol tree), sparse index, block lengths and
+// blocks.
+type tbEncoded struct {
+	sizes, sparse, lengths, blocks []byte
+}
+
+// tbSymbol is a node of the symbol tree: a leaf holding a value, or a pair of
+// symbols. n is the number of values it expands to.
+type tbSymbol struct {
+	left, right int // right is -1 for a leaf, whose value is left
+	n           int
+}
+
+// compressValues encodes values, the contents of one sub-table.
+func compressValues(flags byte, values []int) tbEncoded {
+	single := true
+	for _, v := range values {
+		single = single && v == values[0]
+	}
+	if single {
+		return tbEncoded{sizes: []byte{flags | tbSingleValue, byte(values[0])}}
+	}
+
+	syms, seq := pairSymbols(values)
+	lengths := codeLengths(syms, seq)
+
+	// Canonical Huffman numbering: the longest codes get the lowest symbols,
+	// and symbols that no longer occur on their own come last.
+	order := make([]int, len(syms))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		la, lb := lengths[order[a]], lengths[order[b]]
+		if (la == 0) != (lb == 0) {
+			return lb == 0
+		}
+		return la > lb
+	})
+	number := make([]int, len(syms))
+	for n, s := range order {
+		number[s] = n
+	}
+	minLen, maxLen := 64, 0
+	var count [tbMaxCodeLen + 2]int
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+			minLen, maxLen = min(minLen, l), max(maxLen, l)
+		}
+	}
+	var base, lowest [tbMaxCodeLen + 2]uint64
+	for l := maxLen - 1; l >= minLen; l-- {
+		base[l] = (base[l+1] + uint64(count[l+1])) / 2
+		lowest[l] = lowest[l+1] + uint64(count[l+1])
+	}
+	code := make([]uint64, len(syms))
+	for s, l := range lengths {
+		if l > 0 {
+			code[s] = base[l] + uint64(number[s]) - lowest[l]
+		}
+	}
+
+	// Pack the codes, most significant bit first, into blocks.
+	const blockSize = 1 << tbBlockBits
+	var blocks []byte
+	var blockValues []int
+	block := make([]byte, blockSize)
+	used, n := 0, 0
+	flush := func() {
+		blocks = append(blocks, block...)
+		blockValues = append(blockValues, n)
+		block = make([]byte, blockSize)
+		used, n = 0, 0
+	}
+	for _, s := range seq {
+		l := lengths[s]
+		if used+l > 8*blockSize || n+syms[s].n > tbMaxBlockValues {
+			flush()
+		}
+		for b := l - 1; b >= 0; b-- {
+			if code[s]>>uint(b)&1 != 0 {
+				block[used/8] |= 0x80 >> uint(used%8)
+			}
+			used++
+		}
+		n += syms[s].n
+	}
+	flush()
+
+	// One sparse index entry per span values locates the middle of the span.
+	spanBits := bits.Len(uint(len(values)/len(blockValues) - 1))
+	spanBits = min(max(spanBits, 1), 16)
+	span := 1 << spanBits
+	starts := make([]int, len(blockValues)+1)
+	for b, n := range blockValues {
+		starts[b+1] = starts[b] + n
+	}
+	locate := func(idx int) (int, int) {
+		b := sort.Search(len(blockValues), func(b int) bool { return starts[b+1] > idx })
+		return b, idx - starts[b]
+	}
+	var sparse []byte
+	for k := 0; k*span < len(values); k++ {
+		target := k*span + span/2
+		anchor := min(target, len(values)-1)
+		b, off := locate(anchor)
+		sparse = binary.LittleEndian.AppendUint32(sparse, uint32(b))
+		sparse = binary.LittleEndian.AppendUint16(sparse, uint16(off+target-anchor))
+	}
+	var blockLengths []byte
+	for _, n := range blockValues {
+		blockLengths = binary.LittleEndian.AppendUint16(blockLengths, uint16(n-1))
+	}
+
+	sizes := []byte{flags, tbBlockBits, byte(spanBits), 0}
+	sizes = binary.LittleEndian.AppendUint32(sizes, uint32(len(blockValues)))
+	sizes = append(sizes, byte(maxLen), byte(minLen))
+	for l := minLen; l <= maxLen; l++ {
+		sizes = binary.LittleEndian.AppendUint16(sizes, uint16(lowest[l]))
+	}
+	sizes = binary.LittleEndian.AppendUint16(sizes, uint16(len(syms)))
+	for _, s := range order {
+		left, right := syms[s].left, 0xFFF
+		if syms[s].right >= 0 {
+			left, right = number[syms[s].left], number[syms[s].right]
+		}
+		sizes = append(sizes, byte(left), byte(left>>8&0xF)|byte(right&0xF)<<4, byte(right>>4))
+	}
+	sizes = padTo(sizes, 2)
+	return tbEncoded{sizes: sizes, sparse: sparse, lengths: blockLengths, blocks: blocks}
+}
+
+// pairSymbols turns values into a sequence of symbols, replacing the most
+// frequent adjacent pairs by new symbols for as long as they repeat often.
+func pairSymbols(values []int) ([]tbSymbol, []int) {
+	var syms []tbSymbol
+	leaf := make(map[int]int)
+	seq := make([]int, len(values))
+	for i, v := range values {
+		s, ok := leaf[v]
+		if !ok {
+			s = len(syms)
+			leaf[v] = s
+			syms = append(syms, tbSymbol{left: v, right: -1, n: 1})
+		}
+		seq[i] = s
+	}
+
+	for len(syms) < tbMaxSymbols {
+		counts := make(map[[2]int]int)
+		for i := 0; i+1 < len(seq); i++ {
+			counts[[2]int{seq[i], seq[i+1]}]++
+		}
+		var pairs [][2]int
+		for p, c := range counts {
+			if c >= tbMinPairCount && syms[p[0]].n+syms[p[1]].n <= tbMaxSymbolLen {
+				pairs = append(pairs, p)
+			}
+		}
+		if len(pairs) == 0 {
+			break
+		}
+		sort.Slice(pairs, func(a, b int) bool {
+			ca, cb := counts[pairs[a]], counts[pairs[b]]
+			if ca != cb {
+				return ca > cb
+			}
+			return pairs[a][0] < pairs[b][0] || pairs[a][0] == pairs[b][0] && pairs[a][1] < pairs[b][1]
+		})
+		pairs = pairs[:min(len(pairs), tbPairsPerRound, tbMaxSymbols-len(syms))]
+		merged := make(map[[2]int]int, len(pairs))
+		for _, p := range pairs {
+			merged[p] = len(syms)
+			syms = append(syms, tbSymbol{left: p[0], right: p[1], n: syms[p[0]].n + syms[p[1]].n})
+		}
+		next := seq[:0]
+		for i := 0; i < len(seq); i++ {
+			if i+1 < len(seq) {
+				if s, ok := merged[[2]int{seq[i], seq[i+1]}]; ok {
+					next = append(next, s)
+					i++
+					continue
+				}
+			}
+			next = append(next, seq[i])
+		}
+		seq = next
+	}
+	return syms, seq
+}
+
+// codeLengths returns the Huffman code length of each symbol of seq, zero for
+// symbols that do not occur, limited to tbMaxCodeLen bits.
+func codeLengths(syms []tbSymbol, seq []int) []int {
+	freq := make([]int, len(syms))
+	for _, s := range seq {
+		freq[s]++
+	}
+	for {
+		lengths := huffman(freq)
+		longest := 0
+		for _, l := range lengths {
+			longest = max(longest, l)
+		}
+		if longest <= tbMaxCodeLen {
+			return lengths
+		}
+		for s, f := range freq {
+			if f > 0 {
+				freq[s] = (f + 1) / 2 // flatten the distribution and try again
+			}
+		}
+	}
+}
+
+// huffman returns the code lengths of an optimal prefix code for freq. A
+// lone symbol gets a one-bit code.
+func huffman(freq []int) []int {
+	lengths := make([]int, len(freq))
+	h := &tbHeap{}
+	var weight, parent []int
+	var leaves []int
+	for s, f := range freq {
+		if f > 0 {
+			heap.Push(h, [2]int{f, len(weight)})
+			leaves = append(leaves, s)
+			weight, parent = append(weight, f), append(parent, -1)
+		}
+	}
+	if len(leaves) == 1 {
+		lengths[leaves[0]] = 1
+		return lengths
+	}
+	for h.Len() > 1 {
+		a, b := heap.Pop(h).([2]int), heap.Pop(h).([2]int)
+		n := len(weight)
+		weight, parent = append(weight, a[0]+b[0]), append(parent, -1)
+		parent[a[1]], parent[b[1]] = n, n
+		heap.Push(h, [2]int{a[0] + b[0], n})
+	}
+	for i, s := range leaves {
+		for n := i; parent[n] >= 0; n = parent[n] {
+			lengths[s]++
+		}
+	}
+	return lengths
+}
+
+// tbHeap is a min-heap of (weight, node) pairs for huffman.
+type tbHeap [][2]int
+
+func (h tbHeap) Len() int { return len(h) }
+func (h tbHeap) Less(i, j int) bool {
+	return h[i][0] < h[j][0] || h[i][0] == h[j][0] && h[i][1] < h[j][1]
+}
+func (h tbHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *tbHeap) Push(x any)   { *h = append(*h, x.([2]int)) }
+func (h *tbHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// padTo appends zero bytes to buf until its length is a multiple of n.
+func padTo(buf []byte, n int) []byte {
+	for len(buf)%n != 0 {
+		buf = append(buf, 0)
+	}
+	return buf
+}
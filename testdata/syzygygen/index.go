@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	chess "chess_go/internal/chess"
+)
+
+// layout is how one table is encoded: its pieces in the order the file lists
+// them, White holding the material named first, split into groups whose
+// squares are numbered together.
+type layout struct {
+	pieces []chess.Piece
+	groups []int // pieces in each group, the leading group first
+	pawns  int   // pawns in the leading group, 0 without pawns
+	mult   [][]uint64
+}
+
+// newLayout parses a signature such as "KBNvK". Pawnless tables lead with
+// the two kings and a unique piece; pawn tables lead with the pawns. Each
+// other group is a run of identical pieces.
+func newLayout(name string) (*layout, error) {
+	sides := strings.Split(name, "v")
+	if len(sides) != 2 {
+		return nil, fmt.Errorf("%q is not a material signature", name)
+	}
+	var count [13]int
+	for c, side := range sides {
+		for i := 0; i < len(side); i++ {
+			k := strings.IndexByte("PNBRQK", side[i])
+			if k < 0 {
+				return nil, fmt.Errorf("%q is not a material signature", name)
+			}
+			count[chess.WhitePawn+chess.Piece(6*c+k)]++
+		}
+	}
+	switch {
+	case count[chess.WhiteKing] != 1 || count[chess.BlackKing] != 1:
+		return nil, fmt.Errorf("%s: each side needs one king", name)
+	case len(name)-1 > 7:
+		return nil, fmt.Errorf("%s: more than seven pieces", name)
+	case sides[0] == sides[1]:
+		return nil, fmt.Errorf("%s: symmetric material is not supported", name)
+	case count[chess.BlackPawn] > 0:
+		return nil, fmt.Errorf("%s: pawns on the second side are not supported", name)
+	}
+
+	l := &layout{pawns: count[chess.WhitePawn]}
+	for i := 0; i < l.pawns; i++ {
+		l.pieces = append(l.pieces, chess.WhitePawn)
+	}
+	l.pieces = append(l.pieces, chess.WhiteKing, chess.BlackKing)
+	var rest []chess.Piece
+	unique := chess.NoPiece
+	for _, base := range []chess.Piece{chess.WhitePawn, chess.BlackPawn} {
+		for p := base + 4; p > base; p-- { // queen down to knight
+			if count[p] == 1 && unique == chess.NoPiece && l.pawns == 0 {
+				unique = p
+				continue
+			}
+			for n := 0; n < count[p]; n++ {
+				rest = append(rest, p)
+			}
+		}
+	}
+	if l.pawns == 0 {
+		if unique == chess.NoPiece {
+			return nil, fmt.Errorf("%s: pawnless material needs a unique piece", name)
+		}
+		l.pieces = append(l.pieces, unique)
+		l.groups = []int{3}
+	} else {
+		l.groups = []int{l.pawns}
+	}
+	l.pieces = append(l.pieces, rest...)
+	for i := l.groups[0]; i < len(l.pieces); i++ {
+		if i > l.groups[0] && l.pieces[i] == l.pieces[i-1] {
+			l.groups[len(l.groups)-1]++
+		} else {
+			l.groups = append(l.groups, 1)
+		}
+	}
+	for f := 0; f < l.files(); f++ {
+		l.mult = append(l.mult, l.multipliers(f))
+	}
+	return l, nil
+}
+
+// files returns the number of sub-tables per side to move: one per file of
+// the leading pawn, a to d, or one without pawns.
+func (l *layout) files() int {
+	if l.pawns > 0 {
+		return 4
+	}
+	return 1
+}
+
+// multipliers returns the factor of each group in the index for the leading
+// pawn on file, followed by the number of indices. newLayout keeps them in
+// l.mult.
+func (l *layout) multipliers(file int) []uint64 {
+	m := []uint64{1}
+	size := uint64(31332) // the kings and a unique piece in the a1-d1-d4 triangle
+	if l.pawns > 0 {
+		size = pawnsBefore(l.pawns, 7*8+file)
+	}
+	free := 64 - l.groups[0]
+	for _, n := range l.groups[1:] {
+		m = append(m, m[len(m)-1]*size)
+		size = choose(free, n)
+		free -= n
+	}
+	return append(m, m[len(m)-1]*size)
+}
+
+// index returns the file of the leading pawn and the index of the position
+// whose pieces, in the order of l.pieces, stand on sq. It mirrors and sorts
+// sq in place.
+func (l *layout) index(sq []int) (int, uint64) {
+	var idx uint64
+	if l.pawns > 0 {
+		lead := 0
+		for i := 1; i < l.pawns; i++ {
+			if pawnOrder(sq[i]) > pawnOrder(sq[lead]) {
+				lead = i
+			}
+		}
+		sq[0], sq[lead] = sq[lead], sq[0]
+		if sq[0]&7 > 3 {
+			transform(sq, mirrorFile)
+		}
+		others := sq[1:l.pawns]
+		sort.Slice(others, func(i, j int) bool { return pawnOrder(others[i]) < pawnOrder(others[j]) })
+		idx = pawnsBefore(l.pawns, sq[0])
+		for i, s := range others {
+			idx += choose(pawnOrder(s), i+1)
+		}
+	} else {
+		if sq[0]&7 > 3 {
+			transform(sq, mirrorFile)
+		}
+		if sq[0]>>3 > 3 {
+			transform(sq, mirrorRank)
+		}
+		for _, s := range sq[:3] {
+			if d := s>>3 - s&7; d != 0 {
+				if d > 0 {
+					transform(sq, mirrorDiagonal)
+				}
+				break
+			}
+		}
+		idx = triangleIndex(sq[0], sq[1], sq[2])
+	}
+
+	file := sq[0] & 7
+	if l.pawns == 0 {
+		file = 0
+	}
+	m := l.mult[file]
+	start := l.groups[0]
+	for g, n := range l.groups[1:] {
+		group := sq[start : start+n]
+		sort.Ints(group)
+		var k uint64
+		for i, s := range group {
+			below := 0
+			for _, prev := range sq[:start] {
+				below += b2i(prev < s)
+			}
+			k += choose(s-below, i+1)
+		}
+		idx += k * m[g+1]
+		start += n
+	}
+	return file, idx
+}
+
+// Square symmetries used to bring the leading pieces into place.
+func mirrorFile(sq int) int     { return sq ^ 7 }
+func mirrorRank(sq int) int     { return sq ^ 56 }
+func mirrorDiagonal(sq int) int { return sq>>3 | sq&7<<3 }
+
+func transform(sq []int, f func(int) int) {
+	for i := range sq {
+		sq[i] = f(sq[i])
+	}
+}
+
+// triangleIndex numbers the placements of the leading group of a pawnless
+// table, its first square in the a1-d1-d4 triangle and, while the squares
+// are on the a1-h8 diagonal, the next off it below the diagonal. Placements
+// with the first piece off the diagonal come first, then those with the
+// second off it, then the third, then all three on it.
+func triangleIndex(a, b, c int) uint64 {
+	adjB := b2i(b > a)
+	adjC := b2i(c > a) + b2i(c > b)
+	onDiagonal := func(sq int) bool { return sq>>3 == sq&7 }
+	var idx int
+	switch {
+	case !onDiagonal(a):
+		idx = (inTriangle[a]*63+b-adjB)*62 + c - adjC
+	case !onDiagonal(b):
+		idx = 6*63*62 + ((a>>3)*28+belowDiagonal[b])*62 + c - adjC
+	case !onDiagonal(c):
+		idx = 6*63*62 + 4*28*62 + ((a>>3)*7+(b>>3)-adjB)*28 + belowDiagonal[c]
+	default:
+		idx = 6*63*62 + 4*28*62 + 4*7*28 + ((a>>3)*7+(b>>3)-adjB)*6 + (c >> 3) - adjC
+	}
+	return uint64(idx)
+}
+
+// pawnOrder numbers the squares a2-h7 from 47 down so that a leading pawn,
+// nearest the edge and then on the lowest rank, has the highest number.
+func pawnOrder(sq int) int {
+	file, rank := sq&7, sq>>3
+	return 47 - 12*min(file, 7-file) - 2*(rank-1) - b2i(file > 3)
+}
+
+// pawnsBefore returns how many placements of n pawns lead with a pawn below
+// sq on its file, a to d. Rank 8 counts every placement for the file.
+func pawnsBefore(n, sq int) uint64 {
+	var total uint64
+	for rank := 1; rank < sq>>3 && rank <= 6; rank++ {
+		total += choose(pawnOrder(rank*8+sq&7), n-1)
+	}
+	return total
+}
+
+func choose(n, k int) uint64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	c := uint64(1)
+	for i := 1; i <= k; i++ {
+		c = c * uint64(n-k+i) / uint64(i)
+	}
+	return c
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// inTriangle numbers the squares of the a1-d1-d4 triangle, those below the
+// diagonal first; belowDiagonal numbers the squares below the a1-h8 diagonal.
+var inTriangle, belowDiagonal [64]int
+
+func init() {
+	n, m := 0, 0
+	for sq := 0; sq < 64; sq++ {
+		if sq>>3 < sq&7 {
+			belowDiagonal[sq] = m
+			m++
+			if sq&7 <= 3 {
+				inTriangle[sq] = n
+				n++
+			}
+		}
+	}
+	for sq := 0; sq <= 27; sq += 9 {
+		inTriangle[sq] = n
+		n++
+	}
+}
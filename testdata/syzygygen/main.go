@@ -0,0 +1,78 @@
+// Command syzygygen writes the Syzygy test tables in testdata/syzygy from the
+// project's own retrograde analysis:
+//
+//	go run ./testdata/syzygygen [-verify] [-out dir] KQK KRK KBK KNK KPK KBNK
+//
+// The WDL and DTZ of each position come from tablebase.Tablebase.Syzygy. The
+// files are laid out here, with an index computation of their own, so that the
+// prober in internal/chess is not checked against its own code. With -verify,
+// every position is probed back through chess.OpenSyzygy.
+//
+// Only what the test tables need is supported: pawnless material with at
+// least one unique piece, or pawns on the side named first, and no cursed
+// wins or blessed losses.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"chess_go/internal/tablebase"
+)
+
+func main() {
+	out := flag.String("out", "testdata/syzygy", "output directory for .rtbw and .rtbz tables")
+	verify := flag.Bool("verify", false, "probe every position of the written tables back")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: syzygygen [flags] KQK KRK KPK KBNK...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		fatal(err)
+	}
+
+	tb := tablebase.New()
+	for _, material := range flag.Args() {
+		start := time.Now()
+		name, value, err := tb.Syzygy(material)
+		if err != nil {
+			fatal(err)
+		}
+		for _, wdl := range []bool{true, false} {
+			data, err := encodeTable(name, wdl, value)
+			if err != nil {
+				fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(*out, name+extension(wdl)), data, 0o644); err != nil {
+				fatal(err)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "syzygygen: %s.rtbw and %s.rtbz written to %s in %v\n",
+			name, name, *out, time.Since(start).Round(time.Millisecond))
+		if *verify {
+			if err := tb.VerifySyzygy(*out, material); err != nil {
+				fatal(err)
+			}
+		}
+	}
+}
+
+func extension(wdl bool) string {
+	if wdl {
+		return ".rtbw"
+	}
+	return ".rtbz"
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "syzygygen:", err)
+	os.Exit(1)
+}
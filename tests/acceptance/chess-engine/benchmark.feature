@@ -0,0 +1,35 @@
+# language: en
+Feature: Search Benchmark
+  As an engine developer
+  I want a fixed search benchmark with a node-count signature
+  So that I can show a commit leaves the search unchanged and measure its speed
+
+  # ─── Position Set ─────────────────────────────────────────────────────────
+
+  Scenario: Developer benchmarks a varied, fixed position set
+    Given the built-in bench positions
+    Then there are exactly 50 distinct legal positions
+    And every one has a move to search
+
+  # ─── Signature ────────────────────────────────────────────────────────────
+
+  Scenario: Developer gets the same signature on every run
+    Given the bench at depth 2
+    When it is run twice
+    Then both runs search the same number of nodes
+    And they print identical per-position lines
+
+  Scenario: Developer sees the signature change with the depth
+    Given the bench at depths 1 and 2
+    Then the deeper bench searches more nodes
+
+  # ─── CLI ──────────────────────────────────────────────────────────────────
+
+  Scenario: Developer runs chess-go bench
+    When "chess-go bench 1" is run
+    Then it prints the total nodes, matching the library bench at depth 1
+    And it prints the nodes per second
+
+  Scenario: Developer passes an invalid depth
+    When "chess-go bench zero" is run
+    Then it exits with status 2
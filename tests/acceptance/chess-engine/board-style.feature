@@ -0,0 +1,63 @@
+# language: en
+Feature: Board Styles
+  As Sofia the TUI player
+  I want a board with chess glyphs, coloured squares and highlights
+  So that I can read the position at a glance
+
+  # Implementation note: scenarios drive tui.RenderWith and tui.DetectStyle
+  # directly, and the game loop through tui.Game.WithStyle.
+
+  # ─── Glyphs and Colour ────────────────────────────────────────────────────
+
+  Scenario: Player sees Unicode chess glyphs
+    Given the Unicode style without colour
+    When the starting position is rendered
+    Then the kings are drawn as ♔ and ♚ inside a box-drawn grid
+
+  Scenario: Player sees coloured squares in 256 colours or truecolor
+    Given a colour style
+    When the starting position is rendered
+    Then every square has a light or dark background in that colour mode
+    And the colour is reset at the end of every rank
+
+  # ─── Highlights ───────────────────────────────────────────────────────────
+
+  Scenario: Player sees the last move highlighted
+    Given a coloured board after 1.e4
+    Then e2 and e4 have the last-move background and the other squares do not
+
+  Scenario: Player sees the king in check highlighted
+    Given a coloured board where White is in check
+    Then the White king's square has the check background
+
+  # ─── Fallback ─────────────────────────────────────────────────────────────
+
+  Scenario: Output that is not a terminal, or NO_COLOR, falls back to ASCII
+    When the style is detected for a pipe, for NO_COLOR, for TERM=dumb and for a terminal
+    Then the pipe, NO_COLOR and TERM=dumb get the ASCII board
+    And the terminal gets glyphs on 256 colours, or truecolor when COLORTERM says so
+
+  # ─── Orientation and Coordinates ──────────────────────────────────────────
+
+  Scenario: Player flips the board
+    Given the flipped style for the White player
+    When the starting position is rendered
+    Then rank 1 is at the top and the files run h to a
+
+  Scenario: Player shows coordinates on all sides
+    Given the style with coordinates on all sides
+    When the starting position is rendered
+    Then the file letters are above and below the board
+    And the rank numbers are left and right of it
+
+  # ─── Game Loop ────────────────────────────────────────────────────────────
+
+  Scenario: Player plays on a coloured board
+    Given a TUI game in a colour style
+    When the player plays e2e4 and the engine answers e7e5
+    Then the board after each move highlights that move
+
+  Scenario: chess-go writing to a pipe draws the ASCII board
+    When chess-go is run with its output piped
+    Then the board has no escape sequences
+    But with "-style 256" it is coloured
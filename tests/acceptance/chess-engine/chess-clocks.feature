@@ -0,0 +1,78 @@
+# language: en
+Feature: Chess Clocks
+  As Sofia the TUI player
+  I want real chess clocks with increment, delay and multi-stage controls
+  So that I can practise the time controls I play over the board and online
+
+  # Implementation note: clock scenarios drive clock.New with an injected time
+  # source, so no scenario waits on the wall clock; the TUI scenarios use
+  # clocks of a few milliseconds.
+
+  # ─── Time Controls ────────────────────────────────────────────────────────
+
+  Scenario: Player writes time controls with increment, delay and stages
+    When the controls "5+3", "15d5", "25b10" and "40/90+30,30+30" are parsed
+    Then each reads back as written
+    And a stage for the rest of the game before another is rejected
+
+  # ─── Ticking ──────────────────────────────────────────────────────────────
+
+  Scenario: Fischer increment is added after every move
+    Given a 5+3 clock with White to move
+    When White moves after 10 seconds
+    Then White has 4:53 and Black's time runs
+
+  Scenario: Simple delay runs before the clock
+    Given a 5d5 clock
+    When White moves after 3 seconds, then after 8 seconds
+    Then White loses no time for the first move and 3 seconds for the second
+
+  Scenario: Bronstein delay adds back the time used, up to the delay
+    Given a 5b5 clock
+    When White moves after 3 seconds, then after 8 seconds
+    Then White loses no time for the first move and 3 seconds for the second
+    But a move longer than the time left still loses on time
+
+  Scenario: Multi-stage control adds time at the move count
+    Given a 40/90+30,30+30 clock
+    When White makes 40 moves
+    Then the moves to go count down from 40
+    And 30 minutes are added after the 40th move
+    And the rest of the game has no moves to go
+
+  # ─── Engine ───────────────────────────────────────────────────────────────
+
+  Scenario: The engine is given the clock
+    Given a 40/90+30 clock after White's first move
+    When the engine's time control is taken for Black
+    Then it has both sides' times, the increments and 40 moves to go
+
+  # ─── Flag ─────────────────────────────────────────────────────────────────
+
+  Scenario: A side whose flag falls loses on time
+    Given a 1+0 clock
+    When White takes more than a minute
+    Then White's flag has fallen and the move is not accepted
+    And the result is 0-1
+
+  Scenario: A flag fall against an opponent without mating material is a draw
+    Given positions where the opponent has a lone king, a king and knight, or bishops on one colour
+    When the side to move runs out of time
+    Then the game is drawn
+    But against a rook, a pawn or two knights the game is lost
+
+  # ─── Display ──────────────────────────────────────────────────────────────
+
+  Scenario: Clocks are displayed in minutes, hours or tenths
+    Then 5 minutes reads "5:00", 90 minutes "1:30:00" and 9.45 seconds "0:09.5"
+
+  Scenario: Player's flag falls while thinking in the TUI
+    Given a TUI game on a terminal with a clock of a few milliseconds
+    When the player does not move
+    Then the clocks beside the board are redrawn in place as they run
+    And the player loses on time
+
+  Scenario: Player's flag falls against a lone king in the TUI
+    Given a TUI game where the player has a rook against a lone king
+    When the player's time runs out
+    Then the game is drawn because the engine cannot checkmate
@@ -0,0 +1,67 @@
+# language: en
+Feature: Syzygy Endgame Tablebases
+  As Daniel the engine developer
+  I want the engine to probe Syzygy WDL and DTZ tables from a local directory
+  So that it plays endgames with five or fewer pieces perfectly
+
+  # ─── Probing ──────────────────────────────────────────────────────────────
+
+  Scenario Outline: Engine developer probes the win/draw/loss value of an endgame
+    Given the Syzygy tables in "testdata/syzygy"
+    When I probe the WDL value of "<fen>"
+    Then the result is a <wdl> for the side to move
+
+    Examples:
+      | fen                              | wdl  |
+      | 8/8/8/4k3/8/8/8/3QK3 w - - 0 1   | win  |
+      | 8/8/8/4k3/8/8/8/3QK3 b - - 0 1   | loss |
+      | 8/8/8/4k3/8/8/8/R3K3 w - - 0 1   | win  |
+      | k7/8/4P3/8/8/8/8/4K3 w - - 0 1   | win  |
+      | 4k3/4P3/4K3/8/8/8/8/8 b - - 0 1  | draw |
+      | 8/8/8/4k3/8/8/8/2B1KN2 w - - 0 1 | win  |
+
+  Scenario Outline: Engine developer probes the distance to zeroing of an endgame
+    Given the Syzygy tables in "testdata/syzygy"
+    When I probe the DTZ value of "<fen>"
+    Then the DTZ is <dtz>
+
+    Examples:
+      | fen                              | dtz |
+      | k7/8/1K6/8/8/8/8/6Q1 w - - 0 1   | 1   |
+      | k7/8/1K6/8/8/8/8/6Q1 b - - 0 1   | -2  |
+      | k7/Q7/1K6/8/8/8/8/8 b - - 0 1    | -1  |
+      | 4k3/8/4K3/4P3/8/8/8/8 w - - 0 1  | 3   |
+      | 4k3/8/4K3/4P3/8/8/8/8 b - - 0 1  | -4  |
+      | k7/8/8/8/8/8/1K5P/8 b - - 0 1    | -2  |
+      | 8/8/8/8/8/8/2k5/KNB5 w - - 0 1   | 65  |
+      | 8/8/8/4k3/8/8/8/2B1KN2 b - - 0 1 | -58 |
+      | 4k3/4P3/4K3/8/8/8/8/8 b - - 0 1  | 0   |
+      | 8/8/8/8/8/2k5/4P3/K7 w - - 0 1   | 0   |
+
+  Scenario: Engine developer cannot probe a pawn on the back rank
+    When I read "8/8/8/8/8/2k5/8/K3P3 w - - 0 1"
+    Then the FEN is refused with ErrInvalidFEN
+    And ProbeWDL and ProbeDTZ refuse a position with a pawn on e1 with ErrInvalidPosition
+
+  Scenario: Engine developer regenerates the test tables
+    Given the Syzygy tables in "testdata/syzygy"
+    When I write the KQvK and KRvK tables with testdata/syzygygen
+    Then the files are identical to the ones in "testdata/syzygy"
+
+  Scenario: Engine developer sees captures resolved before the table is trusted
+    Given a KQvK table where every position with White to move is won
+    When I probe the WDL value of "8/8/8/8/8/8/2k5/2Q4K b - - 0 1"
+    Then the result is a draw because Black captures the queen
+
+  Scenario: Engine developer sees positions with castling rights refused
+    Given a KQvK table where every position with White to move is won
+    When I probe the WDL value of "4k3/8/8/8/8/8/8/4K2R w K - 0 1"
+    Then the probe reports that the position is not in the tablebase
+
+  # ─── Engine integration ───────────────────────────────────────────────────
+
+  Scenario: Engine developer sets SyzygyPath and the engine keeps the win
+    Given the Syzygy tables in "testdata/syzygy"
+    When I send "setoption name SyzygyPath value <dir>"
+    And I send "position fen 8/8/8/4k3/8/8/8/R3K3 w - - 0 1" and "go movetime 200"
+    Then the engine answers with a move that keeps the win
@@ -0,0 +1,59 @@
+# language: en
+Feature: Engine Interface
+  As a developer of a chess front-end
+  I want the built-in search and external UCI engines behind one interface
+  So that the terminal and web front-ends can play against any UCI engine
+
+  # ─── Built-in Engine ──────────────────────────────────────────────────────
+
+  Scenario: Front-end searches a position with the built-in engine
+    Given the built-in engine set to the position after 1. e4 e5
+    When it searches to depth 3 with an info callback
+    Then it returns a legal best move heading its principal variation
+    And the callback reports every depth from 1 to 3
+
+  Scenario: Front-end stops an infinite search
+    Given an infinite search running on the built-in engine
+    When Stop is called
+    Then the search returns a legal move
+
+  Scenario: Front-end sets options on the built-in engine
+    When "Skill Level" is set to 5
+    And "Threads" is set to 1 as a GUI does at startup
+    Then the options are accepted
+    And "Threads" 4 is rejected as the search runs on one thread
+    And "Hash", which needs a hash table, and "Ponder" are rejected as unknown
+
+  # ─── External UCI Engines ─────────────────────────────────────────────────
+
+  Scenario: Front-end plays through an external UCI engine
+    Given "chess-go uci" started as an external engine
+    Then its name and advertised options are known from the handshake
+    And a search to depth 2 returns a legal move with progress reported
+
+  Scenario: Front-end passes options through to the external engine
+    Given an external engine advertising a "Pick" option
+    When "Pick" is set to 3
+    Then the engine plays the fourth legal move
+    And an option it did not advertise is rejected without being sent
+
+  Scenario: Front-end stops an external engine's infinite search
+    Given an external engine searching infinitely
+    When Stop is called
+    Then the engine's best move is returned
+
+  Scenario: Front-end recovers from an engine crash
+    Given an external engine that crashes on its first search
+    When it is asked to search twice
+    Then the first search fails with ErrEngine
+    And the second runs on a restarted engine with its options set again
+
+  Scenario: Front-end recovers from a hung engine
+    Given an external engine that ignores its first go command
+    When it searches for 100 ms
+    Then the search fails with ErrEngine soon after the time is up
+    And the next search succeeds on a restarted engine
+
+  Scenario: Player plays the terminal game against an external engine
+    When I run "chess-go -engine fakeuci -option Pick=2" and play e2e4
+    Then the external engine's reply is shown
@@ -0,0 +1,62 @@
+# language: en
+Feature: Engine Matches and SPRT
+  As an engine developer
+  I want to play one engine version against another
+  So that a change is accepted only when it measurably gains strength
+
+  # ─── Playing matches ──────────────────────────────────────────────────────
+
+  Scenario: Engine developer plays a fixed-depth match with colours reversed
+    Given engine A searching to depth 2 and engine B searching to depth 1
+    When they play 4 games
+    Then each opening is played once with each colour
+    And every game is written as PGN with Round, White, Black, Result and Termination tags
+
+  Scenario: Engine developer starts games from an EPD opening suite
+    Given an EPD suite of two positions
+    When the engines play 4 games
+    Then games 1 and 2 start from the first position and games 3 and 4 from the second
+    And the PGN records the start position with SetUp and FEN tags
+
+  Scenario: Engine developer plays against an external UCI engine
+    Given the chess-go binary run as a UCI subprocess
+    When it plays 2 games at depth 2 against the built-in search
+    Then both games finish with a result and a legal game record
+
+  Scenario: Engine developer sees a missing external engine reported
+    Given an engine command that does not exist
+    When the match starts
+    Then it fails with an engine error
+
+  # ─── Clocks and adjudication ──────────────────────────────────────────────
+
+  Scenario: Engine developer sees a slow engine lose on time
+    Given an engine that takes 300ms per move at a 0.1 second clock
+    When it plays a game
+    Then it loses by time forfeit
+
+  Scenario: Engine developer adjudicates hopeless and dead positions
+    Given engines whose scores say White is lost for three moves
+    Then the game is adjudicated as a resignation
+    Given engines that agree the position is level
+    Then the game is adjudicated as a draw after the draw move count
+
+  Scenario: Engine developer writes clocks the cutechess way
+    When I parse "40/120", "10+0.1" and "60"
+    Then I get the moves per period, base time and increment
+
+  # ─── Statistics ───────────────────────────────────────────────────────────
+
+  Scenario: Engine developer reads an Elo difference with error bars
+    Given a score of 60 wins, 20 draws and 20 losses
+    Then the Elo difference is about +147 with a margin below 80
+
+  Scenario: Engine developer stops a match with an SPRT
+    Given engine A at depth 2 and a random mover as engine B
+    And an SPRT of elo0 0 and elo1 200
+    When they play up to 200 games
+    Then the match stops early with H1 accepted
+
+  Scenario: Engine developer rejects a change that loses strength
+    Given a score of 30 wins, 120 draws and 150 losses
+    Then an SPRT of elo0 0 and elo1 5 accepts H0
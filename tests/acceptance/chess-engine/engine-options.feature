@@ -0,0 +1,59 @@
+# language: en
+Feature: Engine Options
+  As an engine developer
+  I want every engine option declared once with its type
+  So that UCI, command-line flags and config files accept the same settings
+
+  # ─── Declarations ─────────────────────────────────────────────────────────
+
+  Scenario: Engine developer declares options of every UCI type
+    Given a registry with check, spin, combo, string and button options
+    When it is advertised over UCI
+    Then each option is written as "option name ... type ..." with its bounds and values
+    And the advertisement parses back to the same declarations
+
+  Scenario: GUI has bad option values rejected
+    Given a registry with typed options
+    Then values outside a spin's range, unknown combo values and non-boolean checks are rejected
+    And unknown options are reported as unknown
+    And a rejected value leaves the option unchanged
+
+  Scenario: Engine developer reacts to option changes
+    Given an option with a change callback
+    When it is set
+    Then the callback receives the value in canonical form
+    And a button's callback runs each time it is pressed
+
+  Scenario: Engine developer has a failed change leave the option unchanged
+    Given an option whose change callback fails for a missing file
+    When it is set to the missing file
+    Then the callback's error is returned
+    And the option keeps its value and the engine what it had loaded
+
+  # ─── Flags and Config Files ───────────────────────────────────────────────
+
+  Scenario: Engine developer sets options from command-line flags
+    Given flags registered from the option registry
+    When "-skill-level 5 -ownbook -clear-hash" is parsed
+    Then the options hold the flag values and the button was pressed
+
+  Scenario: Engine developer sets options from a config file
+    Given a config file with comments, blank lines and "Name = Value" lines
+    When it is read and applied
+    Then the options hold the file's values
+
+  Scenario: GUI sees options set on the command line
+    When I run "chess-go uci -config engine.conf -skill-level 3"
+    Then the uci response advertises the values from the file and the flag
+
+  # ─── Built-in Options ─────────────────────────────────────────────────────
+
+  Scenario: Engine developer reports bad setoption commands to the GUI
+    When the UCI handler receives "setoption name Skill Level value 99"
+    Then it answers with an info string naming the allowed range
+
+  Scenario: Engine avoids or seeks draws according to Contempt
+    Given a position where every king move draws by the fifty-move rule
+    When the engine searches it with Contempt 50
+    Then it plays a pawn move to avoid the draw
+    And with Contempt -50 it plays a king move to take the draw
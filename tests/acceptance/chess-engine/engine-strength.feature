@@ -0,0 +1,49 @@
+# language: en
+Feature: Engine Strength Limiting
+  As a casual player
+  I want to choose how strong the engine plays
+  So that a game against it is neither a random walk nor a rout
+
+  # ─── Skill levels ─────────────────────────────────────────────────────────
+
+  Scenario: Casual player faces the weakest skill level
+    Given the starting position
+    When the engine searches at Skill Level 0
+    Then it returns a legal move after searching only a few hundred nodes
+
+  Scenario: Casual player sees a weak engine vary its moves
+    Given the starting position
+    When the engine searches twenty times at Skill Level 0
+    Then it plays more than one different move
+
+  Scenario: Casual player sees a limited engine still take a mate in one
+    Given the position "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1"
+    When the engine searches at Skill Level 19
+    Then it plays "a1a8"
+
+  # ─── Elo mapping ──────────────────────────────────────────────────────────
+
+  Scenario: Casual player picks a target rating
+    When I ask for the strength of a rating between MinElo and MaxElo
+    Then the skill level rises with the rating
+    And the strength reports the rating it was calibrated to
+
+  # ─── UCI options ──────────────────────────────────────────────────────────
+
+  Scenario: Engine developer sees the strength options advertised
+    When I send "uci"
+    Then the response lists "Skill Level", "UCI_LimitStrength" and "UCI_Elo"
+
+  Scenario: Engine developer limits strength through UCI
+    When I send "setoption name UCI_LimitStrength value true"
+    And I send "setoption name UCI_Elo value 1200"
+    And I send "position startpos" and "go movetime 2000"
+    Then the engine answers with a legal move well before the movetime
+
+  # ─── Command line ─────────────────────────────────────────────────────────
+
+  Scenario: Casual player sets the strength on the command line
+    When I run "chess-go -uci-limitstrength -uci-elo 1200" and play e2e4
+    Then the engine answers with a legal move
+    And "chess-go -elo 1200", short for the same, answers e2e4 too
+    And "chess-go -skill-level 99" and "chess-go -skill 99" are rejected with exit status 2
@@ -0,0 +1,38 @@
+# language: en
+Feature: Engine Watch
+  As an engine developer
+  I want to watch two engine configurations play each other move by move
+  So that I can sanity-check engine changes visually
+
+  # Implementation note: scenarios drive tui.Spectator directly, the per-move
+  # hook of match.Match with fixed-depth contenders, and the chess-go watch
+  # binary with no delay between moves.
+
+  # ─── Drawing Moves ────────────────────────────────────────────────────────
+
+  Scenario: Each move is drawn with an eval bar and the principal variation
+    Given a search for Black that reports a score and a principal variation
+    When the spectator shows Black's move
+    Then the board, the move in SAN, the eval from White's view and the PV are drawn
+    And the eval bar leans towards the side that is better
+
+  Scenario: A search without an evaluation shows no eval bar
+    When the spectator shows a move from an engine that reports no search
+    Then the board and the move are drawn without an eval bar
+
+  # ─── Playing the Series ───────────────────────────────────────────────────
+
+  Scenario: The match reports every move of every game
+    Given a two-game match between fixed-depth engines
+    When the match is played with a per-move hook
+    Then the hook sees every move of both games with their round numbers
+
+  Scenario: Engine developer watches a series with alternating colours
+    When chess-go watch plays two games at depth 1 with no delay
+    Then each game's moves are drawn with the eval and the PV
+    And the running score is shown after each game
+    And the PGN file holds both games with colours reversed
+
+  Scenario: chess-go watch rejects a bad engine spec
+    When chess-go watch is run with an unknown builtin setting
+    Then it exits with status 2
@@ -0,0 +1,50 @@
+# language: en
+Feature: EPD Test Suites
+  As an engine developer
+  I want to run tactical test suites such as WAC, ECM and STS
+  So that tactical regressions show up in CI alongside perft
+
+  # ─── EPD parsing ──────────────────────────────────────────────────────────
+
+  Scenario: Engine developer parses an EPD record with operations
+    Given the record '2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001"; c0 "mate; quick"; acd 7; hmvc 3; fmvn 20;'
+    Then its position, bm, id, c0 and acd operations are read
+    And hmvc and fmvn set the clocks
+    And it prints back as the same record
+
+  Scenario: Engine developer sees malformed records refused
+    When I parse records with missing fields, a missing semicolon or an illegal bm
+    Then each is refused with ErrInvalidEPD
+
+  Scenario: Engine developer loads the bundled tactical suite
+    When I read data/tactics.epd
+    Then every record has an id and a legal best move
+
+  # ─── Running suites ───────────────────────────────────────────────────────
+
+  Scenario: Engine developer runs a suite at a fixed depth
+    Given three WAC tactics and two elementary mates
+    When the suite runs at depth 4
+    Then all five positions are solved with per-position timing
+
+  Scenario: Engine developer scores avoid-move and STS-style positions
+    Given a position with "am" and a position with a c0 points list
+    When the engine plays the avoided move and a 5-point move
+    Then the first is unsolved and the second scores 5 of 10 points
+
+  Scenario: Engine developer lets the suite choose the depth
+    Given a record with "acd 2" and no limit on the command line
+    When the suite runs
+    Then the position is searched to depth 2
+
+  Scenario: Engine developer publishes a JUnit report
+    Given a suite with one solved, one unsolved, one unscored position and one expected to fail
+    When the report is written as JUnit XML
+    Then it lists four testcases with one failure and two skipped
+
+  Scenario: Engine developer sees CI fail on an unsolved position
+    Given the bundled suite, solved at depth 6 but for WAC.002 and WAC.003
+    And a record that marks a mate in one as the move to avoid
+    When "chess-go epd" runs each, with "-xfail WAC.002,WAC.003" for the bundled suite
+    Then it exits 0 for the bundled suite, reporting the two as expected failures
+    And it exits 1 for the unsolved record
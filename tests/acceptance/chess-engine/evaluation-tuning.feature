@@ -0,0 +1,48 @@
+# language: en
+Feature: Evaluation Tuning
+  As an engine developer
+  I want to fit the evaluation weights to the results of real games
+  So that changes to the evaluation are driven by data rather than guesswork
+
+  # ─── Training data ────────────────────────────────────────────────────────
+
+  Scenario: Engine developer loads positions labelled with results from EPD
+    Given EPD records labelled with c9 "1-0", "1/2-1/2" and "0-1" and one without a label
+    When I load them
+    Then the three labelled positions are added and the unlabelled one is skipped
+    And a record with an unknown result is refused with ErrInvalidData
+
+  Scenario: Engine developer extracts quiet positions from PGN games
+    Given a finished game with an exchange and an unfinished game
+    When I extract positions from them
+    Then only positions outside the exchange are taken from the finished game
+
+  # ─── Fitting ──────────────────────────────────────────────────────────────
+
+  Scenario: Engine developer fits the sigmoid scale
+    Given positions where the side with more material tends to win
+    When K is fitted to the default weights
+    Then the error at the fitted K is no larger than at nearby values
+
+  Scenario: Engine developer tunes a piece value that is far too low
+    Given games won by the side with an extra knight
+    And starting weights that value a knight like a pawn
+    When the weights are tuned
+    Then the error falls and the extra knight is worth more than before
+
+  # ─── Weight files ─────────────────────────────────────────────────────────
+
+  Scenario: Engine developer loads tuned weights into the engine
+    Given tuned weights written as a JSON weight file
+    When the engine loads the file
+    Then it holds the same weights and the search plays by them
+    And an incomplete weight file is refused with ErrInvalidWeights
+
+  Scenario: Engine developer regenerates the built-in weights as Go source
+    When the weights are written as Go source
+    Then the file is valid Go defining DefaultWeights in package engine
+
+  Scenario: Engine developer tunes from the command line
+    Given an EPD file of labelled positions
+    When I run "chess-go tune" with JSON and Go output
+    Then both files are written and the JSON loads as engine weights
@@ -0,0 +1,60 @@
+# language: en
+Feature: Full-Screen TUI
+  As Sofia the TUI player
+  I want to move pieces with the arrow keys on a full-screen board
+  So that I can play without typing moves and see the game around the board
+
+  # Implementation note: scenarios drive tui.Game.WithFullScreen with a fake
+  # terminal of a fixed size and key sequences as input; each frame of the
+  # screen starts with the cursor-home sequence.
+
+  # ─── Moving Pieces ────────────────────────────────────────────────────────
+
+  Scenario: Player picks up a piece and sees its legal destinations
+    Given a full-screen game from the starting position
+    When the player presses Enter on e2 and moves the cursor up
+    Then e2 is marked as picked up, the cursor is on e3
+    And e3 and e4 are marked as the pawn's destinations
+
+  Scenario: Destinations come from the legal moves
+    When the player picks up the knight on g1
+    Then exactly f3 and h3 are marked
+
+  Scenario: Player plays a move with the arrow keys
+    When the player picks up e2, moves up twice and presses Enter
+    Then 1.e4 is played and the engine answers
+    And the move list shows "1. e4      e5"
+
+  Scenario: Player puts a piece down on a square it cannot reach
+    When the player picks up e2 and puts it down on e5
+    Then "Illegal move: e2e5" is shown and the pawn is still picked up
+
+  Scenario: Player chooses the piece to promote to
+    Given a White pawn on e7
+    When the player moves it to e8 and presses "n"
+    Then the pawn promotes to a knight with check
+
+  # ─── Panels ───────────────────────────────────────────────────────────────
+
+  Scenario: Panels show the clocks, captured pieces and the engine's eval
+    Given a full-screen game with a 5+0 clock against an engine that reports its search
+    When the player captures a pawn and the engine answers
+    Then the panel shows both clocks, the pawn taken with White's lead
+    And the engine's evaluation from White's view with its principal variation in SAN
+
+  # ─── Terminal ─────────────────────────────────────────────────────────────
+
+  Scenario: The screen is laid out again when the terminal is resized
+    Given a wide terminal with the panel beside the board
+    When the terminal is made narrow and tall
+    Then the panel is drawn below the board
+    And a terminal too small for the board says so
+
+  Scenario: The terminal is restored when the player quits or the game panics
+    When the player quits
+    Then the alternate screen is left, the terminal restored once and the game reported abandoned
+    And when the engine panics the terminal is restored before the panic goes on
+
+  Scenario: chess-go refuses full-screen mode without a terminal
+    When chess-go is run with -fullscreen and its input piped
+    Then it exits with status 2 saying it needs a terminal
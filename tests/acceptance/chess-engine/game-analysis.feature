@@ -0,0 +1,37 @@
+# language: en
+Feature: Game Analysis
+  As a player
+  I want the engine to review my finished games
+  So that I can see where I went wrong and what I should have played
+
+  # ─── Classification ───────────────────────────────────────────────────────
+
+  Scenario: Player sees each move graded by the evaluation it gives away
+    Given a game whose positions are evaluated 0.20, -0.40, -0.40, -1.60, 2.40, 2.40, 2.40
+    When it is analysed
+    Then the moves are graded inaccuracy, best, mistake, blunder, best, best
+    And White's average centipawn loss is 60 and Black's is 133
+
+  Scenario: Player has a hung queen flagged as a blunder
+    Given the game 1. e4 e5 2. Nf3 Qg5 3. Nxg5
+    When it is analysed at depth 3
+    Then 2... Qg5 is a blunder and 3. Nxg5 is the best move
+
+  Scenario: Player analyses a game held in memory
+    Given Fool's mate played move by move as a chess.Game
+    When it is analysed
+    Then 2. g4 is a blunder and the game ends in mate
+
+  # ─── Annotated PGN ────────────────────────────────────────────────────────
+
+  Scenario: Player receives an annotated PGN
+    Given an analysed game with a blunder
+    When the annotated game is written as PGN
+    Then moves carry [%eval] comments and the blunder carries "??"
+    And the blunder is followed by the better line as a variation
+    And the annotated PGN reads back as the same game
+
+  Scenario: Player analyses a PGN file from the command line
+    Given a PGN file with one game
+    When I run "chess-go analyze -depth 2 -out annotated.pgn games.pgn"
+    Then the annotated game is written and each side's ACPL is reported
@@ -0,0 +1,66 @@
+# language: en
+Feature: Hot-Seat and Accessible Play
+  As Sofia the TUI player, at the club with a friend or with a screen reader
+  I want to play another person at one terminal, and to hear the game in words
+  So that two members can share a terminal and blind members can play too
+
+  # Implementation note: scenarios drive tui.Game.Run with a Setup whose
+  # HotSeat is set and no engine, or with an accessible Style; the
+  # command-line scenario runs chess-go -hotseat -accessible.
+
+  # ─── Hot Seat ─────────────────────────────────────────────────────────────
+
+  Scenario: Two players take turns at one terminal
+    When two players play the fool's mate in a hot-seat game
+    Then each prompt names the side to move and no engine plays
+    And the game ends in checkmate for Black
+
+  Scenario: The board turns to the side to move
+    When the hot-seat game is played with auto-flip
+    Then after White's move the board is drawn from Black's side
+    But without auto-flip it stays drawn from White's
+
+  Scenario: Commands act for the side to move
+    When the players undo a move, save the game, and Black resigns
+    Then one move is taken back, the PGN names both players and White wins
+    And a draw offered to the other player is agreed or declined by their answer
+    And asking for a hint without an engine is reported
+
+  Scenario: Undo gives the clock back to the side to move
+    Given a hot-seat game with a one-minute clock
+    When White plays e2e4 and the players undo it
+    Then White's clock is running and Black's is stopped
+
+  Scenario: A draw offer does not stop the clock
+    Given a hot-seat game with a clock
+    When White offers a draw and Black does not answer
+    Then White's clock keeps running and White loses on time
+
+  # ─── Accessible Text ──────────────────────────────────────────────────────
+
+  Scenario: Moves and positions are described in words
+    When the player and the engine play in the accessible style
+    Then each move is described as "White knight from g1 to f3"
+    And no board is drawn, only the side to move
+
+  Scenario: Captures, castling, en passant, promotion and check are described
+    When moves of each kind are played in the accessible style
+    Then each is described in words, ending with check or checkmate when it gives one
+
+  Scenario: Player asks for all the pieces
+    When the player types "pieces"
+    Then every piece of each side is announced with its square, king first
+
+  Scenario: The clocks are read out without redrawing
+    When an accessible game with a clock is played on a terminal
+    Then the clocks are written in words and never redrawn in place
+
+  # ─── Command Line ─────────────────────────────────────────────────────────
+
+  Scenario: Two players play an accessible game from the command line
+    When chess-go -hotseat -accessible is given both players' moves
+    Then the moves are described in words and the result is shown
+
+  Scenario: Board flags are refused in an accessible game
+    When chess-go -accessible is given -coords, -flip or -fullscreen
+    Then it exits with status 2 before starting the engine, naming the flag
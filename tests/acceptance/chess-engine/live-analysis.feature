@@ -0,0 +1,49 @@
+# language: en
+Feature: Live Analysis
+  As Sofia the TUI player, coaching from an analysis board
+  I want the engine to search the position on the board until I change it
+  So that I can see its best lines improve and explore the alternatives
+
+  # Implementation note: the board scenarios drive tui.Analysis with a search
+  # that reports two fixed lines for each position and then waits until it is
+  # stopped; the engine scenarios drive engine.Builtin with the MultiPV option.
+
+  # ─── Engine ───────────────────────────────────────────────────────────────
+
+  Scenario: The engine reports its best lines at every depth
+    Given the built-in engine with MultiPV set to 3
+    When it searches the starting position to depth 3
+    Then every depth reports lines ranked 1 to 3 with different first moves
+    And their scores do not improve from one rank to the next
+    And the UCI info lines carry "multipv"
+
+  Scenario: An infinite search stops when it is cancelled
+    When the built-in engine searches without a limit and is then cancelled
+    Then it returns its best move promptly, having reported its progress
+
+  # ─── Analysis Board ───────────────────────────────────────────────────────
+
+  Scenario: The analysis board shows the engine's lines
+    When the analysis board is opened on the starting position
+    Then each line shows its rank, the eval from White's view, the depth and the moves in SAN
+
+  Scenario: The analysis restarts on each new position
+    When the player enters a move, undoes it and pastes a FEN
+    Then each position is searched in turn and each search but the last is stopped by the next
+    And quitting stops the last
+
+  Scenario: Input that does not change the position keeps the analysis going
+    When the player enters an illegal move, a bad FEN, nonsense and undo at the start
+    Then each is reported and the first search goes on
+
+  Scenario: Lines are redrawn in place on a terminal
+    When the analysis board draws on a terminal with two lines
+    Then each line is written over its own row below the board
+
+  Scenario: A finished game is not analysed
+    When the player plays the mate in a back-rank mate position
+    Then the result is shown and no search is started
+
+  Scenario: chess-go analyses positions with -analysis
+    When chess-go is run with -analysis -lines 2 on a back-rank mate position
+    Then it shows two lines, the first the mate
@@ -7,7 +7,7 @@ Feature: Milestone 3 — Terminal User Interface
   # Stories: US-24 through US-28
   # Acceptance Criteria: AC-15
   #
-  # All scenarios are tagged @skip.
+  # Scenarios still tagged @skip are not yet implemented.
   # Enable one at a time, implement, commit, then enable the next.
   #
   # Implementation note: TUI scenarios drive the tui package via injected io.Reader and io.Writer.
@@ -16,7 +16,6 @@ Feature: Milestone 3 — Terminal User Interface
 
   # ─── Board Render (US-24, AC-15-01) ───────────────────────────────────────
 
-  @skip
   Scenario: TUI player launches the game and sees the starting board
     Given a new TUI game session is started
     When the game loop renders the initial position
@@ -25,7 +24,6 @@ Feature: Milestone 3 — Terminal User Interface
     And file letters a through h are visible in the output
     And the text "White to move" appears in the output
 
-  @skip
   Scenario: TUI player sees all starting pieces in their correct positions
     Given a new TUI game session is started
     When the game loop renders the initial position
@@ -33,7 +31,6 @@ Feature: Milestone 3 — Terminal User Interface
     And the Black king symbol appears on the e8 position
     And pawns are present on ranks 2 and 7
 
-  @skip
   Scenario: TUI player sees Black to move indicator after the first move
     Given a new TUI game session is started
     When the player inputs the move "e2e4"
@@ -41,14 +38,12 @@ Feature: Milestone 3 — Terminal User Interface
 
   # ─── Legal Move Acceptance (US-25, AC-15-02) ──────────────────────────────
 
-  @skip
   Scenario: TUI player enters a legal move and sees the updated board
     Given a new TUI game session is started with a predictable engine stub
     When the player inputs the move "e2e4"
     Then the board is redrawn with the pawn on e4
     And the engine thinking indicator is displayed before the engine move is shown
 
-  @skip
   Scenario: TUI player enters a legal move and the engine responds with a move
     Given a new TUI game session is started with a predictable engine stub that always plays "e7e5"
     When the player inputs the move "e2e4"
@@ -56,7 +51,6 @@ Feature: Milestone 3 — Terminal User Interface
     And the board shows the Black pawn on e5
     And it is White to move again
 
-  @skip
   Scenario: TUI player makes several moves in sequence and the game state accumulates correctly
     Given a new TUI game session is started with an engine stub
     When the player inputs the moves "e2e4" then "g1f3" then "f1c4"
@@ -64,7 +58,6 @@ Feature: Milestone 3 — Terminal User Interface
 
   # ─── Illegal Move Rejection (US-25, AC-15-03) ─────────────────────────────
 
-  @skip
   Scenario: TUI player enters an illegal move and sees a clear error message
     Given a new TUI game session is started
     When the player inputs the move "e2e5"
@@ -72,7 +65,6 @@ Feature: Milestone 3 — Terminal User Interface
     And the board is unchanged from the previous render
     And the move prompt is displayed again
 
-  @skip
   Scenario: TUI player enters a move in the wrong format and sees a format error
     Given a new TUI game session is started
     When the player inputs the text "hello"
@@ -80,13 +72,11 @@ Feature: Milestone 3 — Terminal User Interface
     And the board is unchanged
     And the move prompt is displayed again
 
-  @skip
   Scenario: TUI player enters an empty input and the prompt is shown again
     Given a new TUI game session is started
     When the player presses Enter without typing a move
     Then the move prompt is shown again without changing the board
 
-  @skip
   Scenario: TUI player tries to move a piece that belongs to the opponent
     Given a new TUI game session is started with White to move
     When the player inputs the move "e7e5"
@@ -95,13 +85,11 @@ Feature: Milestone 3 — Terminal User Interface
 
   # ─── Check Notification (US-27, AC-15-04) ─────────────────────────────────
 
-  @skip
   Scenario: TUI player sees a check notification when a move delivers check
     Given a TUI game session at a position one move from delivering check
     When the engine plays a move that puts White in check
     Then the output contains the text "Check!" after the board is rendered
 
-  @skip
   Scenario: TUI player is notified when the player's own move delivers check to the opponent
     Given a TUI game with a position where "e5f7" delivers check
     When the player inputs the move "e5f7"
@@ -109,32 +97,33 @@ Feature: Milestone 3 — Terminal User Interface
 
   # ─── Game Result Display (US-27, AC-15-05) ────────────────────────────────
 
-  @skip
   Scenario: TUI player sees a checkmate result message and no further move prompt
     Given a TUI game session where the next engine move is checkmate
     When the engine delivers checkmate
     Then the output contains "Checkmate" and the winner's colour
     And no move prompt appears after the result message
 
-  @skip
   Scenario: TUI player sees a stalemate result message when the game is drawn by stalemate
     Given a TUI game session where the next move results in stalemate
     When the move that causes stalemate is played
     Then the output contains "Stalemate"
     And no move prompt appears after the result message
 
-  @skip
   Scenario: TUI player sees a draw-by-fifty-move-rule message
     Given a TUI game where the half-move clock reaches 100
     When Result is checked
     Then the output contains a draw message mentioning the fifty-move rule
 
-  @skip
   Scenario: TUI player sees a draw-by-threefold-repetition message
     Given a TUI game where the same position has occurred three times
     When Result is checked
     Then the output contains a draw message mentioning repetition
 
+  Scenario: TUI player sees a draw-by-insufficient-material message
+    Given a TUI game where the player captures the last Black piece but the king
+    When Result is checked
+    Then the output contains a draw message mentioning insufficient material
+
   # ─── PGN Export (US-28, AC-15-05) ────────────────────────────────────────
 
   @skip
@@ -0,0 +1,49 @@
+# language: en
+Feature: NNUE Evaluation
+  As an engine developer
+  I want the engine to evaluate positions with an efficiently updatable neural network
+  So that networks trained outside the engine can replace the hand-crafted evaluation
+
+  # ─── Network files ────────────────────────────────────────────────────────
+
+  Scenario: Engine developer loads the test network
+    Given the network file testdata/nnue/tiny.nnue
+    When I load it
+    Then it has 768 inputs and 8 hidden neurons
+    And it evaluates the starting position as level
+
+  Scenario: Engine developer round-trips the network file format
+    Given a loaded network
+    When I write it and read it back, with zero padding after it
+    Then the parameters are unchanged
+    And files with a bad magic, the wrong input count, missing parameters or trailing data are refused with ErrInvalidNetwork
+
+  Scenario: Engine developer loads a trainer's headerless quantised.bin
+    Given the parameters of a loaded network without the header, padded to 64 bytes
+    When I read them with a hidden size of 8, and load them as a file
+    Then the parameters are unchanged
+    And reading them with the wrong hidden size is refused with ErrInvalidNetwork
+
+  # ─── Inference ────────────────────────────────────────────────────────────
+
+  Scenario: Engine developer checks the network's symmetry
+    Given positions with a material imbalance
+    Then the side with more material is ahead
+    And mirroring the board and the side to move leaves the evaluation unchanged
+
+  Scenario: Engine developer checks incremental accumulator updates
+    Given random games from positions with castling, en passant and promotions
+    When accumulators are updated move by move
+    Then every evaluation equals that of an accumulator refreshed from scratch
+
+  # ─── Search ───────────────────────────────────────────────────────────────
+
+  Scenario: Engine developer searches with the network
+    Given a position where the opponent's queen hangs
+    When the engine searches with the network configured
+    Then it captures the queen
+
+  Scenario: Engine developer selects the network over UCI
+    When the GUI sets EvalFile to the test network
+    Then the engine reports the network and still plays a legal move
+    And an unreadable EvalFile falls back to the hand-crafted evaluation
@@ -0,0 +1,48 @@
+# language: en
+Feature: Polyglot Opening Book
+  As Daniel the engine developer
+  I want the engine to read, play from and build Polyglot opening books
+  So that it plays sound, varied openings without spending search time on them
+
+  # ─── Polyglot keys ────────────────────────────────────────────────────────
+
+  Scenario Outline: Engine developer computes the standard Polyglot key of a position
+    Given the position "<fen>"
+    When I compute its Polyglot key
+    Then the key is "<key>"
+
+    Examples:
+      | fen                                                              | key              |
+      | rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1         | 463b96181691fc9c |
+      | rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1      | 823c9b50fd114196 |
+      | rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3    | 22a48b5a8e47ff78 |
+      | rnbq1bnr/ppp1pkpp/8/3pPp2/8/8/PPPPKPPP/RNBQ1BNR w - - 0 4        | 00fdd303c946bdd9 |
+      | rnbqkbnr/p1pppppp/8/8/PpP4P/8/1P1PPPP1/RNBQKBNR b KQkq c3 0 3    | 3c8123ea7b067637 |
+
+  # ─── Building and reading books ───────────────────────────────────────────
+
+  Scenario: Engine developer builds a book from a PGN collection and reads it back
+    Given the opening collection "data/openings.pgn"
+    When I build a book with a ply depth of 16 and a minimum of 1 game
+    And I write it to disk and open it again
+    Then the starting position has the book moves "e4", "d4", "Nf3" and "c4"
+    And "e4" has the highest weight
+
+  Scenario: Engine developer drops rarely played moves with the minimum-games filter
+    Given the opening collection "data/openings.pgn"
+    When I build a book with a minimum of 2 games
+    Then the starting position has the book moves "e4" and "d4" only
+
+  Scenario: Engine developer limits the book to the first plies of each game
+    Given the opening collection "data/openings.pgn"
+    When I build a book with a ply depth of 1
+    Then the position after 1. e4 is not in the book
+
+  # ─── Engine integration ───────────────────────────────────────────────────
+
+  Scenario: Engine developer enables OwnBook and receives the book move instantly
+    Given a book built from "data/openings.pgn"
+    When I send "setoption name BookFile value <book>" and "setoption name OwnBook value true"
+    And I send "setoption name BookBestMove value true"
+    And I send "position startpos" and "go movetime 1000"
+    Then the engine answers "bestmove e2e4" without searching
@@ -0,0 +1,66 @@
+# language: en
+Feature: PGN Viewer
+  As Sofia the TUI player, studying annotated games
+  I want to step through a PGN file's games with their comments and variations
+  So that I can follow the annotator's ideas and try my own against the engine
+
+  # Implementation note: the viewer scenarios drive tui.Viewer with scripted
+  # input over games read by chess.ReadPGN; playing on uses a scripted engine.
+  # The command-line scenarios run the chess-go binary on a temporary file.
+
+  # ─── Reading Annotated PGN ────────────────────────────────────────────────
+
+  Scenario: Comments, NAGs and variations are read from PGN
+    Given a game with a comment before the first move, NAGs, comments and nested variations
+    When it is read with ReadPGN
+    Then each move carries its NAGs and comment and each variation its own annotations
+    And the game is written back as PGN that reads the same
+
+  # ─── Stepping Through a Game ──────────────────────────────────────────────
+
+  Scenario: Player steps forward and backward through a game
+    When the player opens the game and steps forward twice and back once
+    Then each position is drawn with its move in SAN, its NAG meaning and its comment
+    And the variations of the next move are listed
+
+  Scenario: Player jumps to a ply and to either end of the game
+    When the player jumps to ply 4, to the end and to the start
+    Then the board shows each of those positions and the end shows the result
+    And a ply beyond the game is reported
+
+  Scenario: Player branches into a variation and back
+    When the player enters a variation, a variation inside it, and leaves both
+    Then the variation's moves are drawn from the position they branch from
+    And stepping back from a variation's first move returns to its parent line
+
+  Scenario: Player reads the game's tags
+    When the player types "tags"
+    Then every PGN tag is shown, the seven roster tags first
+
+  # ─── Choosing a Game ──────────────────────────────────────────────────────
+
+  Scenario: Player chooses a game from a searchable list
+    Given a file with three games
+    When the viewer opens it and the player searches by a player's name and picks a game
+    Then the list narrows to the matching games and the chosen game is shown
+
+  # ─── Playing On ───────────────────────────────────────────────────────────
+
+  Scenario: Player plays on against the engine from a position in the game
+    When the player steps to a position and types "play"
+    Then a game starts there with the player on the side to move
+    And resigning returns to the viewer at the same position
+
+  Scenario: The viewer without an engine says so
+    When the player types "play" in a viewer without an engine
+    Then the viewer reports that there is no engine
+
+  # ─── Command Line ─────────────────────────────────────────────────────────
+
+  Scenario: Player views a PGN file from the command line
+    When chess-go view is run on a PGN file with moves typed on its input
+    Then the game is replayed with its comments and result
+
+  Scenario: chess-go view reports a missing file
+    When chess-go view is run on a file that does not exist
+    Then it exits with status 1
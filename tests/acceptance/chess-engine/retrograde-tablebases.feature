@@ -0,0 +1,66 @@
+# language: en
+Feature: Retrograde DTM Tablebases
+  As Daniel the engine developer
+  I want to generate exact depth-to-mate tables for small material sets
+  So that the engine and the endgame trainer know the shortest mate, and the
+  move generator is cross-checked against every position of each table
+
+  # ─── Generation ───────────────────────────────────────────────────────────
+
+  Scenario Outline: Engine developer generates a table and finds its longest mate
+    When I generate the "<material>" table
+    Then the longest win for White to move takes <plies> plies
+
+    Examples:
+      | material | plies |
+      | KQK      | 19    |
+      | KRK      | 31    |
+      | KPK      | 55    |
+
+  @slow
+  Scenario: Engine developer generates the bishop and knight mate
+    When I generate the "KBNK" table
+    Then the longest win for White to move takes 65 plies
+
+  Scenario: Engine developer cross-checks every table with the move generator
+    Given the generated "KQK", "KRK" and "KPK" tables
+    When I verify each table against the forward move generator
+    Then every stored value equals the best value over its legal moves
+
+  Scenario: Engine developer sees every unmove retracted into a legal predecessor
+    Given a position with pawns, sliders and both kings
+    When I generate its unmoves
+    Then replaying each retracted move from the predecessor restores the position
+
+  # ─── Probing ──────────────────────────────────────────────────────────────
+
+  Scenario Outline: Engine developer probes the distance to mate of an endgame
+    Given the generated "KQK", "KRK" and "KPK" tables
+    When I probe "<fen>"
+    Then the result is "<result>"
+
+    Examples:
+      | fen                               | result          |
+      | k7/8/1K6/8/8/8/8/6Q1 w - - 0 1    | win in 1 plies  |
+      | k5Q1/8/1K6/8/8/8/8/8 b - - 0 1    | loss in 0 plies |
+      | 4k3/4P3/4K3/8/8/8/8/8 b - - 0 1   | draw            |
+      | 8/8/8/8/8/8/2k5/2Q4K b - - 0 1    | draw            |
+
+  Scenario: Engine developer follows the table's best moves to mate
+    Given the generated "KRK" table
+    When both sides play the table's best move from its longest win
+    Then White checkmates in exactly 31 plies
+
+  Scenario: Engine developer sees unsupported positions refused
+    Given the generated "KQK" table
+    When I probe a position with castling rights or an illegal position
+    Then the probe reports that the position is not in the tablebase
+    And generating "KPKP" is refused because both sides have pawns
+
+  # ─── Persistence ──────────────────────────────────────────────────────────
+
+  Scenario: Engine developer saves the tables and loads them back
+    Given the generated "KRK" table
+    When I write the tablebase to a directory and open it again
+    Then every probe gives the same result
+    And a truncated table file is rejected as invalid
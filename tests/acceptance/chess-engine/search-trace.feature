@@ -0,0 +1,57 @@
+# language: en
+Feature: Search Trace
+  As an engine developer
+  I want to record and inspect the tree the search explored
+  So that I can see why the engine chose a bad move
+
+  # ─── Recording ────────────────────────────────────────────────────────────
+
+  Scenario: Developer traces a search within a ply limit
+    Given a tracer limited to 2 plies
+    When the starting position is searched to depth 3
+    Then the trace holds the depth 3 iteration with every root move
+    And no recorded node is deeper than 2 plies
+    And the trace is marked truncated
+
+  Scenario: Developer traces a search within a node budget
+    Given a tracer limited to 30 nodes
+    When a search is traced
+    Then exactly 30 nodes are recorded
+
+  Scenario: Developer sees why each node returned
+    Given a position with a back-rank mate in one
+    When it is searched to depth 2 with a tracer
+    Then the mating move is extended for check and ends in mate
+    And a traced search of Kiwipete records beta cutoffs and stand-pat nodes with their windows
+
+  Scenario: Developer sees scores that agree with the search result
+    When a traced search completes
+    Then the root score and the best move's score match the result
+
+  Scenario: Tracing does not change the search
+    When the bench is run with and without a tracer
+    Then both search the same number of nodes
+
+  # ─── Export ───────────────────────────────────────────────────────────────
+
+  Scenario: Developer exports the trace as JSON and Graphviz DOT
+    Given a traced search
+    When the trace is written as JSON and as DOT
+    Then the JSON holds the tree with moves in UCI notation
+    And the DOT graph has one edge per recorded node below the root
+
+  # ─── UCI ──────────────────────────────────────────────────────────────────
+
+  Scenario: Developer dumps the last search from a UCI session
+    Given "trace on ply 2" has been sent
+    When a search completes and "trace dump json FILE" is sent
+    Then FILE holds the trace of the last iteration
+
+  Scenario: Developer dumps before tracing
+    When "trace dump" is sent without "trace on"
+    Then the engine answers with an info string
+
+  Scenario: GUI switches on the UCI debug mode
+    When "debug on" is sent
+    Then commands the engine ignores are reported in info strings until "debug off"
+    And the search is not traced
@@ -0,0 +1,184 @@
+// analysis_steps_test.go — Executable specifications for game analysis.
+//
+// Mirrors: game-analysis.feature
+// Driving ports:
+//   - analysis.Analyze / analysis.AnalyzeGame / Report.Annotated
+//   - chess.PGNGame.String with Annotations
+//   - chess-go analyze (CLI)
+
+package acceptance_test
+
+import (
+	"context"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"chess_go/internal/analysis"
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ─── Classification ───────────────────────────────────────────────────────────
+
+// TestAnalysis_GradesMovesByLoss validates the thresholds and ACPL.
+// Gherkin: "Player sees each move graded by the evaluation it gives away"
+func TestAnalysis_GradesMovesByLoss(t *testing.T) {
+	pg := mustReadOneGame(t, "1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 *")
+	search := scriptedSearch(20, -40, -40, -160, 240, 240, 240)
+	rep, err := analysis.Analyze(context.Background(), pg, analysis.Options{Search: search})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	want := []analysis.Class{analysis.Inaccuracy, analysis.Best, analysis.Mistake, analysis.Blunder, analysis.Best, analysis.Best}
+	if len(rep.Moves) != len(want) {
+		t.Fatalf("graded %d moves, want %d", len(rep.Moves), len(want))
+	}
+	for i, m := range rep.Moves {
+		if m.Class != want[i] {
+			t.Errorf("%s: %v (loss %d), want %v", m.SAN, m.Class, m.Loss, want[i])
+		}
+	}
+	if w, b := rep.ACPL(chess.White), rep.ACPL(chess.Black); w != 60 || math.Abs(b-133.33) > 0.01 {
+		t.Errorf("ACPL White %.2f, Black %.2f; want 60 and 133.33", w, b)
+	}
+	if rep.Count(chess.Black, analysis.Blunder) != 1 || rep.Count(chess.White, analysis.Mistake) != 1 {
+		t.Error("Count does not match the grades")
+	}
+}
+
+// TestAnalysis_FlagsHungQueen validates a real search finding a blunder.
+// Gherkin: "Player has a hung queen flagged as a blunder"
+func TestAnalysis_FlagsHungQueen(t *testing.T) {
+	pg := mustReadOneGame(t, "1. e4 e5 2. Nf3 Qg5 3. Nxg5 *")
+	rep, err := analysis.Analyze(context.Background(), pg, analysis.Options{Limit: engine.TimeControl{Depth: 3}})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if m := rep.Moves[3]; m.SAN != "Qg5" || m.Class != analysis.Blunder {
+		t.Errorf("2... %s graded %v (loss %d), want a blunder", m.SAN, m.Class, m.Loss)
+	}
+	if m := rep.Moves[4]; m.SAN != "Nxg5" || m.Class != analysis.Best {
+		t.Errorf("3. %s graded %v, want best", m.SAN, m.Class)
+	}
+}
+
+// TestAnalysis_AnalyzesGameInMemory validates AnalyzeGame on a chess.Game.
+// Gherkin: "Player analyses a game held in memory"
+func TestAnalysis_AnalyzesGameInMemory(t *testing.T) {
+	g := mustGame(t, StartingFEN)
+	for _, uci := range []string{"f2f3", "e7e5", "g2g4", "d8h4"} {
+		next, err := g.Apply(mustParseUCI(t, g, uci))
+		if err != nil {
+			t.Fatal(err)
+		}
+		g = next
+	}
+	rep, err := analysis.AnalyzeGame(context.Background(), g, analysis.Options{Limit: engine.TimeControl{Depth: 2}})
+	if err != nil {
+		t.Fatalf("AnalyzeGame: %v", err)
+	}
+	if rep.Game.Result != "0-1" || len(rep.Moves) != 4 {
+		t.Fatalf("result %s with %d moves, want 0-1 with 4", rep.Game.Result, len(rep.Moves))
+	}
+	if m := rep.Moves[2]; m.SAN != "g4" || m.Class != analysis.Blunder {
+		t.Errorf("2. %s graded %v, want a blunder", m.SAN, m.Class)
+	}
+	if rep.Final != -engine.MateScore {
+		t.Errorf("final evaluation %d, want White mated", rep.Final)
+	}
+}
+
+// ─── Annotated PGN ────────────────────────────────────────────────────────────
+
+// TestAnalysis_WritesAnnotatedPGN validates comments, suffixes and variations.
+// Gherkin: "Player receives an annotated PGN"
+func TestAnalysis_WritesAnnotatedPGN(t *testing.T) {
+	pg := mustReadOneGame(t, "1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 *")
+	rep, err := analysis.Analyze(context.Background(), pg, analysis.Options{
+		Search: scriptedSearch(20, -40, -40, -160, 240, 240, 240),
+	})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	text := rep.Annotated().String()
+	for _, want := range []string{
+		"1. e4?! {[%eval -0.40] Inaccuracy (0.20 -> -0.40). a3 was best.} (1. a3)",
+		"1... e5 {[%eval -0.40]}",
+		"2... Nc6?? {[%eval 2.40] Blunder (-1.60 -> 2.40). h6 was best.} (2... h6)",
+		`[Annotator "chess-go"]`,
+	} {
+		if !strings.Contains(strings.Join(strings.Fields(text), " "), want) {
+			t.Errorf("annotated PGN lacks %q:\n%s", want, text)
+		}
+	}
+	back, err := chess.ReadPGN(strings.NewReader(text))
+	if err != nil || len(back) != 1 {
+		t.Fatalf("ReadPGN: %d games, %v", len(back), err)
+	}
+	if len(back[0].Moves) != len(pg.Moves) {
+		t.Fatalf("read back %d moves, want %d", len(back[0].Moves), len(pg.Moves))
+	}
+	for i, m := range back[0].Moves {
+		if m != pg.Moves[i] {
+			t.Errorf("move %d reads back as %s, want %s", i+1, m.UCIString(), pg.Moves[i].UCIString())
+		}
+	}
+}
+
+// TestAnalysis_CommandLine validates the analyze subcommand end to end.
+// Gherkin: "Player analyses a PGN file from the command line"
+func TestAnalysis_CommandLine(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	dir := t.TempDir()
+	in, out := filepath.Join(dir, "games.pgn"), filepath.Join(dir, "annotated.pgn")
+	game := "[White \"Alice\"]\n[Black \"Bob\"]\n[Result \"1-0\"]\n\n1. e4 e5 2. Nf3 Qg5 3. Nxg5 1-0\n"
+	if err := os.WriteFile(in, []byte(game), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	summary, err := exec.Command(bin, "analyze", "-depth", "2", "-out", out, in).CombinedOutput()
+	if err != nil {
+		t.Fatalf("chess-go analyze: %v\n%s", err, summary)
+	}
+	for _, want := range []string{"Alice - Bob", "White: ACPL", "Black: ACPL", "1 blunders"} {
+		if !strings.Contains(string(summary), want) {
+			t.Errorf("summary lacks %q:\n%s", want, summary)
+		}
+	}
+	annotated, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(annotated), "Qg5??") || !strings.Contains(string(annotated), "[%eval") {
+		t.Errorf("annotated PGN lacks the blunder or evaluations:\n%s", annotated)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+func mustReadOneGame(t *testing.T, movetext string) chess.PGNGame {
+	t.Helper()
+	games, err := chess.ReadPGN(strings.NewReader("[Event \"analysis\"]\n\n" + movetext + "\n"))
+	if err != nil || len(games) != 1 {
+		t.Fatalf("ReadPGN: %d games, %v", len(games), err)
+	}
+	return games[0]
+}
+
+// scriptedSearch returns a search scoring the position at each ply with the
+// given evaluations from White's view. It recommends a3 for White and h6 for
+// Black, which the scripted game never plays.
+func scriptedSearch(whiteView ...int) engine.SearchFunc {
+	return func(_ context.Context, g chess.Game, _ engine.TimeControl, _ io.Writer) engine.SearchResult {
+		score, best := whiteView[len(g.History())], "a2a3"
+		if g.State.ActiveColor == chess.Black {
+			score, best = -score, "h7h6"
+		}
+		m, _ := chess.ParseUCI(g, best)
+		return engine.SearchResult{BestMove: m, Score: score, Depth: 1, PV: []chess.Move{m}}
+	}
+}
@@ -0,0 +1,114 @@
+// bench_steps_test.go — Executable specifications for the search benchmark.
+//
+// Mirrors: benchmark.feature
+// Driving ports:
+//   - engine.BenchPositions / engine.Config.Bench
+//   - chess-go bench (CLI)
+
+package acceptance_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	engine "chess_go/internal/engine"
+)
+
+// ─── Position Set ─────────────────────────────────────────────────────────────
+
+// TestBench_PositionSetIsValid validates the fixed positions.
+// Gherkin: "Developer benchmarks a varied, fixed position set"
+func TestBench_PositionSetIsValid(t *testing.T) {
+	// The signature depends on the set, so its size is pinned.
+	if n := len(engine.BenchPositions); n != 50 {
+		t.Errorf("%d bench positions, want 50", n)
+	}
+	seen := map[string]bool{}
+	for i, fen := range engine.BenchPositions {
+		if seen[fen] {
+			t.Errorf("position %d repeats %q", i+1, fen)
+		}
+		seen[fen] = true
+		if len(mustGame(t, fen).LegalMoves()) == 0 {
+			t.Errorf("position %d (%s) has no legal move", i+1, fen)
+		}
+	}
+}
+
+// ─── Signature ────────────────────────────────────────────────────────────────
+
+// TestBench_SignatureIsDeterministic validates repeated runs.
+// Gherkin: "Developer gets the same signature on every run"
+func TestBench_SignatureIsDeterministic(t *testing.T) {
+	var first, second strings.Builder
+	a := mustBench(t, 2, &first)
+	b := mustBench(t, 2, &second)
+	if a.Positions != len(engine.BenchPositions) {
+		t.Errorf("searched %d positions, want %d", a.Positions, len(engine.BenchPositions))
+	}
+	if a.Nodes == 0 || a.Nodes != b.Nodes {
+		t.Errorf("signatures %d and %d, want equal and non-zero", a.Nodes, b.Nodes)
+	}
+	if first.String() != second.String() {
+		t.Errorf("per-position output differs:\n%s\nvs\n%s", first.String(), second.String())
+	}
+}
+
+// TestBench_SignatureGrowsWithDepth validates the depth argument.
+// Gherkin: "Developer sees the signature change with the depth"
+func TestBench_SignatureGrowsWithDepth(t *testing.T) {
+	if d1, d2 := mustBench(t, 1, io.Discard), mustBench(t, 2, io.Discard); d2.Nodes <= d1.Nodes {
+		t.Errorf("depth 2 searched %d nodes, depth 1 %d", d2.Nodes, d1.Nodes)
+	}
+}
+
+// ─── CLI ──────────────────────────────────────────────────────────────────────
+
+// TestBench_CLIPrintsSignatureAndNPS validates chess-go bench.
+// Gherkin: "Developer runs chess-go bench"
+func TestBench_CLIPrintsSignatureAndNPS(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	out, err := exec.Command(bin, "bench", "1").Output()
+	if err != nil {
+		t.Fatalf("chess-go bench 1: %v", err)
+	}
+	nodes := regexp.MustCompile(`(?m)^Nodes searched\s*: (\d+)$`).FindSubmatch(out)
+	if nodes == nil {
+		t.Fatalf("no node count in:\n%s", out)
+	}
+	if got, want := string(nodes[1]), strconv.FormatInt(mustBench(t, 1, io.Discard).Nodes, 10); got != want {
+		t.Errorf("CLI signature %s, library %s", got, want)
+	}
+	if !regexp.MustCompile(`(?m)^Nodes/second\s*: \d+$`).Match(out) {
+		t.Errorf("no nodes per second in:\n%s", out)
+	}
+}
+
+// TestBench_CLIRejectsInvalidDepth validates argument checking.
+// Gherkin: "Developer passes an invalid depth"
+func TestBench_CLIRejectsInvalidDepth(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	err := exec.Command(bin, "bench", "zero").Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+		t.Errorf("chess-go bench zero: %v, want exit status 2", err)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// mustBench runs the bench at depth with the default configuration.
+func mustBench(t *testing.T, depth int, w io.Writer) engine.BenchResult {
+	t.Helper()
+	res, err := engine.Config{}.Bench(context.Background(), depth, w)
+	if err != nil {
+		t.Fatalf("Bench(%d): %v", depth, err)
+	}
+	return res
+}
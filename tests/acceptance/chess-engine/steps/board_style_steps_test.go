@@ -0,0 +1,237 @@
+// board_style_steps_test.go — Executable specifications for the board styles.
+//
+// Mirrors: board-style.feature
+// Driving ports:
+//   - tui.RenderWith, tui.DetectStyle
+//   - tui.Game.WithStyle
+//   - the chess-go binary with -style
+
+package acceptance_test
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/tui"
+)
+
+// ─── Glyphs and Colour ────────────────────────────────────────────────────────
+
+// TestBoardStyle_UnicodeGlyphs validates the Unicode board.
+// Gherkin: "Player sees Unicode chess glyphs"
+func TestBoardStyle_UnicodeGlyphs(t *testing.T) {
+	out := renderStyled(t, StartingFEN, tui.RenderOptions{Style: tui.Style{Unicode: true}})
+	if !strings.Contains(out, "1 │ ♖ │ ♘ │ ♗ │ ♕ │ ♔ │ ♗ │ ♘ │ ♖ │") {
+		t.Errorf("rank 1 must show White's glyphs in a grid:\n%s", out)
+	}
+	if !strings.Contains(out, "8 │ ♜ │ ♞ │ ♝ │ ♛ │ ♚ │ ♝ │ ♞ │ ♜ │") {
+		t.Errorf("rank 8 must show Black's glyphs:\n%s", out)
+	}
+	if !strings.Contains(out, "┌───┬") || !strings.Contains(out, "┴───┘") || strings.Contains(out, "\x1b[") {
+		t.Errorf("want a box-drawn grid without escape sequences:\n%s", out)
+	}
+}
+
+// TestBoardStyle_ColouredSquares validates the colour modes.
+// Gherkin: "Player sees coloured squares in 256 colours or truecolor"
+func TestBoardStyle_ColouredSquares(t *testing.T) {
+	for mode, backgrounds := range map[tui.ColorMode][2]string{
+		tui.Color256:  {"48;5;223m", "48;5;137m"},
+		tui.TrueColor: {"48;2;240;217;181m", "48;2;181;136;99m"},
+	} {
+		out := renderStyled(t, StartingFEN, tui.RenderOptions{Style: tui.Style{Unicode: true, Color: mode}})
+		for _, bg := range backgrounds {
+			if n := strings.Count(out, bg); n != 32 {
+				t.Errorf("mode %d: %d squares with background %q, want 32", mode, n, bg)
+			}
+		}
+		if n := strings.Count(out, "\x1b[0m\n"); n != 8 {
+			t.Errorf("mode %d: colour reset at the end of %d ranks, want 8", mode, n)
+		}
+		if a8 := colourCell(t, out, "a8"); !strings.Contains(a8, backgrounds[0]) || !strings.Contains(a8, "♜") {
+			t.Errorf("mode %d: a8 = %q, want a light square with a rook", mode, a8)
+		}
+	}
+}
+
+// ─── Highlights ───────────────────────────────────────────────────────────────
+
+// TestBoardStyle_LastMoveHighlighted validates the last-move highlight.
+// Gherkin: "Player sees the last move highlighted"
+func TestBoardStyle_LastMoveHighlighted(t *testing.T) {
+	start := mustGame(t, StartingFEN)
+	m := mustParseUCI(t, start, "e2e4")
+	g, _ := start.Apply(m)
+	var buf bytes.Buffer
+	tui.RenderWith(g, &buf, tui.RenderOptions{Style: tui.Style{Unicode: true, Color: tui.Color256}, LastMove: m})
+	out := buf.String()
+	for _, sq := range []string{"e2", "e4"} {
+		if cell := colourCell(t, out, sq); !strings.Contains(cell, "48;5;186m") && !strings.Contains(cell, "48;5;142m") {
+			t.Errorf("%s = %q, want the last-move background", sq, cell)
+		}
+	}
+	if n := strings.Count(out, "48;5;186m") + strings.Count(out, "48;5;142m"); n != 2 {
+		t.Errorf("%d highlighted squares, want 2", n)
+	}
+}
+
+// TestBoardStyle_CheckHighlighted validates the check highlight.
+// Gherkin: "Player sees the king in check highlighted"
+func TestBoardStyle_CheckHighlighted(t *testing.T) {
+	out := renderStyled(t, "4k3/8/8/8/8/8/8/r3K3 w - - 0 1", tui.RenderOptions{Style: tui.Style{Unicode: true, Color: tui.TrueColor}})
+	if cell := colourCell(t, out, "e1"); !strings.Contains(cell, "48;2;235;97;80m") && !strings.Contains(cell, "48;2;200;70;60m") {
+		t.Errorf("e1 = %q, want the check background", cell)
+	}
+	if cell := colourCell(t, out, "e8"); strings.Contains(cell, "235;97;80") || strings.Contains(cell, "200;70;60") {
+		t.Errorf("the king not in check must not be highlighted: %q", cell)
+	}
+}
+
+// ─── Fallback ─────────────────────────────────────────────────────────────────
+
+// TestBoardStyle_Detection validates the NO_COLOR and non-TTY fallback.
+// Gherkin: "Output that is not a terminal, or NO_COLOR, falls back to ASCII"
+func TestBoardStyle_Detection(t *testing.T) {
+	env := func(vars ...string) func(string) string {
+		return func(name string) string {
+			for i := 0; i+1 < len(vars); i += 2 {
+				if vars[i] == name {
+					return vars[i+1]
+				}
+			}
+			return ""
+		}
+	}
+	cases := []struct {
+		name     string
+		terminal bool
+		getenv   func(string) string
+		want     tui.Style
+	}{
+		{"pipe", false, env("COLORTERM", "truecolor"), tui.Style{}},
+		{"NO_COLOR", true, env("NO_COLOR", "1"), tui.Style{}},
+		{"TERM=dumb", true, env("TERM", "dumb"), tui.Style{}},
+		{"terminal", true, env("TERM", "xterm-256color"), tui.Style{Unicode: true, Color: tui.Color256}},
+		{"truecolor", true, env("COLORTERM", "truecolor"), tui.Style{Unicode: true, Color: tui.TrueColor}},
+	}
+	for _, c := range cases {
+		if got := tui.DetectStyle(c.terminal, c.getenv); got != c.want {
+			t.Errorf("%s: style %+v, want %+v", c.name, got, c.want)
+		}
+	}
+}
+
+// ─── Orientation and Coordinates ──────────────────────────────────────────────
+
+// TestBoardStyle_Flipped validates flipping the board.
+// Gherkin: "Player flips the board"
+func TestBoardStyle_Flipped(t *testing.T) {
+	out := renderStyled(t, StartingFEN, tui.RenderOptions{Style: tui.Style{Flipped: true}, Side: chess.White})
+	rows := lastBoardRows(t, out)
+	if !strings.HasPrefix(rows[0], "1 | R | N | B | K | Q |") {
+		t.Errorf("top row = %q, want rank 1 from the h-file", rows[0])
+	}
+	if !strings.Contains(out, "    h   g   f   e   d   c   b   a") {
+		t.Errorf("file labels must run h to a:\n%s", out)
+	}
+}
+
+// TestBoardStyle_AllCoordinates validates coordinates on all sides.
+// Gherkin: "Player shows coordinates on all sides"
+func TestBoardStyle_AllCoordinates(t *testing.T) {
+	for _, style := range []tui.Style{{AllCoordinates: true}, {AllCoordinates: true, Unicode: true, Color: tui.Color256}} {
+		out := renderStyled(t, StartingFEN, tui.RenderOptions{Style: style})
+		lines := strings.Split(out, "\n")
+		if !strings.HasSuffix(lines[0], "a  b  c  d  e  f  g  h") && !strings.HasSuffix(lines[0], "a   b   c   d   e   f   g   h") {
+			t.Errorf("%+v: first line %q, want the file letters above the board", style, lines[0])
+		}
+		if n := strings.Count(out, "h\n"); n != 2 {
+			t.Errorf("%+v: file letters appear %d times, want above and below", style, n)
+		}
+		for rank := '1'; rank <= '8'; rank++ {
+			found := false
+			for _, l := range lines {
+				if strings.HasPrefix(l, string(rank)+" ") && strings.HasSuffix(l, " "+string(rank)) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%+v: rank %c not labelled on both sides:\n%s", style, rank, out)
+			}
+		}
+	}
+}
+
+// ─── Game Loop ────────────────────────────────────────────────────────────────
+
+// TestBoardStyle_GameLoopHighlightsMoves validates the styled game loop.
+// Gherkin: "Player plays on a coloured board"
+func TestBoardStyle_GameLoopHighlightsMoves(t *testing.T) {
+	var out bytes.Buffer
+	tui.NewGame(strings.NewReader("e2e4\n"), &out, scriptedEngine(t, "e7e5")).
+		WithStyle(tui.Style{Unicode: true, Color: tui.Color256}).Run()
+	boards := strings.Split(out.String(), "Black to move")
+	if len(boards) != 2 {
+		t.Fatalf("want the board after White's move:\n%s", out.String())
+	}
+	if cell := colourCell(t, boards[0], "e4"); !strings.Contains(cell, "48;5;186m") {
+		t.Errorf("after e2e4, e4 = %q, want it highlighted", cell)
+	}
+	if cell := colourCell(t, out.String(), "e5"); !strings.Contains(cell, "48;5;142m") {
+		t.Errorf("after e7e5, e5 = %q, want it highlighted", cell)
+	}
+	if cell := colourCell(t, out.String(), "e4"); strings.Contains(cell, "48;5;186m") {
+		t.Errorf("after e7e5, e4 = %q, want the highlight gone", cell)
+	}
+}
+
+// TestBoardStyle_CLIFallsBackToASCII validates chess-go's style detection.
+// Gherkin: "chess-go writing to a pipe draws the ASCII board"
+func TestBoardStyle_CLIFallsBackToASCII(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	for _, c := range []struct {
+		args     []string
+		coloured bool
+	}{
+		{[]string{"-depth", "1"}, false},
+		{[]string{"-depth", "1", "-style", "256"}, true},
+	} {
+		cmd := exec.Command(bin, c.args...)
+		cmd.Stdin = strings.NewReader("")
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("chess-go %v: %v", c.args, err)
+		}
+		if got := strings.Contains(string(out), "\x1b["); got != c.coloured {
+			t.Errorf("chess-go %v: escape sequences %v, want %v:\n%s", c.args, got, c.coloured, out)
+		}
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// renderStyled renders fen with o and returns the output.
+func renderStyled(t *testing.T, fen string, o tui.RenderOptions) string {
+	t.Helper()
+	var buf bytes.Buffer
+	tui.RenderWith(mustGame(t, fen), &buf, o)
+	return buf.String()
+}
+
+// colourCell returns the escape sequence and contents of square in the last
+// coloured board of rendered, drawn from White's side.
+func colourCell(t *testing.T, rendered, square string) string {
+	t.Helper()
+	lines := strings.Split(rendered, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.HasPrefix(lines[i], string(square[1])+" \x1b[") {
+			cells := strings.Split(lines[i], "\x1b[")
+			return cells[1+int(square[0]-'a')]
+		}
+	}
+	t.Fatalf("no coloured rank %c in output:\n%s", square[1], rendered)
+	return ""
+}
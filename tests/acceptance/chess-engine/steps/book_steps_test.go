@@ -0,0 +1,172 @@
+// book_steps_test.go — Executable specifications for the Polyglot opening book.
+//
+// Mirrors: opening-book.feature
+// Driving ports:
+//   - book.Key(chess.GameState) uint64
+//   - book.NewBuilder(opts).AddPGN(r) / Builder.Book() / Book.WriteFile / book.Open
+//   - engine.UCIHandler.Run(r io.Reader, w io.Writer) with OwnBook/BookFile options
+
+package acceptance_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"chess_go/internal/book"
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ─── Polyglot Keys ────────────────────────────────────────────────────────────
+
+// TestBookKey_MatchesPolyglotReferenceKeys checks the published Polyglot test vectors.
+// Gherkin: "Engine developer computes the standard Polyglot key of a position"
+func TestBookKey_MatchesPolyglotReferenceKeys(t *testing.T) {
+	cases := []struct {
+		fen string
+		key uint64
+	}{
+		{StartingFEN, 0x463b96181691fc9c},
+		{AfterE2E4FEN, 0x823c9b50fd114196},
+		{"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3", 0x22a48b5a8e47ff78},
+		{"rnbq1bnr/ppp1pkpp/8/3pPp2/8/8/PPPPKPPP/RNBQ1BNR w - - 0 4", 0x00fdd303c946bdd9},
+		{"rnbqkbnr/p1pppppp/8/8/PpP4P/8/1P1PPPP1/RNBQKBNR b KQkq c3 0 3", 0x3c8123ea7b067637},
+	}
+	for _, c := range cases {
+		game := mustGame(t, c.fen)
+		if got := book.Key(game.State); got != c.key {
+			t.Errorf("Key(%q) = %016x, want %016x", c.fen, got, c.key)
+		}
+	}
+}
+
+// TestBookMove_CastlingRoundTripsThroughPolyglotEncoding checks the king-takes-rook convention.
+func TestBookMove_CastlingRoundTripsThroughPolyglotEncoding(t *testing.T) {
+	game := mustGame(t, WhiteKingsideFEN)
+	castle := mustParseUCI(t, game, "e1g1")
+	enc := book.EncodeMove(game.State, castle)
+	if to := chess.SquareOf(int(enc&7), int(enc>>3&7)); to != chess.H1 {
+		t.Errorf("encoded castling destination = %d, want h1", to)
+	}
+	if got := book.DecodeMove(game.State, enc); got != castle {
+		t.Errorf("DecodeMove(EncodeMove(e1g1)) = %s", got.UCIString())
+	}
+}
+
+// ─── Building and Reading Books ───────────────────────────────────────────────
+
+// TestBookBuilder_BuildWriteAndReadBack validates the PGN → book → file round trip.
+// Gherkin: "Engine developer builds a book from a PGN collection and reads it back"
+func TestBookBuilder_BuildWriteAndReadBack(t *testing.T) {
+	b := buildOpeningBook(t, book.BuildOptions{MaxPly: 16, MinGames: 1})
+	path := filepath.Join(t.TempDir(), "book.bin")
+	if err := b.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	reread, err := book.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if reread.Len() != b.Len() {
+		t.Fatalf("re-read book has %d entries, want %d", reread.Len(), b.Len())
+	}
+
+	game := mustGame(t, StartingFEN)
+	got := bookSANs(game, reread)
+	if strings.Join(got, " ") != "e4 d4 Nf3 c4" {
+		t.Errorf("start position book moves = %v, want [e4 d4 Nf3 c4]", got)
+	}
+}
+
+// TestBookBuilder_MinGamesDropsRareMoves validates the minimum-games filter.
+// Gherkin: "Engine developer drops rarely played moves with the minimum-games filter"
+func TestBookBuilder_MinGamesDropsRareMoves(t *testing.T) {
+	b := buildOpeningBook(t, book.BuildOptions{MinGames: 2})
+	game := mustGame(t, StartingFEN)
+	if got := bookSANs(game, b); strings.Join(got, " ") != "e4 d4" {
+		t.Errorf("start position book moves = %v, want [e4 d4]", got)
+	}
+}
+
+// TestBookBuilder_PlyDepthLimitsBook validates the ply depth filter.
+// Gherkin: "Engine developer limits the book to the first plies of each game"
+func TestBookBuilder_PlyDepthLimitsBook(t *testing.T) {
+	b := buildOpeningBook(t, book.BuildOptions{MaxPly: 1})
+	game := mustGame(t, StartingFEN)
+	game, _ = game.Apply(mustParseUCI(t, game, "e2e4"))
+	if moves := b.Moves(game); len(moves) != 0 {
+		t.Errorf("expected no book moves after 1. e4 with depth 1, got %d", len(moves))
+	}
+}
+
+// TestBookRead_RejectsTruncatedFile validates the typed error for corrupt books.
+func TestBookRead_RejectsTruncatedFile(t *testing.T) {
+	_, err := book.Read(bytes.NewReader(make([]byte, 17)))
+	if err == nil {
+		t.Fatal("expected an error for a 17-byte book")
+	}
+}
+
+// ─── Engine Integration ───────────────────────────────────────────────────────
+
+// TestUCIHandler_OwnBookPlaysBookMove validates that the engine consults the book before searching.
+// Gherkin: "Engine developer enables OwnBook and receives the book move instantly"
+func TestUCIHandler_OwnBookPlaysBookMove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.bin")
+	if err := buildOpeningBook(t, book.BuildOptions{}).WriteFile(path); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	searched := false
+	search := engine.SearchFunc(func(ctx context.Context, g chess.Game, tc engine.TimeControl, info io.Writer) engine.SearchResult {
+		searched = true
+		return engine.SearchContext(ctx, g, tc, info)
+	})
+
+	var input, output bytes.Buffer
+	fmt.Fprintf(&input, "setoption name BookFile value %s\n", path)
+	input.WriteString("setoption name OwnBook value true\n")
+	input.WriteString("setoption name BookBestMove value true\n")
+	input.WriteString("position startpos\n")
+	input.WriteString("go movetime 1000\n")
+	engine.NewUCIHandler(search).Run(&input, &output)
+
+	if !strings.Contains(output.String(), "bestmove e2e4") {
+		t.Errorf("expected book move e2e4; got:\n%s", output.String())
+	}
+	if searched {
+		t.Error("search must not run when the position is in the book")
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// buildOpeningBook builds a book from the repository's opening collection.
+func buildOpeningBook(t *testing.T, opts book.BuildOptions) *book.Book {
+	t.Helper()
+	f, err := os.Open(filepath.Join(projectRoot(t), "data", "openings.pgn"))
+	if err != nil {
+		t.Fatalf("open openings: %v", err)
+	}
+	defer f.Close()
+	b := book.NewBuilder(opts)
+	if err := b.AddPGN(f); err != nil {
+		t.Fatalf("AddPGN: %v", err)
+	}
+	return b.Book()
+}
+
+// bookSANs lists the book moves of game in SAN, highest weight first.
+func bookSANs(game chess.Game, b *book.Book) []string {
+	var out []string
+	for _, bm := range b.Moves(game) {
+		out = append(out, bm.Move.SANString(game))
+	}
+	return out
+}
@@ -0,0 +1,293 @@
+// clock_steps_test.go — Executable specifications for the chess clocks.
+//
+// Mirrors: chess-clocks.feature
+// Driving ports:
+//   - clock.Parse, clock.New, clock.Clock, clock.TimeoutIn, clock.Format
+//   - chess.Game.HasMatingMaterial
+//   - tui.NewGameWithSetup with a clock, on a terminal
+
+package acceptance_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/clock"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tui"
+)
+
+// ─── Time Controls ────────────────────────────────────────────────────────────
+
+// TestClock_ParseControls validates the time control notation.
+// Gherkin: "Player writes time controls with increment, delay and stages"
+func TestClock_ParseControls(t *testing.T) {
+	for _, s := range []string{"5+3", "15d5", "25b10", "40/90+30,30+30", "40/120", "0.5"} {
+		c, err := clock.Parse(s)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", s, err)
+			continue
+		}
+		if c.String() != s {
+			t.Errorf("Parse(%q).String() = %q", s, c.String())
+		}
+	}
+	c := mustControl(t, "40/90+30,30+30")
+	if len(c) != 2 || c[0].Moves != 40 || c[0].Time != 90*time.Minute || c[1].Increment != 30*time.Second {
+		t.Errorf("40/90+30,30+30 parsed as %+v", c)
+	}
+	for _, s := range []string{"", "0+1", "5+x", "+3", "x/5", "90+30,40/30"} {
+		if _, err := clock.Parse(s); !errors.Is(err, clock.ErrInvalidControl) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidControl", s, err)
+		}
+	}
+}
+
+// ─── Ticking ──────────────────────────────────────────────────────────────────
+
+// TestClock_FischerIncrement validates the increment.
+// Gherkin: "Fischer increment is added after every move"
+func TestClock_FischerIncrement(t *testing.T) {
+	now, advance := fakeNow()
+	c := clock.New(mustControl(t, "5+3"), now)
+	c.Start(chess.White)
+	advance(10 * time.Second)
+	if got := c.Remaining(chess.White); got != 4*time.Minute+50*time.Second {
+		t.Errorf("White's running clock shows %v, want 4m50s", got)
+	}
+	if !c.Press() {
+		t.Fatal("Press reported a flag fall")
+	}
+	if got := c.Remaining(chess.White); got != 4*time.Minute+53*time.Second {
+		t.Errorf("White has %v, want 4m53s", got)
+	}
+	advance(time.Second)
+	if side, ok := c.Running(); !ok || side != chess.Black || c.Remaining(chess.Black) != 4*time.Minute+59*time.Second {
+		t.Errorf("Black's time must run after White's move: running %v %v, Black %v", side, ok, c.Remaining(chess.Black))
+	}
+}
+
+// TestClock_SimpleDelay validates simple delay.
+// Gherkin: "Simple delay runs before the clock"
+func TestClock_SimpleDelay(t *testing.T) {
+	assertDelayCharges(t, "5d5")
+	now, advance := fakeNow()
+	c := clock.New(clock.Control{{Time: time.Second, Delay: 5 * time.Second}}, now)
+	c.Start(chess.White)
+	if got := c.UntilFlag(); got != 6*time.Second {
+		t.Errorf("UntilFlag = %v, want the delay and the second left", got)
+	}
+	advance(3 * time.Second)
+	if !c.Press() {
+		t.Error("a move within the delay must not lose on time")
+	}
+}
+
+// TestClock_BronsteinDelay validates Bronstein delay.
+// Gherkin: "Bronstein delay adds back the time used, up to the delay"
+func TestClock_BronsteinDelay(t *testing.T) {
+	assertDelayCharges(t, "5b5")
+	now, advance := fakeNow()
+	c := clock.New(clock.Control{{Time: time.Second, Delay: 5 * time.Second, Bronstein: true}}, now)
+	c.Start(chess.White)
+	advance(3 * time.Second)
+	if c.Press() {
+		t.Error("with Bronstein delay a move longer than the time left must lose on time")
+	}
+}
+
+// TestClock_MultiStage validates multi-stage controls.
+// Gherkin: "Multi-stage control adds time at the move count"
+func TestClock_MultiStage(t *testing.T) {
+	now, advance := fakeNow()
+	c := clock.New(mustControl(t, "40/90+30,30+30"), now)
+	c.Start(chess.White)
+	for i := 0; i < 40; i++ {
+		if got := c.MovesToGo(chess.White); got != 40-i {
+			t.Fatalf("before move %d: %d moves to go, want %d", i+1, got, 40-i)
+		}
+		advance(time.Minute)
+		c.Press() // White
+		c.Press() // Black, instantly
+	}
+	// 90 minutes less 40 of thinking, plus 40 increments of 30s and the 30 minutes of the second stage.
+	if got, want := c.Remaining(chess.White), 90*time.Minute-40*time.Minute+20*time.Minute+30*time.Minute; got != want {
+		t.Errorf("White has %v after 40 moves, want %v", got, want)
+	}
+	if got := c.MovesToGo(chess.White); got != 0 {
+		t.Errorf("%d moves to go in the second stage, want 0 for the rest of the game", got)
+	}
+
+	// A last stage with a move count repeats.
+	c = clock.New(mustControl(t, "2/1"), now)
+	c.Start(chess.White)
+	for i := 0; i < 4; i++ {
+		c.Press()
+		c.Press()
+	}
+	if got := c.Remaining(chess.White); got != 3*time.Minute {
+		t.Errorf("2/1 after 4 instant moves: %v, want 1 minute plus 2 more", got)
+	}
+}
+
+// ─── Engine ───────────────────────────────────────────────────────────────────
+
+// TestClock_EngineTimeControl validates the time control given to the engine.
+// Gherkin: "The engine is given the clock"
+func TestClock_EngineTimeControl(t *testing.T) {
+	now, advance := fakeNow()
+	c := clock.New(mustControl(t, "40/90+30"), now)
+	c.Start(chess.White)
+	advance(time.Minute)
+	c.Press()
+	want := engine.TimeControl{WTime: 89*time.Minute + 30*time.Second, BTime: 90 * time.Minute,
+		WInc: 30 * time.Second, BInc: 30 * time.Second, MovesToGo: 40}
+	if got := c.TimeControl(chess.Black); got != want {
+		t.Errorf("TimeControl = %+v, want %+v", got, want)
+	}
+	delay := clock.New(mustControl(t, "5d2"), now).TimeControl(chess.White)
+	if delay.WInc != 2*time.Second {
+		t.Errorf("a 2s delay is given as %v of increment, want 2s", delay.WInc)
+	}
+}
+
+// ─── Flag ─────────────────────────────────────────────────────────────────────
+
+// TestClock_FlagFallLoses validates losing on time.
+// Gherkin: "A side whose flag falls loses on time"
+func TestClock_FlagFallLoses(t *testing.T) {
+	now, advance := fakeNow()
+	c := clock.New(mustControl(t, "1+0"), now)
+	c.Start(chess.White)
+	advance(61 * time.Second)
+	if side, ok := c.Flagged(); !ok || side != chess.White {
+		t.Errorf("Flagged = %v, %v; want White", side, ok)
+	}
+	if c.Press() {
+		t.Error("a move after the flag fell must not be accepted")
+	}
+	if to := clock.TimeoutIn(mustGame(t, StartingFEN), chess.White); to.Draw || to.Score() != "0-1" {
+		t.Errorf("timeout %+v (%s), want a loss for White", to, to.Score())
+	}
+}
+
+// TestClock_FlagAgainstInsufficientMaterial validates the draw on a flag fall.
+// Gherkin: "A flag fall against an opponent without mating material is a draw"
+func TestClock_FlagAgainstInsufficientMaterial(t *testing.T) {
+	// White to move and flagged; Black's material decides.
+	for fen, draw := range map[string]bool{
+		"4k3/8/8/8/8/8/8/R3K3 w - - 0 1":      true,  // lone king
+		"4k3/8/8/3n4/8/8/8/4K3 w - - 0 1":     true,  // king and knight against a lone king
+		"4k3/8/8/3n4/8/8/8/R3K3 w - - 0 1":    false, // the rook may block its own king in
+		"4k3/2b5/8/4b3/8/8/8/4K3 w - - 0 1":   true,  // bishops on one colour
+		"4k3/2b5/8/4b3/8/8/4B3/4K3 w - - 0 1": false, // White's bishop is on the other colour
+		"4k3/8/8/8/8/8/3p4/4K3 w - - 0 1":     false, // a pawn
+		"4k3/8/8/2nn4/8/8/8/4K3 w - - 0 1":    false, // two knights
+	} {
+		to := clock.TimeoutIn(mustGame(t, fen), chess.White)
+		if to.Draw != draw {
+			t.Errorf("%s: draw = %v, want %v", fen, to.Draw, draw)
+		}
+		if want := map[bool]string{true: "1/2-1/2", false: "0-1"}[draw]; to.Score() != want {
+			t.Errorf("%s: score %s, want %s", fen, to.Score(), want)
+		}
+	}
+}
+
+// ─── Display ──────────────────────────────────────────────────────────────────
+
+// TestClock_Format validates the clock display.
+// Gherkin: "Clocks are displayed in minutes, hours or tenths"
+func TestClock_Format(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		5 * time.Minute: "5:00",
+		4*time.Minute + 59*time.Second + time.Millisecond: "5:00",
+		90 * time.Minute:        "1:30:00",
+		9450 * time.Millisecond: "0:09.5",
+		-time.Second:            "0:00",
+	} {
+		if got := clock.Format(d); got != want {
+			t.Errorf("Format(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+// TestClock_TUIFlagFallWhileThinking validates the live clock and flag in the TUI.
+// Gherkin: "Player's flag falls while thinking in the TUI"
+func TestClock_TUIFlagFallWhileThinking(t *testing.T) {
+	s := tui.DefaultSetup()
+	s.Clock = clock.Control{{Time: 300 * time.Millisecond}}
+	rendered := playTUIWaiting(t, s)
+	if !strings.Contains(rendered, "White lost on time. 0-1") {
+		t.Errorf("the player's flag fell without a loss on time:\n%s", rendered)
+	}
+	if !strings.Contains(rendered, "\x1b7") || !strings.Contains(rendered, "\x1b8") {
+		t.Error("the running clock must be redrawn in place on a terminal")
+	}
+}
+
+// TestClock_TUIFlagFallAgainstLoneKing validates the draw on a flag fall in the TUI.
+// Gherkin: "Player's flag falls against a lone king in the TUI"
+func TestClock_TUIFlagFallAgainstLoneKing(t *testing.T) {
+	s := tui.DefaultSetup()
+	s.Start = mustGame(t, "4k3/8/8/8/8/8/8/R3K3 w - - 0 1")
+	s.Clock = clock.Control{{Time: 50 * time.Millisecond}}
+	rendered := playTUIWaiting(t, s)
+	if !strings.Contains(rendered, "White ran out of time, but Black cannot checkmate. Draw. 1/2-1/2") {
+		t.Errorf("want a draw against the lone king:\n%s", rendered)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// mustControl parses a time control.
+func mustControl(t *testing.T, s string) clock.Control {
+	t.Helper()
+	c, err := clock.Parse(s)
+	if err != nil {
+		t.Fatalf("clock.Parse(%q): %v", s, err)
+	}
+	return c
+}
+
+// fakeNow returns a time source and a function advancing it.
+func fakeNow() (func() time.Time, func(time.Duration)) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+// assertDelayCharges checks that a 5-minute clock with a 5-second delay
+// charges nothing for a 3-second move and 3 seconds for an 8-second one.
+func assertDelayCharges(t *testing.T, control string) {
+	t.Helper()
+	now, advance := fakeNow()
+	c := clock.New(mustControl(t, control), now)
+	c.Start(chess.White)
+	for _, step := range []struct{ took, want time.Duration }{
+		{3 * time.Second, 5 * time.Minute},
+		{8 * time.Second, 4*time.Minute + 57*time.Second},
+	} {
+		advance(step.took)
+		c.Press() // White
+		c.Press() // Black, instantly
+		if got := c.Remaining(chess.White); got != step.want {
+			t.Errorf("%s: after a %v move White has %v, want %v", control, step.took, got, step.want)
+		}
+	}
+}
+
+// playTUIWaiting runs a TUI game on a terminal whose player never moves, and
+// returns its output once the game is over.
+func playTUIWaiting(t *testing.T, s tui.Setup) string {
+	t.Helper()
+	r, w := io.Pipe()
+	t.Cleanup(func() { _ = w.Close() })
+	var out bytes.Buffer
+	tui.NewGameWithSetup(r, &out, noOpEngine(t), s).WithTerminal(true).Run()
+	return out.String()
+}
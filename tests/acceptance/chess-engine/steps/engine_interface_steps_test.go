@@ -0,0 +1,237 @@
+// engine_interface_steps_test.go — Executable specifications for the engine
+// interface and external UCI engines.
+//
+// Mirrors: engine-interface.feature
+// Driving ports:
+//   - engine.Engine, implemented by engine.Builtin and engine.UCIEngine
+//   - chess-go -engine (CLI)
+
+package acceptance_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ─── Built-in Engine ──────────────────────────────────────────────────────────
+
+// TestEngineInterface_BuiltinSearch validates SetPosition, Search and info.
+// Gherkin: "Front-end searches a position with the built-in engine"
+func TestEngineInterface_BuiltinSearch(t *testing.T) {
+	var e engine.Engine = engine.NewBuiltin()
+	defer e.Close()
+	start := mustGame(t, StartingFEN)
+	moves := []chess.Move{mustParseUCI(t, start, "e2e4")}
+	after, _ := start.Apply(moves[0])
+	moves = append(moves, mustParseUCI(t, after, "e7e5"))
+	if err := e.SetPosition(start, moves); err != nil {
+		t.Fatalf("SetPosition: %v", err)
+	}
+
+	var depths []int
+	res, err := e.Search(context.Background(), engine.TimeControl{Depth: 3}, func(info engine.SearchResult) {
+		depths = append(depths, info.Depth)
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	g, _ := after.Apply(moves[1])
+	assertMoveIsLegal(t, res.BestMove, g.LegalMoves())
+	if len(res.PV) == 0 || res.PV[0] != res.BestMove {
+		t.Errorf("PV %v does not start with %s", res.PV, res.BestMove.UCIString())
+	}
+	if !slices.Equal(depths, []int{1, 2, 3}) {
+		t.Errorf("info reported depths %v, want [1 2 3]", depths)
+	}
+}
+
+// TestEngineInterface_BuiltinStop validates Stop from another goroutine.
+// Gherkin: "Front-end stops an infinite search"
+func TestEngineInterface_BuiltinStop(t *testing.T) {
+	e := engine.NewBuiltin()
+	defer e.Close()
+	time.AfterFunc(100*time.Millisecond, e.Stop)
+	start := time.Now()
+	res, err := e.Search(context.Background(), engine.TimeControl{Infinite: true}, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("search ran %v after Stop", elapsed)
+	}
+	assertMoveIsLegal(t, res.BestMove, mustGame(t, StartingFEN).LegalMoves())
+}
+
+// TestEngineInterface_BuiltinOptions validates option validation.
+// Gherkin: "Front-end sets options on the built-in engine"
+func TestEngineInterface_BuiltinOptions(t *testing.T) {
+	e := engine.NewBuiltin()
+	for _, o := range []struct{ name, value string }{{"Skill Level", "5"}, {"Threads", "1"}} {
+		if err := e.SetOption(o.name, o.value); err != nil {
+			t.Errorf("%s: %v", o.name, err)
+		}
+	}
+	if err := e.SetOption("Threads", "4"); !errors.Is(err, engine.ErrInvalidOption) {
+		t.Errorf("Threads 4: err = %v, want ErrInvalidOption", err)
+	}
+	for _, name := range []string{"Hash", "Ponder"} {
+		if err := e.SetOption(name, "true"); !errors.Is(err, engine.ErrUnknownOption) {
+			t.Errorf("%s: err = %v, want ErrUnknownOption", name, err)
+		}
+	}
+}
+
+// ─── External UCI Engines ─────────────────────────────────────────────────────
+
+// TestEngineInterface_ExternalChessGo validates the handshake and a search.
+// Gherkin: "Front-end plays through an external UCI engine"
+func TestEngineInterface_ExternalChessGo(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	e, err := engine.StartUCIEngine(bin, "uci")
+	if err != nil {
+		t.Fatalf("StartUCIEngine: %v", err)
+	}
+	defer e.Close()
+	if e.Name() != "chess-go" {
+		t.Errorf("name %q, want chess-go", e.Name())
+	}
+	if !slices.ContainsFunc(e.Advertised(), func(o engine.Option) bool { return o.Name == "Skill Level" }) {
+		t.Errorf("advertised options %v lack Skill Level", e.Advertised())
+	}
+	if err := e.NewGame(context.Background()); err != nil {
+		t.Fatalf("NewGame: %v", err)
+	}
+	g := mustGame(t, KiwipeteFEN)
+	if err := e.SetPosition(g, nil); err != nil {
+		t.Fatalf("SetPosition: %v", err)
+	}
+	infos := 0
+	res, err := e.Search(context.Background(), engine.TimeControl{Depth: 2}, func(engine.SearchResult) { infos++ })
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	assertMoveIsLegal(t, res.BestMove, g.LegalMoves())
+	if infos != 2 || res.Depth != 2 {
+		t.Errorf("%d info callbacks, depth %d; want 2 and 2", infos, res.Depth)
+	}
+}
+
+// TestEngineInterface_OptionPassthrough validates SetOption on an external engine.
+// Gherkin: "Front-end passes options through to the external engine"
+func TestEngineInterface_OptionPassthrough(t *testing.T) {
+	e := mustStartFakeUCI(t)
+	if err := e.SetOption("pick", "3"); err != nil {
+		t.Fatalf("SetOption: %v", err)
+	}
+	if err := e.SetOption("Hash", "64"); !errors.Is(err, engine.ErrUnknownOption) {
+		t.Errorf("Hash: err = %v, want ErrUnknownOption", err)
+	}
+	g := mustGame(t, StartingFEN)
+	if want := g.LegalMoves()[3]; mustSearch(t, e, g) != want {
+		t.Errorf("engine did not play the fourth legal move %s", want.UCIString())
+	}
+}
+
+// TestEngineInterface_ExternalStop validates Stop on an infinite search.
+// Gherkin: "Front-end stops an external engine's infinite search"
+func TestEngineInterface_ExternalStop(t *testing.T) {
+	e := mustStartFakeUCI(t)
+	time.AfterFunc(100*time.Millisecond, e.Stop)
+	res, err := e.Search(context.Background(), engine.TimeControl{Infinite: true}, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	assertMoveIsLegal(t, res.BestMove, mustGame(t, StartingFEN).LegalMoves())
+}
+
+// TestEngineInterface_CrashRecovery validates restarting a crashed engine.
+// Gherkin: "Front-end recovers from an engine crash"
+func TestEngineInterface_CrashRecovery(t *testing.T) {
+	e := mustStartFakeUCI(t, "-fail", "crash", "-once", filepath.Join(t.TempDir(), "crashed"))
+	if err := e.SetOption("Pick", "5"); err != nil {
+		t.Fatalf("SetOption: %v", err)
+	}
+	g := mustGame(t, StartingFEN)
+	if _, err := e.Search(context.Background(), engine.TimeControl{Depth: 1}, nil); !errors.Is(err, engine.ErrEngine) {
+		t.Fatalf("first search: err = %v, want ErrEngine", err)
+	}
+	if got, want := mustSearch(t, e, g), g.LegalMoves()[5]; got != want {
+		t.Errorf("restarted engine played %s, want %s with Pick set again", got.UCIString(), want.UCIString())
+	}
+	if e.Restarts() != 1 {
+		t.Errorf("%d restarts, want 1", e.Restarts())
+	}
+}
+
+// TestEngineInterface_HangRecovery validates killing an engine that overruns.
+// Gherkin: "Front-end recovers from a hung engine"
+func TestEngineInterface_HangRecovery(t *testing.T) {
+	e := mustStartFakeUCI(t, "-fail", "hang", "-once", filepath.Join(t.TempDir(), "hung"))
+	start := time.Now()
+	_, err := e.Search(context.Background(), engine.TimeControl{MoveTime: 100 * time.Millisecond}, nil)
+	if !errors.Is(err, engine.ErrEngine) {
+		t.Fatalf("first search: err = %v, want ErrEngine", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("hung engine detected after %v", elapsed)
+	}
+	assertMoveIsLegal(t, mustSearch(t, e, mustGame(t, StartingFEN)), mustGame(t, StartingFEN).LegalMoves())
+}
+
+// TestEngineInterface_TerminalAgainstExternalEngine validates chess-go -engine.
+// Gherkin: "Player plays the terminal game against an external engine"
+func TestEngineInterface_TerminalAgainstExternalEngine(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	fake := mustBuildFakeUCI(t)
+	cmd := exec.Command(bin, "-engine", fake, "-option", "Pick=2")
+	cmd.Stdin = strings.NewReader("e2e4\n")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("chess-go -engine: %v\n%s", err, out)
+	}
+	// With Pick=2 the fake engine answers with the third legal move.
+	g, _ := mustGame(t, StartingFEN).Apply(mustParseUCI(t, mustGame(t, StartingFEN), "e2e4"))
+	if want := "Engine plays " + g.LegalMoves()[2].SANString(g); !strings.Contains(string(out), want) {
+		t.Errorf("output lacks %q:\n%s", want, out)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+func mustBuildFakeUCI(t *testing.T) string {
+	t.Helper()
+	return mustBuildBinary(t, "./testdata/fakeuci")
+}
+
+// mustStartFakeUCI starts the fake engine of testdata/fakeuci with args.
+func mustStartFakeUCI(t *testing.T, args ...string) *engine.UCIEngine {
+	t.Helper()
+	e, err := engine.StartUCIEngine(mustBuildFakeUCI(t), args...)
+	if err != nil {
+		t.Fatalf("StartUCIEngine: %v", err)
+	}
+	t.Cleanup(func() { _ = e.Close() })
+	return e
+}
+
+// mustSearch sets g as the position of e and returns its move at depth 1.
+func mustSearch(t *testing.T, e engine.Engine, g chess.Game) chess.Move {
+	t.Helper()
+	if err := e.SetPosition(g, nil); err != nil {
+		t.Fatalf("SetPosition: %v", err)
+	}
+	res, err := e.Search(context.Background(), engine.TimeControl{Depth: 1}, nil)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	return res.BestMove
+}
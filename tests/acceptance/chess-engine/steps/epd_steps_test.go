@@ -0,0 +1,255 @@
+// epd_steps_test.go — Executable specifications for EPD test suites.
+//
+// Mirrors: epd-test-suites.feature
+// Driving ports:
+//   - chess.ParseEPD / chess.ReadEPD / EPD.Moves / EPD.String
+//   - suite.Run / suite.WriteJUnit
+//   - chess-go epd (CLI)
+
+package acceptance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/suite"
+)
+
+// ─── EPD Parsing ──────────────────────────────────────────────────────────────
+
+// TestEPD_ParsesOperations validates opcodes, quoting and clocks.
+// Gherkin: "Engine developer parses an EPD record with operations"
+func TestEPD_ParsesOperations(t *testing.T) {
+	line := `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001"; c0 "mate; quick"; acd 7; hmvc 3; fmvn 20;`
+	e, err := chess.ParseEPD(line)
+	if err != nil {
+		t.Fatalf("ParseEPD: %v", err)
+	}
+	if got := e.Game.ToFEN(); got != "2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - 3 20" {
+		t.Errorf("position = %q", got)
+	}
+	if e.ID() != "WAC.001" {
+		t.Errorf("id = %q", e.ID())
+	}
+	if c0, _ := e.Op("c0"); len(c0) != 1 || c0[0] != "mate; quick" {
+		t.Errorf("c0 = %q", c0)
+	}
+	if acd, ok := e.Int("acd"); !ok || acd != 7 {
+		t.Errorf("acd = %d, %v", acd, ok)
+	}
+	bm, err := e.Moves("bm")
+	if err != nil || len(bm) != 1 || bm[0].SANString(e.Game) != "Qg6" {
+		t.Errorf("bm = %v, %v", bm, err)
+	}
+	if e.String() != line {
+		t.Errorf("String() = %q, want %q", e.String(), line)
+	}
+}
+
+// TestEPD_RefusesMalformedRecords validates the ErrInvalidEPD paths.
+// Gherkin: "Engine developer sees malformed records refused"
+func TestEPD_RefusesMalformedRecords(t *testing.T) {
+	for _, line := range []string{
+		"8/8/8/8/8/8/8/K6k w -",
+		"6k1/5ppp/8/8/8/8/8/R5K1 w - - bm Ra8#",
+		`6k1/5ppp/8/8/8/8/8/R5K1 w - - id "unterminated;`,
+	} {
+		if _, err := chess.ParseEPD(line); !errors.Is(err, chess.ErrInvalidEPD) {
+			t.Errorf("ParseEPD(%q): err = %v, want ErrInvalidEPD", line, err)
+		}
+	}
+	e, err := chess.ParseEPD("6k1/5ppp/8/8/8/8/8/R5K1 w - - bm Qh5;")
+	if err != nil {
+		t.Fatalf("ParseEPD: %v", err)
+	}
+	if _, err := e.Moves("bm"); !errors.Is(err, chess.ErrInvalidEPD) {
+		t.Errorf("an illegal bm: err = %v, want ErrInvalidEPD", err)
+	}
+}
+
+// TestEPD_BundledSuiteIsValid validates data/tactics.epd.
+// Gherkin: "Engine developer loads the bundled tactical suite"
+func TestEPD_BundledSuiteIsValid(t *testing.T) {
+	f, err := os.Open(filepath.Join(projectRoot(t), "data", "tactics.epd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := chess.ReadEPD(f)
+	if err != nil {
+		t.Fatalf("ReadEPD: %v", err)
+	}
+	if len(records) < 10 {
+		t.Fatalf("suite holds %d records, want at least 10", len(records))
+	}
+	for _, e := range records {
+		if bm, err := e.Moves("bm"); err != nil || len(bm) == 0 || e.ID() == "" {
+			t.Errorf("%s: id %q, bm %v, %v", e, e.ID(), bm, err)
+		}
+	}
+}
+
+// ─── Running Suites ───────────────────────────────────────────────────────────
+
+// TestSuite_SolvesAtFixedDepth validates a real search over quick tactics.
+// Gherkin: "Engine developer runs a suite at a fixed depth"
+func TestSuite_SolvesAtFixedDepth(t *testing.T) {
+	records := mustReadEPD(t, `2rr3k/pp3pp1/1nnqbN1p/3pN3/2pP4/2P3Q1/PPB4P/R4RK1 w - - bm Qg6; id "WAC.001";
+r1bq2rk/pp3pbp/2p1p1pQ/7P/3P4/2PB1N2/PP3PPR/2KR4 w - - bm Qxh7+; id "WAC.004";
+5k2/6pp/p1qN4/1p1p4/3P4/2PKP2Q/PP3r2/3R4 b - - bm Qc4+; id "WAC.005";
+r1bqkbnr/pppp1ppp/2n5/4p3/2B1P3/5Q2/PPPP1PPP/RNB1K1NR w KQkq - bm Qxf7#; id "scholar";
+6k1/5ppp/8/8/8/8/8/R5K1 w - - bm Ra8#; id "backrank";
+`)
+	var progress int
+	rep := suite.Run(context.Background(), "quick", records, suite.Options{
+		Limit:    engine.TimeControl{Depth: 4},
+		Progress: func(suite.Result) { progress++ },
+	})
+	solved, scored := rep.Solved()
+	if solved != 5 || scored != 5 {
+		for _, r := range rep.Results {
+			if !r.Solved {
+				t.Logf("%s: %s", r.ID, r.Expected())
+			}
+		}
+		t.Errorf("solved %d/%d, want 5/5", solved, scored)
+	}
+	if progress != 5 {
+		t.Errorf("progress was reported %d times, want 5", progress)
+	}
+	for _, r := range rep.Results {
+		if r.Search.Elapsed <= 0 || r.Search.Depth == 0 {
+			t.Errorf("%s: no timing or depth recorded", r.ID)
+		}
+	}
+}
+
+// TestSuite_ScoresAvoidMovesAndPoints validates am and STS c0 scoring.
+// Gherkin: "Engine developer scores avoid-move and STS-style positions"
+func TestSuite_ScoresAvoidMovesAndPoints(t *testing.T) {
+	records := mustReadEPD(t, `rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - am e4; id "avoid";
+rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm d4; c0 "d4=10, e4=5, Nf3=3"; id "sts";
+`)
+	rep := suite.Run(context.Background(), "scoring", records, suite.Options{Search: playing("e2e4")})
+	avoid, sts := rep.Results[0], rep.Results[1]
+	if !avoid.Scored || avoid.Solved {
+		t.Errorf("playing the am move: scored %v, solved %v; want an unsolved position", avoid.Scored, avoid.Solved)
+	}
+	if sts.Solved || sts.Points != 5 || sts.Max != 10 {
+		t.Errorf("sts: solved %v, %d of %d points; want unsolved with 5 of 10", sts.Solved, sts.Points, sts.Max)
+	}
+	if points, possible := rep.Points(); points != 5 || possible != 10 {
+		t.Errorf("suite points %d/%d, want 5/10", points, possible)
+	}
+}
+
+// TestSuite_UsesACDAsDepth validates the acd fallback.
+// Gherkin: "Engine developer lets the suite choose the depth"
+func TestSuite_UsesACDAsDepth(t *testing.T) {
+	records := mustReadEPD(t, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - acd 2; id \"start\";\n")
+	rep := suite.Run(context.Background(), "acd", records, suite.Options{})
+	if got := rep.Results[0].Search.Depth; got != 2 {
+		t.Errorf("searched to depth %d, want 2", got)
+	}
+}
+
+// TestSuite_WritesJUnitReport validates the JUnit XML document.
+// Gherkin: "Engine developer publishes a JUnit report"
+func TestSuite_WritesJUnitReport(t *testing.T) {
+	records := mustReadEPD(t, `rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm e4; id "solved";
+rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm d4; id "unsolved";
+rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - id "unscored";
+rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - bm d4; id "known";
+`)
+	rep := suite.Run(context.Background(), "junit.epd", records,
+		suite.Options{Search: playing("e2e4"), ExpectFail: []string{"known"}})
+	var buf bytes.Buffer
+	if err := suite.WriteJUnit(&buf, []suite.Report{rep}); err != nil {
+		t.Fatalf("WriteJUnit: %v", err)
+	}
+	var doc struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"failure"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("report is not XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Skipped != 2 || len(doc.Suites) != 1 {
+		t.Fatalf("tests %d, failures %d, skipped %d, suites %d; want 4, 1, 2, 1",
+			doc.Tests, doc.Failures, doc.Skipped, len(doc.Suites))
+	}
+	failure := doc.Suites[0].Cases[1].Failure
+	if failure == nil || !strings.Contains(failure.Message, "expected bm d4") {
+		t.Errorf("unsolved testcase failure = %+v, want it to name bm d4", failure)
+	}
+}
+
+// TestSuite_CLIExitStatus validates the exit status of chess-go epd.
+// Gherkin: "Engine developer sees CI fail on an unsolved position"
+func TestSuite_CLIExitStatus(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	out, err := exec.Command(bin, "epd", "-depth", "6", "-xfail", "WAC.002,WAC.003",
+		filepath.Join(projectRoot(t), "data", "tactics.epd")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("chess-go epd data/tactics.epd: %v, want every other position solved at depth 6\n%s", err, out)
+	}
+	for _, id := range []string{"WAC.002", "WAC.003"} {
+		if !regexp.MustCompile(id + `.*(xfail|expected to fail)`).Match(out) {
+			t.Errorf("%s is not reported as an expected failure:\n%s", id, out)
+		}
+	}
+
+	// The engine always mates in one, so a record avoiding the mate is unsolved.
+	path := filepath.Join(t.TempDir(), "unsolved.epd")
+	if err := os.WriteFile(path, []byte(`6k1/5ppp/8/8/8/8/8/R5K1 w - - am Ra8#; id "backrank";`+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err = exec.Command(bin, "epd", "-depth", "1", path).CombinedOutput()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+		t.Errorf("chess-go epd on an unsolved position: %v, want exit status 1\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "FAIL") {
+		t.Errorf("the unsolved position is not reported:\n%s", out)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+func mustReadEPD(t *testing.T, text string) []chess.EPD {
+	t.Helper()
+	records, err := chess.ReadEPD(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("ReadEPD: %v", err)
+	}
+	return records
+}
+
+// playing returns a search that always plays the given UCI move.
+func playing(uci string) engine.SearchFunc {
+	return func(_ context.Context, g chess.Game, _ engine.TimeControl, _ io.Writer) engine.SearchResult {
+		m, _ := chess.ParseUCI(g, uci)
+		return engine.SearchResult{BestMove: m, Depth: 1}
+	}
+}
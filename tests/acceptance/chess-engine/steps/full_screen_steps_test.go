@@ -0,0 +1,275 @@
+// full_screen_steps_test.go — Executable specifications for the full-screen TUI.
+//
+// Mirrors: full-screen-tui.feature
+// Driving ports:
+//   - tui.Game.WithFullScreen, tui.Game.WithSearch
+//   - the chess-go binary with -fullscreen
+
+package acceptance_test
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tui"
+)
+
+// Keys as a terminal sends them.
+const (
+	keyUp    = "\x1b[A"
+	keyDown  = "\x1b[B"
+	keyRight = "\x1b[C"
+	keyLeft  = "\x1b[D"
+	keyEnter = "\r"
+)
+
+// ─── Moving Pieces ────────────────────────────────────────────────────────────
+
+// TestFullScreen_PickUpShowsDestinations validates picking up a piece.
+// Gherkin: "Player picks up a piece and sees its legal destinations"
+func TestFullScreen_PickUpShowsDestinations(t *testing.T) {
+	out, _ := playFullScreen(t, tui.DefaultSetup(), keyEnter+keyUp, noOpEngine(t), 100, 30)
+	frame := lastFrame(out)
+	if !strings.Contains(frame, "3 | . | . | . | . |[*]| . | . | . |") {
+		t.Errorf("want the cursor on e3, a destination:\n%s", frame)
+	}
+	if !strings.Contains(frame, "4 | . | . | . | . | * | . | . | . |") {
+		t.Errorf("want e4 marked as a destination:\n%s", frame)
+	}
+	if !strings.Contains(frame, "2 | P | P | P | P |<P>| P | P | P |") {
+		t.Errorf("want the pawn on e2 picked up:\n%s", frame)
+	}
+}
+
+// TestFullScreen_DestinationsAreLegalMoves validates the destinations.
+// Gherkin: "Destinations come from the legal moves"
+func TestFullScreen_DestinationsAreLegalMoves(t *testing.T) {
+	out, _ := playFullScreen(t, tui.DefaultSetup(), keyRight+keyRight+keyDown+keyEnter, noOpEngine(t), 100, 30)
+	frame := lastFrame(out)
+	if !strings.Contains(frame, "3 | . | . | . | . | . | * | . | * |") {
+		t.Errorf("want f3 and h3 marked:\n%s", frame)
+	}
+	if n := strings.Count(frame, "*"); n != 2 {
+		t.Errorf("%d squares marked, want 2:\n%s", n, frame)
+	}
+	if !strings.Contains(frame, "1 | R | N | B | Q | K | B |[N]| R |") {
+		t.Errorf("want the cursor on the knight:\n%s", frame)
+	}
+}
+
+// TestFullScreen_PlayMoveWithArrows validates playing a move.
+// Gherkin: "Player plays a move with the arrow keys"
+func TestFullScreen_PlayMoveWithArrows(t *testing.T) {
+	out, _ := playFullScreen(t, tui.DefaultSetup(), keyEnter+keyUp+keyUp+keyEnter, scriptedEngine(t, "e7e5"), 100, 30)
+	frame := lastFrame(out)
+	if !strings.Contains(frame, "5 | . | . | . | . | p | . | . | . |") ||
+		!strings.Contains(frame, "4 | . | . | . | . |[P]| . | . | . |") {
+		t.Errorf("want 1.e4 e5 on the board, the cursor on e4:\n%s", frame)
+	}
+	if !strings.Contains(frame, "  1. e4      e5") {
+		t.Errorf("want the move list:\n%s", frame)
+	}
+	if !strings.Contains(frame, "White to move") {
+		t.Errorf("want White to move:\n%s", frame)
+	}
+}
+
+// TestFullScreen_IllegalDrop validates a drop on an unreachable square.
+// Gherkin: "Player puts a piece down on a square it cannot reach"
+func TestFullScreen_IllegalDrop(t *testing.T) {
+	out, _ := playFullScreen(t, tui.DefaultSetup(), keyEnter+keyUp+keyUp+keyUp+keyEnter, noOpEngine(t), 100, 30)
+	frame := lastFrame(out)
+	if !strings.Contains(frame, "Illegal move: e2e5") {
+		t.Errorf("want the illegal move reported:\n%s", frame)
+	}
+	if !strings.Contains(frame, "<P>") {
+		t.Errorf("want the pawn still picked up:\n%s", frame)
+	}
+}
+
+// TestFullScreen_Promotion validates choosing the promotion piece.
+// Gherkin: "Player chooses the piece to promote to"
+func TestFullScreen_Promotion(t *testing.T) {
+	s := tui.DefaultSetup()
+	s.Start = mustGame(t, "8/4P1k1/8/8/8/8/8/4K3 w - - 0 1")
+	keys := strings.Repeat(keyUp, 5) + keyEnter + keyUp + keyEnter
+	out, _ := playFullScreen(t, s, keys, noOpEngine(t), 100, 30)
+	if frame := lastFrame(out); !strings.Contains(frame, "Promote to: q, r, b or n") {
+		t.Fatalf("want the promotion choice:\n%s", frame)
+	}
+	out, _ = playFullScreen(t, s, keys+"n", scriptedEngine(t, "g7g6"), 100, 30)
+	frame := lastFrame(out)
+	if !strings.Contains(frame, "8 | . | . | . | . |[N]|") || !strings.Contains(frame, "1. e8=N+   Kg6") {
+		t.Errorf("want e8=N+ played:\n%s", frame)
+	}
+}
+
+// ─── Panels ───────────────────────────────────────────────────────────────────
+
+// TestFullScreen_Panels validates the side panels.
+// Gherkin: "Panels show the clocks, captured pieces and the engine's eval"
+func TestFullScreen_Panels(t *testing.T) {
+	s := tui.DefaultSetup()
+	s.Start = mustGame(t, "4k3/8/8/3p4/4P3/8/8/4K3 w - - 0 1")
+	s.Clock = mustControl(t, "5+0")
+	search := func(g chess.Game, _ engine.TimeControl, info func(engine.SearchResult)) engine.SearchResult {
+		pv := []chess.Move{mustParseUCI(t, g, "e8e7")}
+		after, _ := g.Apply(pv[0])
+		pv = append(pv, mustParseUCI(t, after, "e1e2"))
+		info(engine.SearchResult{BestMove: pv[0], Score: -50, Depth: 1, PV: pv[:1]})
+		return engine.SearchResult{BestMove: pv[0], Score: -120, Depth: 5, PV: pv}
+	}
+	term := newFakeTerminal(100, 30)
+	var out bytes.Buffer
+	tui.NewGameWithSetup(strings.NewReader(keyUp+keyUp+keyEnter+keyUp+keyLeft+keyEnter), &out, noOpEngine(t), s).
+		WithSearch(search).WithFullScreen(term).Run()
+	frame := lastFrame(out.String())
+	for _, want := range []string{"Black 5:00", "White 5:00", "White: p  +1", "Black:\n", "1. exd5    Ke7", "Engine +1.20  depth 5", "1...Ke7 2.Ke2"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("panel must show %q:\n%s", want, frame)
+		}
+	}
+}
+
+// ─── Terminal ─────────────────────────────────────────────────────────────────
+
+// TestFullScreen_Resize validates laying the screen out again.
+// Gherkin: "The screen is laid out again when the terminal is resized"
+func TestFullScreen_Resize(t *testing.T) {
+	term := newFakeTerminal(100, 30)
+	resizing := func(g chess.Game, _ engine.TimeControl) chess.Move {
+		term.resize(40, 60)
+		return mustParseUCI(t, g, "e7e5")
+	}
+	var out bytes.Buffer
+	tui.NewGameWithSetup(strings.NewReader(keyEnter+keyUp+keyUp+keyEnter), &out, resizing, tui.DefaultSetup()).
+		WithFullScreen(term).Run()
+	frames := strings.Split(stripANSI(out.String()), "\x1b[H")
+	if first := frames[1]; !strings.Contains(first, "   Captured") {
+		t.Errorf("want the panel beside the board on a wide terminal:\n%s", first)
+	}
+	if last := lastFrame(out.String()); !strings.Contains(last, "\nCaptured\n") || !strings.Contains(last, "  1. e4      e5") {
+		t.Errorf("want the panel below the board on a narrow terminal:\n%s", last)
+	}
+
+	out.Reset()
+	tui.NewGame(strings.NewReader(""), &out, noOpEngine(t)).WithFullScreen(newFakeTerminal(30, 10)).Run()
+	if frame := lastFrame(out.String()); !strings.Contains(frame, "The terminal is too small") {
+		t.Errorf("want a too-small terminal reported:\n%s", frame)
+	}
+}
+
+// TestFullScreen_RestoresTerminal validates restoring the terminal.
+// Gherkin: "The terminal is restored when the player quits or the game panics"
+func TestFullScreen_RestoresTerminal(t *testing.T) {
+	out, term := playFullScreen(t, tui.DefaultSetup(), keyEnter+"q", noOpEngine(t), 100, 30)
+	if !strings.HasPrefix(out, "\x1b[?1049h") || !strings.HasSuffix(out, "\x1b[?25h\x1b[?1049lGame abandoned.\n") {
+		t.Errorf("want the alternate screen entered and left, then the game abandoned: %q", out)
+	}
+	if term.restored != 1 {
+		t.Errorf("terminal restored %d times, want 1", term.restored)
+	}
+
+	term = newFakeTerminal(100, 30)
+	var buf bytes.Buffer
+	crashing := func(chess.Game, engine.TimeControl) chess.Move { panic("engine crashed") }
+	s := tui.DefaultSetup()
+	s.Human = chess.Black
+	func() {
+		defer func() {
+			if p := recover(); p != "engine crashed" {
+				t.Errorf("recovered %v, want the engine's panic", p)
+			}
+		}()
+		tui.NewGameWithSetup(strings.NewReader(""), &buf, crashing, s).WithFullScreen(term).Run()
+	}()
+	if term.restored != 1 || !strings.HasSuffix(buf.String(), "\x1b[?1049l") {
+		t.Errorf("terminal restored %d times, output ends %q; want restored before the panic", term.restored, buf.String()[max(buf.Len()-20, 0):])
+	}
+}
+
+// TestFullScreen_CLINeedsTerminal validates -fullscreen without a terminal.
+// Gherkin: "chess-go refuses full-screen mode without a terminal"
+func TestFullScreen_CLINeedsTerminal(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	cmd := exec.Command(bin, "-fullscreen")
+	cmd.Stdin = strings.NewReader("")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+		t.Fatalf("chess-go -fullscreen: %v, want exit status 2", err)
+	}
+	if !strings.Contains(stderr.String(), "needs a terminal") {
+		t.Errorf("stderr = %q, want it to say a terminal is needed", stderr.String())
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// fakeTerminal is a terminal of a size the test sets.
+type fakeTerminal struct {
+	mu            sync.Mutex
+	width, height int
+	resized       chan struct{}
+	restored      int
+}
+
+func newFakeTerminal(width, height int) *fakeTerminal {
+	return &fakeTerminal{width: width, height: height, resized: make(chan struct{}, 1)}
+}
+
+func (f *fakeTerminal) Size() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.width, f.height
+}
+
+func (f *fakeTerminal) Resized() <-chan struct{} { return f.resized }
+
+func (f *fakeTerminal) Restore() error {
+	f.restored++
+	return nil
+}
+
+// resize changes the terminal's size and tells the game.
+func (f *fakeTerminal) resize(width, height int) {
+	f.mu.Lock()
+	f.width, f.height = width, height
+	f.mu.Unlock()
+	select {
+	case f.resized <- struct{}{}:
+	default:
+	}
+}
+
+// playFullScreen plays a full-screen game on a width x height terminal with
+// keys as input, then returns its output and terminal.
+func playFullScreen(t *testing.T, s tui.Setup, keys string, engineFn tui.EngineFunc, width, height int) (string, *fakeTerminal) {
+	t.Helper()
+	term := newFakeTerminal(width, height)
+	var out bytes.Buffer
+	tui.NewGameWithSetup(strings.NewReader(keys), &out, engineFn, s).WithFullScreen(term).Run()
+	return out.String(), term
+}
+
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[A-GJKSTfhlm]`)
+
+// stripANSI removes the escape sequences from out, all but cursor home.
+func stripANSI(out string) string {
+	return ansiSequence.ReplaceAllString(out, "")
+}
+
+// lastFrame returns the text of the last screen drawn in out.
+func lastFrame(out string) string {
+	frames := strings.Split(stripANSI(out), "\x1b[H")
+	return frames[len(frames)-1]
+}
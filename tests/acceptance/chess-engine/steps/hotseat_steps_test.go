@@ -0,0 +1,282 @@
+// hotseat_steps_test.go — Executable specifications for hot-seat and accessible play.
+//
+// Mirrors: hot-seat-accessible.feature
+// Driving ports:
+//   - tui.Game.Run with Setup.HotSeat and Setup.AutoFlip
+//   - tui.Game.WithStyle with Style.Accessible
+//   - chess-go -hotseat -accessible
+
+package acceptance_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"chess_go/internal/clock"
+	"chess_go/internal/tui"
+)
+
+// ─── Hot Seat ─────────────────────────────────────────────────────────────────
+
+// TestHotSeat_PlayersTakeTurns validates a game between two players.
+// Gherkin: "Two players take turns at one terminal"
+func TestHotSeat_PlayersTakeTurns(t *testing.T) {
+	out := playHotSeat(t, hotSeatSetup(t, StartingFEN), "f2f3\ne7e5\ng2g4\nd8h4\n", tui.Style{})
+	if n := strings.Count(out, "White's move: "); n != 2 {
+		t.Errorf("White prompted %d times, want 2:\n%s", n, out)
+	}
+	if n := strings.Count(out, "Black's move: "); n != 2 {
+		t.Errorf("Black prompted %d times, want 2:\n%s", n, out)
+	}
+	if strings.Contains(out, "Engine") || strings.Contains(out, "Your move") {
+		t.Errorf("want no engine and no single player in a hot-seat game:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "Checkmate! Black wins. 0-1\n") {
+		t.Errorf("want Black to win by checkmate:\n%s", out)
+	}
+}
+
+// TestHotSeat_AutoFlip validates turning the board to the side to move.
+// Gherkin: "The board turns to the side to move"
+func TestHotSeat_AutoFlip(t *testing.T) {
+	s := hotSeatSetup(t, StartingFEN)
+	out := playHotSeat(t, s, "e2e4\n", tui.Style{})
+	if rows := lastBoardRows(t, out); !strings.HasPrefix(rows[0], "8 |") {
+		t.Errorf("top row = %q, want rank 8 without auto-flip", rows[0])
+	}
+
+	s.AutoFlip = true
+	out = playHotSeat(t, s, "e2e4\n", tui.Style{})
+	if rows := lastBoardRows(t, out); !strings.HasPrefix(rows[0], "1 | R | N | B | K | Q |") {
+		t.Errorf("top row = %q, want rank 1 with Black to move", rows[0])
+	}
+	if first := strings.Index(out, "8 | r |"); first < 0 || first > strings.Index(out, "1 | R |") {
+		t.Errorf("want the first board drawn from White's side:\n%s", out)
+	}
+}
+
+// TestHotSeat_Commands validates undo, save, resign, draw and hint in a hot-seat game.
+// Gherkin: "Commands act for the side to move"
+func TestHotSeat_Commands(t *testing.T) {
+	s := hotSeatSetup(t, StartingFEN)
+	path := filepath.Join(t.TempDir(), "game.pgn")
+	out := playHotSeat(t, s, "e2e4\ne7e5\nundo\nsave "+path+"\nresign\n", tui.Style{})
+	assertPieceOn(t, out, "e4", 'P')
+	assertPieceOn(t, out, "e5", '.')
+	if !strings.HasSuffix(out, "Black's move: Black resigns. White wins. 1-0\n") {
+		t.Errorf("want Black, to move, to resign:\n%s", out)
+	}
+	pgn, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("saved game: %v", err)
+	}
+	for _, want := range []string{`[White "Player 1"]`, `[Black "Player 2"]`, "1. e4 *"} {
+		if !strings.Contains(string(pgn), want) {
+			t.Errorf("saved PGN lacks %q:\n%s", want, pgn)
+		}
+	}
+
+	out = playHotSeat(t, s, "e2e4\ndraw\nyes\n", tui.Style{})
+	if !strings.Contains(out, "White, do you accept a draw? (y/n): Draw agreed. 1/2-1/2\n") {
+		t.Errorf("want White to agree to Black's draw offer:\n%s", out)
+	}
+	out = playHotSeat(t, s, "draw\nn\n", tui.Style{})
+	if !strings.Contains(out, "Black declines the draw.") {
+		t.Errorf("want Black to decline White's draw offer:\n%s", out)
+	}
+
+	out = playHotSeat(t, s, "hint\n", tui.Style{})
+	if !strings.Contains(out, "No hint: there is no engine in this game.") {
+		t.Errorf("want the missing engine reported:\n%s", out)
+	}
+}
+
+// TestHotSeat_UndoRestartsTheClock validates that taking a move back hands
+// the clock back to the side that moved.
+// Gherkin: "Undo gives the clock back to the side to move"
+func TestHotSeat_UndoRestartsTheClock(t *testing.T) {
+	s := hotSeatSetup(t, StartingFEN)
+	s.Clock = clock.Control{{Time: time.Minute}}
+	rows := lastBoardRows(t, playHotSeat(t, s, "e2e4\nundo\n", tui.Style{}))
+	if !strings.HasSuffix(rows[7], "White 1:00 *") {
+		t.Errorf("rank 1 = %q, want White's clock running after undo", rows[7])
+	}
+	if !strings.HasSuffix(rows[0], "Black 1:00") {
+		t.Errorf("rank 8 = %q, want Black's clock stopped after undo", rows[0])
+	}
+}
+
+// TestHotSeat_DrawOfferOnTheClock validates that the clock keeps running,
+// and can fall, while a draw offer waits for its answer.
+// Gherkin: "A draw offer does not stop the clock"
+func TestHotSeat_DrawOfferOnTheClock(t *testing.T) {
+	s := hotSeatSetup(t, StartingFEN)
+	s.Clock = clock.Control{{Time: 300 * time.Millisecond}}
+	r, w := io.Pipe()
+	t.Cleanup(func() { _ = w.Close() })
+	go func() { _, _ = io.WriteString(w, "draw\n") }()
+	var out bytes.Buffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tui.NewGameWithSetup(r, &out, nil, s).Run()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the game did not end while the draw offer went unanswered")
+	}
+	if !strings.HasSuffix(out.String(), "Black, do you accept a draw? (y/n): \nWhite lost on time. 0-1\n") {
+		t.Errorf("want White's flag to fall while Black considers the offer:\n%s", out.String())
+	}
+}
+
+// ─── Accessible Text ──────────────────────────────────────────────────────────
+
+// TestAccessible_DescribesMoves validates moves and positions in words.
+// Gherkin: "Moves and positions are described in words"
+func TestAccessible_DescribesMoves(t *testing.T) {
+	var out bytes.Buffer
+	tui.NewGame(strings.NewReader("g1f3\n"), &out, scriptedEngine(t, "e7e5")).
+		WithStyle(tui.Style{Accessible: true}).Run()
+	want := "White to move\n" +
+		"Your move: White knight from g1 to f3\n" +
+		"Black to move\n" +
+		"Engine thinking...\n" +
+		"Engine plays Black pawn from e7 to e5\n" +
+		"White to move\n" +
+		"Your move: \n"
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+// TestAccessible_DescribesMoveKinds validates the words for each kind of move.
+// Gherkin: "Captures, castling, en passant, promotion and check are described"
+func TestAccessible_DescribesMoveKinds(t *testing.T) {
+	cases := []struct {
+		fen, moves string
+		want       []string
+	}{
+		{StartingFEN, "e2e4\nd7d5\ne4d5\n", []string{"White pawn from e4 takes pawn on d5\n"}},
+		{"r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "e1g1\ne8c8\n",
+			[]string{"White castles kingside\n", "Black castles queenside\n"}},
+		{"4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", "e5d6\n", []string{"White pawn from e5 takes pawn en passant on d6\n"}},
+		{"4k3/1P6/8/8/8/8/8/4K3 w - - 0 1", "b7b8q\n", []string{"White pawn from b7 to b8, promotes to queen, check\n"}},
+		{BackRankMateFEN, "a1a8\n", []string{"White rook from a1 to a8, checkmate\n", "Checkmate! White wins. 1-0\n"}},
+	}
+	for _, c := range cases {
+		out := playHotSeat(t, hotSeatSetup(t, c.fen), c.moves, tui.Style{Accessible: true})
+		for _, want := range c.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s: output lacks %q:\n%s", c.fen, want, out)
+			}
+		}
+		if strings.Contains(out, "Check!") {
+			t.Errorf("%s: want the check in the move's description only:\n%s", c.fen, out)
+		}
+	}
+}
+
+// TestAccessible_ListsPieces validates announcing every piece.
+// Gherkin: "Player asks for all the pieces"
+func TestAccessible_ListsPieces(t *testing.T) {
+	out := playHotSeat(t, hotSeatSetup(t, KiwipeteFEN), "pieces\n", tui.Style{Accessible: true})
+	want := "White: king on e1, queen on f3, rooks on a1 and h1, bishops on d2 and e2, knights on c3 and e5, " +
+		"pawns on a2, b2, c2, f2, g2, h2, e4 and d5.\n" +
+		"Black: king on e8, queen on e7, rooks on a8 and h8, bishops on a6 and g7, knights on b6 and f6, " +
+		"pawns on h3, b4, e6, g6, a7, c7, d7 and f7.\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("output:\n%s\nwant it to contain:\n%s", out, want)
+	}
+}
+
+// TestAccessible_Clocks validates the clocks in words on a terminal.
+// Gherkin: "The clocks are read out without redrawing"
+func TestAccessible_Clocks(t *testing.T) {
+	s := tui.DefaultSetup()
+	s.Clock = clock.Control{{Time: 300 * time.Millisecond}}
+	r, w := io.Pipe()
+	t.Cleanup(func() { _ = w.Close() })
+	var out bytes.Buffer
+	tui.NewGameWithSetup(r, &out, noOpEngine(t), s).WithTerminal(true).WithStyle(tui.Style{Accessible: true}).Run()
+	if !strings.HasPrefix(out.String(), "White to move\nClocks: White ") {
+		t.Errorf("want the clocks in words:\n%q", out.String())
+	}
+	if strings.Contains(out.String(), "\x1b") {
+		t.Errorf("want no escape sequences for a screen reader:\n%q", out.String())
+	}
+	if !strings.Contains(out.String(), "White lost on time.") {
+		t.Errorf("want the flag fall announced:\n%q", out.String())
+	}
+}
+
+// ─── Command Line ─────────────────────────────────────────────────────────────
+
+// TestHotSeat_CLI validates chess-go -hotseat -accessible.
+// Gherkin: "Two players play an accessible game from the command line"
+func TestHotSeat_CLI(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	cmd := exec.Command(bin, "-hotseat", "-accessible")
+	cmd.Stdin = strings.NewReader("f3\ne5\ng4\nQh4\n")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("chess-go -hotseat -accessible: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"White's move: White pawn from f2 to f3\n",
+		"Black's move: Black queen from d8 to h4, checkmate\n",
+		"Checkmate! Black wins. 0-1\n",
+	} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if bytes.Contains(out, []byte("+---")) {
+		t.Errorf("want no board drawn:\n%s", out)
+	}
+}
+
+// TestAccessible_CLIRejectsBoardFlags validates -accessible with board flags.
+// Gherkin: "Board flags are refused in an accessible game"
+func TestAccessible_CLIRejectsBoardFlags(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	for _, flag := range []string{"-coords", "-flip", "-fullscreen"} {
+		cmd := exec.Command(bin, "-accessible", flag)
+		cmd.Stdin = strings.NewReader("")
+		out, err := cmd.CombinedOutput()
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+			t.Errorf("chess-go -accessible %s: %v, want exit status 2", flag, err)
+		}
+		if want := "-accessible draws no board and cannot be used with " + flag; !bytes.Contains(out, []byte(want)) {
+			t.Errorf("chess-go -accessible %s: output lacks %q:\n%s", flag, want, out)
+		}
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// hotSeatSetup returns a hot-seat game from fen.
+func hotSeatSetup(t *testing.T, fen string) tui.Setup {
+	t.Helper()
+	s := tui.DefaultSetup()
+	s.Start, s.HotSeat = mustGame(t, fen), true
+	return s
+}
+
+// playHotSeat runs a game set up by s, with no engine, on input in style and
+// returns its output.
+func playHotSeat(t *testing.T, s tui.Setup, input string, style tui.Style) string {
+	t.Helper()
+	var out bytes.Buffer
+	tui.NewGameWithSetup(strings.NewReader(input), &out, nil, s).WithStyle(style).Run()
+	return out.String()
+}
@@ -0,0 +1,260 @@
+// live_analysis_steps_test.go — Executable specifications for the analysis board.
+//
+// Mirrors: live-analysis.feature
+// Driving ports:
+//   - engine.Builtin.Search with the MultiPV option and TimeControl.Infinite
+//   - tui.Analysis.Run with an injected AnalysisFunc
+//   - chess-go -analysis
+
+package acceptance_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tui"
+)
+
+// ─── Engine ───────────────────────────────────────────────────────────────────
+
+// TestLiveAnalysis_MultiPV validates the engine's ranked lines.
+// Gherkin: "The engine reports its best lines at every depth"
+func TestLiveAnalysis_MultiPV(t *testing.T) {
+	b := engine.NewBuiltin()
+	if err := b.SetOption("MultiPV", "3"); err != nil {
+		t.Fatalf("SetOption: %v", err)
+	}
+	var lines []engine.SearchResult
+	res, err := b.Search(context.Background(), engine.TimeControl{Depth: 3}, func(r engine.SearchResult) {
+		lines = append(lines, r)
+	})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(lines) != 9 {
+		t.Fatalf("got %d lines, want 3 per depth for 3 depths", len(lines))
+	}
+	for i, l := range lines {
+		if want := i/3 + 1; l.Depth != want || l.MultiPV != i%3+1 {
+			t.Errorf("line %d: depth %d rank %d, want depth %d rank %d", i, l.Depth, l.MultiPV, want, i%3+1)
+		}
+		if i%3 > 0 {
+			prev := lines[i-1]
+			if l.PV[0] == prev.PV[0] || (i%3 == 2 && l.PV[0] == lines[i-2].PV[0]) {
+				t.Errorf("depth %d: rank %d repeats a first move", l.Depth, l.MultiPV)
+			}
+			if l.Score > prev.Score {
+				t.Errorf("depth %d: rank %d scores %d, above rank %d's %d", l.Depth, l.MultiPV, l.Score, prev.MultiPV, prev.Score)
+			}
+		}
+	}
+	if best := lines[6]; res.BestMove != best.BestMove || res.Score != best.Score {
+		t.Errorf("result %s %d, want rank 1 of the last depth %s %d",
+			res.BestMove.UCIString(), res.Score, best.BestMove.UCIString(), best.Score)
+	}
+
+	var info bytes.Buffer
+	engine.Config{MultiPV: 2}.Search(context.Background(), mustGame(t, StartingFEN), engine.TimeControl{Depth: 1}, &info)
+	if !strings.Contains(info.String(), "info depth 1 multipv 1 score") || !strings.Contains(info.String(), "info depth 1 multipv 2 score") {
+		t.Errorf("want info lines with multipv:\n%s", info.String())
+	}
+}
+
+// TestLiveAnalysis_InfiniteSearchCancels validates stopping an analysis search.
+// Gherkin: "An infinite search stops when it is cancelled"
+func TestLiveAnalysis_InfiniteSearchCancels(t *testing.T) {
+	b := engine.NewBuiltin()
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	var depths int
+	start := time.Now()
+	res, err := b.Search(ctx, engine.TimeControl{Infinite: true}, func(engine.SearchResult) { depths++ })
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if took := time.Since(start); took > 2*time.Second {
+		t.Errorf("search took %v after being cancelled at 200ms", took)
+	}
+	if depths == 0 {
+		t.Error("want progress reports before the search is cancelled")
+	}
+	assertMoveIsLegal(t, res.BestMove, mustGame(t, StartingFEN).LegalMoves())
+}
+
+// ─── Analysis Board ───────────────────────────────────────────────────────────
+
+// TestLiveAnalysis_ShowsLines validates the lines below the board.
+// Gherkin: "The analysis board shows the engine's lines"
+func TestLiveAnalysis_ShowsLines(t *testing.T) {
+	out, _ := runAnalysis(t, StartingFEN, "e2e4\nquit\n", false)
+	start := mustGame(t, StartingFEN)
+	first, second := start.LegalMoves()[0], start.LegalMoves()[1]
+	for _, want := range []string{
+		fmt.Sprintf(" 1. +0.40  d7   1.%s\n", first.SANString(start)),
+		fmt.Sprintf(" 2. +0.25  d7   1.%s\n", second.SANString(start)),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %q in:\n%s", want, out)
+		}
+	}
+	after, _ := start.Apply(mustParseUCI(t, start, "e2e4"))
+	if want := fmt.Sprintf(" 1. -0.40  d7   1...%s\n", after.LegalMoves()[0].SANString(after)); !strings.Contains(out, want) {
+		t.Errorf("want Black's line from White's view, %q, in:\n%s", want, out)
+	}
+}
+
+// TestLiveAnalysis_RestartsOnNewPosition validates restarting the search.
+// Gherkin: "The analysis restarts on each new position"
+func TestLiveAnalysis_RestartsOnNewPosition(t *testing.T) {
+	_, rec := runAnalysis(t, StartingFEN, "e2e4\nundo\n"+KiwipeteFEN+"\nquit\n", false)
+
+	start := mustGame(t, StartingFEN)
+	e4, _ := start.Apply(mustParseUCI(t, start, "e2e4"))
+	want := []string{start.ToFEN(), e4.ToFEN(), start.ToFEN(), mustGame(t, KiwipeteFEN).ToFEN()}
+	if strings.Join(rec.fens, "\n") != strings.Join(want, "\n") {
+		t.Errorf("searched\n%s\nwant\n%s", strings.Join(rec.fens, "\n"), strings.Join(want, "\n"))
+	}
+	if rec.stopped != len(want) {
+		t.Errorf("%d of %d searches stopped", rec.stopped, len(want))
+	}
+}
+
+// TestLiveAnalysis_BadInput validates input that leaves the position alone.
+// Gherkin: "Input that does not change the position keeps the analysis going"
+func TestLiveAnalysis_BadInput(t *testing.T) {
+	out, rec := runAnalysis(t, StartingFEN, "e2e5\nnot/a fen\nfoo\nundo\nquit\n", false)
+	for _, want := range []string{"Illegal move: e2e5\n", "Cannot read the FEN", "Invalid input: \"foo\"", "Nothing to undo.\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("want %q in:\n%s", want, out)
+		}
+	}
+	if len(rec.fens) != 1 {
+		t.Errorf("searched %d positions, want the first only", len(rec.fens))
+	}
+}
+
+// TestLiveAnalysis_TerminalRedraw validates redrawing in place.
+// Gherkin: "Lines are redrawn in place on a terminal"
+func TestLiveAnalysis_TerminalRedraw(t *testing.T) {
+	out, _ := runAnalysis(t, StartingFEN, "quit\n", true)
+	start := mustGame(t, StartingFEN)
+	if !strings.Contains(out, "Analysing...\n\nMove, FEN, undo or quit: ") {
+		t.Errorf("want two rows kept for the lines above the prompt:\n%q", out)
+	}
+	// The rows are counted up from the prompt, or the line below it once the
+	// player has pressed Enter.
+	var up [2]int
+	for i, score := range []string{"+0.40", "+0.25"} {
+		text := fmt.Sprintf(" %d. %s  d7   1.%s\x1b[K\x1b8", i+1, score, start.LegalMoves()[i].SANString(start))
+		m := regexp.MustCompile(`\x1b7\x1b\[(\d+)A\r` + regexp.QuoteMeta(text)).FindStringSubmatch(out)
+		if m == nil {
+			t.Fatalf("want line %d written in place as %q in:\n%q", i+1, text, out)
+		}
+		up[i], _ = strconv.Atoi(m[1])
+	}
+	if up[0] != up[1]+1 || up[1] < 1 || up[1] > 2 {
+		t.Errorf("lines written %d and %d rows up, want the two rows above the prompt", up[0], up[1])
+	}
+}
+
+// TestLiveAnalysis_GameOver validates a finished game.
+// Gherkin: "A finished game is not analysed"
+func TestLiveAnalysis_GameOver(t *testing.T) {
+	out, rec := runAnalysis(t, BackRankMateFEN, "a1a8\nquit\n", false)
+	if !strings.Contains(out, "Checkmate! White wins. 1-0\n") {
+		t.Errorf("want the result shown:\n%s", out)
+	}
+	if len(rec.fens) != 1 {
+		t.Errorf("searched %d positions, want the one before the mate only", len(rec.fens))
+	}
+}
+
+// TestLiveAnalysis_Binary validates the -analysis flag.
+// Gherkin: "chess-go analyses positions with -analysis"
+func TestLiveAnalysis_Binary(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	cmd := exec.Command(bin, "-analysis", "-lines", "2", "-fen", BackRankMateFEN)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cmd.Wait() }()
+	defer stdin.Close()
+
+	var seen []string
+	sc := bufio.NewScanner(stdout)
+	for len(seen) < 2 && sc.Scan() {
+		if line := sc.Text(); strings.HasPrefix(line, " 1. ") || strings.HasPrefix(line, " 2. ") {
+			seen = append(seen, line)
+		}
+	}
+	_, _ = io.WriteString(stdin, "quit\n")
+	if len(seen) != 2 || !strings.HasPrefix(seen[0], " 1. #1     d1   1.Ra8#") || !strings.HasPrefix(seen[1], " 2. ") {
+		t.Errorf("want the mate then a second line, got %q", seen)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// analysisRecorder is an AnalysisFunc that reports two lines for every
+// position, the first two legal moves at depth 7 scoring 0.40 and 0.25 for
+// the side to move, then waits to be stopped. It records the positions it
+// searched and the searches stopped.
+type analysisRecorder struct {
+	mu      sync.Mutex
+	fens    []string
+	stopped int
+}
+
+func (r *analysisRecorder) search(ctx context.Context, g chess.Game, info func(engine.SearchResult)) {
+	r.mu.Lock()
+	r.fens = append(r.fens, g.ToFEN())
+	r.mu.Unlock()
+	for i, score := range []int{40, 25} {
+		m := g.LegalMoves()[i]
+		info(engine.SearchResult{BestMove: m, Score: score, Depth: 7, PV: []chess.Move{m}, MultiPV: i + 1})
+	}
+	<-ctx.Done()
+	r.mu.Lock()
+	r.stopped++
+	r.mu.Unlock()
+}
+
+// runAnalysis runs an analysis board from fen, showing two lines from an
+// analysisRecorder, with input in, and returns its output and the recorder.
+func runAnalysis(t *testing.T, fen, in string, terminal bool) (string, *analysisRecorder) {
+	t.Helper()
+	rec := &analysisRecorder{}
+	var out bytes.Buffer
+	a := tui.NewAnalysis(strings.NewReader(in), &out, rec.search, mustGame(t, fen)).WithLines(2).WithTerminal(terminal)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		a.Run()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the analysis board did not finish")
+	}
+	return out.String(), rec
+}
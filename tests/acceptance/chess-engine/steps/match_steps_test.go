@@ -0,0 +1,301 @@
+// match_steps_test.go — Executable specifications for engine matches and SPRT.
+//
+// Mirrors: engine-match.feature
+// Driving ports:
+//   - match.Match.Run with match.InProcess and match.UCI contenders
+//   - match.ReadEPDOpenings, match.ParseTimeControl
+//   - match.Score.Elo, match.SPRT.Decide
+
+package acceptance_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/match"
+)
+
+// ─── Playing Matches ──────────────────────────────────────────────────────────
+
+// TestMatch_FixedDepthColoursReversed validates game pairing and PGN output.
+// Gherkin: "Engine developer plays a fixed-depth match with colours reversed"
+func TestMatch_FixedDepthColoursReversed(t *testing.T) {
+	var pgn bytes.Buffer
+	m := match.Match{
+		A:            depthContender("depth2", 2),
+		B:            depthContender("depth1", 1),
+		Games:        4,
+		Adjudication: match.Adjudication{MaxPlies: 40},
+		PGN:          &pgn,
+	}
+	rep, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if rep.Score.Games() != 4 {
+		t.Fatalf("played %d games, want 4", rep.Score.Games())
+	}
+
+	games, err := chess.ReadPGN(&pgn)
+	if err != nil {
+		t.Fatalf("the match PGN does not parse: %v", err)
+	}
+	if len(games) != 4 {
+		t.Fatalf("PGN holds %d games, want 4", len(games))
+	}
+	var whiteA int
+	for _, g := range games {
+		for _, tag := range []string{"Round", "White", "Black", "Result", "Termination"} {
+			if g.Tags[tag] == "" || g.Tags[tag] == "?" {
+				t.Errorf("game %s lacks a %s tag", g.Tags["Round"], tag)
+			}
+		}
+		if g.Result != g.Tags["Result"] || g.Result == "*" {
+			t.Errorf("game %s: result %q, tag %q", g.Tags["Round"], g.Result, g.Tags["Result"])
+		}
+		if g.Tags["White"] == "depth2" {
+			whiteA++
+		}
+	}
+	if whiteA != 2 {
+		t.Errorf("engine A had White in %d of 4 games, want 2", whiteA)
+	}
+}
+
+// TestMatch_EPDOpeningSuite validates opening cycling and the FEN tag.
+// Gherkin: "Engine developer starts games from an EPD opening suite"
+func TestMatch_EPDOpeningSuite(t *testing.T) {
+	suite := "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - id \"open game\";\n" +
+		"rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 bm Nf3;\n"
+	ops, err := match.ReadEPDOpenings(strings.NewReader(suite))
+	if err != nil {
+		t.Fatalf("ReadEPDOpenings: %v", err)
+	}
+	var pgn bytes.Buffer
+	m := match.Match{
+		A: depthContender("a", 1), B: depthContender("b", 1),
+		Openings: ops, Games: 4, PGN: &pgn,
+		Adjudication: match.Adjudication{MaxPlies: 10},
+	}
+	if _, err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	games, err := chess.ReadPGN(&pgn)
+	if err != nil || len(games) != 4 {
+		t.Fatalf("ReadPGN: %d games, %v", len(games), err)
+	}
+	byRound := map[string]string{}
+	for _, g := range games {
+		if g.Tags["SetUp"] != "1" {
+			t.Errorf("game %s lacks the SetUp tag", g.Tags["Round"])
+		}
+		byRound[g.Tags["Round"]] = strings.Join(strings.Fields(g.Tags["FEN"])[:4], " ")
+	}
+	first := "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq -"
+	second := "rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6"
+	for round, want := range map[string]string{"1": first, "2": first, "3": second, "4": second} {
+		if byRound[round] != want {
+			t.Errorf("game %s started from %q, want %q", round, byRound[round], want)
+		}
+	}
+}
+
+// TestMatch_ExternalUCIEngine validates the subprocess player.
+// Gherkin: "Engine developer plays against an external UCI engine"
+func TestMatch_ExternalUCIEngine(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	var pgn bytes.Buffer
+	m := match.Match{
+		A:            match.UCI("external", bin, []string{"uci"}, nil),
+		B:            depthContender("builtin", 2),
+		Games:        2,
+		TimeControl:  match.TimeControl{Depth: 2},
+		Adjudication: match.Adjudication{MaxPlies: 40},
+		PGN:          &pgn,
+	}
+	rep, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if rep.Score.Games() != 2 {
+		t.Errorf("played %d games, want 2", rep.Score.Games())
+	}
+	games, err := chess.ReadPGN(&pgn)
+	if err != nil || len(games) != 2 {
+		t.Fatalf("ReadPGN: %d games, %v", len(games), err)
+	}
+	for _, g := range games {
+		if len(g.Moves) == 0 || g.Result == "*" {
+			t.Errorf("game %s: %d moves, result %s", g.Tags["Round"], len(g.Moves), g.Result)
+		}
+	}
+}
+
+// TestMatch_MissingEngineReported validates the start-up error path.
+// Gherkin: "Engine developer sees a missing external engine reported"
+func TestMatch_MissingEngineReported(t *testing.T) {
+	m := match.Match{
+		A:     match.UCI("missing", "/nonexistent/engine", nil, nil),
+		B:     depthContender("builtin", 1),
+		Games: 2,
+	}
+	if _, err := m.Run(context.Background()); !errors.Is(err, match.ErrEngine) {
+		t.Errorf("Run: err = %v, want ErrEngine", err)
+	}
+}
+
+// ─── Clocks and Adjudication ──────────────────────────────────────────────────
+
+// TestMatch_SlowEngineLosesOnTime validates clock enforcement.
+// Gherkin: "Engine developer sees a slow engine lose on time"
+func TestMatch_SlowEngineLosesOnTime(t *testing.T) {
+	slow := match.InProcess("slow", func(ctx context.Context, g chess.Game, _ engine.TimeControl, _ io.Writer) engine.SearchResult {
+		time.Sleep(300 * time.Millisecond)
+		return engine.SearchResult{BestMove: g.LegalMoves()[0]}
+	})
+	var last match.Game
+	m := match.Match{
+		A: slow, B: depthContender("fast", 1), Games: 2,
+		TimeControl: match.TimeControl{Base: 100 * time.Millisecond, Margin: 10 * time.Millisecond},
+		Progress:    func(g match.Game, _ match.Report) { last = g },
+	}
+	rep, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if rep.Score.Losses != 2 || last.Termination != "time forfeit" {
+		t.Errorf("score %s, last termination %q; want two losses on time", rep.Score, last.Termination)
+	}
+}
+
+// TestMatch_Adjudication validates resign and draw adjudication.
+// Gherkin: "Engine developer adjudicates hopeless and dead positions"
+func TestMatch_Adjudication(t *testing.T) {
+	// Both engines report scores from the side to move's view.
+	scoring := func(white, black int) match.Contender {
+		return match.InProcess("scoring", func(_ context.Context, g chess.Game, _ engine.TimeControl, _ io.Writer) engine.SearchResult {
+			score := white
+			if g.State.ActiveColor == chess.Black {
+				score = black
+			}
+			return engine.SearchResult{BestMove: g.LegalMoves()[0], Score: score}
+		})
+	}
+	run := func(c match.Contender, adj match.Adjudication) match.Game {
+		var g match.Game
+		m := match.Match{A: c, B: c, Games: 1, Adjudication: adj,
+			Progress: func(game match.Game, _ match.Report) {
+				if game.Round == 1 {
+					g = game
+				}
+			}}
+		if _, err := m.Run(context.Background()); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return g
+	}
+
+	g := run(scoring(-900, 900), match.Adjudication{ResignMoves: 3, ResignScore: 800})
+	if g.Result != "0-1" || g.Termination != "adjudication: resignation" || len(g.Moves) != 5 {
+		t.Errorf("resign: %s {%s} after %d plies, want 0-1 by resignation after 5", g.Result, g.Termination, len(g.Moves))
+	}
+	g = run(scoring(3, -3), match.Adjudication{DrawMoveNumber: 1, DrawMoves: 4, DrawScore: 10})
+	if g.Result != "1/2-1/2" || g.Termination != "adjudication: draw" || len(g.Moves) != 8 {
+		t.Errorf("draw: %s {%s} after %d plies, want a draw by adjudication after 8", g.Result, g.Termination, len(g.Moves))
+	}
+}
+
+// TestMatch_ParseTimeControl validates the clock notation.
+// Gherkin: "Engine developer writes clocks the cutechess way"
+func TestMatch_ParseTimeControl(t *testing.T) {
+	cases := []struct {
+		in   string
+		want match.TimeControl
+	}{
+		{"40/120", match.TimeControl{Moves: 40, Base: 120 * time.Second}},
+		{"10+0.1", match.TimeControl{Base: 10 * time.Second, Inc: 100 * time.Millisecond}},
+		{"60", match.TimeControl{Base: time.Minute}},
+	}
+	for _, c := range cases {
+		got, err := match.ParseTimeControl(c.in)
+		if err != nil || got != c.want {
+			t.Errorf("ParseTimeControl(%q) = %+v, %v; want %+v", c.in, got, err, c.want)
+		}
+		if got.String() != c.in {
+			t.Errorf("%+v.String() = %q, want %q", got, got.String(), c.in)
+		}
+	}
+	for _, bad := range []string{"", "abc", "0/60", "10+x"} {
+		if _, err := match.ParseTimeControl(bad); !errors.Is(err, match.ErrInvalidTimeControl) {
+			t.Errorf("ParseTimeControl(%q): err = %v, want ErrInvalidTimeControl", bad, err)
+		}
+	}
+}
+
+// ─── Statistics ───────────────────────────────────────────────────────────────
+
+// TestMatch_EloWithErrorBars validates the Elo estimate.
+// Gherkin: "Engine developer reads an Elo difference with error bars"
+func TestMatch_EloWithErrorBars(t *testing.T) {
+	diff, margin := match.Score{Wins: 60, Draws: 20, Losses: 20}.Elo()
+	if math.Abs(diff-147.2) > 1 || margin <= 0 || margin >= 80 {
+		t.Errorf("Elo = %+.1f ± %.1f, want about +147 with a margin below 80", diff, margin)
+	}
+	if diff, _ := (match.Score{Wins: 5, Draws: 10, Losses: 5}).Elo(); diff != 0 {
+		t.Errorf("an even score gives %+.1f Elo, want 0", diff)
+	}
+}
+
+// TestMatch_SPRTStopsEarly validates that a decided SPRT ends the match.
+// Gherkin: "Engine developer stops a match with an SPRT"
+func TestMatch_SPRTStopsEarly(t *testing.T) {
+	random := match.InProcess("first-move", func(_ context.Context, g chess.Game, _ engine.TimeControl, _ io.Writer) engine.SearchResult {
+		return engine.SearchResult{BestMove: g.LegalMoves()[0]}
+	})
+	m := match.Match{
+		A: depthContender("depth2", 2), B: random, Games: 200,
+		SPRT:         &match.SPRT{Elo0: 0, Elo1: 200, Alpha: 0.05, Beta: 0.05},
+		Adjudication: match.Adjudication{MaxPlies: 200},
+	}
+	rep, err := m.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if rep.Decision != match.AcceptH1 || rep.Score.Games() >= 200 {
+		t.Errorf("after %d games (%s) the SPRT says %s, want H1 accepted early", rep.Score.Games(), rep.Score, rep.Decision)
+	}
+}
+
+// TestMatch_SPRTRejectsLosingChange validates the H0 bound.
+// Gherkin: "Engine developer rejects a change that loses strength"
+func TestMatch_SPRTRejectsLosingChange(t *testing.T) {
+	sprt := match.SPRT{Elo0: 0, Elo1: 5, Alpha: 0.05, Beta: 0.05}
+	lower, upper := sprt.Bounds()
+	if math.Abs(lower+2.944) > 0.01 || math.Abs(upper-2.944) > 0.01 {
+		t.Errorf("bounds = (%.3f, %.3f), want ±2.944", lower, upper)
+	}
+	if d := sprt.Decide(match.Score{Wins: 30, Draws: 120, Losses: 150}); d != match.AcceptH0 {
+		t.Errorf("Decide = %s, want H0 accepted", d)
+	}
+	if d := sprt.Decide(match.Score{Wins: 3, Draws: 4, Losses: 3}); d != match.Continue {
+		t.Errorf("Decide after an even start = %s, want continue", d)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// depthContender runs the built-in search to a fixed depth, whatever the match
+// time control.
+func depthContender(name string, depth int) match.Contender {
+	return match.InProcess(name, func(ctx context.Context, g chess.Game, tc engine.TimeControl, info io.Writer) engine.SearchResult {
+		return engine.SearchContext(ctx, g, engine.TimeControl{Depth: depth}, info)
+	})
+}
@@ -0,0 +1,217 @@
+// nnue_steps_test.go — Executable specifications for NNUE evaluation.
+//
+// Mirrors: nnue-evaluation.feature
+// Driving ports:
+//   - engine.LoadNetwork / engine.ReadNetwork / engine.ReadQuantised / Network.WriteTo
+//   - Network.Evaluate / Network.Refresh / Network.Update / Network.Output
+//   - engine.Config{Network}.Search / UCIHandler (setoption name EvalFile)
+
+package acceptance_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ─── Network Files ────────────────────────────────────────────────────────────
+
+// TestNNUE_LoadsTestNetwork validates the bundled test network.
+// Gherkin: "Engine developer loads the test network"
+func TestNNUE_LoadsTestNetwork(t *testing.T) {
+	nn := loadTinyNetwork(t)
+	if nn.Hidden != 8 || len(nn.FeatureWeights) != 768*8 || len(nn.OutputWeights) != 16 {
+		t.Fatalf("network is 768x%d with %d feature and %d output weights, want 768x8",
+			nn.Hidden, len(nn.FeatureWeights), len(nn.OutputWeights))
+	}
+	if got := nn.Evaluate(mustState(t, StartingFEN)); got != 0 {
+		t.Errorf("starting position evaluates %d, want 0", got)
+	}
+}
+
+// TestNNUE_FileFormatRoundTrip validates writing, reading and rejecting files.
+// Gherkin: "Engine developer round-trips the network file format"
+func TestNNUE_FileFormatRoundTrip(t *testing.T) {
+	nn := loadTinyNetwork(t)
+	var buf bytes.Buffer
+	if _, err := nn.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	file := buf.Bytes()
+	if want := 16 + 2*(768*8+8+16+1); len(file) != want {
+		t.Errorf("file is %d bytes, want %d", len(file), want)
+	}
+	padded := append(append([]byte(nil), file...), make([]byte, 30)...)
+	back, err := engine.ReadNetwork(bytes.NewReader(padded))
+	if err != nil {
+		t.Fatalf("ReadNetwork: %v", err)
+	}
+	if !reflect.DeepEqual(back, nn) {
+		t.Error("parameters changed in the round trip")
+	}
+
+	wrongInputs := append([]byte(nil), file...)
+	binary.LittleEndian.PutUint32(wrongInputs[8:], 640)
+	for name, data := range map[string][]byte{
+		"bad magic":     append([]byte("NNUE"), file[4:]...),
+		"wrong inputs":  wrongInputs,
+		"truncated":     file[:len(file)-3],
+		"trailing data": append(append([]byte(nil), file...), 1, 2, 3),
+	} {
+		if _, err := engine.ReadNetwork(bytes.NewReader(data)); !errors.Is(err, engine.ErrInvalidNetwork) {
+			t.Errorf("%s: err = %v, want ErrInvalidNetwork", name, err)
+		}
+	}
+}
+
+// TestNNUE_LoadsHeaderlessTrainerOutput validates reading the parameters a
+// trainer writes as quantised.bin, which has no header.
+// Gherkin: "Engine developer loads a trainer's headerless quantised.bin"
+func TestNNUE_LoadsHeaderlessTrainerOutput(t *testing.T) {
+	nn := loadTinyNetwork(t)
+	var buf bytes.Buffer
+	if _, err := nn.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	raw := buf.Bytes()[16:]
+	raw = append(raw, make([]byte, (64-len(raw)%64)%64)...) // padded to 64 bytes
+
+	back, err := engine.ReadQuantised(bytes.NewReader(raw), 8)
+	if err != nil {
+		t.Fatalf("ReadQuantised: %v", err)
+	}
+	if !reflect.DeepEqual(back, nn) {
+		t.Error("ReadQuantised: parameters differ from the network's")
+	}
+
+	path := filepath.Join(t.TempDir(), "quantised.bin")
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	back, err = engine.LoadNetwork(path)
+	if err != nil {
+		t.Fatalf("LoadNetwork(quantised.bin): %v", err)
+	}
+	if !reflect.DeepEqual(back, nn) {
+		t.Errorf("LoadNetwork(quantised.bin): hidden %d, parameters differ from the network's", back.Hidden)
+	}
+
+	if _, err := engine.ReadQuantised(bytes.NewReader(raw), 16); !errors.Is(err, engine.ErrInvalidNetwork) {
+		t.Errorf("ReadQuantised with the wrong hidden size: err = %v, want ErrInvalidNetwork", err)
+	}
+}
+
+// ─── Inference ────────────────────────────────────────────────────────────────
+
+// TestNNUE_EvaluationIsSymmetric validates material scoring and colour symmetry.
+// Gherkin: "Engine developer checks the network's symmetry"
+func TestNNUE_EvaluationIsSymmetric(t *testing.T) {
+	nn := loadTinyNetwork(t)
+	for _, tc := range []struct{ fen, mirrored string }{
+		{"4k3/pppppppp/8/8/8/8/PPPPPPPP/1N2K3 w - - 0 1", "1n2k3/pppppppp/8/8/8/8/PPPPPPPP/4K3 b - - 0 1"},
+		{"4k3/8/8/3q4/8/8/3PP3/4K3 b - - 0 1", "4k3/3pp3/8/8/3Q4/8/8/4K3 w - - 0 1"},
+		{"r3k3/8/8/8/8/8/4PPPP/4K2R w K - 0 1", "4k2r/4pppp/8/8/8/8/8/R3K3 b k - 0 1"},
+	} {
+		pos, mirror := mustState(t, tc.fen), mustState(t, tc.mirrored)
+		got := nn.Evaluate(pos)
+		if got <= 50 {
+			t.Errorf("%s: evaluates %d, want the side to move clearly ahead", tc.fen, got)
+		}
+		if m := nn.Evaluate(mirror); m != got {
+			t.Errorf("%s: mirrored position evaluates %d, original %d", tc.fen, m, got)
+		}
+	}
+}
+
+// TestNNUE_IncrementalUpdatesMatchRefresh validates the accumulator updates.
+// Gherkin: "Engine developer checks incremental accumulator updates"
+func TestNNUE_IncrementalUpdatesMatchRefresh(t *testing.T) {
+	nn := loadTinyNetwork(t)
+	rng := rand.New(rand.NewSource(7))
+	for _, fen := range []string{
+		StartingFEN,
+		KiwipeteFEN,
+		"n1n5/PPPk4/8/8/8/8/4Kppp/5N1N b - - 0 1",
+		"rnbqkbnr/ppp1p1pp/8/3pPp2/8/8/PPPP1PPP/RNBQKBNR w KQkq f6 0 3",
+	} {
+		for game := 0; game < 5; game++ {
+			pos := mustState(t, fen)
+			var acc, fresh engine.Accumulator
+			nn.Refresh(&acc, pos)
+			for ply := 0; ply < 120; ply++ {
+				moves := pos.LegalMoves()
+				if len(moves) == 0 {
+					break
+				}
+				next := pos.Play(moves[rng.Intn(len(moves))])
+				nn.Update(&acc, &acc, pos, next)
+				nn.Refresh(&fresh, next)
+				for _, side := range []chess.Color{chess.White, chess.Black} {
+					if a, b := nn.Output(&acc, side), nn.Output(&fresh, side); a != b {
+						t.Fatalf("%s game %d ply %d: incremental %d, refreshed %d", fen, game, ply, a, b)
+					}
+				}
+				pos = next
+			}
+		}
+	}
+}
+
+// ─── Search ───────────────────────────────────────────────────────────────────
+
+// TestNNUE_SearchUsesNetwork validates Config.Network in search.
+// Gherkin: "Engine developer searches with the network"
+func TestNNUE_SearchUsesNetwork(t *testing.T) {
+	cfg := engine.Config{Network: loadTinyNetwork(t)}
+	g := mustGame(t, "rnb1kbnr/pppp1ppp/8/4p3/4P2q/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3")
+	res := cfg.Search(context.Background(), g, engine.TimeControl{Depth: 3}, io.Discard)
+	if got := res.BestMove.UCIString(); got != "f3h4" {
+		t.Errorf("best move %s (score %d), want f3h4 winning the queen", got, res.Score)
+	}
+}
+
+// TestNNUE_UCIEvalFileOption validates the EvalFile option and its fallback.
+// Gherkin: "Engine developer selects the network over UCI"
+func TestNNUE_UCIEvalFileOption(t *testing.T) {
+	path := filepath.Join(projectRoot(t), "testdata", "nnue", "tiny.nnue")
+	for _, tc := range []struct{ file, want string }{
+		{path, "info string NNUE evaluation using"},
+		{filepath.Join(t.TempDir(), "missing.nnue"), "using the hand-crafted evaluation"},
+	} {
+		input := strings.NewReader("uci\nsetoption name EvalFile value " + tc.file + "\nposition startpos\ngo depth 2\n")
+		var output bytes.Buffer
+		engine.NewUCIHandler(nil).Run(input, &output)
+		out := output.String()
+		if !strings.Contains(out, "option name EvalFile type string") {
+			t.Error("EvalFile is not announced")
+		}
+		if !strings.Contains(out, tc.want) {
+			t.Errorf("EvalFile %s: no %q in\n%s", tc.file, tc.want, out)
+		}
+		g := mustGame(t, StartingFEN)
+		assertMoveIsLegal(t, mustParseUCI(t, g, extractBestmove(strings.Split(out, "\n"))), g.LegalMoves())
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// loadTinyNetwork loads testdata/nnue/tiny.nnue (see testdata/nnue/gen.go).
+func loadTinyNetwork(t *testing.T) *engine.Network {
+	t.Helper()
+	nn, err := engine.LoadNetwork(filepath.Join(projectRoot(t), "testdata", "nnue", "tiny.nnue"))
+	if err != nil {
+		t.Fatalf("LoadNetwork: %v", err)
+	}
+	return nn
+}
@@ -0,0 +1,256 @@
+// options_steps_test.go — Executable specifications for the engine option
+// registry.
+//
+// Mirrors: engine-options.feature
+// Driving ports:
+//   - engine.Options: Declare, Set, WriteUCI, RegisterFlags, Apply
+//   - engine.ParseOption, engine.ReadConfig
+//   - engine.UCIHandler (setoption), chess-go uci -config (CLI)
+//   - engine.Config{Contempt}.Search
+
+package acceptance_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ─── Declarations ─────────────────────────────────────────────────────────────
+
+// TestOptions_AdvertisesEveryType validates the UCI option lines.
+// Gherkin: "Engine developer declares options of every UCI type"
+func TestOptions_AdvertisesEveryType(t *testing.T) {
+	o := sampleOptions(nil)
+	var out bytes.Buffer
+	o.WriteUCI(&out)
+	want := "option name Ponder type check default false\n" +
+		"option name Move Overhead type spin default 30 min 0 max 5000\n" +
+		"option name Style type combo default Solid var Solid var Normal var Risky\n" +
+		"option name Log File type string default <empty>\n" +
+		"option name Clear Hash type button\n"
+	if out.String() != want {
+		t.Errorf("WriteUCI wrote\n%s\nwant\n%s", out.String(), want)
+	}
+
+	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		got, err := engine.ParseOption(strings.Fields(line)[1:])
+		if err != nil {
+			t.Fatalf("ParseOption(%q): %v", line, err)
+		}
+		decl := o.List()[i]
+		decl.Usage, decl.OnChange = "", nil
+		if !reflect.DeepEqual(got, decl) {
+			t.Errorf("%q parses as %+v, want %+v", line, got, decl)
+		}
+	}
+}
+
+// TestOptions_RejectsBadValues validates type checking in Set.
+// Gherkin: "GUI has bad option values rejected"
+func TestOptions_RejectsBadValues(t *testing.T) {
+	o := sampleOptions(nil)
+	for _, tc := range []struct{ name, value string }{
+		{"Move Overhead", "5001"},
+		{"Move Overhead", "-1"},
+		{"Move Overhead", "fast"},
+		{"Style", "Wild"},
+		{"Ponder", "yes"},
+	} {
+		if _, err := o.Set(tc.name, tc.value); !errors.Is(err, engine.ErrInvalidOption) {
+			t.Errorf("%s = %q: err = %v, want ErrInvalidOption", tc.name, tc.value, err)
+		}
+	}
+	if _, err := o.Set("Hash", "64"); !errors.Is(err, engine.ErrUnknownOption) {
+		t.Errorf("Hash: err = %v, want ErrUnknownOption", err)
+	}
+	if got := o.Value("Move Overhead"); got != "30" {
+		t.Errorf("Move Overhead is %q after rejected values, want the default 30", got)
+	}
+}
+
+// TestOptions_ChangeCallbacks validates OnChange with canonical values.
+// Gherkin: "Engine developer reacts to option changes"
+func TestOptions_ChangeCallbacks(t *testing.T) {
+	var changes []string
+	o := sampleOptions(func(name, value string) { changes = append(changes, name+"="+value) })
+	for _, set := range [][2]string{
+		{"style", "risky"}, {"PONDER", "True"}, {"Move Overhead", "0100"},
+		{"Log File", "<empty>"}, {"Clear Hash", ""}, {"Clear Hash", ""},
+	} {
+		if _, err := o.Set(set[0], set[1]); err != nil {
+			t.Fatalf("Set(%q, %q): %v", set[0], set[1], err)
+		}
+	}
+	want := []string{"Style=Risky", "Ponder=true", "Move Overhead=100", "Log File=", "Clear Hash=", "Clear Hash="}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("callbacks saw %v, want %v", changes, want)
+	}
+}
+
+// TestOptions_FailedChangeKeepsValue validates that Set records a value only
+// once OnChange accepts it.
+// Gherkin: "Engine developer has a failed change leave the option unchanged"
+func TestOptions_FailedChangeKeepsValue(t *testing.T) {
+	var o engine.Options
+	loaded := "a.bin"
+	o.Declare(engine.Option{Name: "Book File", Type: engine.String, Default: loaded,
+		OnChange: func(v string) (string, error) {
+			if v == "missing.bin" {
+				return "", os.ErrNotExist
+			}
+			loaded = v
+			return "loaded " + v, nil
+		}})
+	if _, err := o.Set("Book File", "missing.bin"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Set(missing.bin): err = %v, want the callback's error", err)
+	}
+	if got := o.Value("Book File"); got != "a.bin" || loaded != "a.bin" {
+		t.Errorf("after a failed change the option is %q with %q loaded, want a.bin for both", got, loaded)
+	}
+	note, err := o.Set("Book File", "b.bin")
+	if err != nil || note != "loaded b.bin" || o.Value("Book File") != "b.bin" {
+		t.Errorf("Set(b.bin) = %q, %v and the option is %q; want the note and b.bin", note, err, o.Value("Book File"))
+	}
+}
+
+// ─── Flags and Config Files ───────────────────────────────────────────────────
+
+// TestOptions_CommandLineFlags validates RegisterFlags.
+// Gherkin: "Engine developer sets options from command-line flags"
+func TestOptions_CommandLineFlags(t *testing.T) {
+	pressed := 0
+	o := sampleOptions(func(name, _ string) {
+		if name == "Clear Hash" {
+			pressed++
+		}
+	})
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	o.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-move-overhead", "250", "-ponder", "-style=Normal", "-clear-hash", "-log-file", "x.log"}); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	for name, want := range map[string]string{"Move Overhead": "250", "Ponder": "true", "Style": "Normal", "Log File": "x.log"} {
+		if got := o.Value(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if pressed != 1 {
+		t.Errorf("Clear Hash pressed %d times, want 1", pressed)
+	}
+	if err := fs.Parse([]string{"-move-overhead", "9999"}); err == nil {
+		t.Error("an out-of-range flag value was accepted")
+	}
+}
+
+// TestOptions_ConfigFile validates ReadConfig and Apply.
+// Gherkin: "Engine developer sets options from a config file"
+func TestOptions_ConfigFile(t *testing.T) {
+	o := sampleOptions(nil)
+	settings, err := engine.ReadConfig(strings.NewReader(
+		"# tournament settings\n\nMove Overhead = 80\n  style=Risky  \nLog File = /tmp/engine log.txt\nClear Hash\n"))
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+	if err := o.Apply(settings); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	for name, want := range map[string]string{"Move Overhead": "80", "Style": "Risky", "Log File": "/tmp/engine log.txt"} {
+		if got := o.Value(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if _, err := engine.ReadConfig(strings.NewReader("= 5\n")); !errors.Is(err, engine.ErrInvalidOption) {
+		t.Errorf("a line without a name: err = %v, want ErrInvalidOption", err)
+	}
+}
+
+// TestOptions_UCICommandLine validates chess-go uci with -config and flags.
+// Gherkin: "GUI sees options set on the command line"
+func TestOptions_UCICommandLine(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	conf := filepath.Join(t.TempDir(), "engine.conf")
+	if err := os.WriteFile(conf, []byte("Contempt = 25\nSkill Level = 10\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(bin, "uci", "-config", conf, "-skill-level", "3")
+	cmd.Stdin = strings.NewReader("uci\nquit\n")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("chess-go uci: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"option name Contempt type spin default 25 min -100 max 100",
+		"option name Skill Level type spin default 3 min 0 max 20",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("uci response lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+// ─── Built-in Options ─────────────────────────────────────────────────────────
+
+// TestOptions_UCIReportsBadValue validates setoption validation in the handler.
+// Gherkin: "Engine developer reports bad setoption commands to the GUI"
+func TestOptions_UCIReportsBadValue(t *testing.T) {
+	var out bytes.Buffer
+	engine.NewUCIHandler(nil).Run(strings.NewReader("setoption name Skill Level value 99\n"), &out)
+	if !strings.Contains(out.String(), "info string invalid option value: Skill Level wants an integer from 0 to 20") {
+		t.Errorf("no info string for the bad value:\n%s", out.String())
+	}
+}
+
+// TestOptions_Contempt validates draw avoidance and seeking.
+// Gherkin: "Engine avoids or seeks draws according to Contempt"
+func TestOptions_Contempt(t *testing.T) {
+	// Any move but a pawn move completes fifty moves without a capture or pawn move.
+	g := mustGame(t, "4k3/7p/8/8/8/8/P7/4K3 w - - 99 80")
+	for _, tc := range []struct {
+		contempt int
+		pawn     bool
+	}{{50, true}, {-50, false}} {
+		res := engine.Config{Contempt: tc.contempt}.Search(context.Background(), g, engine.TimeControl{Depth: 3}, io.Discard)
+		if isPawn := g.State.Board[res.BestMove.From] == chess.WhitePawn; isPawn != tc.pawn {
+			t.Errorf("Contempt %d: played %s (score %d), want a pawn move: %v",
+				tc.contempt, res.BestMove.UCIString(), res.Score, tc.pawn)
+		}
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// sampleOptions declares one option of each type, calling changed (if not
+// nil) from every OnChange.
+func sampleOptions(changed func(name, value string)) *engine.Options {
+	var o engine.Options
+	for _, opt := range []engine.Option{
+		{Name: "Ponder", Type: engine.Check, Default: "false"},
+		{Name: "Move Overhead", Type: engine.Spin, Default: "30", Min: 0, Max: 5000},
+		{Name: "Style", Type: engine.Combo, Default: "Solid", Vars: []string{"Solid", "Normal", "Risky"}},
+		{Name: "Log File", Type: engine.String},
+		{Name: "Clear Hash", Type: engine.Button},
+	} {
+		name := opt.Name
+		opt.OnChange = func(value string) (string, error) {
+			if changed != nil {
+				changed(name, value)
+			}
+			return "", nil
+		}
+		o.Declare(opt)
+	}
+	return &o
+}
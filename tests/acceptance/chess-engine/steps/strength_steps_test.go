@@ -0,0 +1,152 @@
+// strength_steps_test.go — Executable specifications for engine strength limiting.
+//
+// Mirrors: engine-strength.feature
+// Driving ports:
+//   - engine.Search with TimeControl.Strength (engine.SkillLevel / engine.EloStrength)
+//   - engine.UCIHandler.Run with the Skill Level, UCI_LimitStrength and UCI_Elo options
+//   - chess-go -skill-level, -uci-limitstrength and -uci-elo, and -skill and -elo (CLI)
+
+package acceptance_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	engine "chess_go/internal/engine"
+)
+
+// ─── Skill Levels ─────────────────────────────────────────────────────────────
+
+// TestStrength_WeakestLevelSearchesLittle validates the node cap of Skill Level 0.
+// Gherkin: "Casual player faces the weakest skill level"
+func TestStrength_WeakestLevelSearchesLittle(t *testing.T) {
+	game := mustGame(t, StartingFEN)
+	res := engine.Search(game, engine.TimeControl{Strength: engine.SkillLevel(0)}, io.Discard)
+	assertMoveIsLegal(t, res.BestMove, game.LegalMoves())
+	if res.Nodes > 500 {
+		t.Errorf("Skill Level 0 searched %d nodes, want at most a few hundred", res.Nodes)
+	}
+}
+
+// TestStrength_WeakLevelVariesItsMoves validates the score-weighted random choice.
+// Gherkin: "Casual player sees a weak engine vary its moves"
+func TestStrength_WeakLevelVariesItsMoves(t *testing.T) {
+	game := mustGame(t, StartingFEN)
+	tc := engine.TimeControl{Strength: engine.SkillLevel(0)}
+	seen := map[string]bool{}
+	for i := 0; i < 20; i++ {
+		seen[engine.Search(game, tc, io.Discard).BestMove.UCIString()] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Skill Level 0 played only %v in 20 searches", seen)
+	}
+}
+
+// TestStrength_LimitedLevelTakesMateInOne validates that the random push
+// never outweighs a mate.
+// Gherkin: "Casual player sees a limited engine still take a mate in one"
+func TestStrength_LimitedLevelTakesMateInOne(t *testing.T) {
+	game := mustGame(t, "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	res := engine.Search(game, engine.TimeControl{Strength: engine.SkillLevel(19)}, io.Discard)
+	if got := res.BestMove.UCIString(); got != "a1a8" {
+		t.Errorf("Skill Level 19 played %s, want the mate a1a8", got)
+	}
+}
+
+// ─── Elo Mapping ──────────────────────────────────────────────────────────────
+
+// TestStrength_EloMapsToRisingSkillLevels validates the calibrated Elo table.
+// Gherkin: "Casual player picks a target rating"
+func TestStrength_EloMapsToRisingSkillLevels(t *testing.T) {
+	if engine.SkillLevel(engine.MaxSkill).Limited() {
+		t.Error("Skill Level 20 should play at full strength")
+	}
+	if lvl := engine.EloStrength(engine.MinElo).Level(); lvl != 0 {
+		t.Errorf("EloStrength(MinElo).Level() = %g, want 0", lvl)
+	}
+	if lvl := engine.EloStrength(engine.MaxElo).Level(); lvl != engine.MaxSkill-1 {
+		t.Errorf("EloStrength(MaxElo).Level() = %g, want %d", lvl, engine.MaxSkill-1)
+	}
+	prev := -1.0
+	for elo := engine.MinElo; elo <= engine.MaxElo; elo += 50 {
+		s := engine.EloStrength(elo)
+		if !s.Limited() || s.Level() <= prev {
+			t.Fatalf("EloStrength(%d) = %v, want a limited level above %g", elo, s, prev)
+		}
+		prev = s.Level()
+		if got := s.Elo(); got < elo-1 || got > elo+1 {
+			t.Errorf("EloStrength(%d).Elo() = %d", elo, got)
+		}
+	}
+}
+
+// ─── UCI Options ──────────────────────────────────────────────────────────────
+
+// TestUCIHandler_AdvertisesStrengthOptions validates the uci option list.
+// Gherkin: "Engine developer sees the strength options advertised"
+func TestUCIHandler_AdvertisesStrengthOptions(t *testing.T) {
+	var out bytes.Buffer
+	engine.NewUCIHandler(nil).Run(strings.NewReader("uci\n"), &out)
+	for _, opt := range []string{
+		"option name Skill Level type spin default 20 min 0 max 20",
+		"option name UCI_LimitStrength type check default false",
+		"option name UCI_Elo type spin",
+	} {
+		if !strings.Contains(out.String(), opt) {
+			t.Errorf("uci response lacks %q:\n%s", opt, out.String())
+		}
+	}
+}
+
+// TestUCIHandler_LimitStrengthPlaysQuickly validates UCI_LimitStrength and UCI_Elo.
+// Gherkin: "Engine developer limits strength through UCI"
+func TestUCIHandler_LimitStrengthPlaysQuickly(t *testing.T) {
+	in := strings.NewReader("setoption name UCI_LimitStrength value true\n" +
+		"setoption name UCI_Elo value 1200\n" +
+		"position startpos\n" +
+		"go movetime 2000\n")
+	var out bytes.Buffer
+	start := time.Now()
+	engine.NewUCIHandler(nil).Run(in, &out)
+	elapsed := time.Since(start)
+
+	game := mustGame(t, StartingFEN)
+	bm := extractBestmove(strings.Split(out.String(), "\n"))
+	assertMoveIsLegal(t, mustParseUCI(t, game, bm), game.LegalMoves())
+	assertWithinDuration(t, time.Second, elapsed, "a node-capped search must not use the whole movetime")
+}
+
+// ─── Command Line ─────────────────────────────────────────────────────────────
+
+// TestStrength_CLI validates the strength options as chess-go flags.
+// Gherkin: "Casual player sets the strength on the command line"
+func TestStrength_CLI(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	for _, args := range [][]string{{"-uci-limitstrength", "-uci-elo", "1200"}, {"-elo", "1200"}} {
+		cmd := exec.Command(bin, args...)
+		cmd.Stdin = strings.NewReader("e2e4\n")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("chess-go %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+		if !strings.Contains(string(out), "Engine plays ") {
+			t.Errorf("chess-go %s: no engine reply:\n%s", strings.Join(args, " "), out)
+		}
+	}
+
+	for _, flag := range []string{"-skill-level", "-skill"} {
+		out, err := exec.Command(bin, flag, "99").CombinedOutput()
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+			t.Errorf("chess-go %s 99: %v, want exit status 2", flag, err)
+		}
+		if !strings.Contains(string(out), "Skill Level wants an integer from 0 to 20") {
+			t.Errorf("chess-go %s 99: bad value not reported:\n%s", flag, out)
+		}
+	}
+}
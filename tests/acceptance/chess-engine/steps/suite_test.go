@@ -42,6 +42,7 @@ const (
 	StalemateFEN            = "k7/8/1Q6/8/8/8/8/7K b - - 0 1"
 	PawnOnE7FEN             = "4k3/4P3/8/8/8/8/8/4K3 w - - 0 1"
 	MateIn1FEN              = "k7/8/1K6/8/8/8/8/R7 w - - 0 1"
+	BackRankMateFEN         = "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1" // Ra8#
 	WhiteKingsideFEN        = "r1bqk2r/pppp1ppp/2n2n2/2b1p3/2B1P3/5N2/PPPP1PPP/RNBQK2R w KQkq - 4 4"
 	CastlingThroughCheckFEN = "rnbqk2r/pppp1ppp/5n2/4p3/1b2P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 4 4"
 	WhiteQueensideFEN       = "r3kbnr/ppp1pppp/2nqb3/3p4/3P4/2NQB3/PPP1PPPP/R3KBNR w KQkq - 4 5"
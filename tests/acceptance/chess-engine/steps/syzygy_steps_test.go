@@ -0,0 +1,249 @@
+// syzygy_steps_test.go — Executable specifications for Syzygy tablebase probing.
+//
+// Mirrors: endgame-tablebases.feature
+// Driving ports:
+//   - chess.OpenSyzygy(path) / Syzygy.ProbeWDL / Syzygy.ProbeDTZ
+//   - engine.UCIHandler.Run(r io.Reader, w io.Writer) with the SyzygyPath option
+//   - testdata/syzygygen, the generator of the test tables
+//
+// The tables in testdata/syzygy are generated by testdata/syzygygen (see
+// their README); scenarios that probe them fail when they are missing.
+
+package acceptance_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ─── Probing ──────────────────────────────────────────────────────────────────
+
+// TestSyzygy_ProbeWDLOfEndgames validates WDL probes against the test tables.
+// Gherkin: "Engine developer probes the win/draw/loss value of an endgame"
+func TestSyzygy_ProbeWDLOfEndgames(t *testing.T) {
+	tb := openTestTablebases(t)
+	cases := []struct {
+		fen  string
+		want chess.WDL
+	}{
+		{"8/8/8/4k3/8/8/8/3QK3 w - - 0 1", chess.WDLWin},
+		{"8/8/8/4k3/8/8/8/3QK3 b - - 0 1", chess.WDLLoss},
+		{"8/8/8/4k3/8/8/8/R3K3 w - - 0 1", chess.WDLWin},
+		{"k7/8/4P3/8/8/8/8/4K3 w - - 0 1", chess.WDLWin},
+		{"4k3/4P3/4K3/8/8/8/8/8 b - - 0 1", chess.WDLDraw},
+		{"8/8/8/4k3/8/8/8/2B1KN2 w - - 0 1", chess.WDLWin},
+	}
+	for _, c := range cases {
+		got, err := tb.ProbeWDL(mustGame(t, c.fen).State)
+		if err != nil {
+			t.Errorf("ProbeWDL(%q): %v", c.fen, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ProbeWDL(%q) = %v, want %v", c.fen, got, c.want)
+		}
+	}
+}
+
+// TestSyzygy_ProbeDTZOfEndgames validates DTZ probes against the test tables.
+// The DTZ tables store White to move, so Black-to-move probes search a ply.
+// Gherkin: "Engine developer probes the distance to zeroing of an endgame"
+func TestSyzygy_ProbeDTZOfEndgames(t *testing.T) {
+	tb := openTestTablebases(t)
+	cases := []struct {
+		fen  string
+		want int
+	}{
+		{"k7/8/1K6/8/8/8/8/6Q1 w - - 0 1", 1},     // mate in one
+		{"k7/8/1K6/8/8/8/8/6Q1 b - - 0 1", -2},    // mated next move
+		{"k7/Q7/1K6/8/8/8/8/8 b - - 0 1", -1},     // checkmated
+		{"4k3/8/4K3/4P3/8/8/8/8 w - - 0 1", 3},    // Kd6 or Kf6, then e6
+		{"4k3/8/4K3/4P3/8/8/8/8 b - - 0 1", -4},   // Black to move in a pawn table
+		{"k7/8/8/8/8/8/1K5P/8 b - - 0 1", -2},     // h-pawn, mirrored onto the a-file
+		{"8/8/8/8/8/8/2k5/KNB5 w - - 0 1", 65},    // the longest KBNvK win
+		{"8/8/8/4k3/8/8/8/2B1KN2 b - - 0 1", -58}, // KBNvK, Black to move
+		{"4k3/4P3/4K3/8/8/8/8/8 b - - 0 1", 0},    // stalemate
+		{"8/8/8/8/8/2k5/4P3/K7 w - - 0 1", 0},     // the king catches the pawn
+	}
+	for _, c := range cases {
+		got, err := tb.ProbeDTZ(mustGame(t, c.fen).State)
+		if err != nil {
+			t.Errorf("ProbeDTZ(%q): %v", c.fen, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("ProbeDTZ(%q) = %d, want %d", c.fen, got, c.want)
+		}
+	}
+
+	// No table has a slot for a pawn on the first or eighth rank.
+	if _, err := chess.NewGameFromFEN("8/8/8/8/8/2k5/8/K3P3 w - - 0 1"); !errors.Is(err, chess.ErrInvalidFEN) {
+		t.Errorf("a pawn on e1: err = %v, want ErrInvalidFEN", err)
+	}
+	s := mustGame(t, "8/8/8/8/8/2k5/4P3/K7 w - - 0 1").State
+	s.Board[chess.SquareOf(4, 0)], s.Board[chess.SquareOf(4, 1)] = chess.WhitePawn, chess.NoPiece
+	if _, err := tb.ProbeDTZ(s); !errors.Is(err, chess.ErrInvalidPosition) {
+		t.Errorf("ProbeDTZ with a pawn on e1: err = %v, want ErrInvalidPosition", err)
+	}
+	if _, err := tb.ProbeWDL(s); !errors.Is(err, chess.ErrInvalidPosition) {
+		t.Errorf("ProbeWDL with a pawn on e1: err = %v, want ErrInvalidPosition", err)
+	}
+}
+
+// TestSyzygy_TestTablesAreReproducible regenerates the small test tables and
+// compares them byte for byte with the checked-in files.
+// Gherkin: "Engine developer regenerates the test tables"
+func TestSyzygy_TestTablesAreReproducible(t *testing.T) {
+	openTestTablebases(t)
+	gen := mustBuildBinary(t, "./testdata/syzygygen")
+	dir := t.TempDir()
+	if out, err := exec.Command(gen, "-out", dir, "KQK", "KRK").CombinedOutput(); err != nil {
+		t.Fatalf("syzygygen: %v\n%s", err, out)
+	}
+	for _, name := range []string{"KQvK", "KRvK"} {
+		for _, ext := range []string{".rtbw", ".rtbz"} {
+			got, err := os.ReadFile(filepath.Join(dir, name+ext))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := os.ReadFile(filepath.Join(testTablebaseDir(t), name+ext))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s%s: regenerated table differs from testdata/syzygy", name, ext)
+			}
+		}
+	}
+}
+
+// TestSyzygy_CapturesResolvedBeforeTableIsTrusted uses a synthetic single-value
+// KQvK table: it claims every position is won for White, yet Black to move can
+// take the undefended queen, which the capture search must find.
+// Gherkin: "Engine developer sees captures resolved before the table is trusted"
+func TestSyzygy_CapturesResolvedBeforeTableIsTrusted(t *testing.T) {
+	tb := openSyntheticKQvK(t)
+
+	got, err := tb.ProbeWDL(mustGame(t, "8/8/8/8/8/8/2k5/2Q4K b - - 0 1").State)
+	if err != nil {
+		t.Fatalf("ProbeWDL: %v", err)
+	}
+	if got != chess.WDLDraw {
+		t.Errorf("queen can be captured: ProbeWDL = %v, want draw", got)
+	}
+
+	got, err = tb.ProbeWDL(mustGame(t, "8/8/8/4k3/8/8/8/3QK3 b - - 0 1").State)
+	if err != nil {
+		t.Fatalf("ProbeWDL: %v", err)
+	}
+	if got != chess.WDLLoss {
+		t.Errorf("queen is safe: ProbeWDL = %v, want loss", got)
+	}
+}
+
+// TestSyzygy_CastlingRightsAreRefused validates that tables are not consulted
+// for positions with castling rights, which Syzygy does not encode.
+// Gherkin: "Engine developer sees positions with castling rights refused"
+func TestSyzygy_CastlingRightsAreRefused(t *testing.T) {
+	tb := openSyntheticKQvK(t)
+	_, err := tb.ProbeWDL(mustGame(t, "4k3/8/8/8/8/8/8/4K2R w K - 0 1").State)
+	if !errors.Is(err, chess.ErrTablebaseMiss) {
+		t.Errorf("ProbeWDL with castling rights: err = %v, want ErrTablebaseMiss", err)
+	}
+}
+
+// ─── Engine Integration ───────────────────────────────────────────────────────
+
+// TestUCIHandler_SyzygyPathKeepsTheWin validates root DTZ probing through UCI.
+// Gherkin: "Engine developer sets SyzygyPath and the engine keeps the win"
+func TestUCIHandler_SyzygyPathKeepsTheWin(t *testing.T) {
+	tb := openTestTablebases(t)
+	const fen = "8/8/8/4k3/8/8/8/R3K3 w - - 0 1"
+
+	var in, out bytes.Buffer
+	fmt.Fprintf(&in, "setoption name SyzygyPath value %s\n", testTablebaseDir(t))
+	fmt.Fprintf(&in, "position fen %s\n", fen)
+	in.WriteString("go movetime 200\n")
+	engine.NewUCIHandler(nil).Run(&in, &out)
+
+	game := mustGame(t, fen)
+	bm := extractBestmove(strings.Split(out.String(), "\n"))
+	after, err := game.Apply(mustParseUCI(t, game, bm))
+	if err != nil {
+		t.Fatalf("bestmove %q: %v\n%s", bm, err, out.String())
+	}
+	if wdl, err := tb.ProbeWDL(after.State); err != nil || wdl != chess.WDLLoss {
+		t.Errorf("after %s the defender should be lost, got %v (%v)", bm, wdl, err)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// testTablebaseDir returns testdata/syzygy under the project root.
+func testTablebaseDir(t *testing.T) string {
+	t.Helper()
+	return filepath.Join(projectRoot(t), "testdata", "syzygy")
+}
+
+// openTestTablebases opens the checked-in tables, failing the test when any
+// of them is missing.
+func openTestTablebases(t *testing.T) *chess.Syzygy {
+	t.Helper()
+	dir := testTablebaseDir(t)
+	for _, name := range []string{"KQvK", "KRvK", "KPvK", "KBNvK", "KBvK", "KNvK"} {
+		for _, ext := range []string{".rtbw", ".rtbz"} {
+			if _, err := os.Stat(filepath.Join(dir, name+ext)); err != nil {
+				t.Fatalf("Syzygy table %s%s missing from %s (see its README): %v", name, ext, dir, err)
+			}
+		}
+	}
+	tb, err := chess.OpenSyzygy(dir)
+	if err != nil {
+		t.Fatalf("OpenSyzygy: %v", err)
+	}
+	return tb
+}
+
+// openSyntheticKQvK writes a KQvK.rtbw file whose two sides each hold a single
+// value: win with White to move, loss with Black to move.
+func openSyntheticKQvK(t *testing.T) *chess.Syzygy {
+	t.Helper()
+	const (
+		king, queen, blackKing = 6, 5, 14 // table piece codes
+		singleValue            = 0x80
+		win, loss              = 4, 0 // stored as WDL + 2
+	)
+	data := []byte{
+		0x71, 0xE8, 0x23, 0x5D, // WDL magic
+		0x01, // split: both sides to move stored
+		0x00, // group order
+		king | king<<4, queen | queen<<4, blackKing | blackKing<<4,
+		0x00, // word alignment
+		singleValue, win,
+		singleValue, loss,
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "KQvK.rtbw"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tb, err := chess.OpenSyzygy(dir)
+	if err != nil {
+		t.Fatalf("OpenSyzygy: %v", err)
+	}
+	if tb.Tables() != 1 || tb.MaxPieces() != 3 {
+		t.Fatalf("found %d tables up to %d pieces, want 1 table of 3", tb.Tables(), tb.MaxPieces())
+	}
+	return tb
+}
@@ -0,0 +1,25 @@
+//go:build slow
+
+// tablebase_slow_test.go — the @slow scenario of retrograde-tablebases.feature.
+// KBNK holds about eight million positions and takes close to a minute.
+
+package acceptance_test
+
+import (
+	"testing"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/tablebase"
+)
+
+// TestTablebase_BishopAndKnightMate validates the longest KBNK mate (33 moves).
+// Gherkin: "Engine developer generates the bishop and knight mate"
+func TestTablebase_BishopAndKnightMate(t *testing.T) {
+	table, err := tablebase.New().Generate("KBNK")
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if _, r := table.Longest(chess.White); r.Outcome != tablebase.Win || r.DTM != 65 {
+		t.Errorf("KBNK longest = %s, want win in 65 plies", r)
+	}
+}
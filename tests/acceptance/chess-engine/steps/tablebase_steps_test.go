@@ -0,0 +1,219 @@
+// tablebase_steps_test.go — Executable specifications for retrograde DTM tables.
+//
+// Mirrors: retrograde-tablebases.feature
+// Driving ports:
+//   - tablebase.New() / Tablebase.Generate / Verify / Probe / BestMove
+//   - Tablebase.WriteDir / tablebase.OpenDir
+//   - chess.GameState.Unmoves / Unplay
+//
+// The @slow KBNK scenario lives in tablebase_slow_test.go (-tags slow).
+
+package acceptance_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/tablebase"
+)
+
+// ─── Generation ───────────────────────────────────────────────────────────────
+
+// TestTablebase_LongestMates validates generation against the known longest
+// mates: KQK in 10 moves, KRK in 16, KPK in 28.
+// Gherkin: "Engine developer generates a table and finds its longest mate"
+func TestTablebase_LongestMates(t *testing.T) {
+	tb := generatedTablebase(t)
+	for material, plies := range map[string]int{"KQK": 19, "KRK": 31, "KPK": 55} {
+		_, r := tb.Table(material).Longest(chess.White)
+		if r.Outcome != tablebase.Win || r.DTM != plies {
+			t.Errorf("%s longest = %s, want win in %d plies", material, r, plies)
+		}
+	}
+}
+
+// TestTablebase_AgreesWithMoveGenerator checks every stored value against the
+// values of its children found with the forward move generator.
+// Gherkin: "Engine developer cross-checks every table with the move generator"
+func TestTablebase_AgreesWithMoveGenerator(t *testing.T) {
+	tb := generatedTablebase(t)
+	for _, material := range []string{"KQK", "KRK", "KPK"} {
+		if err := tb.Verify(material); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// TestUnmoves_RetractIntoLegalPredecessors validates the reverse move
+// generator: each retracted move, replayed forward, restores the position.
+// Gherkin: "Engine developer sees every unmove retracted into a legal predecessor"
+func TestUnmoves_RetractIntoLegalPredecessors(t *testing.T) {
+	for _, fen := range []string{
+		"4k3/8/8/3pP3/8/2N5/1P3B2/R3K3 b - - 0 1",
+		"r3k3/2p5/8/8/1Q1p4/8/6P1/4K2n w - - 0 1",
+	} {
+		s := mustGame(t, fen).State
+		unmoves := s.Unmoves()
+		if len(unmoves) == 0 {
+			t.Fatalf("%s: no unmoves", fen)
+		}
+		for _, m := range unmoves {
+			prev := s.Unplay(m)
+			if prev.ActiveColor == s.ActiveColor {
+				t.Fatalf("%s: unplaying %s kept the side to move", fen, m.UCIString())
+			}
+			var found bool
+			for _, legal := range prev.LegalMoves() {
+				if legal == m {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s: %s is not legal in its predecessor %s", fen, m.UCIString(), chess.Game{State: prev}.ToFEN())
+				continue
+			}
+			if !chess.SamePosition(prev.Play(m), s) {
+				t.Errorf("%s: replaying %s does not restore the position", fen, m.UCIString())
+			}
+		}
+	}
+}
+
+// ─── Probing ──────────────────────────────────────────────────────────────────
+
+// TestTablebase_ProbeDistanceToMate validates probes of known positions.
+// Gherkin: "Engine developer probes the distance to mate of an endgame"
+func TestTablebase_ProbeDistanceToMate(t *testing.T) {
+	tb := generatedTablebase(t)
+	cases := []struct {
+		fen  string
+		want tablebase.Result
+	}{
+		{"k7/8/1K6/8/8/8/8/6Q1 w - - 0 1", tablebase.Result{Outcome: tablebase.Win, DTM: 1}},
+		{"k5Q1/8/1K6/8/8/8/8/8 b - - 0 1", tablebase.Result{Outcome: tablebase.Loss, DTM: 0}},
+		{"4k3/4P3/4K3/8/8/8/8/8 b - - 0 1", tablebase.Result{Outcome: tablebase.Draw}},
+		{"8/8/8/8/8/8/2k5/2Q4K b - - 0 1", tablebase.Result{Outcome: tablebase.Draw}},
+	}
+	for _, c := range cases {
+		got, err := tb.Probe(mustGame(t, c.fen).State)
+		if err != nil {
+			t.Errorf("Probe(%q): %v", c.fen, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("Probe(%q) = %s, want %s", c.fen, got, c.want)
+		}
+	}
+}
+
+// TestTablebase_BestMovesMateOnSchedule plays the table's best moves for both
+// sides from the longest KRK win and expects mate after exactly 31 plies.
+// Gherkin: "Engine developer follows the table's best moves to mate"
+func TestTablebase_BestMovesMateOnSchedule(t *testing.T) {
+	tb := generatedTablebase(t)
+	start, _ := tb.Table("KRK").Longest(chess.White)
+	game := mustGame(t, chess.Game{State: start}.ToFEN())
+	for ply := 0; ply < 31; ply++ {
+		m, _, err := tb.BestMove(game)
+		if err != nil {
+			t.Fatalf("ply %d: %v", ply, err)
+		}
+		if game, err = game.Apply(m); err != nil {
+			t.Fatalf("ply %d: %s: %v", ply, m.UCIString(), err)
+		}
+	}
+	if game.Result() != chess.WhiteWins {
+		t.Errorf("after 31 plies the result is %v, want White checkmates", game.Result())
+	}
+}
+
+// TestTablebase_UnsupportedPositionsRefused validates the ErrNotCovered and
+// ErrInvalidMaterial paths.
+// Gherkin: "Engine developer sees unsupported positions refused"
+func TestTablebase_UnsupportedPositionsRefused(t *testing.T) {
+	tb := generatedTablebase(t)
+	for _, fen := range []string{
+		"4k3/8/8/8/8/8/8/R3K3 w Q - 0 1",  // castling rights
+		"k7/8/1K6/8/8/8/8/7Q w - - 0 1",   // Black already in check
+		"4k3/8/8/8/8/8/8/2BNK3 w - - 0 1", // no KBNK table loaded
+	} {
+		if _, err := tb.Probe(mustGame(t, fen).State); !errors.Is(err, tablebase.ErrNotCovered) {
+			t.Errorf("Probe(%q): err = %v, want ErrNotCovered", fen, err)
+		}
+	}
+	if _, err := tablebase.New().Generate("KPKP"); !errors.Is(err, tablebase.ErrInvalidMaterial) {
+		t.Errorf("Generate(KPKP): err = %v, want ErrInvalidMaterial", err)
+	}
+}
+
+// ─── Persistence ──────────────────────────────────────────────────────────────
+
+// TestTablebase_WriteAndOpenRoundTrip validates the binary format.
+// Gherkin: "Engine developer saves the tables and loads them back"
+func TestTablebase_WriteAndOpenRoundTrip(t *testing.T) {
+	tb := generatedTablebase(t)
+	dir := t.TempDir()
+	krk := tablebase.New()
+	krk.Add(tb.Table("KRK"))
+	krk.Add(tb.Table("KK"))
+	if err := krk.WriteDir(dir); err != nil {
+		t.Fatalf("WriteDir: %v", err)
+	}
+	loaded, err := tablebase.OpenDir(dir)
+	if err != nil {
+		t.Fatalf("OpenDir: %v", err)
+	}
+	for _, fen := range []string{
+		"8/8/8/4k3/8/8/8/R3K3 w - - 0 1",
+		"8/8/8/4k3/8/8/8/R3K3 b - - 0 1",
+		"8/8/8/8/8/8/2k5/2R4K b - - 0 1",
+	} {
+		s := mustGame(t, fen).State
+		want, _ := tb.Probe(s)
+		if got, err := loaded.Probe(s); err != nil || got != want {
+			t.Errorf("loaded Probe(%q) = %s (%v), want %s", fen, got, err, want)
+		}
+	}
+
+	path := filepath.Join(dir, "KRK"+tablebase.Ext)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data[:len(data)/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tablebase.Open(path); !errors.Is(err, tablebase.ErrInvalidTable) {
+		t.Errorf("truncated table: err = %v, want ErrInvalidTable", err)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+var (
+	tablebaseOnce sync.Once
+	tablebaseErr  error
+	tablebaseGen  *tablebase.Tablebase
+)
+
+// generatedTablebase returns KQK, KRK and KPK with their sub-tables, generated
+// once and shared by the scenarios that only read them.
+func generatedTablebase(t *testing.T) *tablebase.Tablebase {
+	t.Helper()
+	tablebaseOnce.Do(func() {
+		tablebaseGen = tablebase.New()
+		for _, material := range []string{"KQK", "KRK", "KPK"} {
+			if _, tablebaseErr = tablebaseGen.Generate(material); tablebaseErr != nil {
+				return
+			}
+		}
+	})
+	if tablebaseErr != nil {
+		t.Fatalf("Generate: %v", tablebaseErr)
+	}
+	return tablebaseGen
+}
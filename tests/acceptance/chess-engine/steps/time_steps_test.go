@@ -0,0 +1,207 @@
+// time_steps_test.go — Executable specifications for the time manager.
+//
+// Mirrors: time-management.feature
+// Driving ports:
+//   - engine.TimeControl.Limits, engine.TimeManager
+//   - engine.Config{MoveOverhead}.Search
+
+package acceptance_test
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+)
+
+// ─── Allocation ───────────────────────────────────────────────────────────────
+
+// TestTime_MoveTimeLessOverhead validates fixed move times.
+// Gherkin: "Engine holds the move overhead back from a fixed move time"
+func TestTime_MoveTimeLessOverhead(t *testing.T) {
+	l := engine.TimeControl{MoveTime: time.Second}.Limits(chess.White, 30*time.Millisecond)
+	if l.Soft != 970*time.Millisecond || l.Hard != 970*time.Millisecond {
+		t.Errorf("limits %+v, want 970ms soft and hard", l)
+	}
+}
+
+// TestTime_MovesToGoAllocation validates movestogo-aware allocation.
+// Gherkin: "Engine allocates more per move when fewer moves are left to the control"
+func TestTime_MovesToGoAllocation(t *testing.T) {
+	limits := func(movesToGo int) engine.TimeLimits {
+		return engine.TimeControl{BTime: time.Minute, MovesToGo: movesToGo}.Limits(chess.Black, engine.DefaultMoveOverhead)
+	}
+	if few, many := limits(10), limits(40); few.Soft <= many.Soft || few.Hard <= many.Hard {
+		t.Errorf("10 to go %+v, 40 to go %+v; want more time with fewer moves", few, many)
+	}
+	if last := limits(1); last.Hard > 45*time.Second || last.Soft < 20*time.Second {
+		t.Errorf("one to go %+v, want a hard limit leaving a quarter of the minute", last)
+	}
+}
+
+// TestTime_EmergencyMode validates the low-clock allocation.
+// Gherkin: "Engine switches to emergency mode when its clock runs low"
+func TestTime_EmergencyMode(t *testing.T) {
+	l := engine.TimeControl{WTime: 500 * time.Millisecond}.Limits(chess.White, engine.DefaultMoveOverhead)
+	if !l.Emergency {
+		t.Errorf("limits %+v, want emergency mode", l)
+	}
+	if l.Hard > 100*time.Millisecond {
+		t.Errorf("hard limit %v, want at most a fifth of the clock", l.Hard)
+	}
+	if l := (engine.TimeControl{WTime: 500 * time.Millisecond, WInc: time.Second}).Limits(chess.White, 0); l.Emergency {
+		t.Error("an increment refilling the clock must not count as an emergency")
+	}
+}
+
+// ─── Instability ──────────────────────────────────────────────────────────────
+
+// TestTime_UnstableBestMoveStretchesSoftLimit validates best-move instability.
+// Gherkin: "Engine thinks longer when the best move keeps changing"
+func TestTime_UnstableBestMoveStretchesSoftLimit(t *testing.T) {
+	g := mustGame(t, StartingFEN)
+	moves := g.LegalMoves()
+	tm := engine.NewTimeManager(engine.TimeControl{WTime: time.Minute}, chess.White, 0)
+	base := tm.Limits().Soft
+	tm.Iteration(moves[0], 20)
+	tm.Iteration(moves[0], 20)
+	if tm.Soft() != base {
+		t.Errorf("stable search: soft %v, want %v", tm.Soft(), base)
+	}
+	for i := 1; i <= 6; i++ {
+		tm.Iteration(moves[i], 20)
+		if tm.Soft() <= base {
+			t.Errorf("after %d changes: soft %v not stretched beyond %v", i, tm.Soft(), base)
+		}
+	}
+	if tm.Soft() > tm.Limits().Hard {
+		t.Errorf("soft %v beyond the hard limit %v", tm.Soft(), tm.Limits().Hard)
+	}
+}
+
+// TestTime_ScoreDropStretchesSoftLimit validates score-drop scaling.
+// Gherkin: "Engine thinks longer when the score drops"
+func TestTime_ScoreDropStretchesSoftLimit(t *testing.T) {
+	m := mustGame(t, StartingFEN).LegalMoves()[0]
+	tm := engine.NewTimeManager(engine.TimeControl{WTime: time.Minute}, chess.White, 0)
+	base := tm.Limits().Soft
+	tm.Iteration(m, 40)
+	tm.Iteration(m, -60)
+	if want := base * 3 / 2; tm.Soft() != want {
+		t.Errorf("soft %v after a pawn's drop, want %v", tm.Soft(), want)
+	}
+}
+
+// ─── Never Flagging ───────────────────────────────────────────────────────────
+
+// TestTime_NeverFlags validates the time manager over simulated games.
+// Gherkin: "Engine never flags over a long game"
+func TestTime_NeverFlags(t *testing.T) {
+	controls := []struct {
+		name            string
+		base, inc       time.Duration
+		movesPerControl int
+	}{
+		{"1+0", time.Minute, 0, 0},
+		{"3+2", 3 * time.Minute, 2 * time.Second, 0},
+		{"40/120", 120 * time.Minute, 0, 40},
+	}
+	speeds := []struct {
+		name  string
+		first time.Duration // time of the depth-1 iteration
+		grow  float64       // time of each iteration over the previous one
+	}{
+		{"fast", time.Millisecond, 2},
+		{"typical", 5 * time.Millisecond, 2.5},
+		{"slow", 200 * time.Millisecond, 3},
+		{"never completes an iteration", time.Hour, 1},
+	}
+	for _, c := range controls {
+		for _, sp := range speeds {
+			left, used := simulateClockedGame(c.base, c.inc, c.movesPerControl, 150, sp.first, sp.grow)
+			if left <= 0 {
+				t.Errorf("%s, %s search: flagged", c.name, sp.name)
+				continue
+			}
+			// A search stopped only by the clock should use a good part of it;
+			// fast searches legitimately finish early.
+			if sp.first >= 5*time.Millisecond && used < c.base/4 {
+				t.Errorf("%s, %s search: used only %v of the first %v", c.name, sp.name, used, c.base)
+			}
+		}
+	}
+}
+
+// TestTime_RealSearchRespectsHardLimit validates the deadline in the search.
+// Gherkin: "Engine search respects the hard limit on a real clock"
+func TestTime_RealSearchRespectsHardLimit(t *testing.T) {
+	tc := engine.TimeControl{WTime: 2 * time.Second, BTime: 2 * time.Second}
+	cfg := engine.Config{MoveOverhead: engine.DefaultMoveOverhead}
+	hard := tc.Limits(chess.White, cfg.MoveOverhead).Hard
+	start := time.Now()
+	res := cfg.Search(context.Background(), mustGame(t, KiwipeteFEN), tc, io.Discard)
+	assertWithinDuration(t, hard+50*time.Millisecond, time.Since(start), "search must stop at its hard limit")
+	assertMoveIsLegal(t, res.BestMove, mustGame(t, KiwipeteFEN).LegalMoves())
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// simulatedLag is the time a move takes to reach the clock in
+// simulateClockedGame: a third of the move overhead, which is a safety margin
+// rather than the lag expected on every move.
+const simulatedLag = engine.DefaultMoveOverhead / 3
+
+// simulateClockedGame plays moves for each side on fake clocks of base plus
+// inc per move, with base added again every movesPerControl moves (0 for
+// sudden death). Every search runs iterations taking first, then grow times
+// the previous one, with the best move changing and the score falling at
+// random; the search is cut off at the hard limit, and every move costs
+// simulatedLag on top. It returns the least time either clock held
+// after a move, and the time spent by White in the first control.
+func simulateClockedGame(base, inc time.Duration, movesPerControl, moves int, first time.Duration, grow float64) (time.Duration, time.Duration) {
+	rng := rand.New(rand.NewSource(1))
+	candidates := []chess.Move{{From: 12, To: 28}, {From: 6, To: 21}, {From: 11, To: 27}}
+	clock := [2]time.Duration{base, base}
+	least, used := base, time.Duration(0)
+	for n := 0; n < moves; n++ {
+		for _, side := range []chess.Color{chess.White, chess.Black} {
+			tc := engine.TimeControl{WTime: clock[chess.White], BTime: clock[chess.Black], WInc: inc, BInc: inc}
+			if movesPerControl > 0 {
+				tc.MovesToGo = movesPerControl - n%movesPerControl
+			}
+			tm := engine.NewTimeManager(tc, side, engine.DefaultMoveOverhead)
+			hard := tm.Limits().Hard
+			elapsed, iteration, score := time.Duration(0), first, 0
+			for {
+				if elapsed+iteration >= hard {
+					elapsed = hard
+					break
+				}
+				elapsed += iteration
+				score -= rng.Intn(40)
+				tm.Iteration(candidates[rng.Intn(len(candidates))], score)
+				if !tm.Continue(elapsed) {
+					break
+				}
+				iteration = time.Duration(float64(iteration) * grow)
+			}
+			clock[side] -= elapsed + simulatedLag
+			if side == chess.White && (movesPerControl == 0 || n < movesPerControl) {
+				used += elapsed + simulatedLag
+			}
+			least = min(least, clock[side])
+			if clock[side] <= 0 {
+				return clock[side], used
+			}
+			clock[side] += inc
+			if movesPerControl > 0 && (n+1)%movesPerControl == 0 {
+				clock[side] += base
+			}
+		}
+	}
+	return least, used
+}
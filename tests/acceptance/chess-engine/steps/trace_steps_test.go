@@ -0,0 +1,284 @@
+// trace_steps_test.go — Executable specifications for the search trace.
+//
+// Mirrors: search-trace.feature
+// Driving ports:
+//   - engine.Config.Trace / engine.NewTracer / Tracer.Last
+//   - engine.Trace.WriteJSON / WriteDOT
+//   - engine.UCIHandler ("trace on", "trace dump", "debug on")
+
+package acceptance_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	engine "chess_go/internal/engine"
+)
+
+// ─── Recording ────────────────────────────────────────────────────────────────
+
+// TestTrace_RecordsTreeWithinPlyLimit validates MaxPly.
+// Gherkin: "Developer traces a search within a ply limit"
+func TestTrace_RecordsTreeWithinPlyLimit(t *testing.T) {
+	tracer := engine.NewTracer(2, 0)
+	g := mustGame(t, StartingFEN)
+	engine.Config{Trace: tracer}.Search(context.Background(), g, engine.TimeControl{Depth: 3}, io.Discard)
+
+	tr := tracer.Last()
+	if tr == nil {
+		t.Fatal("no trace after a completed search")
+	}
+	if tr.Depth != 3 || tr.FEN != StartingFEN {
+		t.Errorf("trace of depth %d from %q, want depth 3 from the start", tr.Depth, tr.FEN)
+	}
+	if got := len(tr.Root.Children); got != len(g.LegalMoves()) {
+		t.Errorf("%d root moves recorded, want %d", got, len(g.LegalMoves()))
+	}
+	nodes := walkTrace(tr.Root, func(n *engine.TraceNode) {
+		if n.Ply > 2 {
+			t.Fatalf("node %s at ply %d", n.Move.UCIString(), n.Ply)
+		}
+	})
+	if nodes != tr.Nodes {
+		t.Errorf("walked %d nodes, trace counts %d", nodes, tr.Nodes)
+	}
+	if !tr.Truncated {
+		t.Error("a depth 3 search traced to 2 plies must be marked truncated")
+	}
+}
+
+// TestTrace_RecordsWithinNodeBudget validates MaxNodes.
+// Gherkin: "Developer traces a search within a node budget"
+func TestTrace_RecordsWithinNodeBudget(t *testing.T) {
+	tracer := engine.NewTracer(64, 30)
+	engine.Config{Trace: tracer}.Search(context.Background(), mustGame(t, KiwipeteFEN), engine.TimeControl{Depth: 2}, io.Discard)
+	tr := tracer.Last()
+	if tr == nil {
+		t.Fatal("no trace after a completed search")
+	}
+	if n := walkTrace(tr.Root, func(*engine.TraceNode) {}); n != 30 || tr.Nodes != 30 || !tr.Truncated {
+		t.Errorf("recorded %d nodes (counted %d, truncated %v), want 30 and truncated", n, tr.Nodes, tr.Truncated)
+	}
+}
+
+// TestTrace_RecordsCutoffReasons validates extensions, mates, beta cutoffs and stand-pats.
+// Gherkin: "Developer sees why each node returned"
+func TestTrace_RecordsCutoffReasons(t *testing.T) {
+	tracer := engine.NewTracer(8, 0)
+	engine.Config{Trace: tracer}.Search(context.Background(), mustGame(t, BackRankMateFEN), engine.TimeControl{Depth: 2}, io.Discard)
+	tr := tracer.Last()
+	if tr == nil {
+		t.Fatal("no trace after a completed search")
+	}
+
+	var mate *engine.TraceNode
+	for _, c := range tr.Root.Children {
+		if c.Move.UCIString() == "a1a8" {
+			mate = c
+		}
+	}
+	if mate == nil || mate.Extension != 1 || mate.Cutoff != engine.CutoffMate || mate.Score > -engine.MateScore+10 {
+		t.Fatalf("Ra8# recorded as %+v, want a check extension and a mate cutoff", mate)
+	}
+
+	engine.Config{Trace: tracer}.Search(context.Background(), mustGame(t, KiwipeteFEN), engine.TimeControl{Depth: 2}, io.Discard)
+	var beta, standPat int
+	walkTrace(tracer.Last().Root, func(n *engine.TraceNode) {
+		switch n.Cutoff {
+		case engine.CutoffBeta:
+			beta++
+			if n.Score < n.Beta {
+				t.Errorf("beta cutoff at %s scores %d below beta %d", n.Move.UCIString(), n.Score, n.Beta)
+			}
+		case engine.CutoffStandPat:
+			standPat++
+			if !n.Quiescence {
+				t.Errorf("stand-pat at %s outside quiescence", n.Move.UCIString())
+			}
+		}
+	})
+	if beta == 0 || standPat == 0 {
+		t.Errorf("%d beta cutoffs and %d stand-pats recorded, want some of each", beta, standPat)
+	}
+}
+
+// TestTrace_ScoresMatchResult validates the recorded scores.
+// Gherkin: "Developer sees scores that agree with the search result"
+func TestTrace_ScoresMatchResult(t *testing.T) {
+	tracer := engine.NewTracer(1, 0)
+	res := engine.Config{Trace: tracer}.Search(context.Background(), mustGame(t, KiwipeteFEN), engine.TimeControl{Depth: 3}, io.Discard)
+	tr := tracer.Last()
+	if tr == nil || tr.Depth != res.Depth {
+		t.Fatalf("trace %+v, want the depth %d iteration", tr, res.Depth)
+	}
+	if tr.Root.Score != res.Score {
+		t.Errorf("root score %d, search score %d", tr.Root.Score, res.Score)
+	}
+	for _, c := range tr.Root.Children {
+		if c.Move == res.BestMove && c.Score != -res.Score {
+			t.Errorf("best move %s scores %d at its node, want %d", c.Move.UCIString(), c.Score, -res.Score)
+		}
+	}
+}
+
+// TestTrace_DoesNotChangeSearch validates that tracing leaves the bench signature alone.
+// Gherkin: "Tracing does not change the search"
+func TestTrace_DoesNotChangeSearch(t *testing.T) {
+	plain, err := engine.Config{}.Bench(context.Background(), 2, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	traced, err := engine.Config{Trace: engine.NewTracer(0, 0)}.Bench(context.Background(), 2, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain.Nodes != traced.Nodes {
+		t.Errorf("bench signature %d with a tracer, %d without", traced.Nodes, plain.Nodes)
+	}
+}
+
+// ─── Export ───────────────────────────────────────────────────────────────────
+
+// TestTrace_ExportsJSONAndDOT validates both formats.
+// Gherkin: "Developer exports the trace as JSON and Graphviz DOT"
+func TestTrace_ExportsJSONAndDOT(t *testing.T) {
+	tracer := engine.NewTracer(2, 0)
+	engine.Config{Trace: tracer}.Search(context.Background(), mustGame(t, StartingFEN), engine.TimeControl{Depth: 2}, io.Discard)
+	tr := tracer.Last()
+	if tr == nil {
+		t.Fatal("no trace after a completed search")
+	}
+
+	var js bytes.Buffer
+	if err := tr.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		FEN   string
+		Depth int
+		Nodes int
+		Root  struct {
+			Children []struct {
+				Move  string
+				Alpha int
+				Beta  int
+			}
+		}
+	}
+	if err := json.Unmarshal(js.Bytes(), &doc); err != nil {
+		t.Fatalf("JSON trace: %v", err)
+	}
+	if doc.FEN != StartingFEN || doc.Depth != 2 || doc.Nodes != tr.Nodes || len(doc.Root.Children) != 20 {
+		t.Errorf("JSON trace header %q depth %d nodes %d with %d root moves", doc.FEN, doc.Depth, doc.Nodes, len(doc.Root.Children))
+	}
+	if len(doc.Root.Children) > 0 {
+		mustParseUCI(t, mustGame(t, StartingFEN), doc.Root.Children[0].Move)
+	}
+
+	var dot bytes.Buffer
+	if err := tr.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dot.String(), "digraph") || strings.Count(dot.String(), "->") != tr.Nodes-1 {
+		t.Errorf("DOT trace with %d edges for %d nodes:\n%.300s", strings.Count(dot.String(), "->"), tr.Nodes, dot.String())
+	}
+}
+
+// ─── UCI ──────────────────────────────────────────────────────────────────────
+
+// TestTrace_UCITraceDump validates "trace on" and "trace dump".
+// Gherkin: "Developer dumps the last search from a UCI session"
+func TestTrace_UCITraceDump(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		engine.NewUCIHandler(nil).Run(inR, outW)
+		outW.Close()
+	}()
+	out := bufio.NewScanner(outR)
+	waitFor := func(prefix string) string {
+		t.Helper()
+		for out.Scan() {
+			if strings.HasPrefix(out.Text(), prefix) {
+				return out.Text()
+			}
+		}
+		t.Fatalf("no %q line from the handler", prefix)
+		return ""
+	}
+
+	io.WriteString(inW, "trace on ply 2\nposition startpos moves e2e4\ngo depth 3\n")
+	waitFor("bestmove")
+	io.WriteString(inW, "trace dump json "+path+"\n")
+	if line := waitFor("info string"); !strings.Contains(line, "trace") {
+		t.Errorf("trace dump answered %q", line)
+	}
+	inW.Close()
+	go io.Copy(io.Discard, outR)
+	<-done
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		FEN   string
+		Depth int
+	}
+	if err := json.Unmarshal(data, &doc); err != nil || doc.Depth != 3 || !strings.Contains(doc.FEN, " b ") {
+		t.Errorf("dumped trace %+v (%v), want depth 3 with Black to move", doc, err)
+	}
+}
+
+// TestTrace_UCIDumpWithoutTrace validates the answer before "trace on".
+// Gherkin: "Developer dumps before tracing"
+func TestTrace_UCIDumpWithoutTrace(t *testing.T) {
+	var out bytes.Buffer
+	engine.NewUCIHandler(nil).Run(strings.NewReader("trace dump\n"), &out)
+	if !strings.HasPrefix(out.String(), "info string") {
+		t.Errorf("trace dump before trace on answered %q", out.String())
+	}
+}
+
+// TestTrace_UCIDebugKeepsItsMeaning validates that "debug on" reports
+// ignored commands, as UCI intends, and does not trace.
+// Gherkin: "GUI switches on the UCI debug mode"
+func TestTrace_UCIDebugKeepsItsMeaning(t *testing.T) {
+	run := func(input string) string {
+		var out bytes.Buffer
+		engine.NewUCIHandler(nil).Run(strings.NewReader(input), &out)
+		return out.String()
+	}
+	if out := run("frobnicate\n"); out != "" {
+		t.Errorf("unknown command without debug answered %q, want nothing", out)
+	}
+	out := run("debug on\nfrobnicate\nposition startpos\ngo depth 2\ntrace dump\ndebug off\nfrobnicate\n")
+	if n := strings.Count(out, "info string unknown command \"frobnicate\""); n != 1 {
+		t.Errorf("%d reports of the unknown command, want one while debug is on:\n%s", n, out)
+	}
+	if !strings.Contains(out, "no search traced") {
+		t.Errorf("debug on traced the search:\n%s", out)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// walkTrace calls visit on every node below and including n and returns their number.
+func walkTrace(n *engine.TraceNode, visit func(*engine.TraceNode)) int {
+	visit(n)
+	count := 1
+	for _, c := range n.Children {
+		count += walkTrace(c, visit)
+	}
+	return count
+}
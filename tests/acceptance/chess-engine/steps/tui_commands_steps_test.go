@@ -0,0 +1,169 @@
+// tui_commands_steps_test.go — Executable specifications for the TUI commands.
+//
+// Mirrors: tui-commands.feature
+// Driving ports:
+//   - tui.Game.Run, with commands typed at the move prompt
+//   - tui.Game.WithSearch
+
+package acceptance_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tui"
+)
+
+// ─── Moves ────────────────────────────────────────────────────────────────────
+
+// TestTUICommands_Undo validates taking back a move pair.
+// Gherkin: "Player takes back the last move pair"
+func TestTUICommands_Undo(t *testing.T) {
+	out := playTUI(t, "e2e4\nundo\n", scriptedEngine(t, "e7e5"))
+	assertPieceOn(t, out, "e2", 'P')
+	assertPieceOn(t, out, "e4", '.')
+	assertPieceOn(t, out, "e7", 'p')
+	assertPieceOn(t, out, "e5", '.')
+	if !strings.Contains(lastBoardAndAfter(out), "White to move") {
+		t.Errorf("want White to move after the undo:\n%s", out)
+	}
+
+	s := tui.DefaultSetup()
+	s.Human = chess.Black
+	out = playTUISetup(t, s, "undo\n", scriptedEngine(t, "e2e4"))
+	if !strings.Contains(out, "Nothing to undo.") {
+		t.Errorf("want nothing to undo before the player's first move:\n%s", out)
+	}
+}
+
+// TestTUICommands_Hint validates the hint.
+// Gherkin: "Player asks for a hint"
+func TestTUICommands_Hint(t *testing.T) {
+	out := playTUI(t, "hint\n", scriptedEngine(t, "g1f3"))
+	if !strings.Contains(out, "Hint: Nf3\n") {
+		t.Errorf("want the hint in SAN:\n%s", out)
+	}
+	assertBoardUnchanged(t, out)
+}
+
+// TestTUICommands_Flip validates flipping the board.
+// Gherkin: "Player flips the board"
+func TestTUICommands_Flip(t *testing.T) {
+	out := playTUI(t, "flip\n", noOpEngine(t))
+	if rows := lastBoardRows(t, out); !strings.HasPrefix(rows[0], "1 | R | N | B | K | Q |") {
+		t.Errorf("top row = %q, want rank 1 from the h-file", rows[0])
+	}
+}
+
+// ─── Positions and Files ──────────────────────────────────────────────────────
+
+// TestTUICommands_Save validates saving the game.
+// Gherkin: "Player saves the game as PGN"
+func TestTUICommands_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.pgn")
+	out := playTUI(t, "e2e4\nsave "+path+"\n", scriptedEngine(t, "e7e5"))
+	if !strings.Contains(out, "Saved the game to "+path) {
+		t.Errorf("want the save confirmed:\n%s", out)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read saved game: %v", err)
+	}
+	games, err := chess.ReadPGN(bytes.NewReader(data))
+	if err != nil || len(games) != 1 {
+		t.Fatalf("saved game does not read back (%v):\n%s", err, data)
+	}
+	if g := games[0]; len(g.Moves) != 2 || g.Tags["White"] != "Player" || g.Result != "*" {
+		t.Errorf("saved game has %d moves, White %q, result %q:\n%s", len(g.Moves), g.Tags["White"], g.Result, data)
+	}
+	if !strings.Contains(string(data), "1. e4 e5 *") {
+		t.Errorf("want the moves in SAN:\n%s", data)
+	}
+}
+
+// TestTUICommands_Load validates loading a position.
+// Gherkin: "Player loads a position from a FEN or a PGN file"
+func TestTUICommands_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.pgn")
+	if err := os.WriteFile(path, []byte("1. d4 d5 2. c4 *\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := playTUI(t, "load "+BackRankMateFEN+"\n", noOpEngine(t))
+	assertPieceOn(t, out, "a1", 'R')
+	assertPieceOn(t, out, "e1", '.')
+
+	out = playTUI(t, "load "+path+"\n", scriptedEngine(t, "e7e6"))
+	assertPieceOn(t, out, "c4", 'P')
+	assertPieceOn(t, out, "e6", 'p')
+
+	out = playTUI(t, "load nonsense\n", noOpEngine(t))
+	if !strings.Contains(out, "Cannot load nonsense") {
+		t.Errorf("want the unreadable position reported:\n%s", out)
+	}
+	assertBoardUnchanged(t, out)
+}
+
+// TestTUICommands_FEN validates printing the FEN.
+// Gherkin: "Player prints the position as FEN"
+func TestTUICommands_FEN(t *testing.T) {
+	g := mustGame(t, StartingFEN)
+	for _, uci := range []string{"e2e4", "e7e5"} {
+		g, _ = g.Apply(mustParseUCI(t, g, uci))
+	}
+	out := playTUI(t, "e2e4\nfen\n", scriptedEngine(t, "e7e5"))
+	if !strings.Contains(out, g.ToFEN()+"\n") {
+		t.Errorf("want %q printed:\n%s", g.ToFEN(), out)
+	}
+}
+
+// ─── Ending the Game ──────────────────────────────────────────────────────────
+
+// TestTUICommands_Resign validates resigning.
+// Gherkin: "Player resigns"
+func TestTUICommands_Resign(t *testing.T) {
+	out := playTUI(t, "resign\ne2e4\n", noOpEngine(t))
+	if !strings.HasSuffix(out, "You resign. Black wins. 0-1\n") {
+		t.Errorf("want the resignation to end the game:\n%s", out)
+	}
+}
+
+// TestTUICommands_Draw validates draw offers.
+// Gherkin: "The engine accepts or declines a draw by its evaluation"
+func TestTUICommands_Draw(t *testing.T) {
+	evaluating := func(score int) tui.SearchFunc {
+		return func(g chess.Game, _ engine.TimeControl, _ func(engine.SearchResult)) engine.SearchResult {
+			return engine.SearchResult{BestMove: g.LegalMoves()[0], Score: score, Depth: 4}
+		}
+	}
+	var out bytes.Buffer
+	tui.NewGame(strings.NewReader("draw\n"), &out, noOpEngine(t)).WithSearch(evaluating(300)).Run()
+	if !strings.HasSuffix(out.String(), "The engine accepts the draw. 1/2-1/2\n") {
+		t.Errorf("an engine 3 pawns down must accept:\n%s", out.String())
+	}
+
+	out.Reset()
+	tui.NewGame(strings.NewReader("draw\n"), &out, noOpEngine(t)).WithSearch(evaluating(-300)).Run()
+	if !strings.Contains(out.String(), "The engine declines the draw.\n") {
+		t.Errorf("an engine 3 pawns up must decline:\n%s", out.String())
+	}
+
+	if out := playTUI(t, "draw\n", scriptedEngine(t, "e2e4")); !strings.Contains(out, "The engine declines the draw.") {
+		t.Errorf("an engine without an evaluation must decline:\n%s", out)
+	}
+}
+
+// TestTUICommands_Help validates the command list.
+// Gherkin: "Player asks for help"
+func TestTUICommands_Help(t *testing.T) {
+	out := playTUI(t, "help\n", noOpEngine(t))
+	for _, cmd := range []string{"undo", "hint", "flip", "save FILE", "load FILE|FEN", "fen", "resign", "draw", "help"} {
+		if !strings.Contains(out, "\n  "+cmd+" ") {
+			t.Errorf("help must list %q:\n%s", cmd, out)
+		}
+	}
+}
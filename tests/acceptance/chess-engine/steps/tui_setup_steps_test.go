@@ -0,0 +1,279 @@
+// tui_setup_steps_test.go — Executable specifications for the TUI game setup.
+//
+// Mirrors: tui-setup.feature
+// Driving ports:
+//   - tui.NewGameWithSetup, tui.Game.Menu and tui.Game.Run with injected I/O
+//   - tui.ParseSide, tui.ParseThink, tui.LoadStart, tui.LoadPGN
+//   - the chess-go binary with setup flags and piped stdin
+
+package acceptance_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	chess "chess_go/internal/chess"
+	"chess_go/internal/clock"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tui"
+)
+
+// ─── Side ─────────────────────────────────────────────────────────────────────
+
+// TestTUISetup_PlayerAsBlackSeesFlippedBoard validates playing Black.
+// Gherkin: "Player plays Black and sees the board from Black's side"
+func TestTUISetup_PlayerAsBlackSeesFlippedBoard(t *testing.T) {
+	s := tui.DefaultSetup()
+	s.Human = chess.Black
+	rendered := playTUISetup(t, s, "", scriptedEngine(t, "e2e4"))
+	if !strings.Contains(rendered, "Engine plays e4") {
+		t.Fatalf("the engine must move first for White:\n%s", rendered)
+	}
+	rows := lastBoardRows(t, rendered)
+	if !strings.HasPrefix(rows[0], "1 | R | N | B | K | Q |") {
+		t.Errorf("top row = %q, want rank 1 from the h-file", rows[0])
+	}
+	if rows[3][4+4*3] != 'P' {
+		t.Errorf("e4 pawn not on the fourth column of rank 4:\n%s", strings.Join(rows, "\n"))
+	}
+	if !strings.Contains(rendered, "    h   g   f   e   d   c   b   a") {
+		t.Error("file labels must run h to a")
+	}
+	if !strings.HasSuffix(strings.TrimSpace(rendered), "Your move:") {
+		t.Errorf("the player must be prompted after the engine's move:\n%s", rendered)
+	}
+}
+
+// TestTUISetup_SideChoices validates the accepted sides.
+// Gherkin: "Player chooses a side by name, letter or at random"
+func TestTUISetup_SideChoices(t *testing.T) {
+	for in, want := range map[string]chess.Color{"white": chess.White, "W": chess.White, "b": chess.Black, "Black": chess.Black} {
+		if got, err := tui.ParseSide(in); err != nil || got != want {
+			t.Errorf("ParseSide(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	seen := map[chess.Color]bool{}
+	for i := 0; i < 64; i++ {
+		c, err := tui.ParseSide("random")
+		if err != nil {
+			t.Fatalf("ParseSide(random): %v", err)
+		}
+		seen[c] = true
+	}
+	if !seen[chess.White] || !seen[chess.Black] {
+		t.Error("random must pick both sides over 64 tosses")
+	}
+	if _, err := tui.ParseSide("green"); !errors.Is(err, tui.ErrSetup) {
+		t.Errorf("ParseSide(green) error = %v, want ErrSetup", err)
+	}
+}
+
+// ─── Start Position ───────────────────────────────────────────────────────────
+
+// TestTUISetup_StartFromFEN validates a FEN start.
+// Gherkin: "Player starts from a FEN"
+func TestTUISetup_StartFromFEN(t *testing.T) {
+	s := tui.DefaultSetup()
+	start, err := tui.LoadStart(BackRankMateFEN)
+	if err != nil {
+		t.Fatalf("LoadStart: %v", err)
+	}
+	s.Start = start
+	rendered := playTUISetup(t, s, "a1a8\n", noOpEngine(t))
+	if !strings.Contains(rendered, "Checkmate! White wins. 1-0") {
+		t.Errorf("Ra8 must mate from the FEN:\n%s", rendered)
+	}
+}
+
+// TestTUISetup_ContinueFromPGN validates a PGN start.
+// Gherkin: "Player continues a game from a PGN file"
+func TestTUISetup_ContinueFromPGN(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "game.pgn")
+	pgn := "[Event \"Casual\"]\n[White \"A\"]\n[Black \"B\"]\n[Result \"*\"]\n\n1. e4 e5 2. Nf3 *\n"
+	if err := os.WriteFile(path, []byte(pgn), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, load := range []func(string) (chess.Game, error){tui.LoadPGN, tui.LoadStart} {
+		g, err := load(path)
+		if err != nil {
+			t.Fatalf("loading %s: %v", path, err)
+		}
+		if want := "rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2"; g.ToFEN() != want {
+			t.Errorf("position = %s, want %s", g.ToFEN(), want)
+		}
+	}
+}
+
+// ─── Engine Limit ─────────────────────────────────────────────────────────────
+
+// TestTUISetup_EngineDepthAndThinkTime validates the engine limit.
+// Gherkin: "Player sets the engine's depth or thinking time"
+func TestTUISetup_EngineDepthAndThinkTime(t *testing.T) {
+	for in, want := range map[string]engine.TimeControl{"d3": {Depth: 3}, "2s": {MoveTime: 2 * time.Second}} {
+		think, err := tui.ParseThink(in)
+		if err != nil {
+			t.Fatalf("ParseThink(%q): %v", in, err)
+		}
+		s := tui.DefaultSetup()
+		s.Think = think
+		var got []engine.TimeControl
+		playTUISetup(t, s, "e2e4\n", recordingEngine(t, &got, "e7e5"))
+		if len(got) != 1 || got[0] != want {
+			t.Errorf("%s: engine asked with %+v, want %+v", in, got, want)
+		}
+	}
+	for _, in := range []string{"", "d0", "fast", "-1s"} {
+		if _, err := tui.ParseThink(in); !errors.Is(err, tui.ErrSetup) {
+			t.Errorf("ParseThink(%q) error = %v, want ErrSetup", in, err)
+		}
+	}
+}
+
+// ─── Clock ────────────────────────────────────────────────────────────────────
+
+// TestTUISetup_ClockFeedsTheEngine validates a clock game.
+// Gherkin: "Player plays with a 5+3 clock"
+func TestTUISetup_ClockFeedsTheEngine(t *testing.T) {
+	cc, err := clock.Parse("5+3")
+	if err != nil {
+		t.Fatalf("clock.Parse: %v", err)
+	}
+	s := tui.DefaultSetup()
+	s.Clock = cc
+	var got []engine.TimeControl
+	rendered := playTUISetup(t, s, "e2e4\n", recordingEngine(t, &got, "e7e5"))
+	if len(got) != 1 {
+		t.Fatalf("engine called %d times, want once", len(got))
+	}
+	tc := got[0]
+	if tc.WInc != 3*time.Second || tc.BInc != 3*time.Second || tc.BTime > 5*time.Minute || tc.BTime < 4*time.Minute {
+		t.Errorf("engine given %+v, want 5 minutes for Black and 3s increments", tc)
+	}
+	if tc.WTime <= 5*time.Minute || tc.WTime > 5*time.Minute+3*time.Second {
+		t.Errorf("White's time after a move = %v, want 5 minutes less the move plus the increment", tc.WTime)
+	}
+	if !strings.Contains(rendered, "White 5:00 *") || !strings.Contains(rendered, "White 5:03") {
+		t.Errorf("clocks not shown beside the board:\n%s", rendered)
+	}
+}
+
+// TestTUISetup_FlagFallLosesOnTime validates a loss on time.
+// Gherkin: "A side that runs out of time loses"
+func TestTUISetup_FlagFallLosesOnTime(t *testing.T) {
+	s := tui.DefaultSetup()
+	s.Human = chess.Black
+	s.Clock = clock.Control{{Time: 10 * time.Millisecond}}
+	slow := func(g chess.Game, _ engine.TimeControl) chess.Move {
+		time.Sleep(50 * time.Millisecond)
+		return g.LegalMoves()[0]
+	}
+	rendered := playTUISetup(t, s, "", slow)
+	if !strings.Contains(rendered, "White lost on time. 0-1") {
+		t.Errorf("the engine's flag fell but no loss on time:\n%s", rendered)
+	}
+	if strings.Contains(rendered, "Engine plays") {
+		t.Error("a move made after the flag fell must not be played")
+	}
+}
+
+// ─── Start Menu ───────────────────────────────────────────────────────────────
+
+// TestTUISetup_StartMenu validates the start menu.
+// Gherkin: "Player answers the start menu"
+func TestTUISetup_StartMenu(t *testing.T) {
+	var out bytes.Buffer
+	game := tui.NewGame(strings.NewReader("purple\nblack\n\nd3\n5+3\ne7e5\n"), &out, scriptedEngine(t, "e2e4", "g1f3"))
+	game, err := game.Menu()
+	if err != nil {
+		t.Fatalf("Menu: %v\n%s", err, out.String())
+	}
+	s := game.Setup()
+	if s.Human != chess.Black || s.Start.ToFEN() != StartingFEN || s.Think != (engine.TimeControl{Depth: 3}) ||
+		s.Clock.String() != "5+3" {
+		t.Errorf("menu setup = %+v", s)
+	}
+	if n := strings.Count(out.String(), "Play white, black or random?"); n != 2 || !strings.Contains(out.String(), `side "purple"`) {
+		t.Errorf("the bad side must be reported and asked again:\n%s", out.String())
+	}
+	// The game goes on reading the same input after the menu.
+	game.Run()
+	if !strings.Contains(out.String(), "Engine plays e4") || strings.Count(out.String(), "Your move:") != 2 {
+		t.Errorf("game after the menu:\n%s", out.String())
+	}
+}
+
+// ─── CLI ──────────────────────────────────────────────────────────────────────
+
+// TestTUISetup_CLIFlags validates the chess-go setup flags.
+// Gherkin: "Player sets up the game with chess-go flags"
+func TestTUISetup_CLIFlags(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	cmd := exec.Command(bin, "-color", "black", "-depth", "1")
+	cmd.Stdin = strings.NewReader("")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("chess-go -color black: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "Engine plays") || !strings.Contains(string(out), "    h   g   f   e   d   c   b   a") {
+		t.Errorf("want the engine's move and a board from Black's side:\n%s", out)
+	}
+}
+
+// TestTUISetup_CLIRejectsFENWithPGN validates the exclusive start flags.
+// Gherkin: "Player gives both a FEN and a PGN file"
+func TestTUISetup_CLIRejectsFENWithPGN(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	cmd := exec.Command(bin, "-fen", StartingFEN, "-pgn", "game.pgn")
+	cmd.Stdin = strings.NewReader("")
+	out, err := cmd.CombinedOutput()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+		t.Errorf("exit = %v, want status 2\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "mutually exclusive") {
+		t.Errorf("output lacks the reason:\n%s", out)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// playTUISetup runs a TUI game set up as s on input and returns its output.
+func playTUISetup(t *testing.T, s tui.Setup, input string, engineFn tui.EngineFunc) string {
+	t.Helper()
+	var out bytes.Buffer
+	tui.NewGameWithSetup(strings.NewReader(input), &out, engineFn, s).Run()
+	return out.String()
+}
+
+// recordingEngine returns a scripted engine that appends each limit it is given to got.
+func recordingEngine(t *testing.T, got *[]engine.TimeControl, moves ...string) tui.EngineFunc {
+	t.Helper()
+	play := scriptedEngine(t, moves...)
+	return func(g chess.Game, tc engine.TimeControl) chess.Move {
+		*got = append(*got, tc)
+		return play(g, tc)
+	}
+}
+
+// lastBoardRows returns the rank lines of the last board in rendered, top to
+// bottom, whichever side it is drawn from.
+func lastBoardRows(t *testing.T, rendered string) []string {
+	t.Helper()
+	lines := strings.Split(rendered, "\n")
+	var rows []string
+	for i := len(lines) - 1; i >= 0 && len(rows) < 8; i-- {
+		if l := lines[i]; len(l) > 2 && l[0] >= '1' && l[0] <= '8' && l[1] == ' ' {
+			rows = append([]string{l}, rows...)
+		}
+	}
+	if len(rows) != 8 {
+		t.Fatalf("no board in output:\n%s", rendered)
+	}
+	return rows
+}
@@ -2,7 +2,7 @@
 //
 // Mirrors: milestone-3-tui.feature
 // Driving port: tui.NewGame(r io.Reader, w io.Writer, engineFn EngineFunc) tui.Game
-//   called via tui.Game.Run()
+//   and tui.NewGameFromFEN, called via tui.Game.Run()
 //
 // The engine is injected as a stub (tui.EngineFunc) for determinism.
 // No real subprocess is launched. No os.Stdin or os.Stdout is used.
@@ -16,115 +16,47 @@ import (
 	"bytes"
 	"strings"
 	"testing"
-	// Production packages — uncomment once implemented.
-	// chess "chess_go/internal/chess"
-	// engine "chess_go/internal/engine"
-	// "chess_go/internal/tui"
-)
-
-// ─── Stub Engine ──────────────────────────────────────────────────────────────
-
-// stubEngine returns a fixed UCI move string regardless of position.
-// It satisfies the tui.EngineFunc signature once the tui package is implemented.
-//
-//nolint:unused
-type stubEngineFunc struct {
-	response string // UCI string of the move to always return, e.g. "e7e5"
-	called   int    // number of times the engine was called
-}
 
-// call is a placeholder for tui.EngineFunc invocation.
-// Uncomment and adapt to tui.EngineFunc signature once the tui package exists.
-//
-//nolint:unused
-func (s *stubEngineFunc) call() {
-	s.called++
-	// return chess.Move matching s.response
-}
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tui"
+)
 
 // ─── Board Render ─────────────────────────────────────────────────────────────
 
 // TestTUIRender_StartingBoardShowsAllRequiredElements validates US-24 / AC-15-01.
 // Gherkin: "TUI player launches the game and sees the starting board"
 func TestTUIRender_StartingBoardShowsAllRequiredElements(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-	_ = input
-
-	// Provide EOF immediately so the game loop exits after rendering.
-	// input stays empty.
-
-	// stub := func(g chess.Game, tc engine.TimeControl) chess.Move {
-	//     return chess.Move{} // never called — input is empty
-	// }
-	// game := tui.NewGame(&input, &output, stub)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.Contains(t, rendered, "White to move", "must show side-to-move indicator")
-	// assertContainsCoordinates(t, rendered)
-	// assertContainsPieces(t, rendered)
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUI(t, "", noOpEngine(t))
+	if !strings.Contains(rendered, "White to move") {
+		t.Error("must show the side-to-move indicator")
+	}
+	assertContainsCoordinates(t, rendered)
+	assertContainsPieces(t, rendered)
+	if got := strings.Count(lastBoard(t, rendered), "|"); got != 8*9 {
+		t.Errorf("board has %d cell separators, want an 8-by-8 grid", got)
+	}
 }
 
 // TestTUIRender_AllStartingPiecesVisible validates US-24 / AC-15-01.
 // Gherkin: "TUI player sees all starting pieces in their correct positions"
 func TestTUIRender_AllStartingPiecesVisible(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// game := tui.NewGame(&input, &output, noOpEngine)
-	// game.Run()
-	rendered := output.String()
-	_ = rendered
-
-	// The render must contain piece symbols for all 32 starting pieces.
-	// At minimum, king symbols for both sides must appear.
-	// assert.Contains(t, rendered, "K", "White king symbol must be present")
-	// assert.Contains(t, rendered, "k", "Black king symbol must be present (or Unicode equivalent)")
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUI(t, "", noOpEngine(t))
+	assertPieceOn(t, rendered, "e1", 'K')
+	assertPieceOn(t, rendered, "e8", 'k')
+	for _, file := range "abcdefgh" {
+		assertPieceOn(t, rendered, string(file)+"2", 'P')
+		assertPieceOn(t, rendered, string(file)+"7", 'p')
+	}
 }
 
 // TestTUIRender_BlackToMoveIndicatorAfterFirstMove validates US-24 / AC-15-01.
 // Gherkin: "TUI player sees Black to move indicator after the first move"
 func TestTUIRender_BlackToMoveIndicatorAfterFirstMove(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess", "internal/engine")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// Provide one valid move then EOF.
-	input.WriteString("e2e4\n")
-
-	// stub := func(g chess.Game, tc engine.TimeControl) chess.Move {
-	//     // Return a legal Black move so the loop advances.
-	//     m, _ := findMove(g, "e7e5")
-	//     return m
-	// }
-	// game := tui.NewGame(&input, &output, stub)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.Contains(t, rendered, "Black to move",
-	//     "after White's first move, board must show Black to move")
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUI(t, "e2e4\n", scriptedEngine(t, "e7e5"))
+	if !strings.Contains(rendered, "Black to move") {
+		t.Error("after White's first move the board must show Black to move")
+	}
 }
 
 // ─── Legal Move Acceptance ────────────────────────────────────────────────────
@@ -132,52 +64,35 @@ func TestTUIRender_BlackToMoveIndicatorAfterFirstMove(t *testing.T) {
 // TestTUIInput_LegalMoveUpdatesBoard validates US-25 / AC-15-02.
 // Gherkin: "TUI player enters a legal move and sees the updated board"
 func TestTUIInput_LegalMoveUpdatesBoard(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess", "internal/engine")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	input.WriteString("e2e4\n")
-
-	// stub := alwaysRespondsWithMove("e7e5")
-	// game := tui.NewGame(&input, &output, stub)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.Contains(t, rendered, "Engine thinking",
-	//     "TUI must show thinking indicator while engine computes")
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUI(t, "e2e4\n", scriptedEngine(t, "e7e5"))
+	afterMove := rendered[:strings.Index(rendered, "Engine thinking")]
+	assertPieceOn(t, afterMove, "e4", 'P')
+	assertPieceOn(t, afterMove, "e2", '.')
+	thinking, reply := strings.Index(rendered, "Engine thinking"), strings.Index(rendered, "Engine plays e5")
+	if thinking < 0 || reply < thinking {
+		t.Error("the thinking indicator must be shown before the engine's move")
+	}
 }
 
 // TestTUIInput_LegalMoveAndEngineResponseBothReflected validates US-25, US-26 / AC-15-02.
 // Gherkin: "TUI player enters a legal move and the engine responds with a move"
 func TestTUIInput_LegalMoveAndEngineResponseBothReflected(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess", "internal/engine")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	input.WriteString("e2e4\n")
-
-	// stub := alwaysRespondsWithMove("e7e5")
-	// game := tui.NewGame(&input, &output, stub)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// The board re-render after engine response must show both moves.
-	// assertBoardContainsPieceOnSquare(t, rendered, "e4") // White pawn
-	// assertBoardContainsPieceOnSquare(t, rendered, "e5") // Black pawn (after engine)
+	rendered := playTUI(t, "e2e4\n", scriptedEngine(t, "e7e5"))
+	assertPieceOn(t, rendered, "e4", 'P')
+	assertPieceOn(t, rendered, "e5", 'p')
+	if !strings.HasSuffix(strings.TrimSpace(lastBoardAndAfter(rendered)), "White to move\nYour move:") {
+		t.Errorf("want White to move again, got:\n%s", lastBoardAndAfter(rendered))
+	}
+}
 
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+// TestTUIInput_MovesAccumulate validates US-25 / AC-15-02.
+// Gherkin: "TUI player makes several moves in sequence and the game state accumulates correctly"
+func TestTUIInput_MovesAccumulate(t *testing.T) {
+	rendered := playTUI(t, "e2e4\ng1f3\nBc4\n", scriptedEngine(t, "e7e5", "b8c6", "g8f6"))
+	assertPieceOn(t, rendered, "e4", 'P')
+	assertPieceOn(t, rendered, "f3", 'N')
+	assertPieceOn(t, rendered, "c4", 'B')
+	assertPieceOn(t, rendered, "f6", 'n')
 }
 
 // ─── Illegal Move Rejection ───────────────────────────────────────────────────
@@ -185,103 +100,47 @@ func TestTUIInput_LegalMoveAndEngineResponseBothReflected(t *testing.T) {
 // TestTUIInput_IllegalMoveShowsErrorAndRepromptsUser validates US-25 / AC-15-03.
 // Gherkin: "TUI player enters an illegal move and sees a clear error message"
 func TestTUIInput_IllegalMoveShowsErrorAndRepromptsUser(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// First send an illegal move, then a quit/EOF to end the loop.
-	input.WriteString("e2e5\n")
-
-	// game := tui.NewGame(&input, &output, noOpEngine)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.Contains(t, rendered, "Illegal move",
-	//     "error message must contain 'Illegal move'")
-	// assert.Contains(t, rendered, "e2e5",
-	//     "error message must echo the attempted move string")
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUI(t, "e2e5\n", noOpEngine(t))
+	if !strings.Contains(rendered, "Illegal move") || !strings.Contains(rendered, "e2e5") {
+		t.Errorf("want an illegal move message naming e2e5, got:\n%s", rendered)
+	}
+	assertBoardUnchanged(t, rendered)
+	if n := strings.Count(rendered, "Your move:"); n != 2 {
+		t.Errorf("move prompt shown %d times, want 2", n)
+	}
 }
 
 // TestTUIInput_InvalidFormatShowsFormatError validates US-25 / AC-15-03.
 // Gherkin: "TUI player enters a move in the wrong format and sees a format error"
 func TestTUIInput_InvalidFormatShowsFormatError(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	input.WriteString("hello\n")
-
-	// game := tui.NewGame(&input, &output, noOpEngine)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.True(t,
-	//     strings.Contains(rendered, "invalid") || strings.Contains(rendered, "format"),
-	//     "error message must indicate invalid format; got: %q", rendered)
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUI(t, "hello\n", noOpEngine(t))
+	if !strings.Contains(rendered, "Invalid move format") {
+		t.Errorf("want a format error, got:\n%s", rendered)
+	}
+	assertBoardUnchanged(t, rendered)
+	if n := strings.Count(rendered, "Your move:"); n != 2 {
+		t.Errorf("move prompt shown %d times, want 2", n)
+	}
 }
 
 // TestTUIInput_EmptyInputShowsPromptAgain validates AC-15-03 edge case.
 // Gherkin: "TUI player enters an empty input and the prompt is shown again"
 func TestTUIInput_EmptyInputShowsPromptAgain(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// Send empty line then EOF.
-	input.WriteString("\n")
-
-	// game := tui.NewGame(&input, &output, noOpEngine)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// Count move prompts: at minimum two (original + re-prompt after empty input).
-	// promptCount := strings.Count(rendered, "Your move:")
-	// assert.GreaterOrEqual(t, promptCount, 2)
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUI(t, "\n", noOpEngine(t))
+	if n := strings.Count(rendered, "Your move:"); n != 2 {
+		t.Errorf("move prompt shown %d times, want 2", n)
+	}
+	assertBoardUnchanged(t, rendered)
 }
 
 // TestTUIInput_MovingOpponentPieceIsRejected validates AC-15-03.
 // Gherkin: "TUI player tries to move a piece that belongs to the opponent"
 func TestTUIInput_MovingOpponentPieceIsRejected(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// White to move; e7e5 is a Black move.
-	input.WriteString("e7e5\n")
-
-	// game := tui.NewGame(&input, &output, noOpEngine)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.Contains(t, rendered, "Illegal move")
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUI(t, "e7e5\n", noOpEngine(t))
+	if !strings.Contains(rendered, "Illegal move: e7e5") {
+		t.Errorf("want an illegal move message, got:\n%s", rendered)
+	}
+	assertBoardUnchanged(t, rendered)
 }
 
 // ─── Check Notification ───────────────────────────────────────────────────────
@@ -289,30 +148,23 @@ func TestTUIInput_MovingOpponentPieceIsRejected(t *testing.T) {
 // TestTUIStatus_CheckNotificationDisplayed validates US-27 / AC-15-04.
 // Gherkin: "TUI player sees a check notification when a move delivers check"
 func TestTUIStatus_CheckNotificationDisplayed(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess", "internal/engine")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// Position: White plays Qh5+ delivering check. Engine stub returns a checking move.
-	// checkingPosFEN := "rnbqkb1r/pppp1ppp/5n2/4p1Q1/4P3/8/PPPP1PPP/RNB1KBNR b KQkq - 3 3"
-	// input stays empty — engine delivers check, game detects it.
-
-	// stub := func(g chess.Game, tc engine.TimeControl) chess.Move {
-	//     m, _ := findMove(g, "d1h5") // Qh5+
-	//     return m
-	// }
-	// game := tui.NewGame(&input, &output, stub)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.Contains(t, rendered, "Check!")
+	// After 1. f3 e5, with Black to move: Qh4+ is check, not mate.
+	rendered := playTUIFromFEN(t, "rnbqkbnr/pppp1ppp/8/4p3/8/5P2/PPPPP1PP/RNBQKBNR b KQkq - 0 2", "",
+		scriptedEngine(t, "d8h4"))
+	board, check := strings.LastIndex(rendered, "White to move"), strings.LastIndex(rendered, "Check!")
+	if check < board || board < 0 {
+		t.Errorf("want \"Check!\" after the board, got:\n%s", rendered)
+	}
+}
 
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+// TestTUIStatus_PlayerCheckNotificationDisplayed validates US-27 / AC-15-04.
+// Gherkin: "TUI player is notified when the player's own move delivers check to the opponent"
+func TestTUIStatus_PlayerCheckNotificationDisplayed(t *testing.T) {
+	rendered := playTUIFromFEN(t, "8/5p2/3k4/4N3/8/8/8/4K3 w - - 0 1", "e5f7\n", scriptedEngine(t, "d6e7"))
+	check, thinking := strings.Index(rendered, "Check!"), strings.Index(rendered, "Engine thinking")
+	if check < 0 || thinking < check {
+		t.Errorf("want \"Check!\" before the engine responds, got:\n%s", rendered)
+	}
 }
 
 // ─── Game Result Display ─────────────────────────────────────────────────────
@@ -320,115 +172,61 @@ func TestTUIStatus_CheckNotificationDisplayed(t *testing.T) {
 // TestTUIResult_CheckmateDisplayedAndNoFurtherPrompt validates US-27 / AC-15-05.
 // Gherkin: "TUI player sees a checkmate result message and no further move prompt"
 func TestTUIResult_CheckmateDisplayedAndNoFurtherPrompt(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess", "internal/engine")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// Position: engine's first move is checkmate.
-	// preMateFEN := "k7/8/1K6/8/8/8/8/R7 b - - 0 1"
-	// Black to move with no legal moves → engine is not called in this direction.
-	// Actually: use a position where engine delivers mate as White.
-	// Simplest: start game with empty input, engine stub always returns Ra8#.
-
-	// stub := func(g chess.Game, tc engine.TimeControl) chess.Move {
-	//     m, _ := findMove(g, "a1a8") // Ra8# in the Lucena-style position
-	//     return m
-	// }
-	// game := tui.NewGame(&input, &output, stub)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.Contains(t, rendered, "Checkmate", "result message must contain 'Checkmate'")
-	// promptsAfterResult := countPromptsAfterSubstring(rendered, "Checkmate", "Your move:")
-	// assert.Equal(t, 0, promptsAfterResult, "no move prompt must appear after checkmate")
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	// After 1. f3 e5 2. g4, with Black to move: Qh4#.
+	rendered := playTUIFromFEN(t, "rnbqkbnr/pppp1ppp/8/4p3/6P1/5P2/PPPPP2P/RNBQKBNR b KQkq - 0 2", "e2e4\n",
+		scriptedEngine(t, "d8h4"))
+	i := strings.Index(rendered, "Checkmate! Black wins.")
+	if i < 0 {
+		t.Fatalf("want a checkmate message naming the winner, got:\n%s", rendered)
+	}
+	if strings.Contains(rendered[i:], "Your move:") || strings.Contains(rendered, "Check!") {
+		t.Errorf("no prompt or check notice may follow checkmate, got:\n%s", rendered[i:])
+	}
 }
 
 // TestTUIResult_StalemateMessageDisplayed validates US-27 / AC-15-05.
 // Gherkin: "TUI player sees a stalemate result message when the game is drawn by stalemate"
 func TestTUIResult_StalemateMessageDisplayed(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// Load stalemate position directly (no engine needed — no legal moves).
-	// game := tui.NewGameFromFEN(&input, &output, noOpEngine, StalemateFEN)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.Contains(t, rendered, "Stalemate")
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	// Black to move: Qb6 stalemates the White king on a8.
+	rendered := playTUIFromFEN(t, "K7/8/8/2q5/8/8/8/7k b - - 0 1", "", scriptedEngine(t, "c5b6"))
+	i := strings.Index(rendered, "Stalemate")
+	if i < 0 {
+		t.Fatalf("want a stalemate message, got:\n%s", rendered)
+	}
+	if strings.Contains(rendered[i:], "Your move:") {
+		t.Error("no move prompt may follow stalemate")
+	}
 }
 
 // TestTUIResult_DrawByFiftyMoveRuleDisplayed validates US-27 / AC-15-05.
 // Gherkin: "TUI player sees a draw-by-fifty-move-rule message"
 func TestTUIResult_DrawByFiftyMoveRuleDisplayed(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess")
-
-	var input bytes.Buffer
-	_ = input
-	var output bytes.Buffer
-
-	// FEN with half-move clock at 100.
-	fiftyMoveFEN := "8/8/8/8/8/8/8/K6k w - - 100 101"
-	_ = fiftyMoveFEN
-
-	// game := tui.NewGameFromFEN(&input, &output, noOpEngine, fiftyMoveFEN)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.True(t,
-	//     strings.Contains(rendered, "fifty") || strings.Contains(rendered, "draw"),
-	//     "must mention fifty-move rule")
-
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+	rendered := playTUIFromFEN(t, "8/8/8/8/8/8/7r/K6k w - - 100 101", "a1b1\n", noOpEngine(t))
+	if !strings.Contains(rendered, "fifty-move rule") || strings.Contains(rendered, "Your move:") {
+		t.Errorf("want a fifty-move draw and no prompt, got:\n%s", rendered)
+	}
 }
 
 // TestTUIResult_DrawByRepetitionDisplayed validates US-27 / AC-15-05.
 // Gherkin: "TUI player sees a draw-by-threefold-repetition message"
 func TestTUIResult_DrawByRepetitionDisplayed(t *testing.T) {
-	skipUnimplemented(t)
-	_ = requiresProduction("internal/tui", "internal/chess")
-
-	var output bytes.Buffer
-
-	// Simulate a game reaching threefold repetition via the TUI loop.
-	// Moves: Ng1f3 Ng8f6 Nf3g1 Nf6g8 Ng1f3 Ng8f6 Nf3g1 Nf6g8 (same position 3 times).
-	repetitionMoves := []string{"g1f3", "g8f6", "f3g1", "f6g8", "g1f3", "g8f6", "f3g1", "f6g8"}
-	var inputBuf bytes.Buffer
-	for _, m := range repetitionMoves {
-		inputBuf.WriteString(m + "\n")
+	rendered := playTUI(t, "g1f3\nf3g1\ng1f3\nf3g1\ne2e4\n", scriptedEngine(t, "g8f6", "f6g8", "g8f6", "f6g8"))
+	i := strings.Index(rendered, "Draw by threefold repetition")
+	if i < 0 {
+		t.Fatalf("want a repetition draw, got:\n%s", rendered)
 	}
-	_ = inputBuf
-	_ = output
-
-	// stub := alternatingMoveStub(t, repetitionMoves)
-	// game := tui.NewGame(&input, &output, stub)
-	// game.Run()
-
-	rendered := output.String()
-	_ = rendered
-
-	// assert.True(t,
-	//     strings.Contains(rendered, "repetition") || strings.Contains(rendered, "draw"),
-	//     "must mention threefold repetition")
+	if strings.Contains(rendered[i:], "Your move:") {
+		t.Error("no move prompt may follow the draw")
+	}
+}
 
-	t.Fatal("not implemented — remove skipUnimplemented to enable this scenario")
+// TestTUIResult_InsufficientMaterialDisplayed validates the remaining draw type.
+// Gherkin: "TUI player sees a draw-by-insufficient-material message"
+func TestTUIResult_InsufficientMaterialDisplayed(t *testing.T) {
+	rendered := playTUIFromFEN(t, "8/8/8/8/8/8/1q6/K6k w - - 0 1", "a1b2\n", noOpEngine(t))
+	if !strings.Contains(rendered, "Draw by insufficient material") {
+		t.Errorf("want an insufficient material draw, got:\n%s", rendered)
+	}
 }
 
 // ─── PGN Export ───────────────────────────────────────────────────────────────
@@ -519,9 +317,97 @@ func TestTUIPGN_DeclineSaveExitsCleanly(t *testing.T) {
 
 // ─── Helpers ──────────────────────────────────────────────────────────────────
 
+// playTUI runs a TUI game from the starting position on input and returns its output.
+func playTUI(t *testing.T, input string, engineFn tui.EngineFunc) string {
+	t.Helper()
+	var out bytes.Buffer
+	tui.NewGame(strings.NewReader(input), &out, engineFn).Run()
+	return out.String()
+}
+
+// playTUIFromFEN runs a TUI game from fen on input and returns its output.
+func playTUIFromFEN(t *testing.T, fen, input string, engineFn tui.EngineFunc) string {
+	t.Helper()
+	var out bytes.Buffer
+	game, err := tui.NewGameFromFEN(strings.NewReader(input), &out, engineFn, fen)
+	if err != nil {
+		t.Fatalf("NewGameFromFEN(%q): %v", fen, err)
+	}
+	game.Run()
+	return out.String()
+}
+
+// scriptedEngine returns an engine that plays moves, given in UCI notation, in turn.
+func scriptedEngine(t *testing.T, moves ...string) tui.EngineFunc {
+	t.Helper()
+	return func(g chess.Game, _ engine.TimeControl) chess.Move {
+		if len(moves) == 0 {
+			t.Fatal("engine called more often than scripted")
+		}
+		m := mustParseUCI(t, g, moves[0])
+		moves = moves[1:]
+		return m
+	}
+}
+
+// noOpEngine returns an engine that fails the test if it is called.
+func noOpEngine(t *testing.T) tui.EngineFunc {
+	t.Helper()
+	return func(chess.Game, engine.TimeControl) chess.Move {
+		t.Fatal("engine must not be called in this scenario")
+		return chess.Move{}
+	}
+}
+
+// lastBoardAndAfter returns the rendered output from the last board on.
+func lastBoardAndAfter(rendered string) string {
+	i := strings.LastIndex(rendered, "  +---+")
+	if i < 0 {
+		return ""
+	}
+	// Step back to the top border of the last board.
+	top := strings.LastIndex(rendered[:i], "8 |")
+	if top < 0 {
+		return rendered[i:]
+	}
+	return rendered[strings.LastIndex(rendered[:top], "\n")+1:]
+}
+
+// lastBoard returns the eight rank lines of the last board in rendered.
+func lastBoard(t *testing.T, rendered string) string {
+	t.Helper()
+	var ranks []string
+	for _, line := range strings.Split(lastBoardAndAfter(rendered), "\n") {
+		if len(line) > 2 && line[0] >= '1' && line[0] <= '8' && line[1] == ' ' {
+			ranks = append(ranks, line)
+		}
+	}
+	if len(ranks) != 8 {
+		t.Fatalf("no board in output:\n%s", rendered)
+	}
+	return strings.Join(ranks, "\n")
+}
+
+// assertPieceOn checks the piece letter ('.' for empty) on square in the last board.
+func assertPieceOn(t *testing.T, rendered, square string, want byte) {
+	t.Helper()
+	ranks := strings.Split(lastBoard(t, rendered), "\n")
+	line := ranks['8'-square[1]]
+	file := int(square[0] - 'a')
+	if got := line[4+4*file]; got != want {
+		t.Errorf("%s shows %c, want %c", square, got, want)
+	}
+}
+
+// assertBoardUnchanged checks that the board was drawn only once.
+func assertBoardUnchanged(t *testing.T, rendered string) {
+	t.Helper()
+	if n := strings.Count(rendered, "8 |"); n != 1 {
+		t.Errorf("board drawn %d times, want it unchanged", n)
+	}
+}
+
 // assertContainsCoordinates checks that rank and file labels appear in the rendered output.
-//
-//nolint:unused
 func assertContainsCoordinates(t *testing.T, rendered string) {
 	t.Helper()
 	for _, file := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
@@ -537,8 +423,6 @@ func assertContainsCoordinates(t *testing.T, rendered string) {
 }
 
 // assertContainsPieces checks that at least some piece symbols appear in the output.
-//
-//nolint:unused
 func assertContainsPieces(t *testing.T, rendered string) {
 	t.Helper()
 	// ASCII or Unicode — at minimum the king symbol must appear.
@@ -547,15 +431,3 @@ func assertContainsPieces(t *testing.T, rendered string) {
 		t.Errorf("rendered board contains no piece symbols")
 	}
 }
-
-// noOpEngine is a stub engine function that panics if called.
-// Use it when the test does not expect the engine to be invoked.
-//
-//nolint:unused
-func noOpEngineHelper(t *testing.T) {
-	t.Helper()
-	// return func(g chess.Game, tc engine.TimeControl) chess.Move {
-	//     t.Fatal("engine must not be called in this scenario")
-	//     return chess.Move{}
-	// }
-}
@@ -0,0 +1,244 @@
+// tune_steps_test.go — Executable specifications for evaluation tuning.
+//
+// Mirrors: evaluation-tuning.feature
+// Driving ports:
+//   - tune.Dataset (ReadEPD, ReadPGN, Add, FitK, Error) / tune.Tune
+//   - tune.WriteJSON / tune.WriteGo / engine.ReadWeights / engine.LoadWeights
+//   - chess-go tune (CLI)
+
+package acceptance_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tune"
+)
+
+// ─── Training Data ────────────────────────────────────────────────────────────
+
+// TestTune_ReadsLabelledEPD validates result labels in EPD records.
+// Gherkin: "Engine developer loads positions labelled with results from EPD"
+func TestTune_ReadsLabelledEPD(t *testing.T) {
+	var d tune.Dataset
+	n, err := d.ReadEPD(strings.NewReader(`rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - c9 "1-0";
+rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - c9 "1/2-1/2";
+# a comment
+rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - result 0-1;
+rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - id "unlabelled";
+`))
+	if err != nil {
+		t.Fatalf("ReadEPD: %v", err)
+	}
+	if n != 3 || d.Len() != 3 {
+		t.Errorf("added %d positions (Len %d), want 3", n, d.Len())
+	}
+	_, err = d.ReadEPD(strings.NewReader(`8/8/8/8/8/8/8/K6k w - - c9 "won";` + "\n"))
+	if !errors.Is(err, tune.ErrInvalidData) {
+		t.Errorf("unknown result: err = %v, want ErrInvalidData", err)
+	}
+}
+
+// TestTune_ExtractsQuietPositionsFromPGN validates the quiet-position filter.
+// Gherkin: "Engine developer extracts quiet positions from PGN games"
+func TestTune_ExtractsQuietPositionsFromPGN(t *testing.T) {
+	pgn := `[Event "finished"]
+[Result "1-0"]
+
+1. e4 d5 2. exd5 Qxd5 3. Nc3 Qa5 1-0
+
+[Event "unfinished"]
+[Result "*"]
+
+1. d4 d5 2. c4 *
+`
+	var d tune.Dataset
+	n, err := d.ReadPGN(strings.NewReader(pgn), tune.PGNOptions{})
+	if err != nil {
+		t.Fatalf("ReadPGN: %v", err)
+	}
+	// Before 1. e4, 1... d5 and 3... Qa5; the capture 2. exd5, the
+	// recapture and the move after it are skipped.
+	if n != 3 {
+		t.Errorf("added %d positions, want 3", n)
+	}
+	if n, _ = d.ReadPGN(strings.NewReader(pgn), tune.PGNOptions{SkipPlies: 2}); n != 1 {
+		t.Errorf("skipping two plies added %d positions, want 1", n)
+	}
+}
+
+// ─── Fitting ──────────────────────────────────────────────────────────────────
+
+// TestTune_FitsSigmoidScale validates the K search.
+// Gherkin: "Engine developer fits the sigmoid scale"
+func TestTune_FitsSigmoidScale(t *testing.T) {
+	d := knightOddsDataset()
+	k := d.FitK(&engine.DefaultWeights)
+	if k <= 0 {
+		t.Fatalf("FitK = %v, want a positive scale", k)
+	}
+	best := d.Error(&engine.DefaultWeights, k)
+	for _, other := range []float64{k / 2, k * 2} {
+		if e := d.Error(&engine.DefaultWeights, other); e < best {
+			t.Errorf("error %.6f at K %.3f is below %.6f at the fitted K %.3f", e, other, best, k)
+		}
+	}
+}
+
+// TestTune_RaisesUndervaluedPiece validates gradient descent on a clear signal.
+// Gherkin: "Engine developer tunes a piece value that is far too low"
+func TestTune_RaisesUndervaluedPiece(t *testing.T) {
+	d := knightOddsDataset()
+	start := engine.DefaultWeights
+	start.Material[1] = start.Material[0]
+	odds := mustState(t, "4k3/pppppppp/8/8/8/8/PPPPPPPP/1N2K3 w - - 0 1")
+
+	var epochs int
+	tuned, res, err := tune.Tune(context.Background(), d, start, tune.Options{
+		K: 1, Epochs: 200, Rate: 5, Workers: 2,
+		Progress: func(int, float64) { epochs++ },
+	})
+	if err != nil {
+		t.Fatalf("Tune: %v", err)
+	}
+	if res.Epochs != 200 || epochs != 200 {
+		t.Errorf("ran %d epochs with %d progress calls, want 200", res.Epochs, epochs)
+	}
+	if res.Final >= res.Start {
+		t.Errorf("error %.6f -> %.6f, want it to fall", res.Start, res.Final)
+	}
+	before, after := start.Evaluate(odds), tuned.Evaluate(odds)
+	if after <= before+100 {
+		t.Errorf("extra knight evaluates %d after tuning, %d before; want a clear rise", after, before)
+	}
+}
+
+// ─── Weight Files ─────────────────────────────────────────────────────────────
+
+// TestTune_EngineLoadsWeightFile validates the JSON round trip and its use in search.
+// Gherkin: "Engine developer loads tuned weights into the engine"
+func TestTune_EngineLoadsWeightFile(t *testing.T) {
+	w := engine.DefaultWeights
+	w.PieceSquare[0][4*8+0] = 300 // a pawn on a4 is worth a lot
+	var buf bytes.Buffer
+	if err := tune.WriteJSON(&buf, w); err != nil {
+		t.Fatalf("WriteJSON: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "weights.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := engine.LoadWeights(path)
+	if err != nil {
+		t.Fatalf("LoadWeights: %v", err)
+	}
+	if *loaded != w {
+		t.Fatal("loaded weights differ from those written")
+	}
+
+	g := mustGame(t, chess.StartFEN)
+	cfg := engine.Config{Weights: loaded}
+	res := cfg.Search(context.Background(), g, engine.TimeControl{Depth: 1}, io.Discard)
+	if res.BestMove.UCIString() != "a2a4" {
+		t.Errorf("with a4 worth 300 the search plays %s, want a2a4", res.BestMove.UCIString())
+	}
+
+	incomplete := strings.Replace(buf.String(), "100, 320, 330, 500, 900, 0", "100, 320", 1)
+	if _, err := engine.ReadWeights(strings.NewReader(incomplete)); !errors.Is(err, engine.ErrInvalidWeights) {
+		t.Errorf("incomplete material: err = %v, want ErrInvalidWeights", err)
+	}
+}
+
+// TestTune_WritesGoSource validates the generated weights.go.
+// Gherkin: "Engine developer regenerates the built-in weights as Go source"
+func TestTune_WritesGoSource(t *testing.T) {
+	var buf bytes.Buffer
+	if err := tune.WriteGo(&buf, engine.DefaultWeights, "Tuned from 3 positions."); err != nil {
+		t.Fatalf("WriteGo: %v", err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "weights.go", buf.Bytes(), parser.ParseComments)
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v\n%s", err, buf.String())
+	}
+	if f.Name.Name != "engine" || f.Scope.Lookup("DefaultWeights") == nil {
+		t.Errorf("package %s without DefaultWeights:\n%s", f.Name.Name, buf.String())
+	}
+	if !strings.Contains(buf.String(), "Tuned from 3 positions.") {
+		t.Error("the note is missing from the doc comment")
+	}
+}
+
+// TestTune_CommandLine validates the tune subcommand end to end.
+// Gherkin: "Engine developer tunes from the command line"
+func TestTune_CommandLine(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	dir := t.TempDir()
+	epd := filepath.Join(dir, "train.epd")
+	var sb strings.Builder
+	for _, fen := range []string{
+		`4k3/pppppppp/8/8/8/8/PPPPPPPP/1N2K3 w - - c9 "1-0";`,
+		`1n2k3/pppppppp/8/8/8/8/PPPPPPPP/4K3 w - - c9 "0-1";`,
+		`4k3/pppppppp/8/8/8/8/PPPPPPPP/4K3 w - - c9 "1/2-1/2";`,
+	} {
+		sb.WriteString(fen + "\n")
+	}
+	if err := os.WriteFile(epd, []byte(sb.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	jsonOut, goOut := filepath.Join(dir, "w.json"), filepath.Join(dir, "weights.go")
+	cmd := exec.Command(bin, "tune", "-epochs", "20", "-threads", "2", "-out", jsonOut, "-go", goOut, epd)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("chess-go tune: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "3 positions") {
+		t.Errorf("output does not report the positions:\n%s", out)
+	}
+	if _, err := engine.LoadWeights(jsonOut); err != nil {
+		t.Errorf("LoadWeights: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), goOut, nil, 0); err != nil {
+		t.Errorf("Go output: %v", err)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// knightOddsDataset returns pawn endings where an extra knight wins: White
+// wins with it, Black wins with it, and the even positions are drawn. The
+// knight and kings stand on many squares so that only material explains
+// the results.
+func knightOddsDataset() *tune.Dataset {
+	var d tune.Dataset
+	base, _ := chess.NewGameFromFEN("8/pppppppp/8/8/8/8/PPPPPPPP/8 w - - 0 1")
+	for wk := chess.Square(0); wk < 8; wk++ {
+		for bk := chess.Square(56); bk < 64; bk++ {
+			for n := chess.Square(16); n < 48; n += 3 {
+				even := base.State
+				even.Board[wk], even.Board[bk] = chess.WhiteKing, chess.BlackKing
+				d.Add(even, 0.5)
+				white, black := even, even
+				white.Board[n], black.Board[n] = chess.WhiteKnight, chess.BlackKnight
+				d.Add(white, 1)
+				d.Add(black, 0)
+			}
+		}
+	}
+	return &d
+}
+
+func mustState(t *testing.T, fen string) chess.GameState {
+	t.Helper()
+	return mustGame(t, fen).State
+}
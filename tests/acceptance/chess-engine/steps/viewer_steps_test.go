@@ -0,0 +1,321 @@
+// viewer_steps_test.go — Executable specifications for the PGN viewer.
+//
+// Mirrors: pgn-viewer.feature
+// Driving ports:
+//   - chess.ReadPGN and PGNGame.String with comments, NAGs and variations
+//   - tui.Viewer.Run with scripted input and an injected EngineFunc
+//   - the chess-go view subcommand
+
+package acceptance_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/tui"
+)
+
+// annotatedPGN is a short game with a comment before its first move, NAGs,
+// comments, a move suffix and a variation with a variation inside it.
+const annotatedPGN = `[Event "Club Championship"]
+[Site "Riga"]
+[Date "2024.03.01"]
+[Round "1"]
+[White "Alice"]
+[Black "Bob"]
+[Result "1-0"]
+[Annotator "Coach"]
+
+{A quiet start} 1. e4 $1 {Best by test} 1... e5 (1... c5 2. Nf3 (2. c3 d5)
+2... d6 {Najdorf soon}) 2. Nf3!? Nc6 3. Bb5 a6 $14 1-0
+`
+
+// ─── Reading Annotated PGN ────────────────────────────────────────────────────
+
+// TestViewer_ReadsAnnotations validates reading PGN commentary.
+// Gherkin: "Comments, NAGs and variations are read from PGN"
+func TestViewer_ReadsAnnotations(t *testing.T) {
+	pg := mustReadPGN(t, annotatedPGN)[0]
+	if pg.Comment != "A quiet start" {
+		t.Errorf("comment before the first move %q, want %q", pg.Comment, "A quiet start")
+	}
+	if len(pg.Moves) != 6 || len(pg.Annotations) != 6 {
+		t.Fatalf("got %d moves and %d annotations, want 6 of each", len(pg.Moves), len(pg.Annotations))
+	}
+	if a := pg.Annotations[0]; !reflect.DeepEqual(a.NAGs, []int{1}) || a.Comment != "Best by test" {
+		t.Errorf("1.e4: NAGs %v comment %q, want [1] and %q", a.NAGs, a.Comment, "Best by test")
+	}
+	if a := pg.Annotations[2]; a.Suffix != "!?" {
+		t.Errorf("2.Nf3: suffix %q, want !?", a.Suffix)
+	}
+	if a := pg.Annotations[5]; !reflect.DeepEqual(a.NAGs, []int{14}) {
+		t.Errorf("3...a6: NAGs %v, want [14]", a.NAGs)
+	}
+
+	a := pg.Annotations[1]
+	if len(a.Variations) != 1 || len(a.Variations[0]) != 3 || len(a.VariationAnnotations) != 1 {
+		t.Fatalf("1...e5: variations %v, want one of three moves with its annotations", a.Variations)
+	}
+	inner := a.VariationAnnotations[0]
+	if len(inner) != 3 || inner[2].Comment != "Najdorf soon" {
+		t.Fatalf("variation annotations %+v, want a comment on its third move", inner)
+	}
+	if len(inner[1].Variations) != 1 || len(inner[1].Variations[0]) != 2 {
+		t.Errorf("2.Nf3 in the variation: variations %v, want 2.c3 d5", inner[1].Variations)
+	}
+
+	back := mustReadPGN(t, pg.String())[0]
+	if back.Comment != pg.Comment || !reflect.DeepEqual(back.Moves, pg.Moves) ||
+		!reflect.DeepEqual(back.Annotations, pg.Annotations) {
+		t.Errorf("written PGN reads back differently:\n%s", pg.String())
+	}
+}
+
+// ─── Stepping Through a Game ──────────────────────────────────────────────────
+
+// TestViewer_StepsThroughGame validates stepping forward and back.
+// Gherkin: "Player steps forward and backward through a game"
+func TestViewer_StepsThroughGame(t *testing.T) {
+	out := viewPGN(t, annotatedPGN, "n\n\np\n", nil)
+	for _, want := range []string{
+		"Game 1 of 1: Alice - Bob, Club Championship, 2024.03.01  1-0 (3 moves)",
+		"{A quiet start}",
+		"Start (ply 0 of 6)",
+		"1.e4! (good move)  [ply 1 of 6]",
+		"{Best by test}",
+		"Variation 1: 1...c5 2.Nf3 d6",
+		"1...e5  [ply 2 of 6]",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "[ply 1 of 6]"); n != 2 {
+		t.Errorf("ply 1 shown %d times, want twice: stepping back returns to it", n)
+	}
+	assertPieceOn(t, out, "e4", 'P')
+	assertPieceOn(t, out, "e5", '.')
+}
+
+// TestViewer_JumpsToPly validates jumping through the game.
+// Gherkin: "Player jumps to a ply and to either end of the game"
+func TestViewer_JumpsToPly(t *testing.T) {
+	out := viewPGN(t, annotatedPGN, "4\n", nil)
+	if !strings.Contains(out, "2...Nc6  [ply 4 of 6]") {
+		t.Errorf("jump to ply 4 not shown:\n%s", out)
+	}
+	assertPieceOn(t, out, "c6", 'n')
+	assertPieceOn(t, out, "b5", '.')
+
+	out = viewPGN(t, annotatedPGN, "end\n", nil)
+	for _, want := range []string{"3...a6 += (White is slightly better)  [ply 6 of 6]", "End of the game: 1-0"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	assertPieceOn(t, out, "a6", 'p')
+
+	out = viewPGN(t, annotatedPGN, "end\nstart\n", nil)
+	if n := strings.Count(out, "Start (ply 0 of 6)"); n != 2 {
+		t.Errorf("start shown %d times, want on opening and after start", n)
+	}
+	assertPieceOn(t, out, "e2", 'P')
+
+	out = viewPGN(t, annotatedPGN, "9\n", nil)
+	if !strings.Contains(out, "No ply 9: the line has plies 0 to 6.") {
+		t.Errorf("ply beyond the game not reported:\n%s", out)
+	}
+	assertBoardUnchanged(t, out)
+}
+
+// TestViewer_BranchesIntoVariations validates entering and leaving variations.
+// Gherkin: "Player branches into a variation and back"
+func TestViewer_BranchesIntoVariations(t *testing.T) {
+	out := viewPGN(t, annotatedPGN, "1\nv 1\n", nil)
+	if !strings.Contains(out, "1...c5  [variation, ply 1 of 3]") || !strings.Contains(out, "Variation 1: 2.c3 d5") {
+		t.Errorf("variation not entered from 1.e4:\n%s", out)
+	}
+	assertPieceOn(t, out, "c5", 'p')
+	assertPieceOn(t, out, "e5", '.')
+
+	out = viewPGN(t, annotatedPGN, "1\nv 1\nv 1\nn\n", nil)
+	if !strings.Contains(out, "2...d5  [variation, ply 2 of 2]") {
+		t.Errorf("inner variation not replayed:\n%s", out)
+	}
+	assertPieceOn(t, out, "c3", 'P')
+	assertPieceOn(t, out, "f3", '.')
+
+	out = viewPGN(t, annotatedPGN, "1\nv 1\nv 1\np\n", nil)
+	if !strings.HasSuffix(strings.TrimSuffix(out, "View (n, p, help): \n"), "Variation 1: 2.c3 d5\n") {
+		t.Errorf("stepping back from 2.c3 did not return to 1...c5:\n%s", out)
+	}
+	assertPieceOn(t, out, "c5", 'p')
+
+	out = viewPGN(t, annotatedPGN, "1\nv 1\nn\nn\nup\nup\n", nil)
+	for _, want := range []string{"{Najdorf soon}", "1.e4! (good move)  [ply 1 of 6]", "Not in a variation."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	assertPieceOn(t, out, "e4", 'P')
+	assertPieceOn(t, out, "c5", '.')
+
+	out = viewPGN(t, annotatedPGN, "v 1\n", nil)
+	if !strings.Contains(out, `No variation "1" here; the next move has 0.`) {
+		t.Errorf("missing variation not reported:\n%s", out)
+	}
+}
+
+// TestViewer_ShowsTags validates the tag listing.
+// Gherkin: "Player reads the game's tags"
+func TestViewer_ShowsTags(t *testing.T) {
+	out := viewPGN(t, annotatedPGN, "tags\n", nil)
+	want := `[Event "Club Championship"]
+[Site "Riga"]
+[Date "2024.03.01"]
+[Round "1"]
+[White "Alice"]
+[Black "Bob"]
+[Result "1-0"]
+[Annotator "Coach"]
+`
+	if !strings.Contains(out, want) {
+		t.Errorf("tags not listed in order:\n%s", out)
+	}
+}
+
+// ─── Choosing a Game ──────────────────────────────────────────────────────────
+
+// TestViewer_ChoosesFromList validates the searchable game list.
+// Gherkin: "Player chooses a game from a searchable list"
+func TestViewer_ChoosesFromList(t *testing.T) {
+	file := annotatedPGN + `
+[Event "Rapid Open"]
+[White "Carol"]
+[Black "Alice"]
+[Result "0-1"]
+
+1. d4 d5 2. c4 0-1
+
+[Event "Blitz"]
+[White "Dave"]
+[Black "Erin"]
+[Result "1/2-1/2"]
+
+1. c4 1/2-1/2
+`
+	out := viewPGN(t, file, "alice\n2\nn\n", nil)
+	list, rest, _ := strings.Cut(out, "Game 2 of 3")
+	if n := strings.Count(list, "Dave - Erin"); n != 1 {
+		t.Errorf("Dave - Erin listed %d times, want only before the search:\n%s", n, list)
+	}
+	if n := strings.Count(list, "Carol - Alice, Rapid Open, ?  0-1 (2 moves)"); n != 2 {
+		t.Errorf("Carol - Alice listed %d times, want before and after the search:\n%s", n, list)
+	}
+	if !strings.Contains(rest, "1.d4  [ply 1 of 3]") {
+		t.Errorf("chosen game not shown:\n%s", out)
+	}
+
+	out = viewPGN(t, file, "nobody\n\nlist blitz\n3\n", nil)
+	for _, want := range []string{`No game matches "nobody".`, "Game 1 of 3", "  3. Dave - Erin", "Game 3 of 3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+// ─── Playing On ───────────────────────────────────────────────────────────────
+
+// TestViewer_PlaysOnAgainstEngine validates playing from a viewed position.
+// Gherkin: "Player plays on against the engine from a position in the game"
+func TestViewer_PlaysOnAgainstEngine(t *testing.T) {
+	out := viewPGN(t, annotatedPGN, "3\nplay\nf8c5\nresign\nn\n", scriptedEngine(t, "f3e5"))
+	game, after, ok := strings.Cut(out, "Back to the game.")
+	if !ok {
+		t.Fatalf("viewer not resumed after the game:\n%s", out)
+	}
+	for _, want := range []string{"Your move: ", "Engine plays Nxe5"} {
+		if !strings.Contains(game, want) {
+			t.Errorf("game lacks %q:\n%s", want, game)
+		}
+	}
+	if !strings.Contains(after, "2.Nf3!?  [ply 3 of 6]") || !strings.Contains(after, "2...Nc6  [ply 4 of 6]") {
+		t.Errorf("viewer did not resume at ply 3:\n%s", after)
+	}
+}
+
+// TestViewer_WithoutEngine validates play in a viewer without an engine.
+// Gherkin: "The viewer without an engine says so"
+func TestViewer_WithoutEngine(t *testing.T) {
+	out := viewPGN(t, annotatedPGN, "play\n", nil)
+	if !strings.Contains(out, "No engine to play against.") {
+		t.Errorf("missing engine not reported:\n%s", out)
+	}
+}
+
+// ─── Command Line ─────────────────────────────────────────────────────────────
+
+// TestViewer_CLI validates the view subcommand.
+// Gherkin: "Player views a PGN file from the command line"
+func TestViewer_CLI(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	path := filepath.Join(t.TempDir(), "game.pgn")
+	if err := os.WriteFile(path, []byte(annotatedPGN), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(bin, "view", "-style", "ascii", path)
+	cmd.Stdin = strings.NewReader("n\nend\nquit\n")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("chess-go view: %v\n%s", err, out)
+	}
+	for _, want := range []string{"{A quiet start}", "{Best by test}", "End of the game: 1-0"} {
+		if !bytes.Contains(out, []byte(want)) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+// TestViewer_CLIMissingFile validates the view subcommand's error exit.
+// Gherkin: "chess-go view reports a missing file"
+func TestViewer_CLIMissingFile(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	err := exec.Command(bin, "view", filepath.Join(t.TempDir(), "missing.pgn")).Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+		t.Errorf("exit %v, want status 1", err)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// mustReadPGN reads the games of pgn.
+func mustReadPGN(t *testing.T, pgn string) []chess.PGNGame {
+	t.Helper()
+	games, err := chess.ReadPGN(strings.NewReader(pgn))
+	if err != nil {
+		t.Fatalf("ReadPGN: %v", err)
+	}
+	return games
+}
+
+// viewPGN runs the viewer over the games of pgn on input, with engineFn to
+// play on against when it is not nil, and returns its output.
+func viewPGN(t *testing.T, pgn, input string, engineFn tui.EngineFunc) string {
+	t.Helper()
+	var out bytes.Buffer
+	v := tui.NewViewer(strings.NewReader(input), &out, mustReadPGN(t, pgn))
+	if engineFn != nil {
+		v = v.WithEngine(engineFn, engine.TimeControl{Depth: 1})
+	}
+	v.Run()
+	return out.String()
+}
@@ -0,0 +1,179 @@
+// watch_steps_test.go — Executable specifications for watching engine games.
+//
+// Mirrors: engine-watch.feature
+// Driving ports:
+//   - tui.Spectator.Show, tui.Spectator.Announce
+//   - match.Match.Run with a Move hook
+//   - the chess-go watch subcommand
+
+package acceptance_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	chess "chess_go/internal/chess"
+	engine "chess_go/internal/engine"
+	"chess_go/internal/match"
+	"chess_go/internal/tui"
+)
+
+// ─── Drawing Moves ────────────────────────────────────────────────────────────
+
+// TestWatch_ShowsEvalAndPV validates the spectator's frame.
+// Gherkin: "Each move is drawn with an eval bar and the principal variation"
+func TestWatch_ShowsEvalAndPV(t *testing.T) {
+	g := mustGame(t, StartingFEN)
+	g, _ = g.Apply(mustParseUCI(t, g, "e2e4"))
+	m := mustParseUCI(t, g, "e7e5")
+	next, _ := g.Apply(m)
+	res := engine.SearchResult{
+		BestMove: m,
+		Score:    -120, // Black's view: White is better
+		Depth:    6,
+		PV:       []chess.Move{m, mustParseUCI(t, next, "g1f3")},
+	}
+
+	var out bytes.Buffer
+	tui.NewSpectator(&out, 0).Show("Game 1 of 2: A (White) vs B (Black)", g, m, res)
+	frame := out.String()
+	for _, want := range []string{
+		"Game 1 of 2: A (White) vs B (Black)\n",
+		"Last move: 1...e5\n",
+		"+1.20  depth 6\n",
+		"PV: 1...e5 2.Nf3\n",
+	} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("want %q in:\n%s", want, frame)
+		}
+	}
+	if !strings.Contains(frame, "5 | . | . | . | . | p |") {
+		t.Errorf("want the board after the move:\n%s", frame)
+	}
+
+	white := evalBarFill(t, frame)
+	out.Reset()
+	res.Score = 120
+	tui.NewSpectator(&out, 0).Show("", g, m, res)
+	if black := evalBarFill(t, out.String()); black >= white {
+		t.Errorf("bar fill %d with Black better, %d with White better", black, white)
+	}
+}
+
+// TestWatch_NoEvaluation validates a frame for an engine without a search.
+// Gherkin: "A search without an evaluation shows no eval bar"
+func TestWatch_NoEvaluation(t *testing.T) {
+	g := mustGame(t, StartingFEN)
+	m := mustParseUCI(t, g, "g1f3")
+	var out bytes.Buffer
+	tui.NewSpectator(&out, 0).Show("Game 1", g, m, engine.SearchResult{BestMove: m})
+	if !strings.Contains(out.String(), "Last move: 1.Nf3\n") {
+		t.Errorf("want the move drawn:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "Eval") || strings.Contains(out.String(), "PV:") {
+		t.Errorf("want no eval without a search:\n%s", out.String())
+	}
+}
+
+// ─── Playing the Series ───────────────────────────────────────────────────────
+
+// TestWatch_MatchMoveHook validates the match's per-move hook.
+// Gherkin: "The match reports every move of every game"
+func TestWatch_MatchMoveHook(t *testing.T) {
+	plies := map[int]int{}
+	var pgn bytes.Buffer
+	m := match.Match{
+		A:            depthContender("depth2", 2),
+		B:            depthContender("depth1", 1),
+		Games:        2,
+		Adjudication: match.Adjudication{MaxPlies: 12},
+		PGN:          &pgn,
+		Move: func(round int, pos match.Position, res engine.SearchResult) {
+			if last := pos.Moves[len(pos.Moves)-1]; last != res.BestMove {
+				t.Errorf("round %d: last move %s, search chose %s", round, last.UCIString(), res.BestMove.UCIString())
+			}
+			plies[round]++
+		},
+	}
+	if _, err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	games, err := chess.ReadPGN(&pgn)
+	if err != nil || len(games) != 2 {
+		t.Fatalf("PGN: %d games, %v", len(games), err)
+	}
+	for _, g := range games {
+		round := map[string]int{"1": 1, "2": 2}[g.Tags["Round"]]
+		if plies[round] != len(g.Moves) {
+			t.Errorf("round %d: hook saw %d moves, game has %d", round, plies[round], len(g.Moves))
+		}
+	}
+}
+
+// TestWatch_Series validates the watch subcommand.
+// Gherkin: "Engine developer watches a series with alternating colours"
+func TestWatch_Series(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	path := filepath.Join(t.TempDir(), "watch.pgn")
+	out, err := exec.Command(bin, "watch", "-a", "builtin:skill=0", "-name-a", "weak", "-name-b", "strong",
+		"-games", "2", "-depth", "1", "-delay", "0", "-max-plies", "16", "-pgnout", path).Output()
+	if err != nil {
+		t.Fatalf("chess-go watch: %v\n%s", err, out)
+	}
+	for _, want := range []string{
+		"Game 1 of 2: weak (White) vs strong (Black)",
+		"Game 2 of 2: strong (White) vs weak (Black)",
+		"Last move: 1.",
+		"depth 1\n",
+		"PV: ",
+		"Game 1: weak - strong ",
+		"Game 2: strong - weak ",
+		"weak vs strong: +",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("want %q in the output", want)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read PGN: %v", err)
+	}
+	games, err := chess.ReadPGN(bytes.NewReader(data))
+	if err != nil || len(games) != 2 {
+		t.Fatalf("PGN holds %d games (%v):\n%s", len(games), err, data)
+	}
+	if games[0].Tags["White"] != "weak" || games[1].Tags["White"] != "strong" {
+		t.Errorf("White was %q then %q, want the colours reversed", games[0].Tags["White"], games[1].Tags["White"])
+	}
+}
+
+// TestWatch_BadSpec validates spec checking.
+// Gherkin: "chess-go watch rejects a bad engine spec"
+func TestWatch_BadSpec(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	err := exec.Command(bin, "watch", "-a", "builtin:bogus=1", "-delay", "0").Run()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+		t.Errorf("chess-go watch -a builtin:bogus=1: %v, want exit status 2", err)
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// evalBarFill returns the number of filled cells of the eval bar in frame.
+func evalBarFill(t *testing.T, frame string) int {
+	t.Helper()
+	_, bar, ok := strings.Cut(frame, "Eval [")
+	bar, _, ok2 := strings.Cut(bar, "]")
+	if !ok || !ok2 {
+		t.Fatalf("no eval bar in:\n%s", frame)
+	}
+	return strings.Count(bar, "#")
+}
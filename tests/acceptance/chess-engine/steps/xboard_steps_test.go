@@ -0,0 +1,235 @@
+// xboard_steps_test.go — Executable specifications for the CECP front-end.
+//
+// Mirrors: xboard-protocol.feature
+// Driving ports:
+//   - engine.XBoardHandler.Run, fed through a pipe so that each scenario can
+//     wait for the engine's reply before sending the next command
+//   - chess-go xboard, for its exit status
+
+package acceptance_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	engine "chess_go/internal/engine"
+)
+
+// ─── Handshake ────────────────────────────────────────────────────────────────
+
+// TestXBoard_AnnouncesFeatures validates the protover 2 reply.
+// Gherkin: "GUI user sees the engine announce its features"
+func TestXBoard_AnnouncesFeatures(t *testing.T) {
+	x := startXBoard(t)
+	x.send("xboard", "protover 2")
+	x.expect("feature done=0")
+	features := x.expect("feature myname=")
+	for _, f := range []string{"setboard=1", "usermove=1", "ping=1", "analyze=1"} {
+		if !strings.Contains(features, f) {
+			t.Errorf("features lack %s: %s", f, features)
+		}
+	}
+	x.expect("feature done=1")
+}
+
+// TestXBoard_AnswersPing validates ping/pong.
+// Gherkin: "GUI user pings the engine"
+func TestXBoard_AnswersPing(t *testing.T) {
+	x := startXBoard(t)
+	x.send("ping 7")
+	if got := x.expect("pong"); got != "pong 7" {
+		t.Errorf("got %q, want \"pong 7\"", got)
+	}
+}
+
+// ─── Playing ──────────────────────────────────────────────────────────────────
+
+// TestXBoard_RepliesToUserMove validates that the engine plays Black after new.
+// Gherkin: "GUI user plays a move and the engine answers"
+func TestXBoard_RepliesToUserMove(t *testing.T) {
+	x := startXBoard(t)
+	x.send("new", "st 1", "usermove e2e4")
+	game := mustGame(t, "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1")
+	assertMoveIsLegal(t, mustParseUCI(t, game, x.move()), game.LegalMoves())
+}
+
+// TestXBoard_RefusesIllegalMove validates the "Illegal move" reply.
+// Gherkin: "GUI user sees an illegal move refused"
+func TestXBoard_RefusesIllegalMove(t *testing.T) {
+	x := startXBoard(t)
+	x.send("new", "usermove e2e5")
+	if got := x.expect("Illegal move"); got != "Illegal move: e2e5" {
+		t.Errorf("got %q, want \"Illegal move: e2e5\"", got)
+	}
+}
+
+// TestXBoard_SetboardAndGo validates setboard, sd, go and the result claim.
+// Gherkin: "GUI user sets up a position and starts the engine"
+func TestXBoard_SetboardAndGo(t *testing.T) {
+	x := startXBoard(t)
+	x.send("force", "setboard 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "sd 3", "go")
+	if got := x.move(); got != "a1a8" {
+		t.Errorf("engine played %s, want the mate a1a8", got)
+	}
+	x.expect("1-0 {White mates}")
+}
+
+// TestXBoard_RemoveTakesBackMoves validates remove in force mode.
+// Gherkin: "GUI user takes back a move in force mode"
+func TestXBoard_RemoveTakesBackMoves(t *testing.T) {
+	x := startXBoard(t)
+	x.send("new", "force", "usermove e2e4", "usermove e7e5", "remove", "sd 2", "usermove d2d4", "go")
+	game := mustGame(t, "rnbqkbnr/pppppppp/8/8/3P4/8/PPP1PPPP/RNBQKBNR b KQkq d3 0 1")
+	assertMoveIsLegal(t, mustParseUCI(t, game, x.move()), game.LegalMoves())
+}
+
+// ─── Thinking and Analysis ────────────────────────────────────────────────────
+
+// TestXBoard_PostsThinking validates post and the thinking output format.
+// Gherkin: "GUI user watches the engine think"
+func TestXBoard_PostsThinking(t *testing.T) {
+	x := startXBoard(t)
+	x.send("new", "post", "sd 3", "usermove e2e4")
+	depth := 0
+	for {
+		line := x.next()
+		if strings.HasPrefix(line, "move ") {
+			break
+		}
+		f := strings.Fields(line)
+		if len(f) < 5 {
+			t.Fatalf("thinking line %q lacks ply, score, time, nodes and pv", line)
+		}
+		for _, n := range f[:4] {
+			if _, err := strconv.Atoi(n); err != nil {
+				t.Fatalf("thinking line %q: %q is not a number", line, n)
+			}
+		}
+		depth, _ = strconv.Atoi(f[0])
+	}
+	if depth != 3 {
+		t.Errorf("last thinking line reached ply %d, want 3", depth)
+	}
+}
+
+// TestXBoard_AnalyzeFindsMate validates analyze mode and exit.
+// Gherkin: "GUI user analyzes a position"
+func TestXBoard_AnalyzeFindsMate(t *testing.T) {
+	x := startXBoard(t)
+	x.send("force", "setboard 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1", "analyze")
+	line := x.expect("1 ")
+	if f := strings.Fields(line); len(f) < 5 || f[1] != "100001" || f[4] != "a1a8" {
+		t.Errorf("analysis line %q, want a mate in one with pv a1a8", line)
+	}
+	x.send("exit", "ping 1")
+	for {
+		line := x.next()
+		if strings.HasPrefix(line, "move ") {
+			t.Fatalf("analysis played %q", line)
+		}
+		if line == "pong 1" {
+			return
+		}
+	}
+}
+
+// TestXBoard_CLIExitStatus validates the exit status of chess-go xboard.
+// Gherkin: "GUI user sees chess-go xboard exit with a status"
+func TestXBoard_CLIExitStatus(t *testing.T) {
+	bin := mustBuildBinary(t, "./cmd/chess-go")
+	cmd := exec.Command(bin, "xboard")
+	cmd.Stdin = strings.NewReader("xboard\nprotover 2\nquit\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("chess-go xboard then quit: %v, want exit status 0\n%s", err, out)
+	}
+
+	for _, args := range [][]string{{"xboard", "-depth", "3"}, {"xboard", "extra"}} {
+		out, err := exec.Command(bin, args...).CombinedOutput()
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+			t.Errorf("chess-go %s: %v, want exit status 2\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+}
+
+// ─── Helpers ──────────────────────────────────────────────────────────────────
+
+// xboardSession is a running XBoardHandler fed through a pipe.
+type xboardSession struct {
+	t     *testing.T
+	in    *io.PipeWriter
+	lines chan string
+}
+
+// startXBoard runs a handler with the built-in search until the test ends.
+func startXBoard(t *testing.T) *xboardSession {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	x := &xboardSession{t: t, in: inW, lines: make(chan string, 1024)}
+	done := make(chan struct{})
+	go func() {
+		engine.NewXBoardHandler(nil).Run(inR, outW)
+		_ = outW.Close()
+		close(done)
+	}()
+	go func() {
+		sc := bufio.NewScanner(outR)
+		for sc.Scan() {
+			x.lines <- sc.Text()
+		}
+		close(x.lines)
+	}()
+	t.Cleanup(func() {
+		_ = inW.Close()
+		<-done
+	})
+	return x
+}
+
+// send writes commands to the engine.
+func (x *xboardSession) send(cmds ...string) {
+	x.t.Helper()
+	for _, c := range cmds {
+		if _, err := io.WriteString(x.in, c+"\n"); err != nil {
+			x.t.Fatalf("send %q: %v", c, err)
+		}
+	}
+}
+
+// next returns the next line of output, failing after ten seconds.
+func (x *xboardSession) next() string {
+	x.t.Helper()
+	select {
+	case line, ok := <-x.lines:
+		if !ok {
+			x.t.Fatal("engine closed its output")
+		}
+		return line
+	case <-time.After(10 * time.Second):
+		x.t.Fatal("timed out waiting for the engine")
+	}
+	return ""
+}
+
+// expect skips output until a line starting with prefix and returns it.
+func (x *xboardSession) expect(prefix string) string {
+	x.t.Helper()
+	for {
+		if line := x.next(); strings.HasPrefix(line, prefix) {
+			return line
+		}
+	}
+}
+
+// move waits for the engine's "move X" and returns X.
+func (x *xboardSession) move() string {
+	x.t.Helper()
+	return strings.TrimPrefix(x.expect("move "), "move ")
+}
@@ -0,0 +1,50 @@
+# language: en
+Feature: Time Management
+  As a player of clocked games against the engine
+  I want the engine to spread its clock sensibly over the game
+  So that it never loses on time and thinks longest where it matters
+
+  # ─── Allocation ───────────────────────────────────────────────────────────
+
+  Scenario: Engine holds the move overhead back from a fixed move time
+    Given "go movetime 1000" with a move overhead of 30 ms
+    Then the soft and hard limits are both 970 ms
+
+  Scenario: Engine allocates more per move when fewer moves are left to the control
+    Given 60 seconds left on the clock
+    Then the allocation with 10 moves to go exceeds the allocation with 40 moves to go
+    And with one move to go the hard limit still leaves a quarter of the clock
+
+  Scenario: Engine switches to emergency mode when its clock runs low
+    Given 500 ms left on the clock and no increment
+    Then the time manager is in emergency mode
+    And the hard limit is at most a fifth of the clock
+
+  # ─── Instability ──────────────────────────────────────────────────────────
+
+  Scenario: Engine thinks longer when the best move keeps changing
+    Given a search whose best move changes between iterations
+    Then the soft limit is stretched but never beyond the hard limit
+
+  Scenario: Engine thinks longer when the score drops
+    Given a search whose score falls by a pawn in an iteration
+    Then the soft limit is stretched by half
+
+  # ─── Never Flagging ───────────────────────────────────────────────────────
+
+  Scenario Outline: Engine never flags over a long game
+    Given a simulated game at <control> with a fake clock
+    And searches of every speed, including ones stopped at the hard limit every move
+    When 150 moves are played by each side
+    Then neither clock falls to zero
+    And the engine uses a fair share of its time
+
+    Examples:
+      | control |
+      | 1+0     |
+      | 3+2     |
+      | 40/120  |
+
+  Scenario: Engine search respects the hard limit on a real clock
+    Given the built-in search with 2 seconds on the clock
+    Then it returns within its hard limit plus 50 ms
@@ -0,0 +1,56 @@
+# language: en
+Feature: TUI Commands
+  As Sofia the TUI player
+  I want commands besides moves at the prompt
+  So that I can take back, get hints, save and load games and end them my way
+
+  # Implementation note: scenarios drive tui.Game.Run with injected input and
+  # scripted engines; draw offers use tui.Game.WithSearch for the evaluation.
+
+  # ─── Moves ────────────────────────────────────────────────────────────────
+
+  Scenario: Player takes back the last move pair
+    Given the player has played 1.e4 and the engine 1...e5
+    When the player types "undo"
+    Then the starting position is drawn again
+    But with no move of the player's to take back "Nothing to undo." is shown
+
+  Scenario: Player asks for a hint
+    When the player types "hint"
+    Then the engine's suggestion is shown in SAN, as "Hint: Nf3"
+
+  Scenario: Player flips the board
+    When the player types "flip"
+    Then the board is drawn again with rank 1 at the top
+
+  # ─── Positions and Files ──────────────────────────────────────────────────
+
+  Scenario: Player saves the game as PGN
+    Given the player has played 1.e4 and the engine 1...e5
+    When the player types "save" with a file name
+    Then the file holds the game in PGN with its two moves
+
+  Scenario: Player loads a position from a FEN or a PGN file
+    When the player types "load" with a FEN, then with a PGN file
+    Then the board shows each position in turn
+    And a position that cannot be read is reported
+
+  Scenario: Player prints the position as FEN
+    Given the player has played 1.e4 and the engine 1...e5
+    When the player types "fen"
+    Then the FEN of the position is printed
+
+  # ─── Ending the Game ──────────────────────────────────────────────────────
+
+  Scenario: Player resigns
+    When the player types "resign"
+    Then "You resign. Black wins. 0-1" is shown and the game ends
+
+  Scenario: The engine accepts or declines a draw by its evaluation
+    When the player offers a draw in a position the engine sees as lost, then won
+    Then the engine accepts the first and declines the second
+    And an engine that reports no evaluation declines
+
+  Scenario: Player asks for help
+    When the player types "help"
+    Then every command is listed
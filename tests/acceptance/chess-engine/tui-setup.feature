@@ -0,0 +1,72 @@
+# language: en
+Feature: TUI Game Setup
+  As Sofia the TUI player
+  I want to choose my side, the start position, the engine's strength and a clock
+  So that I can play the game I want instead of always White from the start
+
+  # Implementation note: scenarios drive tui.NewGameWithSetup and tui.Game.Menu
+  # with injected I/O; the CLI scenarios run the chess-go binary with piped stdin.
+
+  # ─── Side ─────────────────────────────────────────────────────────────────
+
+  Scenario: Player plays Black and sees the board from Black's side
+    Given a TUI game where the player has Black
+    When the game starts from the standard position
+    Then the engine makes the first move
+    And the board is drawn with rank 1 at the top and the h-file on the left
+    And the player is prompted for their move
+
+  Scenario: Player chooses a side by name, letter or at random
+    When the side "white", "b" or "random" is chosen
+    Then it is accepted
+    And an unknown side is rejected
+
+  # ─── Start Position ───────────────────────────────────────────────────────
+
+  Scenario: Player starts from a FEN
+    Given a TUI game set up from a FEN with White to move
+    When the player makes the mating move
+    Then the game ends in checkmate
+
+  Scenario: Player continues a game from a PGN file
+    Given a PGN file of a game after 1.e4 e5 2.Nf3
+    When it is loaded as the start position
+    Then Black is to move in the position after 2.Nf3
+
+  # ─── Engine Limit ─────────────────────────────────────────────────────────
+
+  Scenario: Player sets the engine's depth or thinking time
+    Given the engine limit "d3", and then "2s"
+    When the engine is asked for a move
+    Then it is asked to search to depth 3, and then for 2 seconds
+
+  # ─── Clock ────────────────────────────────────────────────────────────────
+
+  Scenario: Player plays with a 5+3 clock
+    Given a TUI game with the clock "5+3"
+    When the engine is asked for a move
+    Then it is given both sides' remaining time and the 3 second increment
+    And both clocks are shown beside the board
+
+  Scenario: A side that runs out of time loses
+    Given a TUI game with a clock shorter than the engine takes to move
+    When the engine moves
+    Then the engine's side loses on time
+
+  # ─── Start Menu ───────────────────────────────────────────────────────────
+
+  Scenario: Player answers the start menu
+    Given the start menu
+    When the player answers black, the standard position, depth 3 and a 5+3 clock
+    Then the game is set up that way
+    And an answer the menu cannot use is reported and the question asked again
+
+  # ─── CLI ──────────────────────────────────────────────────────────────────
+
+  Scenario: Player sets up the game with chess-go flags
+    When "chess-go -color black -depth 1" is run
+    Then the engine moves first and the board is drawn from Black's side
+
+  Scenario: Player gives both a FEN and a PGN file
+    When "chess-go -fen FEN -pgn FILE" is run
+    Then it exits with a usage error
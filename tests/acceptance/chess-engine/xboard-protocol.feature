@@ -0,0 +1,61 @@
+# language: en
+Feature: XBoard (CECP v2) Protocol
+  As a chess GUI user
+  I want the engine to speak the XBoard protocol as well as UCI
+  So that it plays inside XBoard, WinBoard and other CECP interfaces
+
+  # ─── Handshake ────────────────────────────────────────────────────────────
+
+  Scenario: GUI user sees the engine announce its features
+    When I send "xboard" and "protover 2"
+    Then the engine replies "feature done=0" before its features
+    And the features include setboard=1, usermove=1, ping=1 and analyze=1
+    And the engine finishes with "feature done=1"
+
+  Scenario: GUI user pings the engine
+    When I send "ping 7"
+    Then the engine replies "pong 7"
+
+  # ─── Playing ──────────────────────────────────────────────────────────────
+
+  Scenario: GUI user plays a move and the engine answers
+    Given "new" and "st 1"
+    When I send "usermove e2e4"
+    Then the engine answers with a legal "move" for Black
+
+  Scenario: GUI user sees an illegal move refused
+    Given "new"
+    When I send "usermove e2e5"
+    Then the engine replies "Illegal move: e2e5"
+
+  Scenario: GUI user sets up a position and starts the engine
+    Given "force" and "setboard 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1"
+    When I send "sd 3" and "go"
+    Then the engine replies "move a1a8"
+    And it claims "1-0 {White mates}"
+
+  Scenario: GUI user takes back a move in force mode
+    Given "new" and "force"
+    When I send "usermove e2e4", "usermove e7e5" and "remove"
+    And I send "usermove d2d4" and "go"
+    Then the engine answers with a legal "move" for Black after 1. d4
+
+  # ─── Thinking and analysis ────────────────────────────────────────────────
+
+  Scenario: GUI user watches the engine think
+    Given "new", "post" and "sd 3"
+    When I send "usermove e2e4"
+    Then the engine prints thinking lines "ply score time nodes pv" up to ply 3
+    And then its move
+
+  Scenario: GUI user analyzes a position
+    Given "force" and "setboard 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1"
+    When I send "analyze"
+    Then the engine prints a mate score with the principal variation "a1a8"
+    When I send "exit"
+    Then no move is played
+
+  Scenario: GUI user sees chess-go xboard exit with a status
+    When chess-go xboard reads "xboard", "protover 2" and "quit"
+    Then it exits 0
+    And it exits 2 when started with a flag or an argument